@@ -0,0 +1,112 @@
+package bind
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	tcvm "github.com/xunleichain/tc-wasm/vm"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// wasmInitArgsID marks the arguments of the Init method in the payload of a
+// tc-wasm contract creation.
+var wasmInitArgsID = []byte("XLTC")
+
+// evmABI binds the Solidity ABI of an EVM contract.
+type evmABI struct {
+	abi.ABI
+}
+
+// deployCode appends the packed arguments of the constructor to the bytecode.
+func (a evmABI) deployCode(bytecode []byte, params ...interface{}) ([]byte, []byte, error) {
+	input, err := a.Pack("", params...)
+	if err != nil {
+		return nil, nil, err
+	}
+	input = append(common.CopyBytes(bytecode), input...)
+	return input, input, nil
+}
+
+func (a evmABI) eventTopics(name string, query ...[]interface{}) ([][]common.Hash, error) {
+	event, ok := a.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("abi: could not locate event '%s'", name)
+	}
+	// The topic of an event that is not anonymous is its id
+	query = append([][]interface{}{{event.Id()}}, query...)
+	return makeTopics(query...)
+}
+
+func (a evmABI) unpackLog(out interface{}, event string, log types.Log) error {
+	if len(log.Data) > 0 {
+		if err := a.Unpack(out, event, log.Data); err != nil {
+			return err
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range a.Events[event].Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(log.Topics) == 0 {
+		return errors.New("log without topics")
+	}
+	return parseTopics(out, indexed, log.Topics[1:])
+}
+
+// wasmABI binds the interface of a tc-wasm contract.
+type wasmABI struct {
+	abi.WasmABI
+}
+
+// deployCode prefixes the code with the arguments of the Init method if there
+// are any. The address of a tc-wasm contract is derived from its code only.
+func (a wasmABI) deployCode(code []byte, params ...interface{}) ([]byte, []byte, error) {
+	if !types.IsWasmContract(code) {
+		return nil, nil, errors.New("not a wasm contract code")
+	}
+	if len(a.Constructor.Inputs) == 0 && len(params) == 0 {
+		return code, code, nil
+	}
+	args, err := a.Pack("", params...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(args) > math.MaxUint16 {
+		return nil, nil, fmt.Errorf("init arguments too long: %d bytes", len(args))
+	}
+
+	var input bytes.Buffer
+	input.Write(tcvm.WasmBytes)
+	input.Write(wasmInitArgsID)
+	binary.Write(&input, binary.BigEndian, uint16(len(args)))
+	input.Write(args)
+	input.Write(code)
+	return input.Bytes(), code, nil
+}
+
+// eventTopics returns the topic of the event, the inputs of the tc-wasm events
+// are not indexed.
+func (a wasmABI) eventTopics(name string, query ...[]interface{}) ([][]common.Hash, error) {
+	event, ok := a.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("abi: could not locate event '%s'", name)
+	}
+	for _, rules := range query {
+		if len(rules) > 0 {
+			return nil, errors.New("wasm events have no indexed inputs")
+		}
+	}
+	return [][]common.Hash{{event.Id()}}, nil
+}
+
+func (a wasmABI) unpackLog(out interface{}, event string, log types.Log) error {
+	return a.Unpack(out, event, log.Data)
+}
@@ -0,0 +1,169 @@
+package bind_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+	"github.com/lianxiangcloud/linkchain/accounts/abi/bind"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/simulated"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+var (
+	_ bind.ContractBackend       = (*simulated.Backend)(nil)
+	_ bind.PendingContractCaller = (*simulated.Backend)(nil)
+	_ bind.DeployBackend         = (*simulated.Backend)(nil)
+)
+
+var testBalance, _ = new(big.Int).SetString("0xfffffffffffffffffffffffffff", 0)
+
+func newTestBackend(t *testing.T) (*simulated.Backend, *bind.TransactOpts) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth := bind.NewKeyedTransactor(key)
+	b, err := simulated.NewBackend(map[string]types.GenesisAccount{auth.From.Hex(): {Balance: testBalance}})
+	require.NoError(t, err)
+	return b, auth
+}
+
+// commit commits the block of tx and returns its successful receipt
+func commit(t *testing.T, b *simulated.Backend, tx *types.Transaction) *types.Receipt {
+	_, err := b.Commit()
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, b, tx)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, receipt.VMErr)
+	return receipt
+}
+
+type transferEvent struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+func TestDeployContract(t *testing.T) {
+	b, auth := newTestBackend(t)
+	defer b.Close()
+
+	bin, err := ioutil.ReadFile("../../../test/token/sol/SimpleToken.bin")
+	require.NoError(t, err)
+	abiJSON, err := ioutil.ReadFile("../../../test/token/sol/SimpleToken.abi")
+	require.NoError(t, err)
+	parsed, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	require.NoError(t, err)
+
+	address, tx, token, err := bind.DeployContract(auth, parsed, common.FromHex(strings.TrimSpace(string(bin))), b)
+	require.NoError(t, err)
+	receipt := commit(t, b, tx)
+	assert.Equal(t, receipt.ContractAddress, address)
+	deployed, err := bind.WaitDeployed(context.Background(), b, tx)
+	require.NoError(t, err)
+	assert.Equal(t, address, deployed)
+
+	balanceOf := func(addr common.Address) *big.Int {
+		balance := new(big.Int)
+		require.NoError(t, token.Call(&bind.CallOpts{From: auth.From}, &balance, "balanceOf", addr))
+		return balance
+	}
+	supply := balanceOf(auth.From)
+	require.True(t, supply.Sign() > 0)
+
+	sink := make(chan types.Log, 1)
+	logs, sub, err := token.WatchLogs(nil, "Transfer", []interface{}{auth.From})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	go func() {
+		for l := range logs {
+			sink <- l
+		}
+	}()
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tx, err = token.Transact(auth, "transfer", to, big.NewInt(100))
+	require.NoError(t, err)
+	commit(t, b, tx)
+	assert.Equal(t, big.NewInt(100), balanceOf(to))
+	assert.Equal(t, new(big.Int).Sub(supply, big.NewInt(100)), balanceOf(auth.From))
+
+	select {
+	case l := <-sink:
+		var ev transferEvent
+		require.NoError(t, token.UnpackLog(&ev, "Transfer", l))
+		assert.Equal(t, transferEvent{From: auth.From, To: to, Value: big.NewInt(100)}, ev)
+		assert.Equal(t, tx.Hash(), l.TxHash)
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log of the transfer")
+	}
+
+	// the transfer of the supply by the constructor is filtered out by the recipient
+	filtered, fsub, err := token.FilterLogs(nil, "Transfer", nil, []interface{}{to})
+	require.NoError(t, err)
+	defer fsub.Unsubscribe()
+	select {
+	case l := <-filtered:
+		assert.Equal(t, tx.Hash(), l.TxHash)
+	case <-time.After(5 * time.Second):
+		t.Fatal("transfer not filtered")
+	}
+	select {
+	case l := <-filtered:
+		t.Fatalf("unexpected log of %x", l.TxHash)
+	case <-fsub.Err():
+	}
+
+	_, err = token.Transact(auth, "transfer", "0xaa", big.NewInt(100))
+	assert.Error(t, err)
+}
+
+// The coefficient system contract holds the coefficients of the chain set by
+// Init, getCoefficient returns them marshaled in a string.
+const coefficientJSON = `[
+	{"type":"function","name":"getCoefficient","constant":true,"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"updateMaxScore","inputs":[{"name":"ms","type":"uint64"}],"outputs":[{"name":"","type":"string"}]}
+]`
+
+func TestDeployWasmContract(t *testing.T) {
+	b, auth := newTestBackend(t)
+	defer b.Close()
+
+	code, err := ioutil.ReadFile("../../../contract/v1/coefficient/output.wasm")
+	require.NoError(t, err)
+	parsed, err := abi.WasmJSON(strings.NewReader(coefficientJSON))
+	require.NoError(t, err)
+
+	address, tx, contract, err := bind.DeployWasmContract(auth, parsed, code, b)
+	require.NoError(t, err)
+	receipt := commit(t, b, tx)
+	assert.Equal(t, receipt.ContractAddress, address)
+
+	var coefficient string
+	require.NoError(t, contract.Call(nil, &coefficient, "getCoefficient"))
+	var co struct {
+		VotePeriod uint64
+		MaxScore   uint64
+	}
+	require.NoError(t, json.Unmarshal([]byte(coefficient), &co), coefficient)
+	assert.Equal(t, uint64(1321), co.VotePeriod)
+	assert.Equal(t, uint64(500), co.MaxScore)
+
+	_, _, _, err = bind.DeployWasmContract(auth, parsed, []byte("not wasm"), b)
+	assert.Error(t, err)
+	_, _, _, err = bind.DeployWasmContract(auth, parsed, code, b, uint64(1))
+	assert.Error(t, err)
+}
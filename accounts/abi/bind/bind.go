@@ -0,0 +1,337 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bind generates Go bindings for contracts, from the Solidity ABI of
+// EVM contracts or the interface of tc-wasm contracts, and holds the helpers
+// the generated bindings are built on.
+package bind
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+)
+
+// Kind is the kind of the contracts to generate bindings for.
+type Kind int
+
+const (
+	// KindEVM is the kind of the EVM contracts, described by their Solidity ABI.
+	KindEVM Kind = iota
+	// KindWASM is the kind of the tc-wasm contracts, described by their interface.
+	KindWASM
+)
+
+// Bind generates a Go wrapper around a contract ABI. This wrapper isn't meant
+// to be used as is in client code, but rather as an intermediate struct which
+// enforces compile time type safety and naming convention opposed to having to
+// manually maintain hard coded strings that break on runtime.
+func Bind(types []string, abis []string, bytecodes []string, pkg string, kind Kind) (string, error) {
+	if len(abis) != len(types) || len(bytecodes) != len(types) {
+		return "", fmt.Errorf("%d types for %d abis and %d bytecodes", len(types), len(abis), len(bytecodes))
+	}
+	// Process each individual contract requested binding
+	contracts := make(map[string]*tmplContract)
+
+	for i := 0; i < len(types); i++ {
+		var (
+			contract *tmplContract
+			err      error
+		)
+		switch kind {
+		case KindEVM:
+			contract, err = bindEVM(abis[i])
+		case KindWASM:
+			contract, err = bindWasm(abis[i])
+		default:
+			err = fmt.Errorf("unsupported contract kind: %d", kind)
+		}
+		if err != nil {
+			return "", err
+		}
+		// Strip any whitespace from the JSON ABI
+		strippedABI := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, abis[i])
+
+		contract.Type = capitalise(types[i])
+		contract.InputABI = strings.Replace(strippedABI, "\"", "\\\"", -1)
+		contract.InputBin = strings.TrimSpace(bytecodes[i])
+		contract.Wasm = kind == KindWASM
+		contracts[types[i]] = contract
+	}
+	// Generate the contract template data content and render it
+	data := &tmplData{
+		Package:   pkg,
+		Contracts: contracts,
+	}
+	buffer := new(bytes.Buffer)
+
+	tmpl := template.Must(template.New("").Parse(tmplSource))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	// Pass the code through gofmt to clean it up
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buffer)
+	}
+	return string(code), nil
+}
+
+// bindEVM returns the template data of an EVM contract from its ABI.
+func bindEVM(input string) (*tmplContract, error) {
+	evmABI, err := abi.JSON(strings.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	contract := newTmplContract()
+	contract.Constructor.Inputs = bindInputs(len(evmABI.Constructor.Inputs), func(i int) (string, string) {
+		arg := evmABI.Constructor.Inputs[i]
+		return arg.Name, bindType(arg.Type.Type)
+	})
+
+	for _, original := range evmABI.Methods {
+		original := original
+		method := &tmplMethod{
+			Original:   original.Name,
+			Normalized: capitalise(original.Name),
+			ID:         fmt.Sprintf("%x", original.Id()),
+			Signature:  original.String(),
+			Inputs: bindInputs(len(original.Inputs), func(i int) (string, string) {
+				return original.Inputs[i].Name, bindType(original.Inputs[i].Type.Type)
+			}),
+		}
+		method.Outputs, method.Structured = bindOutputs(len(original.Outputs), func(i int) (string, string) {
+			return original.Outputs[i].Name, bindType(original.Outputs[i].Type.Type)
+		})
+		if original.Const {
+			contract.Calls[original.Name] = method
+		} else {
+			contract.Transacts[original.Name] = method
+		}
+	}
+	for _, original := range evmABI.Events {
+		// Skip anonymous events as they don't support explicit filtering
+		if original.Anonymous {
+			continue
+		}
+		ev := &tmplEvent{
+			Original:   original.Name,
+			Normalized: capitalise(original.Name),
+			ID:         fmt.Sprintf("%x", original.Id()),
+			Signature:  original.String(),
+		}
+		for i, input := range original.Inputs {
+			arg := tmplArg{
+				Name:       capitalise(input.Name),
+				Type:       bindType(input.Type.Type),
+				FilterType: bindType(input.Type.Type),
+				Indexed:    input.Indexed,
+			}
+			if arg.Name == "" {
+				arg.Name = fmt.Sprintf("Arg%d", i)
+			}
+			// Dynamic types are replaced by their hash in the topics
+			if input.Indexed {
+				switch input.Type.T {
+				case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+					arg.Type = "common.Hash"
+				}
+			}
+			ev.Inputs = append(ev.Inputs, arg)
+		}
+		contract.Events[original.Name] = ev
+	}
+	return contract, nil
+}
+
+// bindWasm returns the template data of a tc-wasm contract from its interface.
+func bindWasm(input string) (*tmplContract, error) {
+	wasmABI, err := abi.WasmJSON(strings.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	contract := newTmplContract()
+	contract.Constructor.Inputs = bindInputs(len(wasmABI.Constructor.Inputs), func(i int) (string, string) {
+		arg := wasmABI.Constructor.Inputs[i]
+		return arg.Name, bindType(arg.Type.Type)
+	})
+
+	for _, original := range wasmABI.Methods {
+		original := original
+		method := &tmplMethod{
+			Original:   original.Name,
+			Normalized: capitalise(original.Name),
+			Signature:  wasmSignature(original.Name, original.Inputs, original.Outputs),
+			Inputs: bindInputs(len(original.Inputs), func(i int) (string, string) {
+				return original.Inputs[i].Name, bindType(original.Inputs[i].Type.Type)
+			}),
+		}
+		method.Outputs, method.Structured = bindOutputs(len(original.Outputs), func(i int) (string, string) {
+			return original.Outputs[i].Name, bindType(original.Outputs[i].Type.Type)
+		})
+		if original.Const {
+			contract.Calls[original.Name] = method
+		} else {
+			contract.Transacts[original.Name] = method
+		}
+	}
+	for _, original := range wasmABI.Events {
+		ev := &tmplEvent{
+			Original:   original.Name,
+			Normalized: capitalise(original.Name),
+			ID:         fmt.Sprintf("%x", original.Id()),
+			Signature:  wasmSignature(original.Name, original.Inputs, nil),
+		}
+		for i, input := range original.Inputs {
+			arg := tmplArg{
+				Name: capitalise(input.Name),
+				Type: bindType(input.Type.Type),
+			}
+			if arg.Name == "" {
+				arg.Name = fmt.Sprintf("Arg%d", i)
+			}
+			ev.Inputs = append(ev.Inputs, arg)
+		}
+		contract.Events[original.Name] = ev
+	}
+	return contract, nil
+}
+
+func newTmplContract() *tmplContract {
+	return &tmplContract{
+		Calls:     make(map[string]*tmplMethod),
+		Transacts: make(map[string]*tmplMethod),
+		Events:    make(map[string]*tmplEvent),
+	}
+}
+
+// reservedNames are the identifiers the generated methods use, the arguments
+// must not shadow them.
+var reservedNames = map[string]bool{
+	"abi": true, "big": true, "bind": true, "common": true, "event": true, "json": true, "strings": true, "types": true,
+	"opts": true, "auth": true, "backend": true, "parsed": true, "address": true, "tx": true, "contract": true,
+	"err": true, "out": true, "ret": true, "logs": true, "sub": true, "sink": true,
+}
+
+// bindInputs returns the arguments of a method from the names and go types
+// returned by arg, naming the arguments that can't be used as go parameters.
+func bindInputs(n int, arg func(i int) (string, string)) []tmplArg {
+	inputs := make([]tmplArg, n)
+	for i := range inputs {
+		name, typ := arg(i)
+		if name == "" || token.Lookup(name).IsKeyword() || reservedNames[name] || strings.HasPrefix(name, "ret") {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		inputs[i] = tmplArg{Name: name, Type: typ}
+	}
+	return inputs
+}
+
+// bindOutputs returns the outputs of a method and whether they are returned
+// in a struct, which is done when they are several and all named.
+func bindOutputs(n int, arg func(i int) (string, string)) ([]tmplArg, bool) {
+	outputs := make([]tmplArg, n)
+	structured := n > 1
+	for i := range outputs {
+		name, typ := arg(i)
+		outputs[i] = tmplArg{Name: capitalise(name), Type: typ}
+		if outputs[i].Name == "" {
+			structured = false
+		}
+	}
+	return outputs, structured
+}
+
+// wasmSignature returns the readable signature of a tc-wasm method or event.
+func wasmSignature(name string, inputs, outputs abi.WasmArguments) string {
+	args := make([]string, len(inputs))
+	for i, input := range inputs {
+		args[i] = strings.TrimSpace(input.Type.String() + " " + input.Name)
+	}
+	signature := fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	if len(outputs) > 0 {
+		rets := make([]string, len(outputs))
+		for i, output := range outputs {
+			rets[i] = output.Type.String()
+		}
+		signature += fmt.Sprintf(" returns(%s)", strings.Join(rets, ", "))
+	}
+	return signature
+}
+
+// capitalise makes a camel-case string which starts with an upper case character.
+func capitalise(input string) string {
+	for len(input) > 0 && input[0] == '_' {
+		input = input[1:]
+	}
+	if len(input) == 0 {
+		return ""
+	}
+	return toCamelCase(strings.ToUpper(input[:1]) + input[1:])
+}
+
+// toCamelCase converts an under-score string to a camel-case string
+func toCamelCase(input string) string {
+	parts := strings.Split(input, "_")
+	for i, s := range parts {
+		if len(s) > 0 {
+			parts[i] = strings.ToUpper(s[:1]) + s[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// bindType returns the go type of the values of type t, as written in the bindings.
+func bindType(t reflect.Type) string {
+	switch t {
+	case reflectHash:
+		return "common.Hash"
+	case reflectAddress:
+		return "common.Address"
+	case reflectBigInt:
+		return "*big.Int"
+	case reflectRawMessage:
+		return "json.RawMessage"
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "[]" + bindElemType(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), bindElemType(t.Elem()))
+	}
+	return t.String()
+}
+
+// bindElemType returns the go type of the elements of slices and arrays, byte
+// rather than uint8.
+func bindElemType(t reflect.Type) string {
+	if t.Kind() == reflect.Uint8 {
+		return "byte"
+	}
+	return bindType(t)
+}
@@ -0,0 +1,103 @@
+package bind
+
+import (
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcvm "github.com/xunleichain/tc-wasm/vm"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+)
+
+const wasmTokenJSON = `[
+	{"type":"constructor","inputs":[{"name":"name","type":"string"},{"name":"supply","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","constant":true,"inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"info","constant":true,"outputs":[{"name":"","type":"json"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"type","type":"string"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}
+]`
+
+func TestBindEVM(t *testing.T) {
+	abiJSON, err := ioutil.ReadFile("../../../test/token/sol/SimpleToken.abi")
+	require.NoError(t, err)
+	bin, err := ioutil.ReadFile("../../../test/token/sol/SimpleToken.bin")
+	require.NoError(t, err)
+
+	code, err := Bind([]string{"simpleToken"}, []string{string(abiJSON)}, []string{string(bin)}, "token", KindEVM)
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		"package token",
+		"func DeploySimpleToken(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *SimpleToken, error) {",
+		"bind.DeployContract(auth, parsed, common.FromHex(SimpleTokenBin), backend)",
+		"func (_SimpleToken *SimpleTokenCaller) BalanceOf(opts *bind.CallOpts, _owner common.Address) (*big.Int, error) {",
+		"func (_SimpleToken *SimpleTokenCallerSession) Decimals() (uint8, error) {",
+		"func (_SimpleToken *SimpleTokenTransactor) Transfer(opts *bind.TransactOpts, _to common.Address, _value *big.Int) (*types.Transaction, error) {",
+		"func (_SimpleToken *SimpleTokenTransactor) Transfertokentest(opts *bind.TransactOpts, arg0 *big.Int) (*types.Transaction, error) {",
+		"func (_SimpleToken *SimpleTokenFilterer) FilterTransfer(opts *bind.FilterOpts, From []common.Address, To []common.Address) (*SimpleTokenTransferIterator, error) {",
+		"func (_SimpleToken *SimpleTokenFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *SimpleTokenTransfer, From []common.Address, To []common.Address) (event.Subscription, error) {",
+	} {
+		assert.Contains(t, code, want)
+	}
+	assert.NotContains(t, code, "abi.WasmJSON")
+
+	_, err = Bind([]string{"a"}, []string{"{"}, []string{""}, "token", KindEVM)
+	assert.Error(t, err)
+	_, err = Bind([]string{"a", "b"}, []string{"[]"}, []string{""}, "token", KindEVM)
+	assert.Error(t, err)
+}
+
+func TestBindWasm(t *testing.T) {
+	code, err := Bind([]string{"token"}, []string{wasmTokenJSON}, []string{"0x0061736d"}, "token", KindWASM)
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		"func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend, name string, supply *big.Int) (common.Address, *types.Transaction, *Token, error) {",
+		"bind.DeployWasmContract(auth, parsed, common.FromHex(TokenBin), backend, name, supply)",
+		"bind.NewWasmBoundContract(address, parsed, caller, transactor, filterer)",
+		"func (_Token *TokenCaller) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error) {",
+		"func (_Token *TokenCaller) Info(opts *bind.CallOpts) (json.RawMessage, error) {",
+		"// Wasm: transfer(address to, uint256 value, string type)",
+		"func (_Token *TokenTransactor) Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int, arg2 string) (*types.Transaction, error) {",
+		"func (_Token *TokenFilterer) FilterTransfer(opts *bind.FilterOpts) (*TokenTransferIterator, error) {",
+		"func (_Token *TokenFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *TokenTransfer) (event.Subscription, error) {",
+	} {
+		assert.Contains(t, code, want)
+	}
+	assert.True(t, strings.Contains(code, "\tFrom  common.Address\n"), "event struct")
+}
+
+func TestWasmDeployCode(t *testing.T) {
+	parsed, err := abi.WasmJSON(strings.NewReader(wasmTokenJSON))
+	require.NoError(t, err)
+	code := append(append([]byte{}, tcvm.WasmBytes...), 1, 0, 0, 0)
+
+	input, deployed, err := wasmABI{parsed}.deployCode(code, "token", big.NewInt(1000))
+	require.NoError(t, err)
+	assert.Equal(t, code, deployed)
+	args, parsedCode, err := tcvm.ParseInitArgsAndCode(input)
+	require.NoError(t, err)
+	assert.Equal(t, `Init|{"0":"token","1":"1000"}`, string(args))
+	assert.Equal(t, code, parsedCode)
+
+	_, _, err = wasmABI{parsed}.deployCode(code, "token")
+	assert.Error(t, err)
+	_, _, err = wasmABI{parsed}.deployCode([]byte{1, 2, 3}, "token", big.NewInt(1000))
+	assert.Error(t, err)
+}
+
+func TestCapitalise(t *testing.T) {
+	for input, want := range map[string]string{
+		"":               "",
+		"balanceOf":      "BalanceOf",
+		"_owner":         "Owner",
+		"INITIAL_SUPPLY": "INITIALSUPPLY",
+		"get_total_fee":  "GetTotalFee",
+	} {
+		assert.Equal(t, want, capitalise(input), input)
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+// tmplData is the data structure required to fill the binding template.
+type tmplData struct {
+	Package   string                   // Name of the package to place the generated file in
+	Contracts map[string]*tmplContract // List of contracts to generate into this file
+}
+
+// tmplContract contains the data needed to generate an individual contract binding.
+type tmplContract struct {
+	Type        string                 // Type name of the main contract binding
+	InputABI    string                 // JSON ABI used as the input to generate the binding from
+	InputBin    string                 // Optional code of the contract to generate deploy methods for
+	Wasm        bool                   // Whether the contract is a tc-wasm contract
+	Constructor tmplMethod             // Contract constructor for deploy parametrization
+	Calls       map[string]*tmplMethod // Contract calls that only read state data
+	Transacts   map[string]*tmplMethod // Contract calls that write state data
+	Events      map[string]*tmplEvent  // Contract events accessors
+}
+
+// tmplMethod is a wrapper around a contract method, with the go types of its
+// arguments resolved.
+type tmplMethod struct {
+	Original   string    // Name of the method in the contract
+	Normalized string    // Normalized version of the method name
+	ID         string    // Hex selector of the method, empty for tc-wasm methods
+	Signature  string    // Readable signature of the method
+	Inputs     []tmplArg // Arguments of the method
+	Outputs    []tmplArg // Return values of the method
+	Structured bool      // Whether the returns should be accumulated into a struct
+}
+
+// tmplEvent is a wrapper around a contract event, with the go types of its
+// arguments resolved.
+type tmplEvent struct {
+	Original   string    // Name of the event in the contract
+	Normalized string    // Normalized version of the event name
+	ID         string    // Hex topic of the event
+	Signature  string    // Readable signature of the event
+	Inputs     []tmplArg // Arguments of the event
+}
+
+// tmplArg is an argument of a contract method or event.
+type tmplArg struct {
+	Name       string // Normalized name of the argument
+	Type       string // Go type of the argument
+	FilterType string // Go type of the filter rules of an indexed argument
+	Indexed    bool   // Whether the argument is an indexed event topic
+}
+
+// tmplSource is the Go source template use to generate the contract binding
+// based on.
+const tmplSource = `
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+	"github.com/lianxiangcloud/linkchain/accounts/abi/bind"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/event"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = json.Marshal
+	_ = big.NewInt
+	_ = event.NewSubscription
+)
+
+{{range $contract := .Contracts}}
+	// {{.Type}}ABI is the input ABI used to generate the binding from.
+	const {{.Type}}ABI = "{{.InputABI}}"
+
+	{{if .InputBin}}
+		// {{.Type}}Bin is the compiled {{if .Wasm}}wasm code{{else}}bytecode{{end}} used for deploying new contracts.
+		const {{.Type}}Bin = ` + "`" + `{{.InputBin}}` + "`" + `
+
+		// Deploy{{.Type}} deploys a new contract, binding an instance of {{.Type}} to it.
+		func Deploy{{.Type}}(auth *bind.TransactOpts, backend bind.ContractBackend {{range .Constructor.Inputs}}, {{.Name}} {{.Type}}{{end}}) (common.Address, *types.Transaction, *{{.Type}}, error) {
+			parsed, err := abi.{{if .Wasm}}WasmJSON{{else}}JSON{{end}}(strings.NewReader({{.Type}}ABI))
+			if err != nil {
+				return common.Address{}, nil, nil, err
+			}
+			address, tx, contract, err := bind.Deploy{{if .Wasm}}Wasm{{end}}Contract(auth, parsed, common.FromHex({{.Type}}Bin), backend {{range .Constructor.Inputs}}, {{.Name}}{{end}})
+			if err != nil {
+				return common.Address{}, nil, nil, err
+			}
+			return address, tx, &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract}, {{.Type}}Filterer: {{.Type}}Filterer{contract: contract} }, nil
+		}
+	{{end}}
+
+	// {{.Type}} is an auto generated Go binding around a {{if .Wasm}}tc-wasm{{else}}Solidity{{end}} contract.
+	type {{.Type}} struct {
+		{{.Type}}Caller     // Read-only binding to the contract
+		{{.Type}}Transactor // Write-only binding to the contract
+		{{.Type}}Filterer   // Log filterer for contract events
+	}
+
+	// {{.Type}}Caller is an auto generated read-only Go binding around a contract.
+	type {{.Type}}Caller struct {
+		contract *bind.BoundContract // Generic contract wrapper for the low level calls
+	}
+
+	// {{.Type}}Transactor is an auto generated write-only Go binding around a contract.
+	type {{.Type}}Transactor struct {
+		contract *bind.BoundContract // Generic contract wrapper for the low level calls
+	}
+
+	// {{.Type}}Filterer is an auto generated log filtering Go binding around a contract events.
+	type {{.Type}}Filterer struct {
+		contract *bind.BoundContract // Generic contract wrapper for the low level calls
+	}
+
+	// {{.Type}}Session is an auto generated Go binding around a contract,
+	// with pre-set call and transact options.
+	type {{.Type}}Session struct {
+		Contract     *{{.Type}}        // Generic contract binding to set the session for
+		CallOpts     bind.CallOpts     // Call options to use throughout this session
+		TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+	}
+
+	// {{.Type}}CallerSession is an auto generated read-only Go binding around a contract,
+	// with pre-set call options.
+	type {{.Type}}CallerSession struct {
+		Contract *{{.Type}}Caller // Generic contract caller binding to set the session for
+		CallOpts bind.CallOpts    // Call options to use throughout this session
+	}
+
+	// {{.Type}}TransactorSession is an auto generated write-only Go binding around a contract,
+	// with pre-set transact options.
+	type {{.Type}}TransactorSession struct {
+		Contract     *{{.Type}}Transactor // Generic contract transactor binding to set the session for
+		TransactOpts bind.TransactOpts    // Transaction auth options to use throughout this session
+	}
+
+	// {{.Type}}Raw is an auto generated low-level Go binding around a contract.
+	type {{.Type}}Raw struct {
+		Contract *{{.Type}} // Generic contract binding to access the raw methods on
+	}
+
+	// {{.Type}}CallerRaw is an auto generated low-level read-only Go binding around a contract.
+	type {{.Type}}CallerRaw struct {
+		Contract *{{.Type}}Caller // Generic read-only contract binding to access the raw methods on
+	}
+
+	// {{.Type}}TransactorRaw is an auto generated low-level write-only Go binding around a contract.
+	type {{.Type}}TransactorRaw struct {
+		Contract *{{.Type}}Transactor // Generic write-only contract binding to access the raw methods on
+	}
+
+	// New{{.Type}} creates a new instance of {{.Type}}, bound to a specific deployed contract.
+	func New{{.Type}}(address common.Address, backend bind.ContractBackend) (*{{.Type}}, error) {
+		contract, err := bind{{.Type}}(address, backend, backend, backend)
+		if err != nil {
+			return nil, err
+		}
+		return &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract}, {{.Type}}Filterer: {{.Type}}Filterer{contract: contract} }, nil
+	}
+
+	// New{{.Type}}Caller creates a new read-only instance of {{.Type}}, bound to a specific deployed contract.
+	func New{{.Type}}Caller(address common.Address, caller bind.ContractCaller) (*{{.Type}}Caller, error) {
+		contract, err := bind{{.Type}}(address, caller, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &{{.Type}}Caller{contract: contract}, nil
+	}
+
+	// New{{.Type}}Transactor creates a new write-only instance of {{.Type}}, bound to a specific deployed contract.
+	func New{{.Type}}Transactor(address common.Address, transactor bind.ContractTransactor) (*{{.Type}}Transactor, error) {
+		contract, err := bind{{.Type}}(address, nil, transactor, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &{{.Type}}Transactor{contract: contract}, nil
+	}
+
+	// New{{.Type}}Filterer creates a new log filterer instance of {{.Type}}, bound to a specific deployed contract.
+	func New{{.Type}}Filterer(address common.Address, filterer bind.ContractFilterer) (*{{.Type}}Filterer, error) {
+		contract, err := bind{{.Type}}(address, nil, nil, filterer)
+		if err != nil {
+			return nil, err
+		}
+		return &{{.Type}}Filterer{contract: contract}, nil
+	}
+
+	// bind{{.Type}} binds a generic wrapper to an already deployed contract.
+	func bind{{.Type}}(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+		parsed, err := abi.{{if .Wasm}}WasmJSON{{else}}JSON{{end}}(strings.NewReader({{.Type}}ABI))
+		if err != nil {
+			return nil, err
+		}
+		return bind.New{{if .Wasm}}Wasm{{end}}BoundContract(address, parsed, caller, transactor, filterer), nil
+	}
+
+	// Call invokes the (constant) contract method with params as input values and
+	// sets the output to result. The result type might be a single field for simple
+	// returns, a slice of interfaces for anonymous returns and a struct for named
+	// returns.
+	func (_{{$contract.Type}} *{{$contract.Type}}Raw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+		return _{{$contract.Type}}.Contract.{{$contract.Type}}Caller.contract.Call(opts, result, method, params...)
+	}
+
+	// Transfer initiates a plain transaction to move funds to the contract, calling
+	// its default method if one is available.
+	func (_{{$contract.Type}} *{{$contract.Type}}Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return _{{$contract.Type}}.Contract.{{$contract.Type}}Transactor.contract.Transfer(opts)
+	}
+
+	// Transact invokes the (paid) contract method with params as input values.
+	func (_{{$contract.Type}} *{{$contract.Type}}Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+		return _{{$contract.Type}}.Contract.{{$contract.Type}}Transactor.contract.Transact(opts, method, params...)
+	}
+
+	// Call invokes the (constant) contract method with params as input values and
+	// sets the output to result. The result type might be a single field for simple
+	// returns, a slice of interfaces for anonymous returns and a struct for named
+	// returns.
+	func (_{{$contract.Type}} *{{$contract.Type}}CallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+		return _{{$contract.Type}}.Contract.contract.Call(opts, result, method, params...)
+	}
+
+	// Transfer initiates a plain transaction to move funds to the contract, calling
+	// its default method if one is available.
+	func (_{{$contract.Type}} *{{$contract.Type}}TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return _{{$contract.Type}}.Contract.contract.Transfer(opts)
+	}
+
+	// Transact invokes the (paid) contract method with params as input values.
+	func (_{{$contract.Type}} *{{$contract.Type}}TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+		return _{{$contract.Type}}.Contract.contract.Transact(opts, method, params...)
+	}
+
+	{{range .Calls}}
+		// {{.Normalized}} is a free data retrieval call binding the contract method {{if .ID}}0x{{.ID}}{{else}}{{.Original}}{{end}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Caller) {{.Normalized}}(opts *bind.CallOpts {{range .Inputs}}, {{.Name}} {{.Type}} {{end}}) ({{if .Structured}}struct{ {{range .Outputs}}{{.Name}} {{.Type}};{{end}} },{{else}}{{range .Outputs}}{{.Type}},{{end}}{{end}} error) {
+			{{if .Structured}}ret := new(struct{
+				{{range .Outputs}}{{.Name}} {{.Type}}
+				{{end}}
+			}){{else}}var (
+				{{range $i, $_ := .Outputs}}ret{{$i}} = new({{.Type}})
+				{{end}}
+			){{end}}
+			out := {{if .Structured}}ret{{else}}{{if eq (len .Outputs) 1}}ret0{{else}}&[]interface{}{
+				{{range $i, $_ := .Outputs}}ret{{$i}},
+				{{end}}
+			}{{end}}{{end}}
+			err := _{{$contract.Type}}.contract.Call(opts, out, "{{.Original}}" {{range .Inputs}}, {{.Name}}{{end}})
+			return {{if .Structured}}*ret,{{else}}{{range $i, $_ := .Outputs}}*ret{{$i}},{{end}}{{end}} err
+		}
+
+		// {{.Normalized}} is a free data retrieval call binding the contract method {{if .ID}}0x{{.ID}}{{else}}{{.Original}}{{end}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Session) {{.Normalized}}({{range $i, $_ := .Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{.Type}} {{end}}) ({{if .Structured}}struct{ {{range .Outputs}}{{.Name}} {{.Type}};{{end}} }, {{else}} {{range .Outputs}}{{.Type}},{{end}} {{end}} error) {
+			return _{{$contract.Type}}.Contract.{{.Normalized}}(&_{{$contract.Type}}.CallOpts {{range .Inputs}}, {{.Name}}{{end}})
+		}
+
+		// {{.Normalized}} is a free data retrieval call binding the contract method {{if .ID}}0x{{.ID}}{{else}}{{.Original}}{{end}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}CallerSession) {{.Normalized}}({{range $i, $_ := .Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{.Type}} {{end}}) ({{if .Structured}}struct{ {{range .Outputs}}{{.Name}} {{.Type}};{{end}} }, {{else}} {{range .Outputs}}{{.Type}},{{end}} {{end}} error) {
+			return _{{$contract.Type}}.Contract.{{.Normalized}}(&_{{$contract.Type}}.CallOpts {{range .Inputs}}, {{.Name}}{{end}})
+		}
+	{{end}}
+
+	{{range .Transacts}}
+		// {{.Normalized}} is a paid mutator transaction binding the contract method {{if .ID}}0x{{.ID}}{{else}}{{.Original}}{{end}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Transactor) {{.Normalized}}(opts *bind.TransactOpts {{range .Inputs}}, {{.Name}} {{.Type}} {{end}}) (*types.Transaction, error) {
+			return _{{$contract.Type}}.contract.Transact(opts, "{{.Original}}" {{range .Inputs}}, {{.Name}}{{end}})
+		}
+
+		// {{.Normalized}} is a paid mutator transaction binding the contract method {{if .ID}}0x{{.ID}}{{else}}{{.Original}}{{end}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Session) {{.Normalized}}({{range $i, $_ := .Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{.Type}} {{end}}) (*types.Transaction, error) {
+			return _{{$contract.Type}}.Contract.{{.Normalized}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Inputs}}, {{.Name}}{{end}})
+		}
+
+		// {{.Normalized}} is a paid mutator transaction binding the contract method {{if .ID}}0x{{.ID}}{{else}}{{.Original}}{{end}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}TransactorSession) {{.Normalized}}({{range $i, $_ := .Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{.Type}} {{end}}) (*types.Transaction, error) {
+			return _{{$contract.Type}}.Contract.{{.Normalized}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Inputs}}, {{.Name}}{{end}})
+		}
+	{{end}}
+
+	{{range .Events}}
+		// {{$contract.Type}}{{.Normalized}}Iterator is returned from Filter{{.Normalized}} and is used to iterate over the raw logs and unpacked data for {{.Normalized}} events raised by the {{$contract.Type}} contract.
+		type {{$contract.Type}}{{.Normalized}}Iterator struct {
+			Event *{{$contract.Type}}{{.Normalized}} // Event containing the contract specifics and raw log
+
+			contract *bind.BoundContract // Generic contract to use for unpacking event data
+			event    string              // Event name to use for unpacking event data
+
+			logs chan types.Log     // Log channel receiving the found contract events
+			sub  event.Subscription // Subscription for errors, completion and termination
+			done bool               // Whether the subscription completed delivering logs
+			fail error              // Occurred error to stop iteration
+		}
+
+		// Next advances the iterator to the subsequent event, returning whether there
+		// are any more events found. In case of a retrieval or parsing error, false is
+		// returned and Error() can be queried for the exact failure.
+		func (it *{{$contract.Type}}{{.Normalized}}Iterator) Next() bool {
+			// If the iterator failed, stop iterating
+			if it.fail != nil {
+				return false
+			}
+			// If the iterator completed, deliver directly whatever's available
+			if it.done {
+				select {
+				case log := <-it.logs:
+					it.Event = new({{$contract.Type}}{{.Normalized}})
+					if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+						it.fail = err
+						return false
+					}
+					it.Event.Raw = log
+					return true
+
+				default:
+					return false
+				}
+			}
+			// Iterator still in progress, wait for either a data or an error event
+			select {
+			case log := <-it.logs:
+				it.Event = new({{$contract.Type}}{{.Normalized}})
+				if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+					it.fail = err
+					return false
+				}
+				it.Event.Raw = log
+				return true
+
+			case err := <-it.sub.Err():
+				it.done = true
+				it.fail = err
+				return it.Next()
+			}
+		}
+
+		// Error returns any retrieval or parsing error occurred during filtering.
+		func (it *{{$contract.Type}}{{.Normalized}}Iterator) Error() error {
+			return it.fail
+		}
+
+		// Close terminates the iteration process, releasing any pending underlying
+		// resources.
+		func (it *{{$contract.Type}}{{.Normalized}}Iterator) Close() error {
+			it.sub.Unsubscribe()
+			return nil
+		}
+
+		// {{$contract.Type}}{{.Normalized}} represents a {{.Normalized}} event raised by the {{$contract.Type}} contract.
+		type {{$contract.Type}}{{.Normalized}} struct { {{range .Inputs}}
+			{{.Name}} {{.Type}}; {{end}}
+			Raw types.Log // Blockchain specific contextual infos
+		}
+
+		// Filter{{.Normalized}} is a free log retrieval operation binding the contract event 0x{{.ID}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Filter{{.Normalized}}(opts *bind.FilterOpts{{range .Inputs}}{{if .Indexed}}, {{.Name}} []{{.FilterType}}{{end}}{{end}}) (*{{$contract.Type}}{{.Normalized}}Iterator, error) {
+			{{range .Inputs}}
+			{{if .Indexed}}var {{.Name}}Rule []interface{}
+			for _, {{.Name}}Item := range {{.Name}} {
+				{{.Name}}Rule = append({{.Name}}Rule, {{.Name}}Item)
+			}{{end}}{{end}}
+
+			logs, sub, err := _{{$contract.Type}}.contract.FilterLogs(opts, "{{.Original}}"{{range .Inputs}}{{if .Indexed}}, {{.Name}}Rule{{end}}{{end}})
+			if err != nil {
+				return nil, err
+			}
+			return &{{$contract.Type}}{{.Normalized}}Iterator{contract: _{{$contract.Type}}.contract, event: "{{.Original}}", logs: logs, sub: sub}, nil
+		}
+
+		// Watch{{.Normalized}} is a free log subscription operation binding the contract event 0x{{.ID}}.
+		//
+		// {{if $contract.Wasm}}Wasm{{else}}Solidity{{end}}: {{.Signature}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Watch{{.Normalized}}(opts *bind.WatchOpts, sink chan<- *{{$contract.Type}}{{.Normalized}}{{range .Inputs}}{{if .Indexed}}, {{.Name}} []{{.FilterType}}{{end}}{{end}}) (event.Subscription, error) {
+			{{range .Inputs}}
+			{{if .Indexed}}var {{.Name}}Rule []interface{}
+			for _, {{.Name}}Item := range {{.Name}} {
+				{{.Name}}Rule = append({{.Name}}Rule, {{.Name}}Item)
+			}{{end}}{{end}}
+
+			logs, sub, err := _{{$contract.Type}}.contract.WatchLogs(opts, "{{.Original}}"{{range .Inputs}}{{if .Indexed}}, {{.Name}}Rule{{end}}{{end}})
+			if err != nil {
+				return nil, err
+			}
+			return event.NewSubscription(func(quit <-chan struct{}) error {
+				defer sub.Unsubscribe()
+				for {
+					select {
+					case log := <-logs:
+						// New log arrived, parse the event and forward to the user
+						event := new({{$contract.Type}}{{.Normalized}})
+						if err := _{{$contract.Type}}.contract.UnpackLog(event, "{{.Original}}", log); err != nil {
+							return err
+						}
+						event.Raw = log
+
+						select {
+						case sink <- event:
+						case err := <-sub.Err():
+							return err
+						case <-quit:
+							return nil
+						}
+					case err := <-sub.Err():
+						return err
+					case <-quit:
+						return nil
+					}
+				}
+			}), nil
+		}
+	{{end}}
+{{end}}
+`
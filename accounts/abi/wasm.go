@@ -0,0 +1,416 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+)
+
+// WasmABI holds the interface of a tc-wasm contract. It is described with the
+// JSON layout of the Solidity ABI, but the contract is called with an input of
+// "method|args", args being a JSON object keyed by the positions of the
+// arguments ({"0":...,"1":...}), and a method returns at most one value, as a
+// string or wrapped in {"ret":...}.
+//
+// The types of the arguments are bool, string, address, hash, bytes, the
+// integers int8 to int64 and uint8 to uint64, int256 and uint256 which are
+// carried as decimal strings, and json for any other JSON value.
+type WasmABI struct {
+	Constructor WasmMethod
+	Methods     map[string]WasmMethod
+	Events      map[string]WasmEvent
+}
+
+// WasmJSON returns a parsed tc-wasm interface and error if it failed.
+func WasmJSON(reader io.Reader) (WasmABI, error) {
+	dec := json.NewDecoder(reader)
+
+	var abi WasmABI
+	if err := dec.Decode(&abi); err != nil {
+		return WasmABI{}, err
+	}
+	return abi, nil
+}
+
+// Pack the given method name and arguments to the input of a tc-wasm call.
+// The empty name packs the arguments of the constructor.
+func (abi WasmABI) Pack(name string, args ...interface{}) ([]byte, error) {
+	if name == "" {
+		return abi.Constructor.Inputs.Pack(args...)
+	}
+	method, exist := abi.Methods[name]
+	if !exist {
+		return nil, fmt.Errorf("method '%s' not found", name)
+	}
+	arguments, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(name+"|"), arguments...), nil
+}
+
+// Unpack the result of a method or the data of an event in v.
+// The result of a method is unpacked in a pointer to the go type of its
+// output, the data of an event in a pointer to a struct with a field for
+// each of its inputs.
+func (abi WasmABI) Unpack(v interface{}, name string, output []byte) error {
+	if method, ok := abi.Methods[name]; ok {
+		if len(method.Outputs) == 0 {
+			return nil
+		}
+		return method.Outputs[0].Type.unpack(v, unwrapWasmResult(output))
+	} else if event, ok := abi.Events[name]; ok {
+		return event.Inputs.Unpack(v, output)
+	}
+	return fmt.Errorf("abi: could not locate named method or event")
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (abi *WasmABI) UnmarshalJSON(data []byte) error {
+	var fields []struct {
+		Type     string
+		Name     string
+		Constant bool
+		Inputs   WasmArguments
+		Outputs  WasmArguments
+	}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	abi.Methods = make(map[string]WasmMethod)
+	abi.Events = make(map[string]WasmEvent)
+	for _, field := range fields {
+		switch field.Type {
+		case "constructor":
+			abi.Constructor = WasmMethod{
+				Inputs: field.Inputs,
+			}
+		case "function", "":
+			if len(field.Outputs) > 1 {
+				return fmt.Errorf("wasm method '%s' returns %d values, at most one is supported", field.Name, len(field.Outputs))
+			}
+			abi.Methods[field.Name] = WasmMethod{
+				Name:    field.Name,
+				Const:   field.Constant,
+				Inputs:  field.Inputs,
+				Outputs: field.Outputs,
+			}
+		case "event":
+			abi.Events[field.Name] = WasmEvent{
+				Name:   field.Name,
+				Inputs: field.Inputs,
+			}
+		}
+	}
+	return nil
+}
+
+// WasmMethod is a method of a tc-wasm contract.
+type WasmMethod struct {
+	Name    string
+	Const   bool
+	Inputs  WasmArguments
+	Outputs WasmArguments
+}
+
+// WasmEvent is an event emitted by TC_Notify. Its single topic is the hash of
+// its name and its data is the value of its input, or the JSON array or object
+// of the values of its inputs when it has several.
+type WasmEvent struct {
+	Name   string
+	Inputs WasmArguments
+}
+
+// Id returns the topic of the event, the hash of its name.
+func (e WasmEvent) Id() common.Hash {
+	return crypto.Keccak256Hash([]byte(e.Name))
+}
+
+// WasmArgument holds the name of an argument of a tc-wasm method or event and its type.
+type WasmArgument struct {
+	Name string
+	Type WasmType
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (argument *WasmArgument) UnmarshalJSON(data []byte) error {
+	var extarg struct {
+		Name string
+		Type string
+	}
+	if err := json.Unmarshal(data, &extarg); err != nil {
+		return fmt.Errorf("argument json err: %v", err)
+	}
+
+	typ, err := NewWasmType(extarg.Type)
+	if err != nil {
+		return err
+	}
+	argument.Name = extarg.Name
+	argument.Type = typ
+	return nil
+}
+
+type WasmArguments []WasmArgument
+
+// Pack encodes the arguments to the JSON object keyed by their positions.
+func (arguments WasmArguments) Pack(args ...interface{}) ([]byte, error) {
+	if len(args) != len(arguments) {
+		return nil, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(arguments))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, arg := range arguments {
+		value, err := arg.Type.encode(args[i])
+		if err != nil {
+			return nil, fmt.Errorf("abi: argument %d: %v", i, err)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(strconv.Quote(strconv.Itoa(i)))
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Unpack decodes the data of an event in v, a pointer to a struct with a field
+// named after each argument.
+func (arguments WasmArguments) Unpack(v interface{}, data []byte) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("abi: Unpack(non-struct pointer %T)", v)
+	}
+	values, err := arguments.split(data)
+	if err != nil {
+		return err
+	}
+	for i, arg := range arguments {
+		name := capitalise(arg.Name)
+		field := value.Elem().FieldByName(name)
+		if !field.IsValid() {
+			return fmt.Errorf("abi: field %s can't be found in the given value", name)
+		}
+		if err := arg.Type.unpack(field.Addr().Interface(), values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// split returns the encoded value of each argument in data.
+func (arguments WasmArguments) split(data []byte) ([][]byte, error) {
+	switch len(arguments) {
+	case 0:
+		return nil, nil
+	case 1:
+		return [][]byte{data}, nil
+	}
+
+	values := make([][]byte, len(arguments))
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		if len(list) != len(arguments) {
+			return nil, fmt.Errorf("abi: wrong length, expected %d values, got %d", len(arguments), len(list))
+		}
+		for i := range list {
+			values[i] = list[i]
+		}
+		return values, nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("abi: improperly formatted data: %v", err)
+	}
+	for i, arg := range arguments {
+		value, ok := object[arg.Name]
+		if !ok {
+			if value, ok = object[strconv.Itoa(i)]; !ok {
+				return nil, fmt.Errorf("abi: no value for argument %s", arg.Name)
+			}
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// unwrapWasmResult returns the value of a result wrapped in {"ret":...}
+func unwrapWasmResult(output []byte) []byte {
+	var wrapped map[string]json.RawMessage
+	if err := json.Unmarshal(output, &wrapped); err == nil && len(wrapped) == 1 {
+		if ret, ok := wrapped["ret"]; ok {
+			return ret
+		}
+	}
+	return output
+}
+
+// WasmType is the type of a tc-wasm argument.
+type WasmType struct {
+	Kind string       // canonical name of the type
+	Type reflect.Type // go type of the values
+}
+
+var (
+	jsonT = reflect.TypeOf(json.RawMessage{})
+
+	wasmTypes = map[string]reflect.Type{
+		"bool":    reflect.TypeOf(false),
+		"string":  reflect.TypeOf(""),
+		"address": addressT,
+		"hash":    reflect.TypeOf(common.Hash{}),
+		"bytes":   reflect.TypeOf([]byte{}),
+		"int8":    int8T,
+		"int16":   int16T,
+		"int32":   int32T,
+		"int64":   int64T,
+		"uint8":   uint8T,
+		"uint16":  uint16T,
+		"uint32":  uint32T,
+		"uint64":  uint64T,
+		"int256":  bigT,
+		"uint256": bigT,
+		"json":    jsonT,
+	}
+	wasmTypeAliases = map[string]string{
+		"int":     "int256",
+		"uint":    "uint256",
+		"bytes32": "hash",
+	}
+)
+
+// NewWasmType returns the tc-wasm type named t.
+func NewWasmType(t string) (WasmType, error) {
+	if alias, ok := wasmTypeAliases[t]; ok {
+		t = alias
+	}
+	typ, ok := wasmTypes[t]
+	if !ok {
+		return WasmType{}, fmt.Errorf("unsupported wasm arg type: %s", t)
+	}
+	return WasmType{Kind: t, Type: typ}, nil
+}
+
+func (t WasmType) String() string {
+	return t.Kind
+}
+
+// encode returns the JSON value of v.
+func (t WasmType) encode(v interface{}) ([]byte, error) {
+	if reflect.TypeOf(v) != t.Type {
+		return nil, fmt.Errorf("cannot use %T as type %v", v, t.Type)
+	}
+	switch value := v.(type) {
+	case *big.Int:
+		if value == nil {
+			return nil, fmt.Errorf("nil %s", t.Kind)
+		}
+		if t.Kind == "uint256" && value.Sign() < 0 {
+			return nil, fmt.Errorf("negative %s", t.Kind)
+		}
+		return json.Marshal(value.String())
+	case []byte:
+		return json.Marshal(hexutil.Encode(value))
+	case json.RawMessage:
+		if !json.Valid(value) {
+			return nil, fmt.Errorf("invalid json value")
+		}
+		return value, nil
+	}
+	return json.Marshal(v)
+}
+
+// unpack decodes the JSON or plain text value data in v, a pointer to the go type.
+func (t WasmType) unpack(v interface{}, data []byte) error {
+	dst := reflect.ValueOf(v)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("abi: Unpack(non-pointer %T)", v)
+	}
+	if dst.Elem().Type() != t.Type {
+		return fmt.Errorf("abi: cannot unmarshal %s in to %v", t.Kind, dst.Elem().Type())
+	}
+	value, err := t.decode(data)
+	if err != nil {
+		return err
+	}
+	dst.Elem().Set(value)
+	return nil
+}
+
+func (t WasmType) decode(data []byte) (reflect.Value, error) {
+	if t.Type == jsonT {
+		if !json.Valid(data) {
+			return reflect.Value{}, fmt.Errorf("abi: invalid json value")
+		}
+		return reflect.ValueOf(json.RawMessage(common.CopyBytes(data))), nil
+	}
+
+	text := string(data)
+	var unquoted string
+	if err := json.Unmarshal(data, &unquoted); err == nil {
+		text = unquoted
+	}
+
+	switch t.Type.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(text), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("abi: cannot unmarshal %q in to bool", text)
+		}
+		return reflect.ValueOf(b), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, t.Type.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("abi: cannot unmarshal %q in to %s", text, t.Kind)
+		}
+		return reflect.ValueOf(n).Convert(t.Type), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, t.Type.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("abi: cannot unmarshal %q in to %s", text, t.Kind)
+		}
+		return reflect.ValueOf(n).Convert(t.Type), nil
+	}
+
+	switch t.Kind {
+	case "int256", "uint256":
+		n, ok := new(big.Int).SetString(text, 0)
+		if !ok || (t.Kind == "uint256" && n.Sign() < 0) {
+			return reflect.Value{}, fmt.Errorf("abi: cannot unmarshal %q in to %s", text, t.Kind)
+		}
+		return reflect.ValueOf(n), nil
+	case "address":
+		if !common.IsHexAddress(text) {
+			return reflect.Value{}, fmt.Errorf("abi: cannot unmarshal %q in to address", text)
+		}
+		return reflect.ValueOf(common.HexToAddress(text)), nil
+	case "hash":
+		b, err := hexutil.Decode(text)
+		if err != nil || len(b) != common.HashLength {
+			return reflect.Value{}, fmt.Errorf("abi: cannot unmarshal %q in to hash", text)
+		}
+		return reflect.ValueOf(common.BytesToHash(b)), nil
+	case "bytes":
+		b, err := hexutil.Decode(text)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("abi: cannot unmarshal %q in to bytes", text)
+		}
+		return reflect.ValueOf(b), nil
+	}
+	return reflect.Value{}, fmt.Errorf("abi: unsupported wasm arg type: %s", t.Kind)
+}
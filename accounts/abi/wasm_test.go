@@ -0,0 +1,106 @@
+package abi
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+)
+
+const wasmTokenJSON = `[
+	{"type":"constructor","inputs":[{"name":"name","type":"string"},{"name":"supply","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","constant":true,"inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"memo","type":"bytes"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"setInfo","inputs":[{"name":"info","type":"json"},{"name":"version","type":"int32"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}]},
+	{"type":"event","name":"Paused","inputs":[{"name":"paused","type":"bool"}]}
+]`
+
+func TestWasmJSON(t *testing.T) {
+	abi, err := WasmJSON(strings.NewReader(wasmTokenJSON))
+	require.NoError(t, err)
+
+	assert.Len(t, abi.Constructor.Inputs, 2)
+	assert.Len(t, abi.Methods, 3)
+	assert.True(t, abi.Methods["balanceOf"].Const)
+	assert.Equal(t, "uint256", abi.Methods["balanceOf"].Outputs[0].Type.String())
+	assert.Equal(t, crypto.Keccak256Hash([]byte("Transfer")), abi.Events["Transfer"].Id())
+
+	_, err = WasmJSON(strings.NewReader(`[{"type":"function","name":"f","inputs":[{"name":"a","type":"uint128"}]}]`))
+	assert.Error(t, err)
+	_, err = WasmJSON(strings.NewReader(`[{"type":"function","name":"f","outputs":[{"name":"a","type":"bool"},{"name":"b","type":"bool"}]}]`))
+	assert.Error(t, err)
+}
+
+func TestWasmPack(t *testing.T) {
+	abi, err := WasmJSON(strings.NewReader(wasmTokenJSON))
+	require.NoError(t, err)
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	input, err := abi.Pack("transfer", to, big.NewInt(100), []byte{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, `transfer|{"0":"0x00000000000000000000000000000000000000aa","1":"100","2":"0x0102"}`, string(input))
+
+	input, err = abi.Pack("setInfo", json.RawMessage(`{"a":1}`), int32(-3))
+	require.NoError(t, err)
+	assert.Equal(t, `setInfo|{"0":{"a":1},"1":-3}`, string(input))
+
+	input, err = abi.Pack("", "token", big.NewInt(1000))
+	require.NoError(t, err)
+	assert.Equal(t, `{"0":"token","1":"1000"}`, string(input))
+
+	_, err = abi.Pack("balanceOf", "0xaa")
+	assert.Error(t, err)
+	_, err = abi.Pack("balanceOf")
+	assert.Error(t, err)
+	_, err = abi.Pack("transferFrom", to)
+	assert.Error(t, err)
+	_, err = abi.Pack("setInfo", json.RawMessage(`{`), int32(0))
+	assert.Error(t, err)
+}
+
+func TestWasmUnpack(t *testing.T) {
+	abi, err := WasmJSON(strings.NewReader(wasmTokenJSON))
+	require.NoError(t, err)
+
+	for _, output := range []string{`1000`, `"1000"`, `{"ret":"1000"}`, `{"ret":1000}`} {
+		balance := new(*big.Int)
+		require.NoError(t, abi.Unpack(balance, "balanceOf", []byte(output)), output)
+		assert.Equal(t, big.NewInt(1000), *balance, output)
+	}
+	ok := new(bool)
+	require.NoError(t, abi.Unpack(ok, "transfer", []byte("true")))
+	assert.True(t, *ok)
+	assert.Error(t, abi.Unpack(new(string), "balanceOf", []byte("1")))
+	assert.Error(t, abi.Unpack(new(*big.Int), "balanceOf", []byte("x")))
+	assert.NoError(t, abi.Unpack(nil, "setInfo", nil))
+
+	var transfer struct {
+		From  common.Address
+		To    common.Address
+		Value *big.Int
+	}
+	from := common.HexToAddress("0x01")
+	to := common.HexToAddress("0x02")
+	for _, data := range []string{
+		`["0x0000000000000000000000000000000000000001","0x0000000000000000000000000000000000000002","7"]`,
+		`{"from":"0x0000000000000000000000000000000000000001","to":"0x0000000000000000000000000000000000000002","value":7}`,
+	} {
+		require.NoError(t, abi.Unpack(&transfer, "Transfer", []byte(data)), data)
+		assert.Equal(t, from, transfer.From)
+		assert.Equal(t, to, transfer.To)
+		assert.Equal(t, big.NewInt(7), transfer.Value)
+	}
+	assert.Error(t, abi.Unpack(&transfer, "Transfer", []byte(`["0x01"]`)))
+
+	var paused struct{ Paused bool }
+	require.NoError(t, abi.Unpack(&paused, "Paused", []byte("true")))
+	assert.True(t, paused.Paused)
+	assert.Error(t, abi.Unpack(&struct{ Other bool }{}, "Paused", []byte("true")))
+}
@@ -92,6 +92,12 @@ type LinkApplication struct {
 	processMap          map[common.Hash]*ProcessResult
 	poceedHandle        PoceedHandle
 	awardHandle         AwardHandle
+
+	isTrie      bool
+	stateDB     dbm.DB
+	statePruner *statePruner
+
+	forks types.Forks
 }
 
 func NewLinkApplication(db dbm.DB, bc *blockchain.BlockStore, utxoStore *utxo.UtxoStore,
@@ -127,16 +133,111 @@ func NewLinkApplication(db dbm.DB, bc *blockchain.BlockStore, utxoStore *utxo.Ut
 		processMap:    make(map[common.Hash]*ProcessResult, 4),
 		poceedHandle:  poceedHandle,
 		awardHandle:   awardHandle,
+		isTrie:        isTrie,
+		stateDB:       db,
 	}
 	app.processor = NewStateProcessor(bc, app)
 	app.lastCoe = GetCoefficient(app.storeState, app.logger)
 
+	// once pruned, the state roots must stay reference counted
+	if isTrie && hasStatePruner(db) {
+		if err := app.SetStatePruning(0); err != nil {
+			return nil, err
+		}
+	}
+
+	// the stores saved before the max output seqs were kept per block
+	if err := utxoStore.IndexMaxUtxoOutputSeqs(bc.Height()); err != nil {
+		return nil, err
+	}
+
 	// Init UtxoChangeRate Getter
 	types.RegisterUTXORateGetter(types.NewUTXOChangeRateGetter(app.GetUTXOChangeRate))
 
 	return app, nil
 }
 
+// Reload reopens the state at the latest block of the block store,
+// it is called once state sync has restored the stores from a snapshot.
+func (app *LinkApplication) Reload() error {
+	bc := app.blockChain
+	currentBlock := bc.LoadBlock(bc.Height())
+	if currentBlock == nil {
+		return types.ErrUnknownBlock
+	}
+
+	txsResult, err := bc.LoadTxsResult(bc.Height())
+	if err != nil {
+		return err
+	}
+
+	storeState, err := state.New(txsResult.TrieRoot, app.storeState.Database())
+	if err != nil {
+		return err
+	}
+
+	app.LockState()
+	app.currentBlock = currentBlock
+	app.storeState = storeState
+	app.checkTxState = storeState.Copy()
+	app.lastTxsResult = *txsResult
+	app.lastCoe = GetCoefficient(storeState, app.logger)
+	app.UnlockState()
+
+	app.logger.Info("Reload: done", "height", currentBlock.Height, "trieRoot", txsResult.TrieRoot)
+	return nil
+}
+
+// SetStatePruning keeps the state of the latest keep blocks only, the tries of
+// the older state roots are deleted as blocks are committed. With keep 0 the
+// state roots are reference counted but never pruned.
+func (app *LinkApplication) SetStatePruning(keep uint64) error {
+	trieDB, ok := app.storeState.Database().TrieDB().(state.PrunableTrieDB)
+	if !ok {
+		return fmt.Errorf("state pruning needs a full node")
+	}
+	if app.statePruner == nil {
+		trieDB.EnablePruning()
+		app.statePruner = newStatePruner(app.stateDB, trieDB, app.logger)
+	}
+	app.statePruner.setKeep(keep)
+	return nil
+}
+
+// SetParallelExec executes the transactions of the blocks optimistically in
+// parallel on workers goroutines.
+func (app *LinkApplication) SetParallelExec(workers int) {
+	if p, ok := app.processor.(*StateProcessor); ok {
+		p.SetParallel(workers)
+	}
+}
+
+// SetForks sets the protocol upgrades scheduled in the consensus params of
+// the chain. It must be called before the first block is processed, it fails
+// if the stateroot fork is scheduled and the application keeps no state trie.
+func (app *LinkApplication) SetForks(forks types.Forks) error {
+	if _, ok := forks.Height(types.ForkStateRoot); ok && !app.isTrie {
+		return fmt.Errorf("The %q fork requires the state trie", types.ForkStateRoot)
+	}
+	app.forks = forks
+	return nil
+}
+
+// CheckStateRetained returns a *StatePrunedError if the state of the block at
+// height is not retained by the node.
+func (app *LinkApplication) CheckStateRetained(height uint64) error {
+	if !app.isTrie {
+		if latest := app.Height(); height < latest {
+			return &StatePrunedError{Height: height, Oldest: latest}
+		}
+		return nil
+	}
+	if app.statePruner != nil {
+		return app.statePruner.check(height)
+	}
+	return nil
+}
+
 func (app *LinkApplication) GetLastChangedVals() (height uint64, vals []*types.Validator) {
 	app.LockState()
 	defer app.UnlockState()
@@ -151,6 +252,9 @@ func (app *LinkApplication) SetLastChangedVals(height uint64, vals []*types.Vali
 
 func (app *LinkApplication) SetLogger(l log.Logger) {
 	app.logger = l
+	if app.statePruner != nil {
+		app.statePruner.logger = l
+	}
 }
 
 func (app *LinkApplication) SetMempool(mempool types.Mempool) {
@@ -205,6 +309,7 @@ func (app *LinkApplication) GetUTXOChangeRate(addr common.Address) (int64, error
 	var rateUint8 uint8
 	if !wasm.IsWasmContract(msgcode) {
 		contextEvm := evm.NewEVMContext(header, app.blockChain, nil, config.EvmGasRate)
+		contextEvm.Forks = app.forks
 		EVM := evm.NewEVM(contextEvm, tmpState, app.vmConfig)
 		evmData := types.UTXOChangeRateDataEVM()
 		ret, _, _, err := EVM.Call(evm.AccountRef(common.EmptyAddress), addr, common.EmptyAddress, evmData, staticCallSimulateGas, big.NewInt(0))
@@ -217,6 +322,7 @@ func (app *LinkApplication) GetUTXOChangeRate(addr common.Address) (int64, error
 		}
 	} else {
 		contextWasm := wasm.NewWASMContext(header, app.blockChain, nil, config.WasmGasRate)
+		contextWasm.Forks = app.forks
 		WASM := wasm.NewWASM(contextWasm, tmpState, app.vmConfig)
 		wasmData := types.UTXOChangeRateDataWASM()
 		ret, _, _, err := WASM.Call(evm.AccountRef(common.EmptyAddress), addr, common.EmptyAddress, wasmData, staticCallSimulateGas, big.NewInt(0))
@@ -267,6 +373,26 @@ func (app *LinkApplication) CreateBlock(height uint64, maxTxs int, gasLimit uint
 	return block
 }
 
+// blockGasFee returns the fees paid by the transactions of block, the gas
+// price of each transaction applies to the gas used in its receipt.
+func blockGasFee(block *types.Block, receipts types.Receipts) *big.Int {
+	prices := make(map[common.Hash]*big.Int, len(block.Data.Txs))
+	for _, tx := range block.Data.Txs {
+		if rtx, ok := tx.(types.RegularTx); ok {
+			prices[tx.Hash()] = rtx.GasPrice()
+		}
+	}
+	fee := new(big.Int)
+	for _, receipt := range receipts {
+		price, ok := prices[receipt.TxHash]
+		if !ok {
+			price = big.NewInt(types.ParGasPrice)
+		}
+		fee.Add(fee, new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), price))
+	}
+	return fee
+}
+
 func (app *LinkApplication) PreRunBlock(block *types.Block) {
 	app.logger.Info("PreRunBlock: begin", "height", block.Height, "NumTxs", block.NumTxs)
 	processResult := ProcessResult{
@@ -473,10 +599,11 @@ func (app *LinkApplication) processBlock(block *types.Block, processResult *Proc
 	}
 
 	contextWasm := wasm.NewWASMContext(types.CopyHeader(block.Header), app.blockChain, nil, config.WasmGasRate)
+	contextWasm.Forks = app.forks
 	wasm := wasm.NewWASM(contextWasm, processResult.tmpState, evm.Config{EnablePreimageRecording: false})
 
 	if gasUsed > 0 && app.poceedHandle != nil {
-		totalGasFee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), new(big.Int).SetInt64(types.ParGasPrice))
+		totalGasFee := blockGasFee(block, receipts)
 		app.logger.Info("processHandle", "foundation_addr", config.ContractFoundationAddr.String(), "totalGasFee", totalGasFee.String())
 		processResult.tmpState.AddBalance(config.ContractFoundationAddr, totalGasFee)
 		if err := app.poceedHandle(wasm, block.Coinbase(), totalGasFee, app.logger); err != nil {
@@ -502,6 +629,10 @@ func (app *LinkApplication) processBlock(block *types.Block, processResult *Proc
 	app.processBlockEvidence(block.Evidence.Evidence, processResult)
 
 	processResult.txsResult.StateHash = processResult.tmpState.IntermediateRoot(false)
+	if app.forks.IsActive(types.ForkStateRoot, block.Height) {
+		// the changes of the block are committed by the state root of the next header
+		processResult.txsResult.StateHash = app.lastTxsResult.TrieRoot
+	}
 	processResult.txsResult.LogsBloom = types.CreateBloom(receipts)
 	processResult.txsResult.SetSpecialTxs(specialTxs)
 	processResult.txsResult.SetUTXOOutputs(utxoOutputs)
@@ -599,6 +730,11 @@ func (app *LinkApplication) CommitBlock(block *types.Block, blockParts *types.Pa
 	}
 
 	processResult.tmpState.Database().TrieDB().Commit(trieRoot, false)
+	if app.statePruner != nil {
+		if err := app.statePruner.commit(block.Height, trieRoot); err != nil {
+			app.logger.Error("CommitBlock: state pruning failed", "height", block.Height, "err", err)
+		}
+	}
 	processResult.tmpState.Reset(trieRoot)
 	processResult.txsResult.TrieRoot = trieRoot
 	types.BlockBalanceRecordsInstance.SetBlockHash(block.Hash())
@@ -633,6 +769,7 @@ func (app *LinkApplication) CommitBlock(block *types.Block, blockParts *types.Pa
 
 	app.LockState()
 	app.checkTxState = processResult.tmpState.Copy()
+	app.storeState = processResult.tmpState
 	app.mempool.KeyImageReset()
 	app.lastCoe = GetCoefficient(processResult.tmpState, app.logger)
 	app.logger.Info("GetCoefficient ", "Coefficient", app.lastCoe)
@@ -645,8 +782,6 @@ func (app *LinkApplication) CommitBlock(block *types.Block, blockParts *types.Pa
 		app.logger.Warn("CommitBlock: update mempool failed", "err", err)
 	}
 
-	app.storeState = processResult.tmpState
-
 	block.Header.SetBloom(processResult.txsResult.LogsBloom)
 	app.currentBlock = block
 	app.lastTxsResult = processResult.txsResult
@@ -706,6 +841,21 @@ func (app *LinkApplication) Height() uint64 {
 	return app.blockChain.Height()
 }
 
+// ForkSchedule returns the protocol upgrades scheduled in the genesis.
+func (app *LinkApplication) ForkSchedule() types.Forks {
+	return app.forks
+}
+
+// IsForkActive reports whether the named protocol upgrade applies to the block at height.
+func (app *LinkApplication) IsForkActive(name string, height uint64) bool {
+	return app.forks.IsActive(name, height)
+}
+
+// ForkRules returns the rules of the next block, the transactions are checked with.
+func (app *LinkApplication) ForkRules() types.Rules {
+	return app.forks.Rules(app.Height() + 1)
+}
+
 func (app *LinkApplication) LoadBlockMeta(height uint64) *types.BlockMeta {
 	return app.blockChain.LoadBlockMeta(height)
 }
@@ -795,6 +945,53 @@ func (app *LinkApplication) GetLatestStateDB() *state.StateDB {
 	return app.storeState.Copy()
 }
 
+// ResetCheckState sets the account of addr in the check state back to the
+// committed state, the mempool checks the transactions of addr again after
+// dropping or replacing one of them.
+func (app *LinkApplication) ResetCheckState(addr common.Address) {
+	app.LockState()
+	defer app.UnlockState()
+	app.checkTxState.SetNonce(addr, app.storeState.GetNonce(addr))
+	app.checkTxState.SetBalance(addr, app.storeState.GetBalance(addr))
+	tokens := append(app.checkTxState.GetTokenBalances(addr), app.storeState.GetTokenBalances(addr)...)
+	for _, token := range tokens {
+		if token.TokenAddr != common.EmptyAddress {
+			app.checkTxState.SetTokenBalance(addr, token.TokenAddr, app.storeState.GetTokenBalance(addr, token.TokenAddr))
+		}
+	}
+}
+
+// NewBlockCheck returns a check of the transactions of a new block, in the
+// order they are included, against a copy of the committed state. The nonce
+// and the balance of the senders are checked like CheckTx does, so that the
+// mempool leaves out what would fail the processing of the block.
+func (app *LinkApplication) NewBlockCheck() func(tx types.Tx) error {
+	app.LockState()
+	censor := &blockCensor{LinkApplication: app, state: app.storeState.Copy()}
+	app.UnlockState()
+	return func(tx types.Tx) error {
+		if utxoTx, ok := tx.(*types.UTXOTransaction); ok && utxoTx.UTXOKind()&types.Ain != types.Ain {
+			// no account input, the key images are checked by the mempool
+			return nil
+		}
+		return tx.CheckState(censor)
+	}
+}
+
+// blockCensor checks the transactions against its own state, see NewBlockCheck.
+type blockCensor struct {
+	*LinkApplication
+	state *state.StateDB
+}
+
+func (c *blockCensor) State() types.State { return c.state }
+
+// LockState is a noop, the state belongs to a single check.
+func (c *blockCensor) LockState() {}
+
+// UnlockState is a noop, the state belongs to a single check.
+func (c *blockCensor) UnlockState() {}
+
 // CheckTx assumes that txs' signature has been verified before.
 func (app *LinkApplication) CheckTx(tx types.Tx, checkBasic bool) error {
 	if checkBasic {
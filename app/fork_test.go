@@ -0,0 +1,145 @@
+package app
+
+import (
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// replayAcrossFork processes the block of genTxs on a fresh state at each of
+// the heights, with forks as the schedule of the chain.
+func replayAcrossFork(t *testing.T, forks types.Forks, heights []uint64, genTxs func() types.Txs) []types.Receipts {
+	return replayAcrossForkOn(t, forks, heights, nil, genTxs)
+}
+
+// replayAcrossForkOn is replayAcrossFork on the fresh states set up by genState.
+func replayAcrossForkOn(t *testing.T, forks types.Forks, heights []uint64, genState func(*state.StateDB), genTxs func() types.Txs) []types.Receipts {
+	SP.app.SetForks(forks)
+	defer SP.app.SetForks(nil)
+
+	results := make([]types.Receipts, 0, len(heights))
+	for _, height := range heights {
+		statedb := newTestState()
+		if genState != nil {
+			genState(statedb)
+		}
+		block := genBlock(genTxs())
+		block.Header.Height = height
+		receipts, _, _, _, _, _, err := SP.Process(block, statedb, VC)
+		if err != nil {
+			t.Fatalf("process block %d failed: %v", height, err)
+		}
+		results = append(results, receipts)
+	}
+	return results
+}
+
+func TestReplayAcrossIstanbul(t *testing.T) {
+	genTxs := func() types.Txs {
+		// SELFBALANCE POP STOP, an invalid opcode before istanbul
+		tx := types.NewContractCreation(0, big.NewInt(0), 100000, gasPrice, common.Hex2Bytes("475000"))
+		tx.Sign(types.GlobalSTDSigner, Bank[0].PrivateKey)
+		return types.Txs{tx}
+	}
+
+	forks := types.Forks{{Name: types.ForkIstanbul, Height: 10}}
+	results := replayAcrossFork(t, forks, []uint64{9, 10}, genTxs)
+	assert.Equal(t, types.ReceiptStatusFailed, results[0][0].Status, "SELFBALANCE executed before istanbul")
+	assert.Equal(t, types.ReceiptStatusSuccessful, results[1][0].Status, "SELFBALANCE failed after istanbul")
+	assert.NotEqual(t, results[0].Hash(), results[1].Hash(), "receipts are equal across the fork")
+}
+
+func TestReplayAcrossCrossVM(t *testing.T) {
+	code, err := ioutil.ReadFile("../vm/wasm/wasm-run/transfer.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		forward  = common.HexToAddress("0xbb")
+		transfer = common.HexToAddress("0xcc")
+		to       = common.HexToAddress("0x01")
+		states   []*state.StateDB
+	)
+	genState := func(statedb *state.StateDB) {
+		// calldatacopy(0, 0, calldatasize) call(gas, transfer, 0, 0, calldatasize, 0, 0) stop
+		fwd := []byte{0x36, 0x60, 0x00, 0x60, 0x00, 0x37, 0x60, 0x00, 0x60, 0x00, 0x36, 0x60, 0x00, 0x60, 0x00, 0x73}
+		fwd = append(fwd, transfer.Bytes()...)
+		fwd = append(fwd, 0x5a, 0xf1, 0x00)
+		statedb.SetCode(forward, fwd)
+		statedb.SetCode(transfer, code)
+		statedb.AddBalance(transfer, big.NewInt(10000))
+		states = append(states, statedb)
+	}
+	genTxs := func() types.Txs {
+		tx := types.NewTransaction(0, forward, big.NewInt(0), 1000000, gasPrice, []byte("a|a"))
+		tx.Sign(types.GlobalSTDSigner, Bank[0].PrivateKey)
+		return types.Txs{tx}
+	}
+
+	forks := types.Forks{{Name: types.ForkCrossVM, Height: 10}}
+	results := replayAcrossForkOn(t, forks, []uint64{9, 10}, genState, genTxs)
+	// the evm stops at the first byte of the wasm code before the fork
+	assert.Equal(t, types.ReceiptStatusSuccessful, results[0][0].Status)
+	assert.Equal(t, 0, states[0].GetBalance(to).Sign(), "wasm contract called before crossvm")
+	assert.Equal(t, types.ReceiptStatusSuccessful, results[1][0].Status)
+	assert.Equal(t, big.NewInt(125), states[1].GetBalance(to), "wasm contract not called after crossvm")
+	assert.NotEqual(t, results[0].Hash(), results[1].Hash(), "receipts are equal across the fork")
+}
+
+func TestCalldataGasAcrossIstanbul(t *testing.T) {
+	// STOP followed by four nonzero bytes the constructor never reads
+	code := common.Hex2Bytes("00ffffffff")
+	genTxs := func() types.Txs {
+		tx := types.NewContractCreation(0, big.NewInt(0), 100000, gasPrice, code)
+		tx.Sign(types.GlobalSTDSigner, Bank[0].PrivateKey)
+		return types.Txs{tx}
+	}
+
+	forks := types.Forks{{Name: types.ForkIstanbul, Height: 10}}
+	results := replayAcrossFork(t, forks, []uint64{9, 10}, genTxs)
+	assert.Equal(t, types.ReceiptStatusSuccessful, results[0][0].Status)
+	assert.Equal(t, types.ReceiptStatusSuccessful, results[1][0].Status)
+
+	before, err := types.IntrinsicGas(code, true, config.EvmGasRate, false)
+	assert.Nil(t, err)
+	after, err := types.IntrinsicGas(code, true, config.EvmGasRate, true)
+	assert.Nil(t, err)
+	assert.True(t, after < before, "calldata not repriced by istanbul")
+	assert.Equal(t, before-after, results[0][0].GasUsed-results[1][0].GasUsed)
+}
+
+func TestStateHashAcrossStateRoot(t *testing.T) {
+	// the fork requires the state trie, the test app keeps the state as kv
+	isTrie := APP.isTrie
+	APP.isTrie = true
+	defer func() { APP.isTrie = isTrie }()
+	assert.Nil(t, APP.SetForks(types.Forks{{Name: types.ForkStateRoot, Height: 10}}))
+	defer APP.SetForks(nil)
+	lastTxsResult := APP.lastTxsResult
+	defer func() { APP.lastTxsResult = lastTxsResult }()
+	parentRoot := common.HexToHash("0x01")
+	APP.lastTxsResult.TrieRoot = parentRoot
+
+	stateHashes := make([]common.Hash, 0, 2)
+	for _, height := range []uint64{9, 10} {
+		block := genBlock(types.Txs{})
+		block.Header.Height = height
+		processResult := ProcessResult{tmpState: newTestState(), height: height}
+		APP.processBlock(block, &processResult, true)
+		assert.True(t, processResult.isOk)
+		stateHashes = append(stateHashes, processResult.txsResult.StateHash)
+	}
+	assert.NotEqual(t, parentRoot, stateHashes[0], "state root committed before the fork")
+	assert.Equal(t, parentRoot, stateHashes[1], "state root not committed after the fork")
+
+	// the fork is refused if the state is kept as kv
+	kvApp := &LinkApplication{isTrie: false}
+	assert.Error(t, kvApp.SetForks(types.Forks{{Name: types.ForkStateRoot, Height: 10}}))
+	assert.Nil(t, kvApp.SetForks(types.Forks{{Name: types.ForkIstanbul, Height: 10}}))
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// AllocGenesisAccounts sets the balances, nonces, code, storage and token balances
+// of the genesis accounts, in the order of their addresses
+func AllocGenesisAccounts(st *state.StateDB, accounts map[string]types.GenesisAccount) {
+	addrs := make([]common.Address, 0, len(accounts))
+	byAddr := make(map[common.Address]types.GenesisAccount, len(accounts))
+	for straddr, account := range accounts {
+		addr := common.HexToAddress(straddr)
+		addrs = append(addrs, addr)
+		byAddr[addr] = account
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	for _, addr := range addrs {
+		account := byAddr[addr]
+		if account.Balance != nil {
+			st.AddBalance(addr, account.Balance)
+		}
+		st.SetNonce(addr, account.Nonce)
+		if len(account.Code) != 0 {
+			st.SetCode(addr, account.Code)
+		}
+
+		slots := make([]string, 0, len(account.Storage))
+		for slot := range account.Storage {
+			slots = append(slots, slot)
+		}
+		sort.Strings(slots)
+		for _, slot := range slots {
+			st.SetState(addr, common.BytesToHash(common.FromHex(slot)), account.Storage[slot])
+		}
+
+		tokens := make([]string, 0, len(account.Tokens))
+		for token := range account.Tokens {
+			tokens = append(tokens, token)
+		}
+		sort.Strings(tokens)
+		for _, token := range tokens {
+			st.AddTokenBalance(addr, common.HexToAddress(token), account.Tokens[token])
+		}
+	}
+}
@@ -44,6 +44,8 @@ const (
 type StateProcessor struct {
 	bc  *blockchain.BlockStore // Canonical block chain
 	app *LinkApplication
+
+	parallel int // number of workers executing transactions in parallel
 }
 
 // NewStateProcessor initialises a new StateProcessor.
@@ -54,6 +56,13 @@ func NewStateProcessor(bc *blockchain.BlockStore, app *LinkApplication) *StatePr
 	}
 }
 
+// SetParallel executes the transactions of the blocks optimistically in
+// parallel on workers goroutines, see processParallel. With less than two
+// workers the transactions are executed sequentially.
+func (p *StateProcessor) SetParallel(workers int) {
+	p.parallel = workers
+}
+
 type processState struct {
 	//outputs
 	Receipts    types.Receipts
@@ -67,9 +76,10 @@ type processState struct {
 	Statedb      *state.StateDB
 	Vmenv        vm.VmFactory
 	KeyImagesMap map[lctypes.Key]bool
+	Forks        types.Forks
 }
 
-func initProcessState(block *types.Block, statedb *state.StateDB, cfg evm.Config, bc *blockchain.BlockStore) (s *processState) {
+func initProcessState(block *types.Block, statedb *state.StateDB, cfg evm.Config, bc *blockchain.BlockStore, forks types.Forks) (s *processState) {
 	length := len(block.Data.Txs)
 	s = &processState{
 		Receipts:    make(types.Receipts, 0),
@@ -81,62 +91,95 @@ func initProcessState(block *types.Block, statedb *state.StateDB, cfg evm.Config
 		// states
 		Block:        block,
 		Statedb:      statedb,
-		Vmenv:        vm.NewVM(),
+		Vmenv:        newVmFactory(block, statedb, cfg, bc, forks),
 		KeyImagesMap: make(map[lctypes.Key]bool),
+		Forks:        forks,
 	}
+	return
+}
+
+func newVmFactory(block *types.Block, statedb *state.StateDB, cfg evm.Config, bc *blockchain.BlockStore, forks types.Forks) vm.VmFactory {
+	vmenv := vm.NewVM()
 	header := types.CopyHeader(block.Header)
 	evmGasRate := config.EvmGasRate
 	contextEvm := evm.NewEVMContext(header, bc, nil, evmGasRate)
-	s.Vmenv.AddVm(&contextEvm, statedb, cfg)
+	contextEvm.Forks = forks
+	vmenv.AddVm(&contextEvm, statedb, cfg)
 	wasmGasRate := config.WasmGasRate
 	contextWasm := wasm.NewWASMContext(header, bc, nil, wasmGasRate)
-	s.Vmenv.AddVm(&contextWasm, statedb, cfg)
-
-	return
+	contextWasm.Forks = forks
+	vmenv.AddVm(&contextWasm, statedb, cfg)
+	return vmenv
 }
 
 func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg evm.Config) (types.Receipts, []*types.Log, uint64, []types.Tx, []*types.UTXOOutputData, []*lctypes.Key, error) {
 
 	// init
-	s := initProcessState(block, statedb, cfg, p.bc)
-	// Iterate over and process the individual transactions
-	for idx, txRaw := range block.Data.Txs {
-		if err := s.checkValid(txRaw, p.app); err != nil {
-			log.Error("Process checkValid Error", "hash", txRaw.Hash(), "err", err)
-			return nil, nil, 0, nil, nil, nil, err
-		}
-		if err := s.resetEnv(txRaw, idx); err != nil {
-			log.Error("Process resetEnv Error", "hash", txRaw.Hash(), "err", err)
-			return nil, nil, 0, nil, nil, nil, err
-		}
-		if err := s.txRawProcess(txRaw); err != nil {
-			log.Error("Process txRawProcess Error", "hash", txRaw.Hash(), "err", err)
-			return nil, nil, 0, nil, nil, nil, err
-		}
-		//TODO: replace AsMessage in /types
-		tx, err := GenerateTransaction(txRaw, statedb, &s.Vmenv)
-		if err != nil {
-			log.Error("Process GenerateTransaction Error", "hash", txRaw.Hash(), "err", err)
-			return nil, nil, 0, nil, nil, nil, err
-		}
-		transRes, vmerr, err := tx.Transit()
-		if err != nil {
-			log.Error("Process Transit Error", "hash", tx.Hash, "err", err)
-			return nil, nil, 0, nil, nil, nil, err
+	s := initProcessState(block, statedb, cfg, p.bc, p.app.ForkSchedule())
+	var err error
+	// tracers follow the transactions one after another
+	if p.parallel > 1 && cfg.Tracer == nil {
+		err = p.processParallel(s, cfg)
+	} else {
+		// Iterate over and process the individual transactions
+		for idx, txRaw := range block.Data.Txs {
+			if err = s.applyTransaction(txRaw, idx, p.app); err != nil {
+				break
+			}
 		}
-		s.postProcess(tx, transRes, vmerr)
+	}
+	if err != nil {
+		return nil, nil, 0, nil, nil, nil, err
 	}
 	log.Debug("Process", "hash", block.Hash, "receipts", s.Receipts, "allLogs", s.AllLogs, "usedGas", s.UsedGas, "specialTxs", s.SpecialTxs, "utxoOutputs", s.UtxoOutputs, "keyImages", s.KeyImages)
 	return s.Receipts, s.AllLogs, s.UsedGas, s.SpecialTxs, s.UtxoOutputs, s.KeyImages, nil
 }
 
+// prepareTransaction checks the transaction against the state and sets up
+// the state for its execution.
+func (s *processState) prepareTransaction(txRaw types.Tx, idx int, app *LinkApplication) error {
+	if err := s.checkValid(txRaw, app); err != nil {
+		log.Error("Process checkValid Error", "hash", txRaw.Hash(), "err", err)
+		return err
+	}
+	if err := s.resetEnv(txRaw, idx); err != nil {
+		log.Error("Process resetEnv Error", "hash", txRaw.Hash(), "err", err)
+		return err
+	}
+	if err := s.txRawProcess(txRaw); err != nil {
+		log.Error("Process txRawProcess Error", "hash", txRaw.Hash(), "err", err)
+		return err
+	}
+	return nil
+}
+
+// applyTransaction executes the transaction at index idx of the block.
+func (s *processState) applyTransaction(txRaw types.Tx, idx int, app *LinkApplication) error {
+	if err := s.prepareTransaction(txRaw, idx, app); err != nil {
+		return err
+	}
+	//TODO: replace AsMessage in /types
+	tx, err := GenerateTransaction(txRaw, s.Statedb, &s.Vmenv)
+	if err != nil {
+		log.Error("Process GenerateTransaction Error", "hash", txRaw.Hash(), "err", err)
+		return err
+	}
+	transRes, vmerr, err := tx.Transit()
+	if err != nil {
+		log.Error("Process Transit Error", "hash", tx.Hash, "err", err)
+		return err
+	}
+	s.postProcess(tx, transRes, vmerr)
+	return nil
+}
+
 func (s *processState) checkValid(txi types.Tx, app *LinkApplication) (err error) {
 	switch tx := txi.(type) {
 	case *types.Transaction:
-		err = tx.CheckBasicWithState(nil, s.Statedb)
+		err = tx.CheckBasicWithState(nil, s.Statedb, s.Forks.Rules(s.Block.Height))
 
 	case *types.TokenTransaction:
-		err = tx.CheckBasicWithState(nil, s.Statedb)
+		err = tx.CheckBasicWithState(nil, s.Statedb, s.Forks.Rules(s.Block.Height))
 
 	case *types.UTXOTransaction:
 		if err = tx.CheckStoreState(app, s.Statedb); err != nil {
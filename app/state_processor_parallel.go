@@ -0,0 +1,193 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lianxiangcloud/linkchain/blockchain"
+	"github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/vm/evm"
+	"github.com/lianxiangcloud/linkchain/vm/wasm"
+)
+
+// parallelBatchSize is the maximum number of transactions executed
+// speculatively on the same state.
+const parallelBatchSize = 64
+
+var errSpeculativeCrossCall = errors.New("call into wasm contract while speculating")
+
+// refuseCrossVM refuses the calls of evm contracts into wasm contracts, which
+// do not run speculatively, see parallelizable.
+type refuseCrossVM struct {
+	called bool
+}
+
+func (r *refuseCrossVM) CrossCall(msg *types.CrossCallMsg) (*types.CrossCallResult, error) {
+	r.called = true
+	return &types.CrossCallResult{}, errSpeculativeCrossCall
+}
+
+// speculation is a transaction executed on a speculative copy of the state.
+type speculation struct {
+	state    *state.StateDB
+	tx       *processTransaction
+	res      *TransitionResult
+	vmerr    error
+	err      error
+	accessed map[common.Address]struct{}
+}
+
+func (sp *speculation) run(txRaw types.Tx, block *types.Block, cfg evm.Config, bc *blockchain.BlockStore, forks types.Forks) {
+	defer func() {
+		// the transaction may fail on a state it is not executed on in the end
+		if r := recover(); r != nil {
+			sp.err = fmt.Errorf("speculation panicked: %v", r)
+		}
+		sp.accessed = sp.state.AccessedAccounts()
+	}()
+	vmenv := newVmFactory(block, sp.state, cfg, bc, forks)
+	cross := new(refuseCrossVM)
+	vmenv.GetEvm().(*evm.EVM).SetCrossVM(cross)
+	if sp.tx, sp.err = GenerateTransaction(txRaw, sp.state, &vmenv); sp.err != nil {
+		return
+	}
+	sp.res, sp.vmerr, sp.err = sp.tx.Transit()
+	if cross.called {
+		// executed again on the state
+		sp.err = errSpeculativeCrossCall
+	}
+}
+
+// conflicts reports whether the speculation accessed one of the accounts
+// written since its state was copied, or could not be merged at all.
+func (sp *speculation) conflicts(written map[common.Address]struct{}) bool {
+	if sp.err != nil || sp.state.Error() != nil {
+		return true
+	}
+	for addr := range sp.accessed {
+		if _, ok := written[addr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// processParallel executes the transactions of the block optimistically in
+// parallel. Consecutive transactions which can be executed in parallel, see
+// parallelizable, are executed in batches, each transaction on its own
+// speculative copy of the state. The results are then committed in block
+// order: a transaction which accessed an account written by a transaction
+// committed before it in the batch is executed again on the state, the
+// results of the others are merged into it. The receipts, logs and state are
+// the same as the ones of executing the transactions one after another.
+func (p *StateProcessor) processParallel(s *processState, cfg evm.Config) error {
+	var (
+		txs               = s.Block.Data.Txs
+		merged, reapplied int
+	)
+	for start := 0; start < len(txs); {
+		if !s.parallelizable(txs[start]) {
+			if err := s.applyTransaction(txs[start], start, p.app); err != nil {
+				return err
+			}
+			start++
+			continue
+		}
+		end := start + 1
+		for end < len(txs) && end-start < parallelBatchSize && s.parallelizable(txs[end]) {
+			end++
+		}
+		specs := s.speculate(txs[start:end], start, cfg, p.bc, p.parallel)
+
+		// accounts written by the transactions committed in this batch
+		written := make(map[common.Address]struct{})
+		for i, sp := range specs {
+			idx, txRaw := start+i, txs[start+i]
+			if sp.conflicts(written) {
+				s.Statedb.TrackAccess()
+				err := s.applyTransaction(txRaw, idx, p.app)
+				for addr := range s.Statedb.AccessedAccounts() {
+					written[addr] = struct{}{}
+				}
+				if err != nil {
+					return err
+				}
+				reapplied++
+				continue
+			}
+			if err := s.prepareTransaction(txRaw, idx, p.app); err != nil {
+				return err
+			}
+			s.Statedb.MergeSpeculative(sp.state)
+			for addr := range sp.accessed {
+				written[addr] = struct{}{}
+			}
+			sp.tx.State, sp.tx.Vmenv = s.Statedb, &s.Vmenv
+			s.postProcess(sp.tx, sp.res, sp.vmerr)
+			merged++
+		}
+		start = end
+	}
+	log.Debug("processParallel", "hash", s.Block.Hash(), "txs", len(txs), "merged", merged, "reapplied", reapplied)
+	return nil
+}
+
+// speculate executes txs, starting at index start of the block, on workers
+// goroutines, each transaction on a speculative copy of the state.
+func (s *processState) speculate(txs types.Txs, start int, cfg evm.Config, bc *blockchain.BlockStore, workers int) []*speculation {
+	specs := make([]*speculation, len(txs))
+	for i, tx := range txs {
+		st := s.Statedb.SpeculativeCopy()
+		st.Prepare(tx.Hash(), s.Block.Hash(), start+i)
+		specs[i] = &speculation{state: st}
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				specs[i].run(txs[i], s.Block, cfg, bc, s.Forks)
+			}
+		}()
+	}
+	for i := range txs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return specs
+}
+
+// parallelizable reports whether the transaction may be executed on a
+// speculative copy of the state. Only account and token transactions are,
+// as long as they neither run wasm contracts, which share the compiled
+// contracts process wide, nor call the blacklist contract, which updates the
+// blacklist of the node. The calls of evm contracts into wasm contracts are
+// refused by refuseCrossVM, such transactions are executed again.
+func (s *processState) parallelizable(txi types.Tx) bool {
+	var data []byte
+	switch tx := txi.(type) {
+	case *types.Transaction:
+		data = tx.Data()
+	case *types.TokenTransaction:
+		data = tx.Data()
+	default:
+		return false
+	}
+	to := txi.To()
+	if to == nil {
+		return !wasm.IsWasmContract(data)
+	}
+	if *to == config.ContractBlacklistAddr {
+		return false
+	}
+	return !wasm.IsWasmContract(s.Statedb.GetCode(*to))
+}
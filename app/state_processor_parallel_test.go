@@ -0,0 +1,121 @@
+package app
+
+import (
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+	"github.com/lianxiangcloud/linkchain/accounts/keystore"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newParallelTestState(keys []*keystore.Key) *state.StateDB {
+	statedb, _ := state.New(common.EmptyHash, state.NewDatabase(dbm.NewMemDB()))
+	for _, key := range keys {
+		statedb.AddBalance(key.Address, initBalance)
+		statedb.AddTokenBalance(key.Address, tokenAddr, initTokenBalance)
+	}
+	statedb.IntermediateRoot(false)
+	return statedb
+}
+
+// genParallelTestBlocks generates blocks of transfers, token transfers and
+// calls of a token contract, the senders and receivers of which are picked
+// at random so that some of the transactions conflict.
+func genParallelTestBlocks(t *testing.T, keys []*keystore.Key, blocks, txsPerBlock int) []*types.Block {
+	f, err := os.Open("../test/token/sol/SimpleToken.abi")
+	require.NoError(t, err)
+	defer f.Close()
+	tokenABI, err := abi.JSON(f)
+	require.NoError(t, err)
+	bin, err := ioutil.ReadFile("../test/token/sol/SimpleToken.bin")
+	require.NoError(t, err)
+
+	var (
+		rnd      = rand.New(rand.NewSource(1))
+		nonces   = make(map[common.Address]uint64)
+		creation = types.NewContractCreation(0, big.NewInt(0), 1494617, gasPrice, common.Hex2Bytes(string(bin)))
+		contract = crypto.CreateAddress(keys[0].Address, 0, creation.Data())
+		result   []*types.Block
+	)
+	require.NoError(t, creation.Sign(types.GlobalSTDSigner, keys[0].PrivateKey))
+	nonces[keys[0].Address]++
+
+	for b := 0; b < blocks; b++ {
+		var txs types.Txs
+		if b == 0 {
+			txs = append(txs, creation)
+		}
+		for len(txs) < txsPerBlock {
+			from := keys[rnd.Intn(len(keys))]
+			to := keys[rnd.Intn(len(keys))].Address
+			nonce := nonces[from.Address]
+			var tx types.Tx
+			switch rnd.Intn(4) {
+			case 0:
+				tx, err = genTx(from, nonce, &to, big.NewInt(rnd.Int63n(1e18)), nil)
+			case 1:
+				tx, err = genTokenTx(from, &to, tokenAddr, nonce, big.NewInt(rnd.Int63n(100)), 0, "")
+			case 2:
+				// fails but for the creator of the contract
+				data, perr := tokenABI.Pack("transfer", to, big.NewInt(rnd.Int63n(100)))
+				require.NoError(t, perr)
+				tx, err = genCallContractTx(from, nonce, &contract, big.NewInt(0), 100000, data)
+			default:
+				fresh := common.BigToAddress(big.NewInt(rnd.Int63()))
+				tx, err = genTx(from, nonce, &fresh, big.NewInt(rnd.Int63n(1e18)), nil)
+			}
+			require.NoError(t, err)
+			nonces[from.Address]++
+			txs = append(txs, tx)
+		}
+		block := genBlock(txs)
+		block.Height = uint64(b + 1)
+		result = append(result, block)
+	}
+	return result
+}
+
+func TestParallelProcess(t *testing.T) {
+	types.SaveBalanceRecord = true
+	keys := make([]*keystore.Key, 24)
+	for i := range keys {
+		sk, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		keys[i] = &keystore.Key{Address: crypto.PubkeyToAddress(sk.PublicKey), PrivateKey: sk}
+	}
+
+	var (
+		seqState, parState = newParallelTestState(keys), newParallelTestState(keys)
+		seqSP, parSP       = NewStateProcessor(nil, APP), NewStateProcessor(nil, APP)
+	)
+	parSP.SetParallel(4)
+
+	for _, block := range genParallelTestBlocks(t, keys, 8, 40) {
+		types.BlockBalanceRecordsInstance = types.NewBlockBalanceRecords()
+		seqReceipts, seqLogs, seqGas, _, _, _, err := seqSP.Process(block, seqState, VC)
+		require.NoError(t, err)
+		seqRecords := types.BlockBalanceRecordsInstance.Json()
+
+		types.BlockBalanceRecordsInstance = types.NewBlockBalanceRecords()
+		parReceipts, parLogs, parGas, _, _, _, err := parSP.Process(block, parState, VC)
+		require.NoError(t, err)
+		parRecords := types.BlockBalanceRecordsInstance.Json()
+
+		assert.Equal(t, seqReceipts, parReceipts, "block %d", block.Height)
+		assert.Equal(t, seqReceipts.Hash(), parReceipts.Hash(), "block %d", block.Height)
+		assert.Equal(t, seqLogs, parLogs, "block %d", block.Height)
+		assert.Equal(t, seqGas, parGas, "block %d", block.Height)
+		assert.Equal(t, seqRecords, parRecords, "block %d", block.Height)
+		require.Equal(t, seqState.IntermediateRoot(false), parState.IntermediateRoot(false), "block %d", block.Height)
+	}
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/state"
+)
+
+var (
+	statePrunerKey        = []byte("statePruner")      // first and oldest retained heights
+	statePrunerRootPrefix = []byte("statePrunerRoot:") // height -> retained state root
+)
+
+// StatePrunedError is returned for the blocks whose state is not retained by
+// the node anymore.
+type StatePrunedError struct {
+	Height uint64 // block whose state is requested
+	Oldest uint64 // oldest block whose state is retained
+}
+
+func (e *StatePrunedError) Error() string {
+	return fmt.Sprintf("state pruned: the state of block %d is not retained, the oldest state is at block %d", e.Height, e.Oldest)
+}
+
+// statePruner retains the state roots of the committed blocks and releases
+// the ones of the blocks that get out of the retention window. The state of
+// the blocks committed before the pruner was enabled is never released.
+type statePruner struct {
+	db     dbm.DB
+	trieDB state.PrunableTrieDB
+	logger log.Logger
+
+	mtx   sync.RWMutex
+	keep  uint64 // number of blocks whose state is retained, 0 retains all
+	start uint64 // first block whose state root was retained
+	floor uint64 // oldest block whose state is retained
+}
+
+func newStatePruner(db dbm.DB, trieDB state.PrunableTrieDB, logger log.Logger) *statePruner {
+	p := &statePruner{db: db, trieDB: trieDB, logger: logger}
+	if bz := db.Get(statePrunerKey); len(bz) == 16 {
+		p.start = binary.BigEndian.Uint64(bz[:8])
+		p.floor = binary.BigEndian.Uint64(bz[8:])
+	}
+	return p
+}
+
+// hasStatePruner reports whether the state roots of db are retained by a pruner.
+func hasStatePruner(db dbm.DB) bool {
+	return db.Has(statePrunerKey)
+}
+
+func statePrunerRootKey(height uint64) []byte {
+	key := make([]byte, len(statePrunerRootPrefix)+8)
+	copy(key, statePrunerRootPrefix)
+	binary.BigEndian.PutUint64(key[len(statePrunerRootPrefix):], height)
+	return key
+}
+
+func (p *statePruner) root(height uint64) common.Hash {
+	return common.BytesToHash(p.db.Get(statePrunerRootKey(height)))
+}
+
+func (p *statePruner) bounds() []byte {
+	bz := make([]byte, 16)
+	binary.BigEndian.PutUint64(bz[:8], p.start)
+	binary.BigEndian.PutUint64(bz[8:], p.floor)
+	return bz
+}
+
+func (p *statePruner) setKeep(keep uint64) {
+	p.mtx.Lock()
+	p.keep = keep
+	p.mtx.Unlock()
+}
+
+// commit retains the state root of the block at height and releases the
+// state roots out of the retention window. The bookkeeping is written before
+// releasing a root, an interruption leaks nodes but never releases a root twice.
+func (p *statePruner) commit(height uint64, root common.Hash) error {
+	if old := p.root(height); old != root {
+		if err := p.trieDB.Retain(root); err != nil {
+			return err
+		}
+		p.db.SetSync(statePrunerRootKey(height), root[:])
+		if old != (common.Hash{}) {
+			// the block is committed again with another state, e.g. after a rollback
+			if err := p.trieDB.Release(old); err != nil {
+				return err
+			}
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.start == 0 {
+		p.start, p.floor = height, height
+		p.db.SetSync(statePrunerKey, p.bounds())
+	}
+	for p.keep > 0 && p.floor+p.keep <= height {
+		pruned := p.floor
+		old := p.root(pruned)
+		p.floor++
+
+		batch := p.db.NewBatch()
+		batch.Delete(statePrunerRootKey(pruned))
+		batch.Set(statePrunerKey, p.bounds())
+		batch.WriteSync()
+
+		if old != (common.Hash{}) {
+			if err := p.trieDB.Release(old); err != nil {
+				return err
+			}
+			p.logger.Debug("Pruned state", "height", pruned, "root", old)
+		}
+	}
+	return nil
+}
+
+// check returns a *StatePrunedError if the state of the block at height has
+// been pruned.
+func (p *statePruner) check(height uint64) error {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if height >= p.start && height < p.floor {
+		return &StatePrunedError{Height: height, Oldest: p.floor}
+	}
+	return nil
+}
@@ -140,6 +140,7 @@ func (tx *processTransaction) buyGas() (err error) { // default use Input[0] to
 func (tx *processTransaction) payIntrinsicGas() (err error) {
 	var intrinsicGas uint64
 	var intrinsicGasSum uint64
+	istanbul := tx.Vmenv.IsForkActive(types.ForkIstanbul)
 	for _, aout := range tx.Outputs {
 		if aout.Type == Createout || aout.Type == Updateout {
 			data := aout.Data
@@ -147,7 +148,7 @@ func (tx *processTransaction) payIntrinsicGas() (err error) {
 			if wasm.IsWasmContract(data) {
 				gasRate = cfg.WasmGasRate
 			}
-			if intrinsicGas, err = types.IntrinsicGas(aout.Data, true, gasRate); err != nil {
+			if intrinsicGas, err = types.IntrinsicGas(aout.Data, true, gasRate, istanbul); err != nil {
 				return
 			}
 			if (math.MaxUint64 - intrinsicGasSum) <= intrinsicGas {
@@ -160,7 +161,7 @@ func (tx *processTransaction) payIntrinsicGas() (err error) {
 			if wasm.IsWasmContract(data) {
 				gasRate = cfg.WasmGasRate
 			}
-			if intrinsicGas, err = types.IntrinsicGas(aout.Data, false, gasRate); err != nil {
+			if intrinsicGas, err = types.IntrinsicGas(aout.Data, false, gasRate, istanbul); err != nil {
 				return
 			}
 			if (math.MaxUint64 - intrinsicGasSum) <= intrinsicGas {
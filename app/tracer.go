@@ -0,0 +1,83 @@
+package app
+
+import (
+	"github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/vm"
+	"github.com/lianxiangcloud/linkchain/vm/evm"
+	"github.com/lianxiangcloud/linkchain/vm/wasm"
+)
+
+// ChainContext supports retrieving headers from the current blockchain,
+// it's needed by both vms to serve the BLOCKHASH lookups.
+type ChainContext interface {
+	GetHeader(uint64) *types.Header
+}
+
+// VmConfigFunc returns the vm config the idx-th transaction of a block
+// should be re-executed with. A wasm aware tracer set in the config
+// also follows the WASM contracts, see vm.VmFactory.AddVm.
+type VmConfigFunc func(idx int, tx types.Tx) evm.Config
+
+// TxTraceResult is the outcome of a transaction re-executed by TraceBlock.
+type TxTraceResult struct {
+	Hash        common.Hash
+	Gas         uint64
+	ReturnValue []byte
+	VmErr       error
+}
+
+// TraceBlock re-executes the transactions of block on top of statedb, which
+// must be the state the block was applied to, and stops after the end-th one
+// (a negative end runs them all). Every transaction gets a fresh vm set up with
+// the config returned by vmConfig, so tracers never span two transactions.
+//
+// Unlike StateProcessor.Process, the block is known to be valid and nothing
+// but statedb is touched: no balance records, receipts or utxo outputs are
+// produced.
+func TraceBlock(block *types.Block, statedb *state.StateDB, chain ChainContext, forks types.Forks, end int, vmConfig VmConfigFunc) ([]*TxTraceResult, error) {
+	var (
+		header  = types.CopyHeader(block.Header)
+		results = make([]*TxTraceResult, 0, len(block.Data.Txs))
+	)
+	for idx, txRaw := range block.Data.Txs {
+		if end >= 0 && idx > end {
+			break
+		}
+		statedb.Prepare(txRaw.Hash(), block.Hash(), idx)
+
+		cfg := vmConfig(idx, txRaw)
+		vmenv := vm.NewVM()
+		contextEvm := evm.NewEVMContext(header, chain, nil, config.EvmGasRate)
+		contextEvm.Forks = forks
+		vmenv.AddVm(&contextEvm, statedb, cfg)
+		contextWasm := wasm.NewWASMContext(header, chain, nil, config.WasmGasRate)
+		contextWasm.Forks = forks
+		vmenv.AddVm(&contextWasm, statedb, cfg)
+
+		tx, err := GenerateTransaction(txRaw, statedb, &vmenv)
+		if err != nil {
+			log.Warn("TraceBlock GenerateTransaction Error", "hash", txRaw.Hash(), "err", err)
+			return nil, err
+		}
+		transRes, vmerr, err := tx.Transit()
+		if err != nil {
+			log.Warn("TraceBlock Transit Error", "hash", tx.Hash, "err", err)
+			return nil, err
+		}
+
+		result := &TxTraceResult{
+			Hash:  tx.Hash,
+			Gas:   transRes.Gas,
+			VmErr: vmerr,
+		}
+		if len(transRes.Rets) > 0 {
+			result.ReturnValue = transRes.Rets[0]
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
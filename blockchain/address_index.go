@@ -0,0 +1,314 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// Directions of an indexed transaction, as seen from the indexed address.
+const (
+	AddressTxIn  uint8 = 1 << iota // the address received value
+	AddressTxOut                   // the address sent the transaction or value
+)
+
+const (
+	addressIndexSubscriber = "address-indexer"
+
+	// address tx keys: addressTxPrefix | address | height | tx index | token
+	addressTxPrefix = "atx_"
+	// AddressTxCursorLen is the length of the cursors handed out by AddressTxs.
+	AddressTxCursorLen = 8 + 4 + common.AddressLength
+)
+
+var addressIndexHeightKey = []byte("atxHeight")
+
+// AddressTx is an entry of the address index: a transaction of a committed
+// block which moved a token from or to the address.
+type AddressTx struct {
+	Height    uint64
+	TxIndex   uint32
+	TxHash    common.Hash
+	Token     common.Address
+	Direction uint8
+}
+
+type addressTxValue struct {
+	TxHash    common.Hash
+	Direction uint8
+}
+
+// AddressIndexer indexes the transactions of the committed blocks by the
+// account addresses they touch. Besides the senders and receivers of the
+// transactions it indexes the transfers of the balance records, which include
+// the internal transfers of contracts. On start it catches up with the blocks
+// committed while it wasn't running, which rebuilds the whole index on the
+// first start. The blocks committed meanwhile are indexed afterwards, the
+// event bus is not held up.
+type AddressIndexer struct {
+	cmn.BaseService
+
+	db       dbm.DB
+	blocks   *BlockStore
+	records  *BalanceRecordStore
+	eventBus *types.EventBus
+
+	mtx    sync.RWMutex
+	height uint64 // last indexed height
+	synced bool   // at least one block indexed
+
+	targetMtx sync.Mutex
+	target    uint64        // height of the last committed block received
+	notifyCh  chan struct{} // signals a new target to the indexRoutine
+}
+
+// NewAddressIndexer returns an AddressIndexer storing its index in db.
+func NewAddressIndexer(db dbm.DB, blocks *BlockStore, records *BalanceRecordStore) *AddressIndexer {
+	ai := &AddressIndexer{
+		db:       db,
+		blocks:   blocks,
+		records:  records,
+		notifyCh: make(chan struct{}, 1),
+	}
+	if b := db.Get(addressIndexHeightKey); len(b) == 8 {
+		ai.height = binary.BigEndian.Uint64(b)
+		ai.synced = true
+	}
+	ai.BaseService = *cmn.NewBaseService(nil, "AddressIndexer", ai)
+	return ai
+}
+
+// SetEventBus sets the event bus the committed blocks are received from.
+func (ai *AddressIndexer) SetEventBus(b *types.EventBus) {
+	ai.eventBus = b
+}
+
+// OnStart implements cmn.Service by subscribing to the new blocks, the event
+// bus has to be running.
+func (ai *AddressIndexer) OnStart() error {
+	blockCh := make(chan interface{}, 128)
+	if err := ai.eventBus.Subscribe(context.Background(), addressIndexSubscriber, types.EventQueryNewBlock, blockCh); err != nil {
+		return err
+	}
+	go ai.receiveRoutine(blockCh)
+	go ai.indexRoutine()
+	return nil
+}
+
+// OnStop implements cmn.Service.
+func (ai *AddressIndexer) OnStop() {
+	ai.eventBus.UnsubscribeAll(context.Background(), addressIndexSubscriber)
+}
+
+// Height returns the height of the last indexed block.
+func (ai *AddressIndexer) Height() uint64 {
+	ai.mtx.RLock()
+	defer ai.mtx.RUnlock()
+	return ai.height
+}
+
+// receiveRoutine keeps reading the new blocks, so that the event bus does not
+// wait for the indexing, and hands the last height to the indexRoutine.
+func (ai *AddressIndexer) receiveRoutine(blockCh <-chan interface{}) {
+	for {
+		select {
+		case ev := <-blockCh:
+			nb, ok := ev.(types.EventDataNewBlock)
+			if !ok || nb.Block == nil {
+				continue
+			}
+			ai.targetMtx.Lock()
+			if nb.Block.Height > ai.target {
+				ai.target = nb.Block.Height
+			}
+			ai.targetMtx.Unlock()
+			select {
+			case ai.notifyCh <- struct{}{}:
+			default:
+			}
+		case <-ai.Quit():
+			return
+		}
+	}
+}
+
+func (ai *AddressIndexer) indexRoutine() {
+	ai.indexTo(ai.blocks.Height())
+	for {
+		select {
+		case <-ai.notifyCh:
+			ai.targetMtx.Lock()
+			target := ai.target
+			ai.targetMtx.Unlock()
+			ai.indexTo(target)
+		case <-ai.Quit():
+			return
+		}
+	}
+}
+
+// indexTo indexes the blocks up to height which are not indexed yet.
+func (ai *AddressIndexer) indexTo(height uint64) {
+	next := types.BlockHeightZero
+	if ai.synced {
+		next = ai.height + 1
+	}
+	if next < height {
+		ai.Logger.Info("Indexing addresses", "from", next, "to", height)
+	}
+	for ; next <= height; next++ {
+		select {
+		case <-ai.Quit():
+			return
+		default:
+		}
+		batch := ai.db.NewBatch()
+		// blocks cleared by keep_latest_blocks are gone for good
+		if block := ai.blocks.LoadBlock(next); block != nil {
+			ai.indexBlock(batch, block)
+		}
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], next)
+		batch.Set(addressIndexHeightKey, b[:])
+		batch.WriteSync()
+
+		ai.mtx.Lock()
+		ai.height, ai.synced = next, true
+		ai.mtx.Unlock()
+	}
+}
+
+// indexBlock adds the index entries of a block to batch, indexing a block
+// again rewrites the same entries.
+func (ai *AddressIndexer) indexBlock(batch dbm.Batch, block *types.Block) {
+	entries := make(map[string]*addressTxValue)
+	add := func(addr common.Address, txIndex int, hash common.Hash, token common.Address, dir uint8) {
+		if addr == common.EmptyAddress {
+			return
+		}
+		key := string(addressTxKey(addr, block.Height, uint32(txIndex), token))
+		if v, ok := entries[key]; ok {
+			v.Direction |= dir
+			return
+		}
+		entries[key] = &addressTxValue{TxHash: hash, Direction: dir}
+	}
+
+	txIndexes := make(map[common.Hash]int, len(block.Data.Txs))
+	for i, tx := range block.Data.Txs {
+		hash := tx.Hash()
+		txIndexes[hash] = i
+		token := tx.TokenAddress()
+
+		if utx, ok := tx.(*types.UTXOTransaction); ok {
+			if utx.UTXOKind()&types.Ain == types.Ain {
+				if from, err := tx.From(); err == nil {
+					add(from, i, hash, token, AddressTxOut)
+				}
+			}
+			for _, out := range utx.Outputs {
+				if aout, ok := out.(*types.AccountOutput); ok {
+					add(aout.To, i, hash, token, AddressTxIn)
+				}
+			}
+			continue
+		}
+		if from, err := tx.From(); err == nil {
+			add(from, i, hash, token, AddressTxOut)
+		}
+		if to := tx.To(); to != nil {
+			add(*to, i, hash, token, AddressTxIn)
+		}
+	}
+
+	if bbr := ai.records.Get(block.Height); bbr != nil {
+		for _, txr := range bbr.TxRecords {
+			i, ok := txIndexes[txr.Hash]
+			if !ok {
+				continue
+			}
+			for _, r := range txr.Records {
+				if r.FromAddressType == types.AccountAddress {
+					add(r.From, i, txr.Hash, r.TokenID, AddressTxOut)
+				}
+				if r.ToAddressType == types.AccountAddress {
+					add(r.To, i, txr.Hash, r.TokenID, AddressTxIn)
+				}
+			}
+		}
+	}
+
+	for key, v := range entries {
+		batch.Set([]byte(key), ser.MustEncodeToBytes(v))
+	}
+}
+
+// AddressTxs returns the indexed transactions of addr in the blocks from
+// fromHeight to toHeight, ordered by height and transaction index. At most
+// limit entries are returned, along with the cursor to pass to get the next
+// ones, which is nil when there are no more.
+func (ai *AddressIndexer) AddressTxs(addr common.Address, fromHeight, toHeight uint64, cursor []byte, limit int) ([]*AddressTx, []byte) {
+	if height := ai.Height(); toHeight > height {
+		toHeight = height
+	}
+	start := addressTxKey(addr, fromHeight, 0, common.EmptyAddress)
+	if len(cursor) == AddressTxCursorLen {
+		if c := append(addressTxAddrKey(addr), cursor...); bytes.Compare(c, start) > 0 {
+			start = c
+		}
+	}
+	end := addressTxKey(addr, toHeight+1, 0, common.EmptyAddress)
+
+	it := ai.db.Iterator(start, end)
+	defer it.Close()
+
+	var txs []*AddressTx
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if len(txs) == limit {
+			return txs, common.CopyBytes(key[len(addressTxPrefix)+common.AddressLength:])
+		}
+		var v addressTxValue
+		if err := ser.DecodeBytes(it.Value(), &v); err != nil {
+			ai.Logger.Error("AddressTxs: decode failed", "key", key, "err", err)
+			continue
+		}
+		height, txIndex, token := parseAddressTxKey(key)
+		txs = append(txs, &AddressTx{
+			Height:    height,
+			TxIndex:   txIndex,
+			TxHash:    v.TxHash,
+			Token:     token,
+			Direction: v.Direction,
+		})
+	}
+	return txs, nil
+}
+
+func addressTxAddrKey(addr common.Address) []byte {
+	key := make([]byte, 0, len(addressTxPrefix)+common.AddressLength+AddressTxCursorLen)
+	key = append(key, addressTxPrefix...)
+	return append(key, addr.Bytes()...)
+}
+
+func addressTxKey(addr common.Address, height uint64, txIndex uint32, token common.Address) []byte {
+	key := append(addressTxAddrKey(addr), make([]byte, 12)...)
+	binary.BigEndian.PutUint64(key[len(key)-12:], height)
+	binary.BigEndian.PutUint32(key[len(key)-4:], txIndex)
+	return append(key, token.Bytes()...)
+}
+
+func parseAddressTxKey(key []byte) (height uint64, txIndex uint32, token common.Address) {
+	key = key[len(addressTxPrefix)+common.AddressLength:]
+	height = binary.BigEndian.Uint64(key)
+	txIndex = binary.BigEndian.Uint32(key[8:])
+	token = common.BytesToAddress(key[12:])
+	return
+}
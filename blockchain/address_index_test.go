@@ -0,0 +1,173 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, to common.Address) types.Tx {
+	tx := types.NewTransaction(nonce, to, big.NewInt(1), 100000, nil, nil)
+	require.NoError(t, tx.Sign(types.GlobalSTDSigner, key))
+	return tx
+}
+
+func saveTestBlock(bs *BlockStore, txs []types.Tx) *types.Block {
+	block := types.MakeBlock(bs.Height()+1, txs, new(types.Commit))
+	bs.SaveBlock(block, block.MakePartSet(2), &types.Commit{}, nil, &types.TxsResult{})
+	return block
+}
+
+func TestAddressIndexer(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	var (
+		from     = crypto.PubkeyToAddress(key.PublicKey)
+		to       = common.HexToAddress("0x01")
+		contract = common.HexToAddress("0x02")
+		token    = common.HexToAddress("0x03")
+	)
+
+	bs := NewBlockStore(dbm.NewMemDB())
+	records := NewBalanceRecordStore(dbm.NewMemDB(), true)
+
+	// blocks committed before the indexer runs
+	b1 := saveTestBlock(bs, []types.Tx{signedTx(t, key, 0, to), signedTx(t, key, 1, contract)})
+	bbr := types.NewBlockBalanceRecords()
+	bbr.AddTxBalanceRecord(&types.TxBalanceRecords{
+		Hash: b1.Data.Txs[1].Hash(),
+		Records: []types.BalanceRecord{{
+			From:            contract,
+			To:              to,
+			FromAddressType: types.AccountAddress,
+			ToAddressType:   types.AccountAddress,
+			TokenID:         token,
+			Amount:          big.NewInt(1),
+		}},
+	})
+	records.Save(b1.Height, bbr)
+	b2 := saveTestBlock(bs, []types.Tx{signedTx(t, key, 2, from)})
+
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+
+	ai := NewAddressIndexer(dbm.NewMemDB(), bs, records)
+	ai.SetEventBus(eventBus)
+	require.NoError(t, ai.Start())
+	defer ai.Stop()
+
+	waitIndexed := func(height uint64) {
+		for i := 0; i < 100 && ai.Height() < height; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.Equal(t, height, ai.Height())
+	}
+	waitIndexed(b2.Height)
+
+	txs, cursor := ai.AddressTxs(from, 0, 100, nil, 10)
+	assert.Nil(t, cursor)
+	assert.Equal(t, []*AddressTx{
+		{Height: b1.Height, TxIndex: 0, TxHash: b1.Data.Txs[0].Hash(), Direction: AddressTxOut},
+		{Height: b1.Height, TxIndex: 1, TxHash: b1.Data.Txs[1].Hash(), Direction: AddressTxOut},
+		{Height: b2.Height, TxIndex: 0, TxHash: b2.Data.Txs[0].Hash(), Direction: AddressTxIn | AddressTxOut},
+	}, txs)
+
+	// the internal transfer of the contract
+	txs, _ = ai.AddressTxs(to, 0, 100, nil, 10)
+	assert.Equal(t, []*AddressTx{
+		{Height: b1.Height, TxIndex: 0, TxHash: b1.Data.Txs[0].Hash(), Direction: AddressTxIn},
+		{Height: b1.Height, TxIndex: 1, TxHash: b1.Data.Txs[1].Hash(), Token: token, Direction: AddressTxIn},
+	}, txs)
+	txs, _ = ai.AddressTxs(contract, 0, 100, nil, 10)
+	assert.Equal(t, []*AddressTx{
+		{Height: b1.Height, TxIndex: 1, TxHash: b1.Data.Txs[1].Hash(), Direction: AddressTxIn},
+		{Height: b1.Height, TxIndex: 1, TxHash: b1.Data.Txs[1].Hash(), Token: token, Direction: AddressTxOut},
+	}, txs)
+
+	// paging
+	txs, cursor = ai.AddressTxs(from, 0, 100, nil, 2)
+	assert.Len(t, txs, 2)
+	require.Len(t, cursor, AddressTxCursorLen)
+	txs, cursor = ai.AddressTxs(from, 0, 100, cursor, 2)
+	assert.Nil(t, cursor)
+	require.Len(t, txs, 1)
+	assert.Equal(t, b2.Height, txs[0].Height)
+
+	// height range
+	txs, _ = ai.AddressTxs(from, b2.Height, b2.Height, nil, 10)
+	assert.Len(t, txs, 1)
+	txs, _ = ai.AddressTxs(from, 0, b1.Height, nil, 10)
+	assert.Len(t, txs, 2)
+
+	// blocks committed while the indexer runs
+	b3 := saveTestBlock(bs, []types.Tx{signedTx(t, key, 3, to)})
+	require.NoError(t, eventBus.PublishEventNewBlock(types.EventDataNewBlock{Block: b3}))
+	waitIndexed(b3.Height)
+	txs, _ = ai.AddressTxs(from, b3.Height, 100, nil, 10)
+	assert.Equal(t, []*AddressTx{
+		{Height: b3.Height, TxIndex: 0, TxHash: b3.Data.Txs[0].Hash(), Direction: AddressTxOut},
+	}, txs)
+
+	// the indexed height survives restarts
+	assert.Equal(t, b3.Height, NewAddressIndexer(ai.db, bs, records).Height())
+}
+
+// blockingDB holds up the batches written to it until it is released.
+type blockingDB struct {
+	dbm.DB
+	release chan struct{}
+}
+
+func (db *blockingDB) NewBatch() dbm.Batch {
+	<-db.release
+	return db.DB.NewBatch()
+}
+
+func TestAddressIndexerCatchUp(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bs := NewBlockStore(dbm.NewMemDB())
+	records := NewBalanceRecordStore(dbm.NewMemDB(), true)
+	saveTestBlock(bs, []types.Tx{signedTx(t, key, 0, common.HexToAddress("0x01"))})
+
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+
+	db := &blockingDB{DB: dbm.NewMemDB(), release: make(chan struct{})}
+	ai := NewAddressIndexer(db, bs, records)
+	ai.SetEventBus(eventBus)
+	require.NoError(t, ai.Start())
+	defer ai.Stop()
+
+	// the blocks are published while the catch up is held up
+	var last *types.Block
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 200; i++ {
+			last = saveTestBlock(bs, nil)
+			eventBus.PublishEventNewBlock(types.EventDataNewBlock{Block: last})
+		}
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event bus held up by the indexer")
+	}
+
+	close(db.release)
+	for i := 0; i < 500 && ai.Height() < last.Height; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, last.Height, ai.Height())
+}
@@ -0,0 +1,224 @@
+package blockchain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+/*
+An export file is a header followed by one record per block, in height order.
+Every entry is a uvarint length prefix and the ser encoding of the entry:
+
+	| ExportHeader | ExportedBlock (first) | ... | ExportedBlock (last) |
+
+The file may be gzip compressed as a whole.
+*/
+
+const (
+	// ExportMagic starts every export file.
+	ExportMagic = "linkchain-export"
+
+	// ExportVersion is the version of the export file format written by this package.
+	ExportVersion uint32 = 1
+
+	// an exported block holds the block, its commit and receipts
+	maxExportEntrySize = 4 * types.MaxBlockSizeBytes
+)
+
+var (
+	// ErrExportFormat is returned when reading a file not written by ExportChain.
+	ErrExportFormat = errors.New("not a linkchain export file")
+)
+
+// ExportHeader describes the blocks of an export file.
+type ExportHeader struct {
+	Magic   string
+	Version uint32
+	ChainID string
+	First   uint64
+	Last    uint64
+}
+
+// ExportedBlock is a block with everything the block store keeps of it.
+// Commit holds the +2/3 precommits of the block.
+type ExportedBlock struct {
+	Block          *types.Block
+	Commit         *types.Commit
+	Receipts       []*types.ReceiptForStorage
+	TxsResult      *types.TxsResult
+	BalanceRecords *types.BlockBalanceRecords
+}
+
+// GetReceipts returns the receipts of the exported block.
+func (eb *ExportedBlock) GetReceipts() *types.Receipts {
+	receipts := make(types.Receipts, len(eb.Receipts))
+	for i, r := range eb.Receipts {
+		receipts[i] = r.ToReceipt()
+	}
+	return &receipts
+}
+
+// ExportChain writes the blocks from first to last into w, progress is called
+// after each block is written.
+// records may be nil if balance records are not saved.
+func ExportChain(w io.Writer, bs *BlockStore, records *BalanceRecordStore, chainID string, first, last uint64, progress func(height uint64)) error {
+	if first > last || last > bs.Height() {
+		return fmt.Errorf("invalid export range [%d, %d], block store height %d", first, last, bs.Height())
+	}
+	bw := bufio.NewWriter(w)
+	header := &ExportHeader{
+		Magic:   ExportMagic,
+		Version: ExportVersion,
+		ChainID: chainID,
+		First:   first,
+		Last:    last,
+	}
+	if err := writeExportEntry(bw, header); err != nil {
+		return err
+	}
+
+	for height := first; height <= last; height++ {
+		eb, err := loadExportedBlock(bs, records, height)
+		if err != nil {
+			return err
+		}
+		if err := writeExportEntry(bw, eb); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(height)
+		}
+	}
+	return bw.Flush()
+}
+
+func loadExportedBlock(bs *BlockStore, records *BalanceRecordStore, height uint64) (*ExportedBlock, error) {
+	block := bs.LoadBlock(height)
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+	// the commit of the top block is only known from the seen commit
+	commit := bs.LoadBlockCommit(height)
+	if commit == nil {
+		commit = bs.LoadSeenCommit(height)
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("commit of block %d not found", height)
+	}
+	txsResult, err := bs.LoadTxsResult(height)
+	if err != nil {
+		return nil, fmt.Errorf("txs result of block %d: %v", height, err)
+	}
+
+	eb := &ExportedBlock{
+		Block:     block,
+		Commit:    commit,
+		Receipts:  make([]*types.ReceiptForStorage, 0),
+		TxsResult: txsResult,
+	}
+	if receipts := bs.GetReceipts(height); receipts != nil {
+		for _, r := range *receipts {
+			eb.Receipts = append(eb.Receipts, r.ForStorage())
+		}
+	}
+	if records != nil {
+		eb.BalanceRecords = records.Get(height)
+	}
+	return eb, nil
+}
+
+func writeExportEntry(w io.Writer, entry interface{}) error {
+	bz, err := ser.EncodeToBytes(entry)
+	if err != nil {
+		return err
+	}
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(bz)))
+	if _, err := w.Write(size[:n]); err != nil {
+		return err
+	}
+	_, err = w.Write(bz)
+	return err
+}
+
+// ChainReader reads the blocks of an export file.
+type ChainReader struct {
+	r      *bufio.Reader
+	header *ExportHeader
+	next   uint64
+}
+
+// NewChainReader reads the header of the export file in r.
+func NewChainReader(r io.Reader) (*ChainReader, error) {
+	cr := &ChainReader{r: bufio.NewReader(r)}
+	header := &ExportHeader{}
+	if err := cr.readEntry(header); err != nil {
+		if err == io.EOF {
+			err = ErrExportFormat
+		}
+		return nil, err
+	}
+	if header.Magic != ExportMagic {
+		return nil, ErrExportFormat
+	}
+	if header.Version != ExportVersion {
+		return nil, fmt.Errorf("unsupported export file version %d, want %d", header.Version, ExportVersion)
+	}
+	if header.First > header.Last {
+		return nil, fmt.Errorf("invalid export range [%d, %d]", header.First, header.Last)
+	}
+	cr.header = header
+	cr.next = header.First
+	return cr, nil
+}
+
+// Header returns the header of the export file.
+func (cr *ChainReader) Header() *ExportHeader {
+	return cr.header
+}
+
+// Next returns the next block of the export file, or io.EOF after the last one.
+func (cr *ChainReader) Next() (*ExportedBlock, error) {
+	if cr.next > cr.header.Last {
+		return nil, io.EOF
+	}
+	eb := &ExportedBlock{}
+	if err := cr.readEntry(eb); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if eb.Block == nil || eb.Block.Header == nil || eb.Commit == nil || eb.TxsResult == nil {
+		return nil, fmt.Errorf("incomplete block %d in export file", cr.next)
+	}
+	if eb.Block.Height != cr.next {
+		return nil, fmt.Errorf("unexpected block %d in export file, want %d", eb.Block.Height, cr.next)
+	}
+	cr.next++
+	return eb, nil
+}
+
+func (cr *ChainReader) readEntry(entry interface{}) error {
+	size, err := binary.ReadUvarint(cr.r)
+	if err != nil {
+		return err
+	}
+	if size > maxExportEntrySize {
+		return fmt.Errorf("export entry exceeds max size (%d > %d)", size, maxExportEntrySize)
+	}
+	bz := make([]byte, size)
+	if _, err := io.ReadFull(cr.r, bz); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return ser.DecodeBytes(bz, entry)
+}
@@ -0,0 +1,63 @@
+package blockchain
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+func TestExportChain(t *testing.T) {
+	bs, _ := freshBlockStore()
+	records := NewBalanceRecordStore(db.NewMemDB(), true)
+	for h := uint64(1); h <= 5; h++ {
+		block := makeBlock(h)
+		seenCommit := &types.Commit{Precommits: []*types.Vote{{Height: h,
+			Timestamp: time.Now().UTC()}}}
+		bs.SaveBlock(block, block.MakePartSet(2), seenCommit, &types.Receipts{}, &types.TxsResult{TrieRoot: common.BytesToHash([]byte{byte(h)})})
+		records.Save(h, &types.BlockBalanceRecords{BlockHash: block.Hash(), BlockTime: h})
+	}
+
+	var buf bytes.Buffer
+	exported := make([]uint64, 0)
+	err := ExportChain(&buf, bs, records, "test-chain", 2, 5, func(h uint64) { exported = append(exported, h) })
+	require.Nil(t, err)
+	assert.Equal(t, []uint64{2, 3, 4, 5}, exported)
+
+	cr, err := NewChainReader(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	assert.Equal(t, "test-chain", cr.Header().ChainID)
+	assert.Equal(t, uint64(2), cr.Header().First)
+	assert.Equal(t, uint64(5), cr.Header().Last)
+	for h := uint64(2); h <= 5; h++ {
+		eb, err := cr.Next()
+		require.Nil(t, err, "block %d", h)
+		assert.Equal(t, bs.LoadBlock(h).Hash(), eb.Block.Hash())
+		assert.Equal(t, common.BytesToHash([]byte{byte(h)}), eb.TxsResult.TrieRoot)
+		assert.Equal(t, h, eb.BalanceRecords.BlockTime)
+		assert.NotNil(t, eb.Commit)
+	}
+	_, err = cr.Next()
+	assert.Equal(t, io.EOF, err)
+
+	// truncated files are reported
+	cr, err = NewChainReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	require.Nil(t, err)
+	for err == nil {
+		_, err = cr.Next()
+	}
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	_, err = NewChainReader(bytes.NewReader(nil))
+	assert.Equal(t, ErrExportFormat, err)
+
+	err = ExportChain(&buf, bs, nil, "test-chain", 4, 6, nil)
+	assert.NotNil(t, err, "expecting an error exporting above the height")
+}
@@ -376,6 +376,21 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.db.SetSync(nil, nil)
 }
 
+// SaveSnapshotBlock persists a block fetched by state sync as the new top of the store.
+// The blocks between the current height and the snapshot block are not available.
+func (bs *BlockStore) SaveSnapshotBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit, receipts *types.Receipts, txsResult *types.TxsResult) {
+	if block == nil {
+		cmn.PanicSanity("BlockStore can only save a non-nil block")
+	}
+	if block.Height <= bs.Height() {
+		cmn.PanicSanity(cmn.Fmt("BlockStore can only save a snapshot above its height. Height %v, got %v", bs.Height(), block.Height))
+	}
+	bs.mtx.Lock()
+	bs.height = block.Height - 1
+	bs.mtx.Unlock()
+	bs.SaveBlock(block, blockParts, seenCommit, receipts, txsResult)
+}
+
 func (bs *BlockStore) saveBlockPart(height uint64, index int, part *types.Part, bsBatch dbm.Batch) {
 	//if height > types.BlockHeightZero && height != bs.Height()+1 {
 	//	cmn.PanicSanity(cmn.Fmt("BlockStore can only save contiguous blocks. Wanted %v, got %v", bs.Height()+1, height))
@@ -298,6 +298,31 @@ func TestBlockStoreSaveLoadBlock(t *testing.T) {
 
 }
 
+func TestBlockStoreSaveSnapshotBlock(t *testing.T) {
+	bs, _ := freshBlockStore()
+
+	block := makeBlock(100)
+	seenCommit := &types.Commit{Precommits: []*types.Vote{{Height: 100,
+		Timestamp: time.Now().UTC()}}}
+	bs.SaveSnapshotBlock(block, block.MakePartSet(2), seenCommit, nil, &types.TxsResult{TrieRoot: common.HexToHash("0x1")})
+	require.Equal(t, uint64(100), bs.Height(), "expecting the snapshot height")
+	require.Equal(t, block.Hash(), bs.LoadBlock(100).Hash(), "snapshot block not loaded")
+	require.Nil(t, bs.LoadBlock(99), "expecting no block below the snapshot")
+	txsResult, err := bs.LoadTxsResult(100)
+	require.Nil(t, err)
+	require.Equal(t, common.HexToHash("0x1"), txsResult.TrieRoot)
+
+	next := makeBlock(101)
+	bs.SaveBlock(next, next.MakePartSet(2), seenCommit, nil, &types.TxsResult{})
+	require.Equal(t, uint64(101), bs.Height(), "expecting blocks to continue from the snapshot")
+
+	_, _, panicErr := doFn(func() (interface{}, error) {
+		bs.SaveSnapshotBlock(block, block.MakePartSet(2), seenCommit, nil, &types.TxsResult{})
+		return nil, nil
+	})
+	require.NotNil(t, panicErr, "expecting a panic on a snapshot below the height")
+}
+
 func TestBlockStoreTxs(t *testing.T) {
 	_, bs := initializeValidatorState(0)
 	require.Equal(t, bs.Height(), uint64(0), "initially the height should be zero")
@@ -0,0 +1,87 @@
+// Command abigen generates the Go bindings of a contract, from the Solidity ABI
+// of an EVM contract or the interface of a tc-wasm contract.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi/bind"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+)
+
+var (
+	abiFlag  = flag.String("abi", "", "Path to the Solidity ABI json or the tc-wasm interface json to bind (- for STDIN)")
+	binFlag  = flag.String("bin", "", "Path to the hex bytecode of the EVM contract, or the .wasm code of the tc-wasm contract, to generate a deploy method")
+	typeFlag = flag.String("type", "", "Go struct name for the binding (default = file name of the abi)")
+	wasmFlag = flag.Bool("wasm", false, "Bind a tc-wasm contract instead of a Solidity contract")
+	pkgFlag  = flag.String("pkg", "", "Package name to generate the binding into")
+	outFlag  = flag.String("out", "", "Output file for the generated binding (default = stdout)")
+)
+
+func main() {
+	flag.Parse()
+
+	if *abiFlag == "" {
+		fatalf("No contract ABI specified (--abi)")
+	}
+	if *pkgFlag == "" {
+		fatalf("No destination package specified (--pkg)")
+	}
+
+	var (
+		abi []byte
+		err error
+	)
+	if *abiFlag == "-" {
+		abi, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		abi, err = ioutil.ReadFile(*abiFlag)
+	}
+	if err != nil {
+		fatalf("Failed to read input ABI: %v", err)
+	}
+
+	var bin []byte
+	if *binFlag != "" {
+		if bin, err = ioutil.ReadFile(*binFlag); err != nil {
+			fatalf("Failed to read input bytecode: %v", err)
+		}
+	}
+	kind, code := bind.KindEVM, string(bin)
+	if *wasmFlag {
+		kind = bind.KindWASM
+		if len(bin) > 0 {
+			code = hexutil.Encode(bin)
+		}
+	}
+
+	kindName := *typeFlag
+	if kindName == "" {
+		if *abiFlag == "-" {
+			fatalf("No type name specified (--type) for the ABI read from STDIN")
+		}
+		kindName = strings.TrimSuffix(filepath.Base(*abiFlag), filepath.Ext(*abiFlag))
+	}
+
+	source, err := bind.Bind([]string{kindName}, []string{string(abi)}, []string{code}, *pkgFlag, kind)
+	if err != nil {
+		fatalf("Failed to generate ABI binding: %v", err)
+	}
+	if *outFlag == "" {
+		fmt.Printf("%s\n", source)
+		return
+	}
+	if err := ioutil.WriteFile(*outFlag, []byte(source), 0644); err != nil {
+		fatalf("Failed to write ABI binding: %v", err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/spf13/cobra"
+)
+
+// DBCmd groups the database maintenance commands.
+var DBCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database maintenance commands",
+}
+
+// DBMigrateCmd copies the databases of the node to another backend.
+var DBMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy the databases of the node to another db backend",
+	Long: `Copy every database of the node from the --from backend into a new
+--to_path directory with the --to backend, and verify the key counts and
+checksums of each copy. An interrupted migration resumes from its checkpoint
+when run again with the same arguments. The node must not be running.`,
+	RunE: migrateDB,
+}
+
+func init() {
+	DBMigrateCmd.Flags().String("from", "", "db backend of the node, defaults to db_backend")
+	DBMigrateCmd.Flags().String("to", "", "db backend to migrate to")
+	DBMigrateCmd.Flags().String("to_path", "", "directory of the migrated databases, defaults to the db directory suffixed with the backend")
+	DBMigrateCmd.Flags().Uint64("to_counts", 0, "db counts of the migrated databases, defaults to db_counts")
+	DBCmd.AddCommand(DBMigrateCmd)
+}
+
+// migrateDBs are the databases opened by the node, with the backend of those
+// opened with a fixed one.
+var migrateDBs = []struct {
+	name    string
+	backend dbm.DBBackendType
+}{
+	{"blockstore", ""},
+	{"balance_record", ""},
+	{"txmgr", ""},
+	{"consensus_state", ""},
+	{"state", ""},
+	{"evidence", ""},
+	{"address_index", ""},
+	{"utxo", ""},
+	{"utxo_output", dbm.BoltBackend},
+	{"utxo_output_token", ""},
+	{"p2p", ""},
+}
+
+const migrateCheckpointFile = "migrate_checkpoint.json"
+
+// migrateCheckpoint records the progress of a migration in its destination directory.
+type migrateCheckpoint struct {
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Source  string       `json:"source"`
+	Done    []string     `json:"done"`
+	Current string       `json:"current"`
+	LastKey cmn.HexBytes `json:"last_key"`
+	Count   uint64       `json:"count"`
+	Updated time.Time    `json:"updated"`
+	path    string
+}
+
+func loadMigrateCheckpoint(path string) (*migrateCheckpoint, error) {
+	cp := &migrateCheckpoint{path: path}
+	bz, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bz, cp); err != nil {
+		return nil, fmt.Errorf("invalid migration checkpoint %s: %v", path, err)
+	}
+	return cp, nil
+}
+
+func (cp *migrateCheckpoint) save() error {
+	cp.Updated = time.Now()
+	bz, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(cp.path, bz, 0644)
+}
+
+func (cp *migrateCheckpoint) isDone(name string) bool {
+	for _, done := range cp.Done {
+		if done == name {
+			return true
+		}
+	}
+	return false
+}
+
+func migrateDB(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	toPath, _ := cmd.Flags().GetString("to_path")
+	toCounts, _ := cmd.Flags().GetUint64("to_counts")
+	if from == "" {
+		from = config.DBBackend
+	}
+	if to == "" {
+		return fmt.Errorf("missing the db backend to migrate to")
+	}
+	if toPath == "" {
+		toPath = config.DBDir() + "_" + to
+	}
+	if toCounts == 0 {
+		toCounts = config.DBCounts
+	}
+	srcPath, err := filepath.Abs(config.DBDir())
+	if err != nil {
+		return err
+	}
+	if toPath, err = filepath.Abs(toPath); err != nil {
+		return err
+	}
+	if srcPath == toPath {
+		return fmt.Errorf("the migrated databases must be in another directory than %s", srcPath)
+	}
+	if err := cmn.EnsureDir(toPath, 0700); err != nil {
+		return err
+	}
+
+	cp, err := loadMigrateCheckpoint(filepath.Join(toPath, migrateCheckpointFile))
+	if err != nil {
+		return err
+	}
+	if cp.Source == "" {
+		cp.From, cp.To, cp.Source = from, to, srcPath
+	} else if cp.From != from || cp.To != to || cp.Source != srcPath {
+		return fmt.Errorf("%s holds a migration of %s from %s to %s", toPath, cp.Source, cp.From, cp.To)
+	}
+
+	start := time.Now()
+	for _, mdb := range migrateDBs {
+		if cp.isDone(mdb.name) {
+			logger.Info("Database already migrated", "db", mdb.name)
+			continue
+		}
+		srcBackend, dstBackend := dbm.DBBackendType(from), dbm.DBBackendType(to)
+		if mdb.backend != "" {
+			srcBackend, dstBackend = mdb.backend, mdb.backend
+		}
+		if err := migrateOneDB(cp, mdb.name, srcBackend, srcPath, dstBackend, toPath, toCounts); err != nil {
+			return fmt.Errorf("migration of %s failed: %v", mdb.name, err)
+		}
+	}
+
+	logger.Info("Migration done", "to", to, "to_path", toPath, "elapsed", time.Since(start))
+	fmt.Printf("Migration done, set db_backend = \"%s\", db_path = \"%s\" and db_counts = %d to run the node on the migrated databases\n",
+		to, toPath, toCounts)
+	return nil
+}
+
+func migrateOneDB(cp *migrateCheckpoint, name string, srcBackend dbm.DBBackendType, srcPath string,
+	dstBackend dbm.DBBackendType, dstPath string, dstCounts uint64) error {
+
+	src := dbm.NewDB(name, srcBackend, srcPath, config.DBCounts)
+	defer src.Close()
+	dst := dbm.NewDB(name, dstBackend, dstPath, dstCounts)
+	defer dst.Close()
+
+	var (
+		startKey []byte
+		copied   uint64
+	)
+	if cp.Current == name && cp.LastKey != nil {
+		startKey = dbm.NextKey(cp.LastKey)
+		copied = cp.Count
+		logger.Info("Resuming database migration", "db", name, "copied", copied, "lastKey", cp.LastKey)
+	} else {
+		cp.Current, cp.LastKey, cp.Count = name, nil, 0
+		logger.Info("Migrating database", "db", name, "from", srcBackend, "to", dstBackend)
+	}
+
+	lastReport := time.Now()
+	_, err := dbm.CopyDB(src, dst, startKey, func(lastKey []byte, count uint64) error {
+		cp.LastKey = lastKey
+		cp.Count = copied + count
+		if time.Since(lastReport) > 10*time.Second {
+			logger.Info("Migrating database", "db", name, "copied", cp.Count)
+			lastReport = time.Now()
+		}
+		return cp.save()
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := dbm.VerifyCopy(src, dst); err != nil {
+		return err
+	}
+	logger.Info("Database migrated", "db", name, "keys", cp.Count)
+
+	cp.Done = append(cp.Done, name)
+	cp.Current, cp.LastKey, cp.Count = "", nil, 0
+	return cp.save()
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	bc "github.com/lianxiangcloud/linkchain/blockchain"
+	cs "github.com/lianxiangcloud/linkchain/consensus"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/spf13/cobra"
+)
+
+// number of blocks between two progress logs of export and import
+const chainProgressInterval = 1000
+
+// ExportCmd writes a range of blocks to a file.
+var ExportCmd = &cobra.Command{
+	Use:   "export <filename>",
+	Short: "Export blocks with their commits, receipts and results to a file",
+	Long: `Export the blocks from --from to --to into a file that can be imported by
+another node with the import command. The file is gzip compressed if its
+name ends with ".gz". The node must not be running.`,
+	Args: cobra.ExactArgs(1),
+	RunE: exportChain,
+}
+
+func init() {
+	ExportCmd.Flags().Uint64("from", 0, "first block to export, defaults to the first block after genesis")
+	ExportCmd.Flags().Uint64("to", 0, "last block to export, defaults to the latest block")
+}
+
+func exportChain(cmd *cobra.Command, args []string) error {
+	blockStoreDB := dbm.NewDB("blockstore", dbm.DBBackendType(config.DBBackend), config.DBDir(), config.DBCounts)
+	defer blockStoreDB.Close()
+
+	balanceRecordDB := dbm.NewDB("balance_record", dbm.DBBackendType(config.DBBackend), config.DBDir(), config.DBCounts)
+	defer balanceRecordDB.Close()
+
+	statusDB := dbm.NewDB("consensus_state", dbm.DBBackendType(config.DBBackend), config.DBDir(), config.DBCounts)
+	defer statusDB.Close()
+
+	blockStore := bc.NewBlockStore(blockStoreDB)
+	initHeight, err := blockStore.LoadInitHeight()
+	if err != nil {
+		return err
+	}
+	types.UpdateBlockHeightZero(initHeight)
+
+	status, err := cs.LoadStatus(statusDB)
+	if err != nil {
+		return err
+	}
+
+	var records *bc.BalanceRecordStore
+	if config.SaveBalanceRecord {
+		records = bc.NewBalanceRecordStore(balanceRecordDB, true)
+	}
+
+	first, _ := cmd.Flags().GetUint64("from")
+	last, _ := cmd.Flags().GetUint64("to")
+	if first == 0 {
+		first = types.BlockHeightOne
+	}
+	if last == 0 {
+		last = blockStore.Height()
+	}
+
+	fh, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	var (
+		w  io.Writer = fh
+		gz *gzip.Writer
+	)
+	if strings.HasSuffix(args[0], ".gz") {
+		gz = gzip.NewWriter(fh)
+		w = gz
+	}
+
+	logger.Info("Exporting blocks", "file", args[0], "from", first, "to", last)
+	start := time.Now()
+	err = bc.ExportChain(w, blockStore, records, status.ChainID, first, last, func(height uint64) {
+		if (height-first+1)%chainProgressInterval == 0 {
+			logger.Info("Exporting blocks", "height", height, "to", last, "elapsed", time.Since(start))
+		}
+	})
+	// closing flushes the gzip footer and the file, their errors leave a truncated export
+	if gz != nil {
+		if cerr := gz.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := fh.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("export failed: %v", err)
+	}
+	logger.Info("Exported blocks", "file", args[0], "from", first, "to", last, "elapsed", time.Since(start))
+	return nil
+}
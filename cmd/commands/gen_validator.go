@@ -4,14 +4,17 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/lianxiangcloud/linkchain/accounts/keystore"
 	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
 	"github.com/lianxiangcloud/linkchain/libs/ser"
 	"github.com/lianxiangcloud/linkchain/types"
 )
 
 type keys struct {
-	Pubkey string `json:"pubkey"`
-	Prikey string `json:"prikey"`
+	Pubkey          string               `json:"pubkey"`
+	Prikey          string               `json:"prikey,omitempty"`
+	EncryptedPrikey *crypto.EncryptedKey `json:"encrypted_prikey,omitempty"`
 }
 
 // GenValidatorCmd allows the generation of a keypair for a
@@ -19,14 +22,28 @@ type keys struct {
 var GenValidatorCmd = &cobra.Command{
 	Use:   "gen_validator",
 	Short: "Generate new validator keypair",
-	Run:   genValidator,
+	RunE:  genValidator,
 }
 
-func genValidator(cmd *cobra.Command, args []string) {
+func init() {
+	GenValidatorCmd.Flags().Bool("encrypt", false, "print the private key encrypted with a passphrase")
+}
+
+func genValidator(cmd *cobra.Command, args []string) error {
 	pv := types.GenFilePV("")
 	key := keys{
 		Pubkey: common.ToHex(pv.PubKey.Bytes()),
-		Prikey: common.ToHex(pv.PrivKey.Bytes()),
+	}
+	if encrypt, _ := cmd.Flags().GetBool("encrypt"); encrypt {
+		passphrase, err := newPrivValidatorPassphrase()
+		if err != nil {
+			return err
+		}
+		if key.EncryptedPrikey, err = crypto.EncryptKey(pv.PrivKey, passphrase, keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
+			return err
+		}
+	} else {
+		key.Prikey = common.ToHex(pv.PrivKey.Bytes())
 	}
 
 	data, err := ser.MarshalJSONIndent(key, "", "  ")
@@ -35,4 +52,5 @@ func genValidator(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Printf("%v", string(data))
+	return nil
 }
@@ -0,0 +1,302 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/app"
+	bc "github.com/lianxiangcloud/linkchain/blockchain"
+	cfg "github.com/lianxiangcloud/linkchain/config"
+	cs "github.com/lianxiangcloud/linkchain/consensus"
+	"github.com/lianxiangcloud/linkchain/evidence"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/libs/txmgr"
+	mempl "github.com/lianxiangcloud/linkchain/mempool"
+	"github.com/lianxiangcloud/linkchain/metrics"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
+	"github.com/spf13/cobra"
+)
+
+// ImportCmd executes the blocks of an export file.
+var ImportCmd = &cobra.Command{
+	Use:   "import <filename>",
+	Short: "Import blocks from a file written by the export command",
+	Long: `Import the blocks of an export file on top of the local chain.
+Every block is verified against the commit of the validators and executed
+again, the import stops at the first block that fails. If save_balance_record
+is on, the balance records of the executed blocks are compared with the ones
+of the export file, otherwise the exported ones are dropped. The node must be
+initialized and must not be running.`,
+	Args: cobra.ExactArgs(1),
+	RunE: importChain,
+}
+
+// chainImporter holds the stores and the application the blocks are
+// committed to, as they are set up by the node.
+type chainImporter struct {
+	app            *app.LinkApplication
+	blockStore     *bc.BlockStore
+	balanceRecords *bc.BalanceRecordStore
+	blockExec      *cs.BlockExecutor
+	status         cs.NewStatus
+
+	dbs []dbm.DB
+}
+
+func newChainImporter() (*chainImporter, error) {
+	ci := &chainImporter{}
+	newDB := func(name string, backend string) dbm.DB {
+		db := dbm.NewDB(name, dbm.DBBackendType(backend), config.DBDir(), config.DBCounts)
+		ci.dbs = append(ci.dbs, db)
+		return db
+	}
+
+	blockStore := bc.NewBlockStore(newDB("blockstore", config.DBBackend))
+	initHeight, err := blockStore.LoadInitHeight()
+	if err != nil {
+		ci.close()
+		return nil, err
+	}
+	types.UpdateBlockHeightZero(initHeight)
+
+	balanceRecord := bc.NewBalanceRecordStore(newDB("balance_record", config.DBBackend), config.SaveBalanceRecord)
+	types.SaveBalanceRecord = config.SaveBalanceRecord
+
+	txService := txmgr.NewCrossState(newDB("txmgr", config.DBBackend), blockStore)
+	txService.SetLogger(logger.With("module", "txmgr"))
+	blockStore.SetCrossState(txService)
+
+	// no block is proposed here, the keys of the node are not loaded
+	metrics.PrometheusMetricInstance.Init(config, nil, logger.With("module", "prometheus_metrics"))
+
+	statusDB := newDB("consensus_state", config.DBBackend)
+	status, err := cs.LoadStatus(statusDB)
+	if err != nil {
+		ci.close()
+		return nil, err
+	}
+
+	evidenceDB := newDB("evidence", config.DBBackend)
+	evidencePool := evidence.NewEvidencePool(statusDB, evidence.NewEvidenceStore(evidenceDB), status.Copy())
+	evidencePool.SetLogger(logger.With("module", "evidence"))
+	types.BlacklistInstance.Init(evidenceDB)
+
+	utxoStore := utxo.NewUtxoStore(newDB("utxo", config.DBBackend), newDB("utxo_output", "bolt"), newDB("utxo_output_token", config.DBBackend))
+	utxoStore.SetLogger(logger.With("module", "utxoStore"))
+
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(logger.With("module", "events"))
+	if err := eventBus.Start(); err != nil {
+		ci.close()
+		return nil, err
+	}
+
+	stateRetention, err := config.StateRetentionMode()
+	if err != nil {
+		ci.close()
+		return nil, err
+	}
+	appHandle, err := app.NewLinkApplication(newDB("state", config.DBBackend), blockStore, utxoStore, txService, eventBus,
+		stateRetention != cfg.StateRetentionKV, balanceRecord, app.SetPoceeds, app.AllocAward)
+	if err != nil {
+		ci.close()
+		return nil, err
+	}
+	appHandle.SetLogger(logger.With("module", "app"))
+	if err := appHandle.SetForks(status.ConsensusParams.Forks); err != nil {
+		ci.close()
+		return nil, fmt.Errorf("%v, state_retention must not be %s", err, cfg.StateRetentionKV)
+	}
+	if stateRetention == cfg.StateRetentionPruned {
+		if err := appHandle.SetStatePruning(config.StateRetentionBlocks); err != nil {
+			ci.close()
+			return nil, err
+		}
+	}
+
+	if status.LastBlockHeight != appHandle.Height() {
+		ci.close()
+		return nil, fmt.Errorf("consensus status at height %d, application at height %d: start the node once to recover it",
+			status.LastBlockHeight, appHandle.Height())
+	}
+
+	mempool := mempl.NewMempool(config.Mempool, status.LastBlockHeight, nil)
+	mempool.SetLogger(logger.With("module", "mempool"))
+	mempool.SetApp(appHandle)
+	appHandle.SetMempool(mempool)
+
+	ci.app = appHandle
+	ci.blockStore = blockStore
+	ci.balanceRecords = balanceRecord
+	ci.blockExec = cs.NewBlockExecutor(statusDB, logger.With("module", "consensus"), evidencePool)
+	ci.status = status
+	return ci, nil
+}
+
+func (ci *chainImporter) close() {
+	for _, db := range ci.dbs {
+		db.Close()
+	}
+}
+
+// importBlock verifies the block the way fast sync does and commits it.
+func (ci *chainImporter) importBlock(eb *bc.ExportedBlock) error {
+	block := eb.Block
+	if block.Height != ci.app.Height()+1 {
+		return fmt.Errorf("block %d does not follow the local chain at height %d", block.Height, ci.app.Height())
+	}
+	if block.ChainID != ci.status.ChainID {
+		return fmt.Errorf("block %d of chain %s, want %s", block.Height, block.ChainID, ci.status.ChainID)
+	}
+
+	blockParts := block.MakePartSet(ci.status.ConsensusParams.BlockGossip.BlockPartSizeBytes)
+	blockID := types.BlockID{Hash: block.Hash(), PartsHeader: blockParts.Header()}
+	if block.Recover > 0 {
+		ci.status.Validators = types.NewValidatorSet(ci.app.GetRecoverValidators(block.Height - 1))
+	}
+	if err := ci.status.Validators.VerifyCommit(ci.status.ChainID, blockID, block.Height, eb.Commit); err != nil {
+		return fmt.Errorf("invalid commit of block %d: %v", block.Height, err)
+	}
+	if eb.BalanceRecords != nil && eb.BalanceRecords.BlockHash != block.Hash() {
+		return fmt.Errorf("balance records of block %d for block %v", block.Height, eb.BalanceRecords.BlockHash)
+	}
+	if !ci.app.CheckBlock(block) {
+		return fmt.Errorf("block %d failed the application checks", block.Height)
+	}
+
+	validators, err := ci.app.CommitBlock(block, blockParts, eb.Commit, true)
+	if err != nil {
+		return fmt.Errorf("commit of block %d failed: %v", block.Height, err)
+	}
+	oldHeight := ci.status.LastHeightValidatorsChanged
+	ci.status, err = ci.blockExec.ApplyBlock(ci.status, blockID, block, validators)
+	if err != nil {
+		return fmt.Errorf("failed to process committed block %d: %v", block.Height, err)
+	}
+	if ci.status.LastHeightValidatorsChanged > oldHeight {
+		ci.app.SetLastChangedVals(ci.status.LastHeightValidatorsChanged, ci.status.Validators.Copy().Validators)
+	}
+	if block.Recover > 0 {
+		ci.status.LastRecover = true
+	}
+
+	// the state root is not in the header, compare it with the exporting node
+	txsResult, err := ci.blockStore.LoadTxsResult(block.Height)
+	if err != nil {
+		return err
+	}
+	if txsResult.TrieRoot != eb.TxsResult.TrieRoot {
+		return fmt.Errorf("state root of block %d mismatch, exported %v, got %v", block.Height, eb.TxsResult.TrieRoot, txsResult.TrieRoot)
+	}
+
+	return ci.checkBalanceRecords(block.Height, eb.BalanceRecords)
+}
+
+// checkBalanceRecords compares the exported balance records of the block with the
+// ones saved as it was executed, if the node keeps them.
+func (ci *chainImporter) checkBalanceRecords(height uint64, exported *types.BlockBalanceRecords) error {
+	if exported == nil || !config.SaveBalanceRecord {
+		return nil
+	}
+	want, err := ser.EncodeToBytes(exported)
+	if err != nil {
+		return err
+	}
+	got, err := ser.EncodeToBytes(ci.balanceRecords.Get(height))
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(want, got) {
+		return fmt.Errorf("balance records of block %d mismatch the exported ones", height)
+	}
+	return nil
+}
+
+func importChain(cmd *cobra.Command, args []string) error {
+	fh, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	r, err := openExportFile(fh)
+	if err != nil {
+		return err
+	}
+	cr, err := bc.NewChainReader(r)
+	if err != nil {
+		return err
+	}
+	header := cr.Header()
+
+	ci, err := newChainImporter()
+	if err != nil {
+		return err
+	}
+	defer ci.close()
+
+	if header.ChainID != ci.status.ChainID {
+		return fmt.Errorf("export file of chain %s, want %s", header.ChainID, ci.status.ChainID)
+	}
+	height := ci.app.Height()
+	if header.Last <= height {
+		logger.Info("Nothing to import", "height", height, "from", header.First, "to", header.Last)
+		return nil
+	}
+	if header.First > height+1 {
+		return fmt.Errorf("export file starts at block %d, local chain at height %d", header.First, height)
+	}
+
+	logger.Info("Importing blocks", "file", args[0], "from", height+1, "to", header.Last)
+	var (
+		start      = time.Now()
+		lastReport = start
+		imported   = 0
+		skipped    = 0
+		lastRate   = 0.0
+		eb         *bc.ExportedBlock
+	)
+	for {
+		if eb, err = cr.Next(); err != nil {
+			break
+		}
+		if eb.Block.Height <= height {
+			skipped++
+			continue
+		}
+		if err = ci.importBlock(eb); err != nil {
+			break
+		}
+		imported++
+		if imported%chainProgressInterval == 0 {
+			lastRate = 0.9*lastRate + 0.1*(chainProgressInterval/time.Since(lastReport).Seconds())
+			logger.Info("Import Rate", "height", eb.Block.Height, "to", header.Last, "blocks/s", lastRate)
+			lastReport = time.Now()
+		}
+	}
+	if err != io.EOF {
+		return fmt.Errorf("import failed at height %d: %v", ci.app.Height()+1, err)
+	}
+	logger.Info("Imported blocks", "imported", imported, "skipped", skipped, "height", ci.app.Height(), "elapsed", time.Since(start))
+	return nil
+}
+
+// openExportFile returns a reader of the export file, decompressed if it is gzipped.
+func openExportFile(fh io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(fh)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
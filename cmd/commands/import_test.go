@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	bc "github.com/lianxiangcloud/linkchain/blockchain"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+func TestCheckBalanceRecords(t *testing.T) {
+	saveBalanceRecord := config.SaveBalanceRecord
+	config.SaveBalanceRecord = true
+	defer func() { config.SaveBalanceRecord = saveBalanceRecord }()
+
+	genRecords := func(amount int64) *types.BlockBalanceRecords {
+		tbr := types.NewTxBalanceRecords()
+		tbr.AddBalanceRecord(types.BalanceRecord{
+			From:   common.HexToAddress("0x1"),
+			To:     common.HexToAddress("0x2"),
+			Type:   types.TxTransfer,
+			Amount: big.NewInt(amount),
+		})
+		return &types.BlockBalanceRecords{TxRecords: []*types.TxBalanceRecords{tbr}, BlockHash: common.HexToHash("0x3"), BlockTime: 4}
+	}
+	ci := &chainImporter{balanceRecords: bc.NewBalanceRecordStore(dbm.NewMemDB(), true)}
+	ci.balanceRecords.Save(1, genRecords(10))
+
+	assert.Nil(t, ci.checkBalanceRecords(1, nil))
+	assert.Nil(t, ci.checkBalanceRecords(1, genRecords(10)))
+	assert.Error(t, ci.checkBalanceRecords(1, genRecords(11)))
+	assert.Error(t, ci.checkBalanceRecords(2, genRecords(10)))
+
+	// the exported records are dropped if the node keeps none
+	config.SaveBalanceRecord = false
+	assert.Nil(t, ci.checkBalanceRecords(1, genRecords(11)))
+}
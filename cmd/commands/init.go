@@ -14,11 +14,13 @@ import (
 	cc "github.com/lianxiangcloud/linkchain/contract/contractcodes"
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	lctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
 	dbm "github.com/lianxiangcloud/linkchain/libs/db"
 	"github.com/lianxiangcloud/linkchain/libs/log"
 	"github.com/lianxiangcloud/linkchain/libs/math"
 	"github.com/lianxiangcloud/linkchain/state"
 	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
 	"github.com/lianxiangcloud/linkchain/vm/evm"
 	"github.com/lianxiangcloud/linkchain/vm/wasm"
 	"github.com/spf13/cobra"
@@ -40,6 +42,7 @@ func init() {
 	InitFilesCmd.Flags().Uint64("db_counts", config.BaseConfig.DBCounts, "db counts")
 
 	InitFilesCmd.Flags().Bool("full_node", config.BaseConfig.FullNode, "light-weight node or full node")
+	InitFilesCmd.Flags().String("state_retention", config.BaseConfig.StateRetention, "state retention of a full node: archive | pruned | kv")
 	InitFilesCmd.Flags().Bool("save_balance_record", config.BaseConfig.SaveBalanceRecord, "open transactions record storage")
 
 	InitFilesCmd.Flags().String("init_state_root", config.BaseConfig.InitStateRoot, "init global state root")
@@ -53,6 +56,11 @@ var InitFilesCmd = &cobra.Command{
 }
 
 func initFiles(cmd *cobra.Command, args []string) error {
+	return initNodeFiles(config)
+}
+
+// initNodeFiles initialises the files of the node of config and its genesis block
+func initNodeFiles(config *cfg.Config) error {
 	// delete dir of data
 	//os.RemoveAll(config.DBDir())
 	types.UpdateBlockHeightZero(config.InitHeight)
@@ -79,12 +87,18 @@ func initFiles(cmd *cobra.Command, args []string) error {
 	}
 
 	// Make first block
+	var allocAccounts map[string]types.GenesisAccount
 	if !config.OnLine {
-		genDoc.AllocAccounts = types.GetTestAllocAccounts()
+		allocAccounts = types.GetTestAllocAccounts()
 	} else {
 		// TODO needCheck
-		genDoc.AllocAccounts = types.GetAllocAccounts()
+		allocAccounts = types.GetAllocAccounts()
+	}
+	// the accounts of the genesis file take precedence over the default ones
+	for addr, account := range genDoc.AllocAccounts {
+		allocAccounts[addr] = account
 	}
+	genDoc.AllocAccounts = allocAccounts
 
 	vals, err := createGenesisBlock(config, genDoc)
 	if err != nil {
@@ -223,7 +237,11 @@ func createGenesisBlock(config *cfg.Config, genDoc *types.GenesisDoc) ([]*types.
 
 	balanceRecordStore := bc.NewBalanceRecordStore(balanceRecordDB, config.SaveBalanceRecord)
 
-	isTrie := config.FullNode
+	stateRetention, err := config.StateRetentionMode()
+	if err != nil {
+		return nil, err
+	}
+	isTrie := stateRetention != cfg.StateRetentionKV
 	stateRoot := common.EmptyHash
 	if len(config.InitStateRoot) != 0 {
 		stateRoot = common.HexToHash(config.InitStateRoot)
@@ -237,13 +255,9 @@ func createGenesisBlock(config *cfg.Config, genDoc *types.GenesisDoc) ([]*types.
 	blockStore.SaveInitHeight(types.BlockHeightZero)
 	defaultParams := genDoc.ConsensusParams
 
-	for straddr, account := range genDoc.AllocAccounts {
-		addr := common.HexToAddress(straddr)
-		storeState.AddBalance(addr, account.Balance)
-		storeState.SetNonce(addr, account.Nonce)
-	}
+	app.AllocGenesisAccounts(storeState, genDoc.AllocAccounts)
 
-	vals, err := deployOriginalContract(storeState)
+	vals, err := deployOriginalContract(storeState, config.OnLine)
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +276,7 @@ func createGenesisBlock(config *cfg.Config, genDoc *types.GenesisDoc) ([]*types.
 	types.SaveBalanceRecord = config.SaveBalanceRecord
 	if len(contractData) > 0 && config.OnLine {
 		contextWasm := wasm.NewWASMContext(types.CopyHeader(header), blockStore, nil, config.WasmGasRate)
+		contextWasm.Forks = genDoc.ConsensusParams.Forks
 		wasm := wasm.NewWASM(contextWasm, storeState, evm.Config{EnablePreimageRecording: false})
 		for _, cData := range contractData {
 			sender, contractAddr := common.HexToAddress(cData.sender), common.HexToAddress(cData.contractAddr)
@@ -292,9 +307,12 @@ func createGenesisBlock(config *cfg.Config, genDoc *types.GenesisDoc) ([]*types.
 	}
 
 	storeState.Database().TrieDB().Commit(trieRoot, false)
-	txsResult := types.TxsResult{TrieRoot: trieRoot, StateHash: stateHash}
+	// the genesis block has no receipts, it commits to its utxo outputs instead
+	receiptHash := genDoc.UTXOOutputsHash()
+	txsResult := types.TxsResult{TrieRoot: trieRoot, StateHash: stateHash, ReceiptHash: receiptHash}
 
 	header.StateHash = stateHash
+	header.ReceiptHash = receiptHash
 	if time.Now().Unix() >= 1569409200 {
 		header.Time = uint64(1569409200)
 	}
@@ -305,6 +323,10 @@ func createGenesisBlock(config *cfg.Config, genDoc *types.GenesisDoc) ([]*types.
 		LastCommit: &types.Commit{},
 	}
 
+	if err := saveGenesisUTXOOutputs(config, genDoc.UTXOOutputs); err != nil {
+		return nil, err
+	}
+
 	fmt.Println("genesisBlock stateHash", stateHash.Hex())
 	fmt.Println("genesisBlock trieRoot", trieRoot.Hex())
 	fmt.Printf("genesisBlock ChainID:%v Height:%d block.Hash:%v\n", block.ChainID, block.Height, block.Hash().String())
@@ -318,6 +340,40 @@ func createGenesisBlock(config *cfg.Config, genDoc *types.GenesisDoc) ([]*types.
 	return vals, nil
 }
 
+// saveGenesisUTXOOutputs saves the utxo outputs of the genesis file as the outputs of the genesis block
+func saveGenesisUTXOOutputs(config *cfg.Config, outputs []types.GenesisUTXOOutput) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	utxoDB := dbm.NewDB("utxo", dbm.DBBackendType(config.DBBackend), config.DBDir(), config.DBCounts)
+	defer utxoDB.Close()
+
+	utxoOutputDB := dbm.NewDB("utxo_output", dbm.BoltBackend, config.DBDir(), config.DBCounts)
+	defer utxoOutputDB.Close()
+
+	utxoOutputTokenDB := dbm.NewDB("utxo_output_token", dbm.DBBackendType(config.DBBackend), config.DBDir(), config.DBCounts)
+	defer utxoOutputTokenDB.Close()
+
+	utxoStore := utxo.NewUtxoStore(utxoDB, utxoOutputDB, utxoOutputTokenDB)
+	utxoStore.SetLogger(logger.With("module", "utxoStore"))
+
+	utxoOutputs := make([]*types.UTXOOutputData, len(outputs))
+	for i, o := range outputs {
+		utxoOutputs[i] = &types.UTXOOutputData{
+			OTAddr:  lctypes.Key(o.OTAddr),
+			Height:  types.BlockHeightZero,
+			Commit:  lctypes.Key(o.Commit),
+			TokenID: o.TokenID,
+		}
+	}
+	if err := utxoStore.SaveUtxo(nil, utxoOutputs, types.BlockHeightZero); err != nil {
+		return fmt.Errorf("save genesis utxo outputs error:%v", err)
+	}
+	fmt.Println("genesis utxo outputs", len(utxoOutputs))
+	return nil
+}
+
 func createConsensusStatus(config *cfg.Config, genDoc *types.GenesisDoc) error {
 	if genDoc == nil {
 		return fmt.Errorf("Error create consensus state: genDoc is nil")
@@ -342,7 +398,7 @@ func isGenesisBlockExist(config *cfg.Config) bool {
 	return false
 }
 
-func deployOriginalContract(st *state.StateDB) ([]*types.Validator, error) {
+func deployOriginalContract(st *state.StateDB, onLine bool) ([]*types.Validator, error) {
 	if len(cc.CandidatesCodes) == 0 {
 		fmt.Println("candidates contract code nil!!!")
 	} else {
@@ -390,7 +446,7 @@ func deployOriginalContract(st *state.StateDB) ([]*types.Validator, error) {
 	}
 
 	var validatorsCode string
-	if config.OnLine {
+	if onLine {
 		if len(cc.ValidatorsCodesOnline) == 0 {
 			return nil, fmt.Errorf("Error:validators white list contract code nil in online mode")
 		}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/app"
+	cfg "github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/xcrypto"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
+)
+
+func TestAllocGenesisAccounts(t *testing.T) {
+	token := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	accounts := map[string]types.GenesisAccount{
+		"0x54fb1c7d0f011dd63b08f85ed7b518ab82028100": {
+			Balance: big.NewInt(100),
+			Nonce:   1,
+			Code:    common.HexBytes{0x60, 0x00},
+			Storage: map[string]common.HexBytes{"0x1": {0x2a}, "0x02": {0x2b}},
+			Tokens:  map[string]*big.Int{token.Hex(): big.NewInt(10)},
+		},
+		"0xa73810e519e1075010678d706533486d8ecc8000": {
+			Tokens: map[string]*big.Int{token.Hex(): big.NewInt(20)},
+		},
+	}
+
+	var root common.Hash
+	for i := 0; i < 5; i++ {
+		st, err := state.New(common.EmptyHash, state.NewDatabase(dbm.NewMemDB()))
+		require.NoError(t, err)
+		app.AllocGenesisAccounts(st, accounts)
+
+		addr := common.HexToAddress("0x54fb1c7d0f011dd63b08f85ed7b518ab82028100")
+		assert.Equal(t, big.NewInt(100), st.GetBalance(addr))
+		assert.Equal(t, uint64(1), st.GetNonce(addr))
+		assert.Equal(t, []byte{0x60, 0x00}, st.GetCode(addr))
+		assert.Equal(t, []byte{0x2a}, st.GetState(addr, common.BigToHash(big.NewInt(1))))
+		assert.Equal(t, []byte{0x2b}, st.GetState(addr, common.BigToHash(big.NewInt(2))))
+		assert.Equal(t, big.NewInt(10), st.GetTokenBalance(addr, token))
+		assert.Equal(t, big.NewInt(20), st.GetTokenBalance(common.HexToAddress("0xa73810e519e1075010678d706533486d8ecc8000"), token))
+
+		// the genesis state root does not depend on the order of the maps
+		if i == 0 {
+			root = st.IntermediateRoot(false)
+		} else {
+			assert.Equal(t, root, st.IntermediateRoot(false))
+		}
+	}
+}
+
+func TestSaveGenesisUTXOOutputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis_utxo")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	config := cfg.TestConfig().SetRoot(dir)
+	config.DBBackend = string(dbm.GoLevelDBBackend)
+
+	commit, err := xcrypto.ZeroCommit(100)
+	require.NoError(t, err)
+	token := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	outputs := []types.GenesisUTXOOutput{
+		{OTAddr: common.HexToHash("0x01"), Commit: common.Hash(commit)},
+		{OTAddr: common.HexToHash("0x02"), Commit: common.Hash(commit), TokenID: token},
+		{OTAddr: common.HexToHash("0x03"), Commit: common.Hash(commit)},
+	}
+	require.NoError(t, saveGenesisUTXOOutputs(config, outputs))
+
+	utxoDB := dbm.NewDB("utxo", dbm.GoLevelDBBackend, config.DBDir(), config.DBCounts)
+	defer utxoDB.Close()
+	utxoOutputDB := dbm.NewDB("utxo_output", dbm.BoltBackend, config.DBDir(), config.DBCounts)
+	defer utxoOutputDB.Close()
+	utxoOutputTokenDB := dbm.NewDB("utxo_output_token", dbm.GoLevelDBBackend, config.DBDir(), config.DBCounts)
+	defer utxoOutputTokenDB.Close()
+	utxoStore := utxo.NewUtxoStore(utxoDB, utxoOutputDB, utxoOutputTokenDB)
+
+	assert.Equal(t, int64(1), utxoStore.GetMaxUtxoOutputSeq(common.EmptyAddress))
+	assert.Equal(t, int64(0), utxoStore.GetMaxUtxoOutputSeq(token))
+	o, err := utxoStore.GetUtxoOutput(common.EmptyAddress, 1)
+	require.NoError(t, err)
+	assert.Equal(t, outputs[2].OTAddr[:], o.OTAddr[:])
+	assert.Equal(t, outputs[2].Commit[:], o.Commit[:])
+	o, err = utxoStore.GetUtxoOutput(token, 0)
+	require.NoError(t, err)
+	assert.Equal(t, outputs[1].OTAddr[:], o.OTAddr[:])
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/lianxiangcloud/linkchain/accounts/keystore"
+	"github.com/lianxiangcloud/linkchain/console"
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/spf13/cobra"
+)
+
+// privValidatorPassphraseEnv is the environment variable holding the
+// passphrase of an encrypted priv_validator_file.
+const privValidatorPassphraseEnv = "LINKCHAIN_PRIV_VALIDATOR_PASSPHRASE"
+
+// PrivValidatorCmd groups the commands managing the validator key file.
+var PrivValidatorCmd = &cobra.Command{
+	Use:   "priv_validator",
+	Short: "Manage this node's validator key file",
+}
+
+var privValidatorEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the private key of priv_validator_file with a passphrase",
+	Long: `Encrypt the private key of priv_validator_file in place, the key is no
+longer saved in the clear. The node then reads the passphrase from the
+` + privValidatorPassphraseEnv + ` environment variable, the
+priv_validator_passphrase_file or a prompt.`,
+	RunE: encryptPrivValidator,
+}
+
+func init() {
+	PrivValidatorCmd.AddCommand(privValidatorEncryptCmd)
+}
+
+func encryptPrivValidator(cmd *cobra.Command, args []string) error {
+	privValFile := config.PrivValidatorFile()
+	if !cmn.FileExists(privValFile) {
+		return fmt.Errorf("missing the validator key %s", privValFile)
+	}
+	pv := types.LoadFilePV(privValFile)
+	if pv.IsEncrypted() {
+		return fmt.Errorf("%s is already encrypted", privValFile)
+	}
+	passphrase, err := newPrivValidatorPassphrase()
+	if err != nil {
+		return err
+	}
+	if err := pv.Encrypt(passphrase, keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
+		return err
+	}
+	logger.Info("Encrypted private validator file", "file", privValFile, "address", pv.GetAddress())
+	return nil
+}
+
+// getPrivValidatorPassphrase returns the passphrase of the priv_validator_file
+// from the environment, the priv_validator_passphrase_file, or else a prompt.
+func getPrivValidatorPassphrase() (string, error) {
+	if passphrase, ok := os.LookupEnv(privValidatorPassphraseEnv); ok {
+		return passphrase, nil
+	}
+	if config.PrivValidatorPassphraseFile != "" {
+		bz, err := ioutil.ReadFile(config.PrivValidatorPassphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read the passphrase file: %v", err)
+		}
+		return strings.TrimRight(string(bz), "\r\n"), nil
+	}
+	return console.Stdin.PromptPassword("Validator key passphrase: ")
+}
+
+// newPrivValidatorPassphrase returns the passphrase to encrypt the
+// priv_validator_file with, a prompted passphrase must be confirmed.
+func newPrivValidatorPassphrase() (string, error) {
+	if _, ok := os.LookupEnv(privValidatorPassphraseEnv); ok || config.PrivValidatorPassphraseFile != "" {
+		return getPrivValidatorPassphrase()
+	}
+	passphrase, err := console.Stdin.PromptPassword("Validator key passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	confirm, err := console.Stdin.PromptPassword("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirm {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// unlockFilePV unlocks an encrypted FilePV, a FilePV saved in the clear is
+// left unchanged.
+func unlockFilePV(pv *types.FilePV) error {
+	if !pv.IsLocked() {
+		return nil
+	}
+	passphrase, err := getPrivValidatorPassphrase()
+	if err != nil {
+		return err
+	}
+	return pv.Unlock(passphrase)
+}
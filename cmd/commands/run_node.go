@@ -48,15 +48,26 @@ func AddNodeFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("mempool.removeFutureTx", config.Mempool.RemoveFutureTx, "Remove future tx when mempool future tx queue is full")
 	cmd.Flags().Int("mempool.size", config.Mempool.Size, "max size in good tx")
 	cmd.Flags().Int("mempool.max_reapSize", config.Mempool.MaxReapSize, "reap txs num of block")
+	cmd.Flags().Uint64("mempool.price_bump", config.Mempool.PriceBump, "Minimum price bump percentage to replace a transaction with the same nonce")
 	// log
 	cmd.Flags().String("log.filename", config.Log.Filename, "log file name")
 
 	cmd.Flags().Bool("full_node", config.BaseConfig.FullNode, "light-weight node or full node")
 	cmd.Flags().Uint64("keep_latest_blocks", config.BaseConfig.KeepLatestBlocks, "number of latest blocks to keep")
 	cmd.Flags().Uint64("clear_data_interval", config.BaseConfig.ClearDataInterval, "number of seconds between two startup cleanups")
+	cmd.Flags().String("priv_validator_passphrase_file", config.BaseConfig.PrivValidatorPassphraseFile, "file containing the passphrase of an encrypted priv_validator_file")
+	cmd.Flags().String("priv_validator_laddr", config.BaseConfig.PrivValidatorListenAddr, "address to listen on for a remote signer holding the validator key, tcp://host:port or unix://path")
+	cmd.Flags().String("priv_validator_pubkey", config.BaseConfig.PrivValidatorPubKey, "public key of the validator held by the remote signer, hex encoded")
+	cmd.Flags().String("priv_validator_signer_key", config.BaseConfig.PrivValidatorSignerKey, "public key of the tcp connections of the remote signer, hex encoded")
+	cmd.Flags().String("state_retention", config.BaseConfig.StateRetention, "state retention of a full node: archive | pruned | kv")
+	cmd.Flags().Uint64("state_retention_blocks", config.BaseConfig.StateRetentionBlocks, "number of latest blocks whose state is kept with the pruned state retention")
 	cmd.Flags().Bool("save_balance_record", config.BaseConfig.SaveBalanceRecord, "open transactions record storage")
+	cmd.Flags().Bool("address_index", config.BaseConfig.AddressIndex, "index the transactions by the addresses they touch")
+	cmd.Flags().Bool("parallel_exec", config.BaseConfig.ParallelExec, "execute the transactions of the blocks optimistically in parallel")
 	//bootnode
 	cmd.Flags().StringSlice("bootnode.addrs", config.BootNodeSvr.Addrs, "Addr or filepath of the bootnode")
+	// state sync
+	cmd.Flags().Bool("state_sync.enable", config.StateSync.Enable, "Join the chain from a state snapshot of the peers")
 }
 
 // NewRunNodeCmd returns the command that allows the CLI to start a node.
@@ -74,6 +85,7 @@ func NewRunNodeCmd(nodeProvider nm.NodeProvider) *cobra.Command {
 			types.InitSignParam(config.TestNet)
 
 			cfg.WasmGasRate = config.BaseConfig.WasmGasRate
+			nm.PrivValidatorPassphrase = getPrivValidatorPassphrase
 			// Create & start node
 			n, err := nodeProvider(config, logger)
 			if err != nil {
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/privval"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/spf13/cobra"
+)
+
+// SignerCmd runs a remote signer for a node listening on priv_validator_laddr.
+var SignerCmd = &cobra.Command{
+	Use:   "signer",
+	Short: "Run a remote signer holding the validator key",
+	Long: `Connect to the priv_validator_laddr address of a node and sign its votes
+and proposals with the priv_validator_file key of this home directory. The
+signer records what it signs in the key file and refuses to double sign, the
+node never sees the key. Besides votes, proposals and heartbeats only the
+multisign txs are signed. Only one signer must run for a validator key.
+
+The TCP connections are made to the node holding --node_pubkey only, and
+authenticated with the key of --signer_key_file. The node pins the public keys
+printed at start in priv_validator_pubkey and priv_validator_signer_key.`,
+	RunE: runSigner,
+}
+
+func init() {
+	SignerCmd.Flags().String("addr", "", "priv_validator_laddr address of the node, tcp://host:port or unix://path")
+	SignerCmd.Flags().String("chain_id", "", "chain to sign for, defaults to chain_id")
+	SignerCmd.Flags().String("node_pubkey", "", "public key of node_key_file of the node, hex encoded, required for a tcp address")
+	SignerCmd.Flags().String("signer_key_file", "config/signer_key.json", "key authenticating the signer to the node, generated if missing")
+}
+
+func runSigner(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	chainID, _ := cmd.Flags().GetString("chain_id")
+	nodePubKey, _ := cmd.Flags().GetString("node_pubkey")
+	signerKeyFile, _ := cmd.Flags().GetString("signer_key_file")
+	if addr == "" {
+		return fmt.Errorf("missing the address of the node")
+	}
+	var nodeKey crypto.PubKey
+	if nodePubKey != "" {
+		var err error
+		if nodeKey, err = privval.PubKeyFromHex(nodePubKey); err != nil {
+			return fmt.Errorf("Invalid node_pubkey: %v", err)
+		}
+	} else if proto, _ := cmn.ProtocolAndAddress(addr); proto == "tcp" {
+		return fmt.Errorf("missing the node_pubkey of the node")
+	}
+	if chainID == "" {
+		chainID = config.ChainID
+	}
+	if !cmn.FileExists(config.PrivValidatorFile()) {
+		return fmt.Errorf("missing the validator key %s", config.PrivValidatorFile())
+	}
+
+	pv := types.LoadFilePV(config.PrivValidatorFile())
+	if err := unlockFilePV(pv); err != nil {
+		return fmt.Errorf("Failed to unlock %s: %v", config.PrivValidatorFile(), err)
+	}
+	if !filepath.IsAbs(signerKeyFile) {
+		signerKeyFile = filepath.Join(config.RootDir, signerKeyFile)
+	}
+	signerKey, err := privval.LoadOrGenNodeKey(signerKeyFile)
+	if err != nil {
+		return err
+	}
+	rs := privval.NewRemoteSigner(logger.With("module", "privval"), chainID, addr, pv, signerKey, nodeKey)
+	if err := rs.Start(); err != nil {
+		return err
+	}
+	logger.Info("Started remote signer", "addr", addr, "chainID", chainID, "validator", pv.GetAddress(),
+		"priv_validator_pubkey", privval.PubKeyHex(pv.GetPubKey()), "priv_validator_signer_key", privval.PubKeyHex(signerKey.PubKey()))
+
+	cmn.TrapSignal(func() {
+		rs.Stop()
+	})
+	return nil
+}
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/bootnode"
+	cfg "github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	p2pcmn "github.com/lianxiangcloud/linkchain/libs/p2p/common"
+	"github.com/lianxiangcloud/linkchain/privval"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// seedsFileName is the static seeds file of a testnet node, read in place of the bootnode service
+	seedsFileName = "seeds.json"
+)
+
+var (
+	testnetValidators     int
+	testnetObservers      int
+	testnetOutput         string
+	testnetChainID        string
+	testnetHost           string
+	testnetP2PPort        int
+	testnetRPCPort        int
+	testnetWSPort         int
+	testnetPrometheusPort int
+)
+
+func init() {
+	TestnetFilesCmd.Flags().IntVar(&testnetValidators, "validators", 4, "Number of validators")
+	TestnetFilesCmd.Flags().IntVar(&testnetObservers, "observers", 0, "Number of observers, the nodes which are not validators")
+	TestnetFilesCmd.Flags().StringVar(&testnetOutput, "output", "./mytestnet", "Directory of the node directories")
+	TestnetFilesCmd.Flags().StringVar(&testnetChainID, "chain_id", "testnet", "Blockchain id")
+	TestnetFilesCmd.Flags().StringVar(&testnetHost, "host", "127.0.0.1", "IP address the nodes dial each other on")
+	TestnetFilesCmd.Flags().IntVar(&testnetP2PPort, "p2p_port", 13500, "P2P port of the first node, the node i listens on p2p_port+i")
+	TestnetFilesCmd.Flags().IntVar(&testnetRPCPort, "rpc_port", 16000, "HTTP-RPC port of the first node, the node i listens on rpc_port+i")
+	TestnetFilesCmd.Flags().IntVar(&testnetWSPort, "ws_port", 18000, "WS-RPC port of the first node, the node i listens on ws_port+i")
+	TestnetFilesCmd.Flags().IntVar(&testnetPrometheusPort, "prometheus_port", 26660, "Prometheus port of the first node, the node i listens on prometheus_port+i")
+}
+
+// TestnetFilesCmd generates the files of a local testnet.
+var TestnetFilesCmd = &cobra.Command{
+	Use:   "testnet",
+	Short: "Initialize the files of a local testnet",
+	Long: `testnet generates the directories of the nodes of a local testnet, each one
+holding its config.toml, priv_validator.json, node_key.json, the shared genesis.json
+and a static seeds file of the other nodes, and initializes the genesis block of
+every node. The nodes are started by "linkchain node --home <output>/node<i>".`,
+	RunE: testnetFiles,
+}
+
+// testnetNode is a node of the testnet
+type testnetNode struct {
+	config *cfg.Config
+	pv     *types.FilePV
+	port   int
+}
+
+func testnetFiles(cmd *cobra.Command, args []string) error {
+	if testnetValidators <= 0 {
+		return fmt.Errorf("a testnet needs at least one validator")
+	}
+	if testnetObservers < 0 {
+		return fmt.Errorf("invalid number of observers %d", testnetObservers)
+	}
+	output, err := filepath.Abs(testnetOutput)
+	if err != nil {
+		return err
+	}
+
+	nodes := make([]*testnetNode, testnetValidators+testnetObservers)
+	for i := range nodes {
+		nodeDir := filepath.Join(output, fmt.Sprintf("node%d", i))
+		if common.FileExists(nodeDir) {
+			return fmt.Errorf("node directory %s already exists", nodeDir)
+		}
+		nodes[i] = newTestnetNode(nodeDir, i)
+	}
+
+	genDoc := types.GenesisDoc{
+		ChainID:         testnetChainID,
+		GenesisTime:     time.Now().Local().String(),
+		ConsensusParams: types.DefaultConsensusParams(),
+		AllocAccounts:   types.GetTestAllocAccounts(),
+	}
+	for _, node := range nodes[:testnetValidators] {
+		pv, err := generateTestnetKeys(node.config)
+		if err != nil {
+			return err
+		}
+		node.pv = pv
+		genDoc.Validators = append(genDoc.Validators, types.GenesisValidator{
+			PubKey: pv.GetPubKey(),
+			Power:  10,
+			Name:   node.config.Moniker,
+		})
+	}
+	for _, node := range nodes[testnetValidators:] {
+		pv, err := generateTestnetKeys(node.config)
+		if err != nil {
+			return err
+		}
+		node.pv = pv
+	}
+
+	for i, node := range nodes {
+		if err := genDoc.SaveAs(node.config.GenesisFile()); err != nil {
+			return err
+		}
+		nodeType := types.NodePeer
+		if i < testnetValidators {
+			nodeType = types.NodeValidator
+		}
+		if err := writeTestnetSeeds(node.config.BootNodeSvr.Addrs[0], nodeType, nodes, i); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range nodes {
+		if err := initNodeFiles(node.config); err != nil {
+			return fmt.Errorf("init %s error:%v", node.config.RootDir, err)
+		}
+	}
+
+	fmt.Printf("Successfully initialized %d validators and %d observers in %s\n", testnetValidators, testnetObservers, output)
+	for _, node := range nodes {
+		fmt.Printf("linkchain node --home %s\n", node.config.RootDir)
+	}
+	return nil
+}
+
+// newTestnetNode returns the node i of the testnet, the ports of which are the base ports plus i
+func newTestnetNode(nodeDir string, i int) *testnetNode {
+	config := cfg.DefaultConfig().SetRoot(nodeDir)
+	config.Moniker = fmt.Sprintf("node%d", i)
+	config.ChainID = testnetChainID
+	config.P2P.ListenAddress = fmt.Sprintf(":%d", testnetP2PPort+i)
+	config.RPC.HTTPEndpoint = fmt.Sprintf("127.0.0.1:%d", testnetRPCPort+i)
+	config.RPC.WSEndpoint = fmt.Sprintf("127.0.0.1:%d", testnetWSPort+i)
+	config.Instrumentation.PrometheusListenAddr = fmt.Sprintf(":%d", testnetPrometheusPort+i)
+	config.BootNodeSvr.Addrs = []string{filepath.Join(nodeDir, "config", seedsFileName)}
+	// writes the config.toml of the node
+	cfg.EnsureRoot(nodeDir, config)
+	return &testnetNode{config: config, port: testnetP2PPort + i}
+}
+
+// generateTestnetKeys generates the priv_validator and the node key of the node of config
+func generateTestnetKeys(config *cfg.Config) (*types.FilePV, error) {
+	pv := types.GenFilePV(config.PrivValidatorFile())
+	pv.Save()
+	if _, err := privval.LoadOrGenNodeKey(config.NodeKeyFile()); err != nil {
+		return nil, err
+	}
+	return pv, nil
+}
+
+// writeTestnetSeeds writes the seeds file of the node self, the seeds of which are the other nodes
+func writeTestnetSeeds(file string, nodeType types.NodeType, nodes []*testnetNode, self int) error {
+	resp := bootnode.GetSeedsResp{
+		Code: bootnode.Succ,
+		Type: int(nodeType),
+	}
+	for i, node := range nodes {
+		if i == self {
+			continue
+		}
+		resp.Seeds = append(resp.Seeds, bootnode.Rnode{
+			ID: p2pcmn.TransPubKeyToNodeID(node.pv.GetPubKey()),
+			Endpoint: &bootnode.Endpoint{
+				IP:   []string{testnetHost},
+				Port: map[string]int{bootnode.TCP: node.port, bootnode.UDP: node.port},
+			},
+		})
+	}
+	data, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(file, data, 0644)
+}
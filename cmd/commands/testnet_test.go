@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/bootnode"
+	bc "github.com/lianxiangcloud/linkchain/blockchain"
+	cfg "github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+func TestTestnetFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testnet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	testnetValidators, testnetObservers, testnetOutput = 2, 1, dir
+	require.NoError(t, testnetFiles(TestnetFilesCmd, nil))
+	// the existing nodes are not overwritten
+	assert.Error(t, testnetFiles(TestnetFilesCmd, nil))
+
+	var genesisHash common.Hash
+	for i, nodeType := range []types.NodeType{types.NodeValidator, types.NodeValidator, types.NodePeer} {
+		nodeDir := filepath.Join(dir, fmt.Sprintf("node%d", i))
+
+		v := viper.New()
+		v.SetConfigFile(filepath.Join(nodeDir, "config", "config.toml"))
+		require.NoError(t, v.ReadInConfig())
+		config := cfg.DefaultConfig()
+		require.NoError(t, v.Unmarshal(config))
+		config.SetRoot(nodeDir)
+		assert.Equal(t, fmt.Sprintf(":%d", testnetP2PPort+i), config.P2P.ListenAddress)
+
+		genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+		require.NoError(t, err)
+		assert.Len(t, genDoc.Validators, 2)
+		assert.Equal(t, "testnet", genDoc.ChainID)
+
+		// the seeds are the other nodes
+		require.Len(t, config.BootNodeSvr.Addrs, 1)
+		seeds, localType, err := bootnode.GetSeeds(config.BootNodeSvr.Addrs[0], nil, log.Test())
+		require.NoError(t, err)
+		assert.Equal(t, nodeType, localType)
+		assert.Len(t, seeds, 2)
+
+		blockStoreDB := dbm.NewDB("blockstore", dbm.DBBackendType(config.DBBackend), config.DBDir(), config.DBCounts)
+		header := bc.NewBlockStore(blockStoreDB).GetHeader(0)
+		blockStoreDB.Close()
+		require.NotNil(t, header)
+		if i == 0 {
+			genesisHash = header.Hash()
+		} else {
+			assert.Equal(t, genesisHash, header.Hash())
+		}
+	}
+}
@@ -13,13 +13,19 @@ import (
 func main() {
 	rootCmd := cmd.RootCmd
 	rootCmd.AddCommand(
+		cmd.DBCmd,
+		cmd.ExportCmd,
 		cmd.GenValidatorCmd,
+		cmd.ImportCmd,
 		cmd.InitFilesCmd,
+		cmd.PrivValidatorCmd,
 		cmd.ReplayCmd,
 		cmd.ReplayConsoleCmd,
 		cmd.ResetAllCmd,
 		cmd.ResetPrivValidatorCmd,
 		cmd.ShowValidatorCmd,
+		cmd.SignerCmd,
+		cmd.TestnetFilesCmd,
 		cmd.VersionCmd,
 		cmd.NewConsoleCommand(),
 	)
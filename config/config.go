@@ -16,6 +16,13 @@ const (
 	FuzzModeDelay
 )
 
+// State retention policies of BaseConfig.StateRetention
+const (
+	StateRetentionArchive = "archive"
+	StateRetentionPruned  = "pruned"
+	StateRetentionKV      = "kv"
+)
+
 // NOTE: Most of the structs & relevant comments + the
 // default configuration options were used to manually
 // generate the config.toml. Please reflect any changes
@@ -34,11 +41,13 @@ var (
 	defaultGenesisJSONName = "genesis.json"
 
 	defaultPrivValName  = "priv_validator.json"
+	defaultNodeKeyName  = "node_key.json"
 	defaultAddrBookName = "addrbook.json"
 
 	defaultConfigFilePath  = filepath.Join(defaultConfigDir, defaultConfigFileName)
 	defaultGenesisJSONPath = filepath.Join(defaultConfigDir, defaultGenesisJSONName)
 	defaultPrivValPath     = filepath.Join(defaultConfigDir, defaultPrivValName)
+	defaultNodeKeyPath     = filepath.Join(defaultConfigDir, defaultNodeKeyName)
 	defaultAddrBookPath    = filepath.Join(defaultConfigDir, defaultAddrBookName)
 
 	WasmGasRate = uint64(1)
@@ -58,6 +67,7 @@ type Config struct {
 	Consensus       *ConsensusConfig       `mapstructure:"consensus"`
 	Instrumentation *InstrumentationConfig `mapstructure:"instrumentation"`
 	BootNodeSvr     *BootNodeConfig        `mapstructure:"bootnode"`
+	StateSync       *StateSyncConfig       `mapstructure:"state_sync"`
 }
 
 // DefaultConfig returns a default configuration for a node
@@ -71,6 +81,7 @@ func DefaultConfig() *Config {
 		Consensus:       DefaultConsensusConfig(),
 		Instrumentation: DefaultInstrumentationConfig(),
 		BootNodeSvr:     DefaultBootNodeConfig(),
+		StateSync:       DefaultStateSyncConfig(),
 	}
 }
 
@@ -84,6 +95,7 @@ func TestConfig() *Config {
 		Mempool:         TestMempoolConfig(),
 		Consensus:       TestConsensusConfig(),
 		Instrumentation: TestInstrumentationConfig(),
+		StateSync:       TestStateSyncConfig(),
 	}
 }
 
@@ -119,6 +131,26 @@ type BaseConfig struct {
 	// Path to the JSON file containing the private key to use as a validator in the consensus protocol
 	PrivValidator string `mapstructure:"priv_validator_file"`
 
+	// TCP or UNIX socket address for the node to listen on for a remote signer
+	// holding the validator key, priv_validator_file is not used if set
+	PrivValidatorListenAddr string `mapstructure:"priv_validator_laddr"`
+
+	// Public key of the validator held by the remote signer, the signers holding
+	// another key are refused
+	PrivValidatorPubKey string `mapstructure:"priv_validator_pubkey"`
+
+	// Public key the remote signer authenticates its TCP connections with, the
+	// connections of other peers are refused
+	PrivValidatorSignerKey string `mapstructure:"priv_validator_signer_key"`
+
+	// Path to the JSON file containing the private key identifying the node in the
+	// p2p network and to the bootnodes when the validator key is held by a remote
+	// signer, it also authenticates the node to the signer
+	NodeKey string `mapstructure:"node_key_file"`
+
+	// Path to a file containing the passphrase of an encrypted priv_validator_file
+	PrivValidatorPassphraseFile string `mapstructure:"priv_validator_passphrase_file"`
+
 	// A custom human readable name for this node
 	Moniker string `mapstructure:"moniker"` //nodetype_hostname
 
@@ -176,8 +208,21 @@ type BaseConfig struct {
 	KeepLatestBlocks  uint64 `mapstructure:"keep_latest_blocks"`
 	ClearDataInterval uint64 `mapstructure:"clear_data_interval"`
 
+	// State retention of a full node: "archive" keeps the state of every block,
+	// "pruned" the state of the latest StateRetentionBlocks blocks and "kv" the
+	// latest state only, as the nodes that are not full nodes do.
+	StateRetention       string `mapstructure:"state_retention"`
+	StateRetentionBlocks uint64 `mapstructure:"state_retention_blocks"`
+
 	SaveBalanceRecord bool `mapstructure:"save_balance_record"`
 
+	// Index the transactions of the committed blocks by the addresses they
+	// touch, for lk_getAddressTransactions
+	AddressIndex bool `mapstructure:"address_index"`
+
+	// Execute the transactions of the blocks optimistically in parallel
+	ParallelExec bool `mapstructure:"parallel_exec"`
+
 	IsTestMode bool `mapstructure:"is_test_mode"`
 
 	TestNet bool `mapstructure:"test_net"`
@@ -186,29 +231,34 @@ type BaseConfig struct {
 // DefaultBaseConfig returns a default base configuration for a node
 func DefaultBaseConfig() BaseConfig {
 	return BaseConfig{
-		ChainID:           "chainID",
-		Genesis:           defaultGenesisJSONPath,
-		PrivValidator:     defaultPrivValPath,
-		Moniker:           defaultMoniker,
-		LogLevel:          DefaultPackageLogLevels(),
-		ProfListenAddress: "",
-		FastSync:          true,
-		FilterPeers:       false,
-		DBBackend:         "leveldb",
-		DBPath:            defaultDataDir,
-		LogPath:           defaultLogDir,
-		KeyStorePath:      defaultKeyStoreDir,
-		OnLine:            false,
-		InfoAddr:          ":40001",
-		InfoPrefix:        "o_blockchain_data",
-		ExecFlag:          "",
-		WasmGasRate:       1,
-		RollBack:          false,
-		FullNode:          false,
-		KeepLatestBlocks:  0,
-		ClearDataInterval: 300,
-		SaveBalanceRecord: false,
-		IsTestMode:        false,
+		ChainID:              "chainID",
+		Genesis:              defaultGenesisJSONPath,
+		PrivValidator:        defaultPrivValPath,
+		NodeKey:              defaultNodeKeyPath,
+		Moniker:              defaultMoniker,
+		LogLevel:             DefaultPackageLogLevels(),
+		ProfListenAddress:    "",
+		FastSync:             true,
+		FilterPeers:          false,
+		DBBackend:            "leveldb",
+		DBPath:               defaultDataDir,
+		LogPath:              defaultLogDir,
+		KeyStorePath:         defaultKeyStoreDir,
+		OnLine:               false,
+		InfoAddr:             ":40001",
+		InfoPrefix:           "o_blockchain_data",
+		ExecFlag:             "",
+		WasmGasRate:          1,
+		RollBack:             false,
+		FullNode:             false,
+		KeepLatestBlocks:     0,
+		ClearDataInterval:    300,
+		StateRetention:       StateRetentionArchive,
+		StateRetentionBlocks: 128,
+		SaveBalanceRecord:    false,
+		AddressIndex:         false,
+		ParallelExec:         false,
+		IsTestMode:           false,
 	}
 }
 
@@ -231,6 +281,30 @@ func (cfg BaseConfig) PrivValidatorFile() string {
 	return rootify(cfg.PrivValidator, cfg.RootDir)
 }
 
+// NodeKeyFile returns the full path to the node_key.json file
+func (cfg BaseConfig) NodeKeyFile() string {
+	return rootify(cfg.NodeKey, cfg.RootDir)
+}
+
+// StateRetentionMode returns the state retention policy of the node, which is
+// "kv" for the nodes that are not full nodes.
+func (cfg BaseConfig) StateRetentionMode() (string, error) {
+	if !cfg.FullNode {
+		return StateRetentionKV, nil
+	}
+	switch cfg.StateRetention {
+	case StateRetentionArchive, StateRetentionKV:
+	case StateRetentionPruned:
+		if cfg.StateRetentionBlocks == 0 {
+			return "", fmt.Errorf("state_retention_blocks must be positive with the pruned state retention")
+		}
+	default:
+		return "", fmt.Errorf("unknown state_retention %q, want %s, %s or %s",
+			cfg.StateRetention, StateRetentionArchive, StateRetentionPruned, StateRetentionKV)
+	}
+	return cfg.StateRetention, nil
+}
+
 // DBDir returns the full path to the database directory
 func (cfg BaseConfig) DBDir() string {
 	return rootify(cfg.DBPath, cfg.RootDir)
@@ -416,6 +490,8 @@ type MempoolConfig struct {
 	Lifetime          time.Duration `mapstructure:"life_time"`     // Maximum amount of time non-executable transaction are queued
 	RemoveFutureTx    bool          `mapstructure:"removeFutureTx"`
 	ReceiveP2pTx      bool          `mapstructure:"receive_p2pTx"`
+	PriceBump         uint64        `mapstructure:"price_bump"` // Minimum price bump percentage to replace a transaction with the same nonce
+	Rejournal         time.Duration `mapstructure:"rejournal"`  // Time interval to regenerate the local transaction journal
 }
 
 // DefaultMempoolConfig returns a default configuration for the mempool
@@ -436,6 +512,8 @@ func DefaultMempoolConfig() *MempoolConfig {
 		Lifetime:          60 * time.Second,
 		RemoveFutureTx:    false,
 		ReceiveP2pTx:      false,
+		PriceBump:         10,
+		Rejournal:         time.Hour,
 	}
 }
 
@@ -446,7 +524,7 @@ func TestMempoolConfig() *MempoolConfig {
 	return cfg
 }
 
-// WalDir returns the full path to the mempool's write-ahead log
+// WalDir returns the full path to the mempool's journal of local transactions
 func (cfg *MempoolConfig) WalDir() string {
 	return rootify(cfg.WalPath, cfg.RootDir)
 }
@@ -631,6 +709,50 @@ func DefaultBootNodeConfig() *BootNodeConfig {
 	}
 }
 
+//-----------------------------------------------------------------------------
+// StateSyncConfig
+
+// StateSyncConfig defines the configuration for joining the chain from a
+// snapshot of the state instead of replaying every block.
+type StateSyncConfig struct {
+	// Fetch the state of a recent block from peers when the node starts
+	// with an empty block store. Only full nodes can state sync.
+	Enable bool `mapstructure:"enable"`
+
+	// Number of peers that must offer the same snapshot before it is trusted.
+	TrustPeers int `mapstructure:"trust_peers"`
+
+	// Height and hash of a block trusted from a source other than the peers,
+	// such as another node. The state of that block is restored.
+	TrustHeight uint64 `mapstructure:"trust_height"`
+	TrustHash   string `mapstructure:"trust_hash"`
+
+	// Time to wait for a peer to answer a request, in milliseconds.
+	RequestTimeout int `mapstructure:"request_timeout"`
+}
+
+// DefaultStateSyncConfig returns a default configuration for state sync.
+func DefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Enable:         false,
+		TrustPeers:     2,
+		RequestTimeout: 10000,
+	}
+}
+
+// TestStateSyncConfig returns a configuration for testing state sync.
+func TestStateSyncConfig() *StateSyncConfig {
+	cfg := DefaultStateSyncConfig()
+	cfg.TrustPeers = 1
+	cfg.RequestTimeout = 1000
+	return cfg
+}
+
+// Timeout returns the amount of time to wait for a peer to answer a request.
+func (cfg *StateSyncConfig) Timeout() time.Duration {
+	return time.Duration(cfg.RequestTimeout) * time.Millisecond
+}
+
 //-----------------------------------------------------------------------------
 // Utils
 
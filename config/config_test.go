@@ -14,6 +14,7 @@ func TestDefaultConfig(t *testing.T) {
 	assert.NotNil(cfg.P2P)
 	assert.NotNil(cfg.Mempool)
 	assert.NotNil(cfg.Consensus)
+	assert.NotNil(cfg.StateSync)
 
 	// check the root dir stuff...
 	cfg.SetRoot("/foo")
@@ -25,4 +26,31 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal("/opt/data", cfg.DBDir())
 	assert.Equal("/foo/wal/mem", cfg.Mempool.WalDir())
 
-}
\ No newline at end of file
+}
+
+func TestStateRetentionMode(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := DefaultBaseConfig()
+	mode, err := cfg.StateRetentionMode()
+	assert.Nil(err)
+	assert.Equal(StateRetentionKV, mode, "not a full node")
+
+	cfg.FullNode = true
+	mode, err = cfg.StateRetentionMode()
+	assert.Nil(err)
+	assert.Equal(StateRetentionArchive, mode)
+
+	cfg.StateRetention = StateRetentionPruned
+	mode, err = cfg.StateRetentionMode()
+	assert.Nil(err)
+	assert.Equal(StateRetentionPruned, mode)
+
+	cfg.StateRetentionBlocks = 0
+	_, err = cfg.StateRetentionMode()
+	assert.NotNil(err)
+
+	cfg.StateRetention = "full"
+	_, err = cfg.StateRetentionMode()
+	assert.NotNil(err)
+}
@@ -61,6 +61,20 @@ var (
 		Suicide:     5000,
 		ExpByte:     50,
 
+		CreateBySuicide: 25000,
+	}
+	// GasTableIstanbul contain the gas re-prices of EIP-1884 for
+	// the Istanbul phase.
+	GasTableIstanbul = GasTable{
+		ExtcodeSize: 700,
+		ExtcodeCopy: 700,
+		ExtcodeHash: 700,
+		Balance:     700,
+		SLoad:       800,
+		Calls:       700,
+		Suicide:     5000,
+		ExpByte:     50,
+
 		CreateBySuicide: 25000,
 	}
 )
@@ -98,6 +98,23 @@ genesis_file = "{{ js .BaseConfig.Genesis }}"
 # Path to the JSON file containing the private key to use as a validator in the consensus protocol
 priv_validator_file = "{{ js .BaseConfig.PrivValidator }}"
 
+# TCP or UNIX socket address for the node to listen on for a remote signer holding the validator key
+priv_validator_laddr = "{{ .BaseConfig.PrivValidatorListenAddr }}"
+
+# Public key of the validator held by the remote signer, hex encoded
+priv_validator_pubkey = "{{ .BaseConfig.PrivValidatorPubKey }}"
+
+# Public key of the TCP connections of the remote signer, hex encoded
+priv_validator_signer_key = "{{ .BaseConfig.PrivValidatorSignerKey }}"
+
+# Path to the JSON file containing the node key, used with a remote signer.
+# It replaces the validator key as identity of the node in the p2p network and
+# to the bootnodes, which have to know its public key instead
+node_key_file = "{{ js .BaseConfig.NodeKey }}"
+
+# Path to a file containing the passphrase of an encrypted priv_validator_file
+priv_validator_passphrase_file = "{{ js .BaseConfig.PrivValidatorPassphraseFile }}"
+
 # TCP or UNIX socket address for the profiling server to listen on
 pprof = "{{ .BaseConfig.ProfListenAddress }}"
 
@@ -110,6 +127,21 @@ wasm_gas_rate = {{ .BaseConfig.WasmGasRate }}
 #test mode
 istestmode = {{ .BaseConfig.IsTestMode }}
 
+# State retention of a full node: archive | pruned | kv
+# archive keeps the state of every block, pruned the state of the latest
+# state_retention_blocks blocks and kv the latest state only
+state_retention = "{{ .BaseConfig.StateRetention }}"
+state_retention_blocks = {{ .BaseConfig.StateRetentionBlocks }}
+
+# Index the transactions by the addresses they touch, for lk_getAddressTransactions.
+# The index is built from the stored blocks on the first start, internal
+# contract transfers are indexed with save_balance_record only
+address_index = {{ .BaseConfig.AddressIndex }}
+
+# Execute the account and token transactions of the blocks optimistically in
+# parallel, the conflicting ones are executed again in block order
+parallel_exec = {{ .BaseConfig.ParallelExec }}
+
 ##### advanced configuration options #####
 
 ##### log rotate configuration options #####
@@ -180,8 +212,14 @@ max_packet_msg_payload_size = {{ .P2P.MaxPacketMsgPayloadSize }}
 recheck = {{ .Mempool.Recheck }}
 recheck_empty = {{ .Mempool.RecheckEmpty }}
 broadcast = {{ .Mempool.Broadcast }}
+
+# directory of the journal keeping the locally submitted txs over restarts,
+# journaling is disabled if empty
 wal_dir = "{{ js .Mempool.WalPath }}"
 
+# how often the journal is regenerated from the txs still in the mempool
+rejournal = "{{ .Mempool.Rejournal }}"
+
 # size of the good tx queue
 size = {{ .Mempool.Size }}
 
@@ -196,6 +234,9 @@ future_size = {{ .Mempool.FutureSize }}
 
 removeFutureTx = {{ .Mempool.RemoveFutureTx }}
 
+# minimum gas price bump percentage to replace a transaction with the same nonce
+price_bump = {{ .Mempool.PriceBump }}
+
 ##### consensus configuration options #####
 [consensus]
 
@@ -239,7 +280,28 @@ prometheus_listen_addr = "{{ .Instrumentation.PrometheusListenAddr }}"
 max_open_connections = {{ .Instrumentation.MaxOpenConnections }}
 
 [bootnode]
-addrs = "{{ .BootNodeSvr.Addrs }}"
+
+# Addrs of the bootnode services, or the path of a static seeds file
+addrs = [{{ range $index, $element := .BootNodeSvr.Addrs }}{{ if $index }}, {{ end }}{{ printf "%q" $element }}{{ end }}]
+
+##### state sync configuration options #####
+[state_sync]
+
+# Fetch the state of a recent block from peers when the node starts with an
+# empty block store, instead of replaying every block. Requires full_node.
+enable = {{ .StateSync.Enable }}
+
+# Number of peers that must offer the same snapshot before it is trusted
+trust_peers = {{ .StateSync.TrustPeers }}
+
+# Height and hash of a block trusted from a source other than the peers, such
+# as another node. The state of that block is restored, it has to be recent
+# enough for the peers to still keep it
+trust_height = {{ .StateSync.TrustHeight }}
+trust_hash = "{{ .StateSync.TrustHash }}"
+
+# Time to wait for a peer to answer a request, in milliseconds
+request_timeout = {{ .StateSync.RequestTimeout }}
 `
 
 /****** these are for test settings ***********/
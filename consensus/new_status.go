@@ -320,7 +320,8 @@ func LoadConsensusParams(db dbm.DB, height uint64) (types.ConsensusParams, error
 		return empty, ErrNoConsensusParamsForHeight{height}
 	}
 
-	if paramsInfo.ConsensusParams == empty {
+	if params := paramsInfo.ConsensusParams; params.BlockSize == empty.BlockSize && params.TxSize == empty.TxSize &&
+		params.BlockGossip == empty.BlockGossip && params.EvidenceParams == empty.EvidenceParams && len(params.Forks) == 0 {
 		paramsInfo = loadConsensusParamsInfo(db, paramsInfo.LastHeightChanged)
 		if paramsInfo == nil {
 			cmn.PanicSanity(fmt.Sprintf(`Couldn't find consensus params at height %d as
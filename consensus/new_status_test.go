@@ -63,6 +63,31 @@ func TestStateSaveLoad(t *testing.T) {
 			loadedState, state))
 }
 
+// TestForksSaveLoad tests the fork schedule is kept with the consensus params.
+func TestForksSaveLoad(t *testing.T) {
+	tearDown, stateDB, state := setupTestCase(t)
+	defer tearDown(t)
+	// nolint: vetshadow
+	assert := assert.New(t)
+
+	forks := types.Forks{{Name: types.ForkIstanbul, Height: 10}}
+	state.LastBlockHeight++
+	state.ConsensusParams.Forks = forks
+	state.LastHeightConsensusParamsChanged = state.LastBlockHeight + 1
+	SaveStatus(stateDB, state)
+
+	loadedState, _ := LoadStatus(stateDB)
+	assert.Equal(forks, loadedState.ConsensusParams.Forks)
+	assert.Equal(state.ConsensusParams.Hash(), loadedState.ConsensusParams.Hash())
+
+	// the params of the next heights are looked up at the height they changed
+	state.LastBlockHeight++
+	SaveStatus(stateDB, state)
+	params, err := LoadConsensusParams(stateDB, state.LastBlockHeight+1)
+	assert.Nil(err)
+	assert.Equal(forks, params.Forks)
+}
+
 // TestValidatorSimpleSaveLoad tests saving and loading validators.
 func TestValidatorSimpleSaveLoad(t *testing.T) {
 	tearDown, stateDB, state := setupTestCase(t)
@@ -0,0 +1,40 @@
+package blake2b
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// sum512 hashes a single block message with F the way BLAKE2b-512 does.
+func sum512(msg []byte) []byte {
+	h := iv
+	h[0] ^= 0x01010000 ^ blake2b.Size
+
+	var (
+		block [blake2b.BlockSize]byte
+		m     [16]uint64
+	)
+	copy(block[:], msg)
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(block[i*8:])
+	}
+	F(&h, m, [2]uint64{uint64(len(msg)), 0}, true, 12)
+
+	out := make([]byte, blake2b.Size)
+	for i, v := range h {
+		binary.LittleEndian.PutUint64(out[i*8:], v)
+	}
+	return out
+}
+
+func TestF(t *testing.T) {
+	for _, msg := range []string{"", "abc", "The quick brown fox jumps over the lazy dog"} {
+		want := blake2b.Sum512([]byte(msg))
+		if have := sum512([]byte(msg)); !bytes.Equal(have, want[:]) {
+			t.Errorf("%q: have %x, want %x", msg, have, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keyCipher      = "aes-128-ctr"
+	keyKDF         = "scrypt"
+	keyScryptR     = 8
+	keyScryptDKLen = 32
+)
+
+// ErrDecrypt is returned when a key cannot be decrypted with a passphrase.
+var ErrDecrypt = errors.New("could not decrypt key with given passphrase")
+
+// ScryptParams are the parameters of the scrypt key derivation.
+type ScryptParams struct {
+	N     int             `json:"n"`
+	R     int             `json:"r"`
+	P     int             `json:"p"`
+	DKLen int             `json:"dklen"`
+	Salt  common.HexBytes `json:"salt"`
+}
+
+// EncryptedKey is a private key encrypted with a passphrase. As in the key
+// files of accounts/keystore, the encryption key is derived from the
+// passphrase with scrypt, the private key is encrypted with AES-128-CTR and
+// authenticated with a Keccak256 MAC.
+type EncryptedKey struct {
+	Cipher     string          `json:"cipher"`
+	CipherText common.HexBytes `json:"ciphertext"`
+	IV         common.HexBytes `json:"iv"`
+	KDF        string          `json:"kdf"`
+	KDFParams  ScryptParams    `json:"kdfparams"`
+	MAC        common.HexBytes `json:"mac"`
+}
+
+// EncryptKey encrypts a private key with a passphrase, scryptN and scryptP
+// being the CPU/memory cost parameters of the key derivation.
+func EncryptKey(privKey PrivKey, passphrase string, scryptN, scryptP int) (*EncryptedKey, error) {
+	salt := CRandBytes(32)
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt, scryptN, keyScryptR, scryptP, keyScryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	iv := CRandBytes(aes.BlockSize)
+	cipherText, err := aesCTRXOR(derivedKey[:16], privKey.Bytes(), iv)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedKey{
+		Cipher:     keyCipher,
+		CipherText: cipherText,
+		IV:         iv,
+		KDF:        keyKDF,
+		KDFParams: ScryptParams{
+			N:     scryptN,
+			R:     keyScryptR,
+			P:     scryptP,
+			DKLen: keyScryptDKLen,
+			Salt:  salt,
+		},
+		MAC: Keccak256(derivedKey[16:32], cipherText),
+	}, nil
+}
+
+// DecryptKey decrypts a private key encrypted by EncryptKey, it returns
+// ErrDecrypt if the passphrase is wrong.
+func DecryptKey(key *EncryptedKey, passphrase string) (PrivKey, error) {
+	if key.Cipher != keyCipher {
+		return nil, fmt.Errorf("Cipher not supported: %v", key.Cipher)
+	}
+	if key.KDF != keyKDF {
+		return nil, fmt.Errorf("Unsupported KDF: %s", key.KDF)
+	}
+	params := key.KDFParams
+	if params.DKLen < 32 {
+		return nil, fmt.Errorf("Invalid derived key length: %d", params.DKLen)
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), params.Salt, params.N, params.R, params.P, params.DKLen)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(Keccak256(derivedKey[16:32], key.CipherText), key.MAC) {
+		return nil, ErrDecrypt
+	}
+	plainText, err := aesCTRXOR(derivedKey[:16], key.CipherText, key.IV)
+	if err != nil {
+		return nil, err
+	}
+	return PrivKeyFromBytes(plainText)
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	aesBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	stream := cipher.NewCTR(aesBlock, iv)
+	outText := make([]byte, len(inText))
+	stream.XORKeyStream(outText, inText)
+	return outText, nil
+}
@@ -1,6 +1,5 @@
 package ringct
 
-import "C"
 import (
 	. "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
 	"github.com/lianxiangcloud/linkchain/libs/cryptonote/xcrypto"
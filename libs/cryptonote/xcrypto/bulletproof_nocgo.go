@@ -0,0 +1,560 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/edwards25519"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+)
+
+// Bulletproof range proofs, following src/ringct/bulletproofs.cc of the
+// cryptonote code base libxcrypto was built from. bulletproofBits is 64 for
+// the standard proofs and 128 for the wide amounts of the *128 variants.
+
+const (
+	bulletproofMaxM = 16
+)
+
+var (
+	generatorsMu sync.Mutex
+	generatorsG  []edwards25519.ExtendedGroupElement
+	generatorsH  []edwards25519.ExtendedGroupElement
+)
+
+// getExponent is hash_to_p3(keccak(base || "bulletproof" || varint(idx))).
+func getExponent(base types.Key, idx uint64) edwards25519.ExtendedGroupElement {
+	return hashToP3(keccak(base[:], []byte("bulletproof"), varint(idx)))
+}
+
+// bulletproofGenerators returns the first n Gi and Hi generators.
+func bulletproofGenerators(n int) ([]edwards25519.ExtendedGroupElement, []edwards25519.ExtendedGroupElement) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	for i := len(generatorsG); i < n; i++ {
+		generatorsH = append(generatorsH, getExponent(keyH, uint64(i*2)))
+		generatorsG = append(generatorsG, getExponent(keyH, uint64(i*2+1)))
+	}
+	return generatorsG[:n], generatorsH[:n]
+}
+
+func isReduced(k *types.Key) bool {
+	return scCheck(k)
+}
+
+func vectorPowers(x types.Key, n int) types.KeyV {
+	res := make(types.KeyV, n)
+	if n == 0 {
+		return res
+	}
+	res[0] = keyIdentity
+	if n == 1 {
+		return res
+	}
+	res[1] = x
+	for i := 2; i < n; i++ {
+		res[i] = scMul(res[i-1], x)
+	}
+	return res
+}
+
+// vectorPowerSum returns 1 + x + ... + x^(n-1).
+func vectorPowerSum(x types.Key, n int) types.Key {
+	sum := keyZero
+	p := keyIdentity
+	for i := 0; i < n; i++ {
+		sum = scAdd(sum, p)
+		p = scMul(p, x)
+	}
+	return sum
+}
+
+func innerProduct(a, b types.KeyV) types.Key {
+	res := keyZero
+	for i := range a {
+		res = scMulAdd(a[i], b[i], res)
+	}
+	return res
+}
+
+func hashCacheMash(hashCache *types.Key, keys ...types.Key) types.Key {
+	*hashCache = hashKeysToScalar(append(types.KeyV{*hashCache}, keys...)...)
+	return *hashCache
+}
+
+// bulletproofLogM returns M and log2(M) for count amounts.
+func bulletproofLogM(count int) (int, int) {
+	logM, M := 0, 1
+	for M <= bulletproofMaxM && M < count {
+		logM++
+		M = 1 << uint(logM)
+	}
+	return M, logM
+}
+
+func log2(n int) int {
+	r := 0
+	for (1 << uint(r)) < n {
+		r++
+	}
+	return r
+}
+
+// crossVectorExponent8 returns
+// (sum a[ao+i]*A[Ao+i] + b[bo+i]*scale[Bo+i]*B[Bo+i] + extra*extraPoint)/8.
+func crossVectorExponent8(size int, A []edwards25519.ExtendedGroupElement, Ao int, B []edwards25519.ExtendedGroupElement, Bo int,
+	a types.KeyV, ao int, b types.KeyV, bo int, scale types.KeyV, extraPoint *edwards25519.ExtendedGroupElement, extraScalar types.Key) types.Key {
+	scalars := make(types.KeyV, 0, 2*size+1)
+	points := make([]edwards25519.ExtendedGroupElement, 0, 2*size+1)
+	for i := 0; i < size; i++ {
+		scalars = append(scalars, scMul(a[ao+i], keyInvEight))
+		points = append(points, A[Ao+i])
+		tmp := scMul(b[bo+i], keyInvEight)
+		if scale != nil {
+			tmp = scMul(tmp, scale[Bo+i])
+		}
+		scalars = append(scalars, tmp)
+		points = append(points, B[Bo+i])
+	}
+	scalars = append(scalars, scMul(extraScalar, keyInvEight))
+	points = append(points, *extraPoint)
+	r := multiexp(scalars, points)
+	return p3ToKey(&r)
+}
+
+// hadamardFold folds v in half: v[i] = a*scale[i]*v[i] + b*scale[sz+i]*v[sz+i].
+func hadamardFold(v []edwards25519.ExtendedGroupElement, scale types.KeyV, a, b types.Key) []edwards25519.ExtendedGroupElement {
+	sz := len(v) / 2
+	for n := 0; n < sz; n++ {
+		sa, sb := a, b
+		if scale != nil {
+			sa = scMul(a, scale[n])
+			sb = scMul(b, scale[sz+n])
+		}
+		l := geScalarMult(sa, &v[n])
+		r := geScalarMult(sb, &v[sz+n])
+		v[n] = geAdd(&l, &r)
+	}
+	return v[:sz]
+}
+
+// proveBulletproof is bulletproof_PROVE(sv, gamma) for amounts of bits bits.
+func proveBulletproof(sv, gamma types.KeyV, bits int) (*types.Bulletproof, error) {
+	if len(sv) != len(gamma) {
+		return nil, fmt.Errorf("Incompatible sizes of sv and gamma")
+	}
+	if len(sv) == 0 {
+		return nil, fmt.Errorf("sv is empty")
+	}
+	for i := range sv {
+		if !isReduced(&sv[i]) {
+			return nil, fmt.Errorf("Invalid sv input")
+		}
+		if !isReduced(&gamma[i]) {
+			return nil, fmt.Errorf("Invalid gamma input")
+		}
+	}
+
+	N := bits
+	logN := log2(N)
+	M, logM := bulletproofLogM(len(sv))
+	if M > bulletproofMaxM {
+		return nil, fmt.Errorf("sv/gamma are too large")
+	}
+	logMN := logM + logN
+	MN := M * N
+	Gi, Hi := bulletproofGenerators(MN)
+
+	minusOne := scSub(keyZero, keyIdentity)
+	minusInvEight := scSub(keyZero, keyInvEight)
+	twoN := vectorPowers(types.Key{2}, N)
+
+	V := make(types.KeyV, len(sv))
+	for i := range sv {
+		gamma8 := scMul(gamma[i], keyInvEight)
+		sv8 := scMul(sv[i], keyInvEight)
+		c, err := AddKeys2(gamma8, sv8, keyH)
+		if err != nil {
+			return nil, err
+		}
+		V[i] = c
+	}
+
+	aL := make(types.KeyV, MN)
+	aR := make(types.KeyV, MN)
+	aL8 := make(types.KeyV, MN)
+	aR8 := make(types.KeyV, MN)
+	for j := 0; j < M; j++ {
+		for i := N - 1; i >= 0; i-- {
+			if j < len(sv) && sv[j][i/8]&(1<<(uint(i)%8)) != 0 {
+				aL[j*N+i] = keyIdentity
+				aL8[j*N+i] = keyInvEight
+				aR[j*N+i] = keyZero
+				aR8[j*N+i] = keyZero
+			} else {
+				aL[j*N+i] = keyZero
+				aL8[j*N+i] = keyZero
+				aR[j*N+i] = minusOne
+				aR8[j*N+i] = minusInvEight
+			}
+		}
+	}
+
+	for {
+		hashCache := hashKeysToScalar(V...)
+
+		// PAPER LINES 38-39
+		alpha := randomScalar()
+		ve := vectorExponent(Gi, Hi, aL8, aR8)
+		aG := geScalarMultBase(scMul(alpha, keyInvEight))
+		Ap := geAdd(&ve, &aG)
+		A := p3ToKey(&Ap)
+
+		// PAPER LINES 40-42
+		sL := make(types.KeyV, MN)
+		sR := make(types.KeyV, MN)
+		for i := 0; i < MN; i++ {
+			sL[i] = randomScalar()
+			sR[i] = randomScalar()
+		}
+		rho := randomScalar()
+		ve = vectorExponent(Gi, Hi, sL, sR)
+		rG := geScalarMultBase(rho)
+		Sp := geAdd(&ve, &rG)
+		Sp = geScalarMult(keyInvEight, &Sp)
+		S := p3ToKey(&Sp)
+
+		// PAPER LINES 43-45
+		y := hashCacheMash(&hashCache, A, S)
+		if y == keyZero {
+			continue
+		}
+		z := hashKeysToScalar(y)
+		hashCache = z
+		if z == keyZero {
+			continue
+		}
+
+		// Polynomial construction by coefficients
+		l0 := make(types.KeyV, MN)
+		for i := range l0 {
+			l0[i] = scSub(aL[i], z)
+		}
+		l1 := sL
+
+		// This computes the ugly sum/concatenation from PAPER LINE 65
+		zpow := vectorPowers(z, M+2)
+		zeroTwos := make(types.KeyV, MN)
+		for i := 0; i < MN; i++ {
+			j := i/N + 1
+			zeroTwos[i] = scMul(zpow[1+j], twoN[i%N])
+		}
+
+		yMN := vectorPowers(y, MN)
+		r0 := make(types.KeyV, MN)
+		r1 := make(types.KeyV, MN)
+		for i := 0; i < MN; i++ {
+			r0[i] = scAdd(scMul(scAdd(aR[i], z), yMN[i]), zeroTwos[i])
+			r1[i] = scMul(yMN[i], sR[i])
+		}
+
+		// Polynomial construction before PAPER LINE 46
+		t1 := scAdd(innerProduct(l0, r1), innerProduct(l1, r0))
+		t2 := innerProduct(l1, r1)
+
+		// PAPER LINES 47-48
+		tau1, tau2 := randomScalar(), randomScalar()
+		T1, err := AddKeys2(scMul(tau1, keyInvEight), scMul(t1, keyInvEight), keyH)
+		if err != nil {
+			return nil, err
+		}
+		T2, err := AddKeys2(scMul(tau2, keyInvEight), scMul(t2, keyInvEight), keyH)
+		if err != nil {
+			return nil, err
+		}
+
+		// PAPER LINES 49-51
+		x := hashCacheMash(&hashCache, z, T1, T2)
+		if x == keyZero {
+			continue
+		}
+
+		// PAPER LINES 52-53
+		taux := scMul(tau1, x)
+		xsq := scMul(x, x)
+		taux = scMulAdd(tau2, xsq, taux)
+		for j := 1; j <= len(sv); j++ {
+			taux = scMulAdd(zpow[j+1], gamma[j-1], taux)
+		}
+		mu := scMulAdd(x, rho, alpha)
+
+		// PAPER LINES 54-57
+		l := make(types.KeyV, MN)
+		r := make(types.KeyV, MN)
+		for i := 0; i < MN; i++ {
+			l[i] = scMulAdd(l1[i], x, l0[i])
+			r[i] = scMulAdd(r1[i], x, r0[i])
+		}
+		t := innerProduct(l, r)
+
+		// PAPER LINES 32-33
+		xIP := hashCacheMash(&hashCache, x, taux, mu, t)
+		if xIP == keyZero {
+			continue
+		}
+
+		// These are used in the inner product rounds
+		nprime := MN
+		Gprime := make([]edwards25519.ExtendedGroupElement, MN)
+		Hprime := make([]edwards25519.ExtendedGroupElement, MN)
+		copy(Gprime, Gi)
+		copy(Hprime, Hi)
+		aprime := l
+		bprime := r
+		yinvpow := vectorPowers(scInvert(y), MN)
+		L := make(types.KeyV, logMN)
+		R := make(types.KeyV, logMN)
+		w := make(types.KeyV, logMN)
+
+		// PAPER LINE 13
+		scale := yinvpow
+		retry := false
+		for round := 0; nprime > 1; round++ {
+			// PAPER LINE 15
+			nprime /= 2
+
+			// PAPER LINES 16-17
+			cL := innerProduct(aprime[:nprime], bprime[nprime:])
+			cR := innerProduct(aprime[nprime:], bprime[:nprime])
+
+			// PAPER LINES 18-19
+			L[round] = crossVectorExponent8(nprime, Gprime, nprime, Hprime, 0, aprime, 0, bprime, nprime, scale, &geH, scMul(cL, xIP))
+			R[round] = crossVectorExponent8(nprime, Gprime, 0, Hprime, nprime, aprime, nprime, bprime, 0, scale, &geH, scMul(cR, xIP))
+
+			// PAPER LINES 21-22
+			w[round] = hashCacheMash(&hashCache, L[round], R[round])
+			if w[round] == keyZero {
+				retry = true
+				break
+			}
+
+			// PAPER LINES 24-25
+			winv := scInvert(w[round])
+			if nprime > 1 {
+				Gprime = hadamardFold(Gprime, nil, winv, w[round])
+				Hprime = hadamardFold(Hprime, scale, w[round], winv)
+			}
+
+			// PAPER LINES 28-29
+			na := make(types.KeyV, nprime)
+			nb := make(types.KeyV, nprime)
+			for i := 0; i < nprime; i++ {
+				na[i] = scAdd(scMul(aprime[i], w[round]), scMul(aprime[nprime+i], winv))
+				nb[i] = scAdd(scMul(bprime[i], winv), scMul(bprime[nprime+i], w[round]))
+			}
+			aprime, bprime = na, nb
+			scale = nil
+		}
+		if retry {
+			continue
+		}
+
+		return &types.Bulletproof{
+			V: V, A: A, S: S, T1: T1, T2: T2,
+			Taux: taux, Mu: mu,
+			L: L, R: R,
+			Aa: aprime[0], B: bprime[0], T: t,
+		}, nil
+	}
+}
+
+// vectorExponent returns sum a[i]*Gi[i] + b[i]*Hi[i].
+func vectorExponent(Gi, Hi []edwards25519.ExtendedGroupElement, a, b types.KeyV) edwards25519.ExtendedGroupElement {
+	scalars := make(types.KeyV, 0, 2*len(a))
+	points := make([]edwards25519.ExtendedGroupElement, 0, 2*len(a))
+	for i := range a {
+		scalars = append(scalars, a[i], b[i])
+		points = append(points, Gi[i], Hi[i])
+	}
+	return multiexp(scalars, points)
+}
+
+// verifyBulletproof is bulletproof_VERIFY for a single proof over amounts of
+// bits bits.
+func verifyBulletproof(proof *types.Bulletproof, bits int) bool {
+	// sanity and figure out which proof is longest
+	if !isReduced(&proof.Taux) || !isReduced(&proof.Mu) || !isReduced(&proof.Aa) ||
+		!isReduced(&proof.B) || !isReduced(&proof.T) {
+		return false
+	}
+	if len(proof.V) < 1 || len(proof.L) != len(proof.R) || len(proof.L) == 0 {
+		return false
+	}
+
+	N := bits
+	logN := log2(N)
+	M, logM := bulletproofLogM(len(proof.V))
+	if M > bulletproofMaxM || len(proof.L) != logN+logM {
+		return false
+	}
+	MN := M * N
+	rounds := logM + logN
+	Gi, Hi := bulletproofGenerators(MN)
+
+	// Reconstruct the challenges
+	hashCache := hashKeysToScalar(proof.V...)
+	y := hashCacheMash(&hashCache, proof.A, proof.S)
+	if y == keyZero {
+		return false
+	}
+	z := hashKeysToScalar(y)
+	hashCache = z
+	if z == keyZero {
+		return false
+	}
+	x := hashCacheMash(&hashCache, z, proof.T1, proof.T2)
+	if x == keyZero {
+		return false
+	}
+	xIP := hashCacheMash(&hashCache, x, proof.Taux, proof.Mu, proof.T)
+	if xIP == keyZero {
+		return false
+	}
+	w := make(types.KeyV, rounds)
+	winv := make(types.KeyV, rounds)
+	for i := 0; i < rounds; i++ {
+		w[i] = hashCacheMash(&hashCache, proof.L[i], proof.R[i])
+		if w[i] == keyZero {
+			return false
+		}
+		winv[i] = scInvert(w[i])
+	}
+
+	decode8 := func(k types.Key) (edwards25519.ExtendedGroupElement, bool) {
+		p, err := decodePoint(&k)
+		if err != nil {
+			return p, false
+		}
+		return geMul8(&p), true
+	}
+
+	// PAPER LINE 61: t*H + taux*G == sum z^(j+2)*V_j + k*H + x*T1 + x^2*T2
+	zpow := vectorPowers(z, M+3)
+	ip1y := vectorPowerSum(y, MN)
+	ip12 := vectorPowerSum(types.Key{2}, N)
+	k := scMulSub(zpow[2], ip1y, keyZero)
+	for j := 1; j <= M; j++ {
+		k = scMulSub(zpow[j+2], ip12, k)
+	}
+	k = scMulAdd(z, ip1y, k)
+
+	lhs, err := AddKeys2(proof.Taux, proof.T, keyH)
+	if err != nil {
+		return false
+	}
+	scalars := make(types.KeyV, 0, len(proof.V)+3)
+	points := make([]edwards25519.ExtendedGroupElement, 0, len(proof.V)+3)
+	for j := range proof.V {
+		p, ok := decode8(proof.V[j])
+		if !ok {
+			return false
+		}
+		scalars = append(scalars, zpow[j+2])
+		points = append(points, p)
+	}
+	T1, ok := decode8(proof.T1)
+	if !ok {
+		return false
+	}
+	T2, ok := decode8(proof.T2)
+	if !ok {
+		return false
+	}
+	scalars = append(scalars, k, x, scMul(x, x))
+	points = append(points, geH, T1, T2)
+	rhs := multiexp(scalars, points)
+	if p3ToKey(&rhs) != lhs {
+		return false
+	}
+
+	// PAPER LINE 62 with the inner product argument folded in
+	A, ok := decode8(proof.A)
+	if !ok {
+		return false
+	}
+	S, ok := decode8(proof.S)
+	if !ok {
+		return false
+	}
+	scalars = make(types.KeyV, 0, 2*MN+2*rounds+3)
+	points = make([]edwards25519.ExtendedGroupElement, 0, 2*MN+2*rounds+3)
+	scalars = append(scalars, keyIdentity, x)
+	points = append(points, A, S)
+
+	minusZ := scSub(keyZero, z)
+	yinv := scInvert(y)
+	yinvpow := keyIdentity
+	twoN := vectorPowers(types.Key{2}, N)
+	for i := 0; i < MN; i++ {
+		g := proof.Aa
+		h := scMul(proof.B, yinvpow)
+		for j := 0; j < rounds; j++ {
+			if (i>>uint(rounds-j-1))&1 == 0 {
+				g = scMul(g, winv[j])
+				h = scMul(h, w[j])
+			} else {
+				g = scMul(g, w[j])
+				h = scMul(h, winv[j])
+			}
+		}
+		// G_i: -z - a*g_i
+		scalars = append(scalars, scSub(minusZ, g))
+		points = append(points, Gi[i])
+		// H_i: z + z^(2+i/N)*2^(i%N)*y^-i - b*h_i
+		hs := scMulAdd(scMul(zpow[2+i/N], twoN[i%N]), yinvpow, z)
+		scalars = append(scalars, scSub(hs, h))
+		points = append(points, Hi[i])
+		yinvpow = scMul(yinvpow, yinv)
+	}
+	for j := 0; j < rounds; j++ {
+		L, ok := decode8(proof.L[j])
+		if !ok {
+			return false
+		}
+		R, ok := decode8(proof.R[j])
+		if !ok {
+			return false
+		}
+		scalars = append(scalars, scMul(w[j], w[j]), scMul(winv[j], winv[j]))
+		points = append(points, L, R)
+	}
+	// H: (t - a*b)*x_ip, G: -mu
+	hScalar := scMul(scMulSub(proof.Aa, proof.B, proof.T), xIP)
+	scalars = append(scalars, hScalar)
+	points = append(points, geH)
+	sum := multiexp(scalars, points)
+	muG := geScalarMultBase(proof.Mu)
+	sum = geSub(&sum, &muG)
+	return p3ToKey(&sum) == keyIdentity
+}
+
+// proveRangeBulletproof is rct::proveRangeBulletproof: the masks are derived
+// from sk and the commitments are the proof's V.
+func proveRangeBulletproof(amounts, sk types.KeyV, bits int) (*types.Bulletproof, types.KeyV, types.KeyV, error) {
+	if len(amounts) != len(sk) {
+		return nil, nil, nil, fmt.Errorf("Invalid amounts/sk sizes")
+	}
+	masks := make(types.KeyV, len(amounts))
+	for i := range sk {
+		masks[i] = genCommitmentMask(sk[i])
+	}
+	proof, err := proveBulletproof(amounts, masks, bits)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c := make(types.KeyV, len(proof.V))
+	copy(c, proof.V)
+	return proof, c, masks, nil
+}
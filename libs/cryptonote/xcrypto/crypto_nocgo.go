@@ -0,0 +1,245 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/edwards25519"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+)
+
+// Pure-Go replacements for the ref10 helpers of libxcrypto. All points and
+// scalars travel as 32 byte keys exactly like the C++ rct::key.
+
+var (
+	keyZero     = types.Key{}
+	keyIdentity = types.Key{0x01}
+	keyL        = types.Key{0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10}
+	keyInvEight = types.Key{0x79, 0x2f, 0xdc, 0xe2, 0x29, 0xe5, 0x06, 0x61, 0xd0, 0xda, 0x1c, 0x7d, 0xb3, 0x9d, 0xd3, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06}
+	keyH        = types.Key{0x8b, 0x65, 0x59, 0x70, 0x15, 0x37, 0x99, 0xaf, 0x2a, 0xea, 0xdc, 0x9f, 0xf1, 0xad, 0xd0, 0xea, 0x6c, 0x72, 0x51, 0xd5, 0x41, 0x54, 0xcf, 0xa9, 0x2c, 0x17, 0x3a, 0x0d, 0xd3, 0x9c, 0x1f, 0x94}
+
+	geH edwards25519.ExtendedGroupElement
+)
+
+func init() {
+	if !geH.FromBytes(ek(&keyH)) {
+		panic("xcrypto: invalid H point")
+	}
+}
+
+func ek(k *types.Key) *edwards25519.Key {
+	return (*edwards25519.Key)(k)
+}
+
+func keccak(data ...[]byte) (h types.Key) {
+	d := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		d.Write(b)
+	}
+	d.Sum(h[:0])
+	return h
+}
+
+func keccakKeys(keys ...types.Key) types.Key {
+	d := sha3.NewLegacyKeccak256()
+	for i := range keys {
+		d.Write(keys[i][:])
+	}
+	var h types.Key
+	d.Sum(h[:0])
+	return h
+}
+
+// hashToScalar is crypto::hash_to_scalar: keccak reduced modulo l.
+func hashToScalar(data ...[]byte) types.Key {
+	h := keccak(data...)
+	edwards25519.ScReduce32(ek(&h))
+	return h
+}
+
+func hashKeysToScalar(keys ...types.Key) types.Key {
+	h := keccakKeys(keys...)
+	edwards25519.ScReduce32(ek(&h))
+	return h
+}
+
+func varint(v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return buf[:binary.PutUvarint(buf, v)]
+}
+
+// randomScalar returns a uniformly distributed scalar modulo l.
+func randomScalar() (s types.Key) {
+	var tmp [64]byte
+	if _, err := io.ReadFull(rand.Reader, tmp[:]); err != nil {
+		panic(fmt.Sprintf("xcrypto: read random: %v", err))
+	}
+	edwards25519.ScReduce(ek(&s), &tmp)
+	return s
+}
+
+func scCheck(s *types.Key) bool {
+	return edwards25519.ScValid(ek(s))
+}
+
+func scIsZero(s *types.Key) bool {
+	return edwards25519.ScIsZero(ek(s))
+}
+
+func scAdd(a, b types.Key) (s types.Key) {
+	edwards25519.ScAdd(ek(&s), ek(&a), ek(&b))
+	return s
+}
+
+func scSub(a, b types.Key) (s types.Key) {
+	edwards25519.ScSub(ek(&s), ek(&a), ek(&b))
+	return s
+}
+
+func scMul(a, b types.Key) (s types.Key) {
+	edwards25519.ScMul(ek(&s), ek(&a), ek(&b))
+	return s
+}
+
+// scMulAdd returns a*b+c.
+func scMulAdd(a, b, c types.Key) (s types.Key) {
+	edwards25519.ScMulAdd(ek(&s), ek(&a), ek(&b), ek(&c))
+	return s
+}
+
+// scMulSub returns c-a*b.
+func scMulSub(a, b, c types.Key) (s types.Key) {
+	edwards25519.ScMulSub(ek(&s), ek(&a), ek(&b), ek(&c))
+	return s
+}
+
+// scInvert returns 1/a by raising a to l-2.
+func scInvert(a types.Key) types.Key {
+	exp := keyL
+	exp[0] -= 2
+	res := keyIdentity
+	for i := 255; i >= 0; i-- {
+		res = scMul(res, res)
+		if exp[i/8]>>(uint(i)%8)&1 == 1 {
+			res = scMul(res, a)
+		}
+	}
+	return res
+}
+
+func d2h(amount uint64) (k types.Key) {
+	binary.LittleEndian.PutUint64(k[:8], amount)
+	return k
+}
+
+func decodePoint(k *types.Key) (p edwards25519.ExtendedGroupElement, err error) {
+	if !p.FromBytes(ek(k)) {
+		return p, fmt.Errorf("invalid point %x", k[:])
+	}
+	return p, nil
+}
+
+func p3ToKey(p *edwards25519.ExtendedGroupElement) (k types.Key) {
+	p.ToBytes(ek(&k))
+	return k
+}
+
+func p2ToKey(p *edwards25519.ProjectiveGroupElement) (k types.Key) {
+	p.ToBytes(ek(&k))
+	return k
+}
+
+func geAdd(a, b *edwards25519.ExtendedGroupElement) (r edwards25519.ExtendedGroupElement) {
+	var c edwards25519.CachedGroupElement
+	var t edwards25519.CompletedGroupElement
+	b.ToCached(&c)
+	edwards25519.GeAdd(&t, a, &c)
+	t.ToExtended(&r)
+	return r
+}
+
+func geSub(a, b *edwards25519.ExtendedGroupElement) (r edwards25519.ExtendedGroupElement) {
+	var c edwards25519.CachedGroupElement
+	var t edwards25519.CompletedGroupElement
+	b.ToCached(&c)
+	edwards25519.GeSub(&t, a, &c)
+	t.ToExtended(&r)
+	return r
+}
+
+func geMul8(p *edwards25519.ExtendedGroupElement) (r edwards25519.ExtendedGroupElement) {
+	var t edwards25519.CompletedGroupElement
+	var q edwards25519.ProjectiveGroupElement
+	p.ToProjective(&q)
+	edwards25519.GeMul8(&t, &q)
+	t.ToExtended(&r)
+	return r
+}
+
+func geScalarMult(a types.Key, p *edwards25519.ExtendedGroupElement) (r edwards25519.ExtendedGroupElement) {
+	edwards25519.GeScalarMultExtended(&r, ek(&a), p)
+	return r
+}
+
+func geScalarMultBase(a types.Key) (r edwards25519.ExtendedGroupElement) {
+	edwards25519.GeScalarMultBase(&r, ek(&a))
+	return r
+}
+
+// geDoubleScalarMultBase returns a*A + b*G.
+func geDoubleScalarMultBase(a types.Key, A *edwards25519.ExtendedGroupElement, b types.Key) types.Key {
+	var r edwards25519.ProjectiveGroupElement
+	edwards25519.GeDoubleScalarMultVartime(&r, ek(&a), A, ek(&b))
+	return p2ToKey(&r)
+}
+
+// geDoubleScalarMultPrecomp returns a*A + b*B, B given by its precomputation.
+func geDoubleScalarMultPrecomp(a types.Key, A *edwards25519.ExtendedGroupElement, b types.Key, B *[8]edwards25519.CachedGroupElement) types.Key {
+	var r edwards25519.ProjectiveGroupElement
+	edwards25519.GeDoubleScalarMultPrecompVartime(&r, ek(&a), A, ek(&b), B)
+	return p2ToKey(&r)
+}
+
+// hashToP3 is ge_fromfe_frombytes_vartime of keccak(k) multiplied by the
+// cofactor, the hash_to_ec of cryptonote.
+func hashToP3(k types.Key) edwards25519.ExtendedGroupElement {
+	h := keccak(k[:])
+	var p edwards25519.ProjectiveGroupElement
+	var t edwards25519.CompletedGroupElement
+	var r edwards25519.ExtendedGroupElement
+	p.FromBytes(ek(&h))
+	edwards25519.GeMul8(&t, &p)
+	t.ToExtended(&r)
+	return r
+}
+
+func hashToPoint(k types.Key) types.Key {
+	p := hashToP3(k)
+	return p3ToKey(&p)
+}
+
+// inPrimeSubgroup reports whether l*P is the identity.
+func inPrimeSubgroup(p *edwards25519.ExtendedGroupElement) bool {
+	r := geScalarMult(keyL, p)
+	return p3ToKey(&r) == keyIdentity
+}
+
+// multiexp sums scalars[i]*points[i].
+func multiexp(scalars []types.Key, points []edwards25519.ExtendedGroupElement) edwards25519.ExtendedGroupElement {
+	var sum edwards25519.ExtendedGroupElement
+	sum.Zero()
+	for i := range scalars {
+		if scIsZero(&scalars[i]) {
+			continue
+		}
+		t := geScalarMult(scalars[i], &points[i])
+		sum = geAdd(&sum, &t)
+	}
+	return sum
+}
@@ -0,0 +1,146 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/edwards25519"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+)
+
+// WordsToBytes convert words to recover_key
+func WordsToBytes(words string) (sec types.SecretKey, err error) {
+	b, err := mnemonicDecode(words)
+	if err != nil {
+		return sec, err
+	}
+	copy(sec[:], b)
+	return sec, nil
+}
+
+// BytesToWords convert recover_key to words
+func BytesToWords(sec types.SecretKey, lang string) (string, error) {
+	return mnemonicEncode(sec[:], lang)
+}
+
+// GenerateKeys generate secret_key and public_key
+func GenerateKeys(recoverKey types.SecretKey) (sk types.SecretKey, pk types.PublicKey) {
+	k := types.Key(recoverKey)
+	edwards25519.ScReduce32(ek(&k))
+	p := geScalarMultBase(k)
+	return types.SecretKey(k), types.PublicKey(p3ToKey(&p))
+}
+
+// SecretAdd : r = a+b
+func SecretAdd(a, b types.SecretKey) (r types.SecretKey) {
+	return types.SecretKey(scAdd(types.Key(a), types.Key(b)))
+}
+
+// GetSubaddressSecretKey get sub secret_key by index.
+// index is the minor index of a cryptonote subaddress, the major one is 0.
+func GetSubaddressSecretKey(main types.SecretKey, index uint32) (sub types.SecretKey) {
+	var idx [8]byte
+	binary.LittleEndian.PutUint32(idx[4:], index)
+	return types.SecretKey(hashToScalar([]byte("SubAddr\x00"), main[:], idx[:]))
+}
+
+// GetSubaddress get sub public_key pair by index
+func GetSubaddress(keys *types.AccountKey, index uint32) (addr types.AccountAddress) {
+	addr, err := TlvGetSubaddress(keys, index)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+// GenerateKeyDerivation generate KeyDerivation
+func GenerateKeyDerivation(pub types.PublicKey, sec types.SecretKey) (der types.KeyDerivation, err error) {
+	k := types.Key(pub)
+	p, err := decodePoint(&k)
+	if err != nil {
+		return der, fmt.Errorf("generate_key_derivation: %v", err)
+	}
+	p = geScalarMult(types.Key(sec), &p)
+	p = geMul8(&p)
+	return types.KeyDerivation(p3ToKey(&p)), nil
+}
+
+// DeriveSubaddressPublicKey derive public-key for subaddress.
+func DeriveSubaddressPublicKey(pub types.PublicKey, derivation types.KeyDerivation, outIndex int) (derPub types.PublicKey, err error) {
+	k := types.Key(pub)
+	p, err := decodePoint(&k)
+	if err != nil {
+		return derPub, fmt.Errorf("derive_subaddress_public_key: %v", err)
+	}
+	s := derivationToScalar(derivation, outIndex)
+	sG := geScalarMultBase(s)
+	r := geSub(&p, &sG)
+	return types.PublicKey(p3ToKey(&r)), nil
+}
+
+// DeriveSecretKey derive sub secret-key
+func DeriveSecretKey(derivation types.KeyDerivation, outIndex int, sec types.SecretKey) (derSec types.SecretKey, err error) {
+	s := derivationToScalar(derivation, outIndex)
+	return types.SecretKey(scAdd(types.Key(sec), s)), nil
+}
+
+// DerivePublicKey derive sub public-key
+func DerivePublicKey(derivation types.KeyDerivation, outIndex int, pub types.PublicKey) (derPub types.PublicKey, err error) {
+	k := types.Key(pub)
+	p, err := decodePoint(&k)
+	if err != nil {
+		return derPub, fmt.Errorf("derive_public_key: %v", err)
+	}
+	s := derivationToScalar(derivation, outIndex)
+	sG := geScalarMultBase(s)
+	r := geAdd(&p, &sG)
+	return types.PublicKey(p3ToKey(&r)), nil
+}
+
+// SecretKeyToPublicKey get public-key from secret-key
+func SecretKeyToPublicKey(sec types.SecretKey) (pub types.PublicKey, err error) {
+	k := types.Key(sec)
+	if !scCheck(&k) {
+		return pub, fmt.Errorf("secret_key_to_public_key: invalid secret key")
+	}
+	p := geScalarMultBase(k)
+	return types.PublicKey(p3ToKey(&p)), nil
+}
+
+// GenerateKeyImage generate key_image
+func GenerateKeyImage(pub types.PublicKey, sec types.SecretKey) (ki types.KeyImage, err error) {
+	hp := hashToP3(types.Key(pub))
+	p := geScalarMult(types.Key(sec), &hp)
+	return types.KeyImage(p3ToKey(&p)), nil
+}
+
+// DerivationToScalar key-derivation to ec-scalar
+func DerivationToScalar(derivation types.KeyDerivation, outIndex int) (res types.EcScalar, err error) {
+	return types.EcScalar(derivationToScalar(derivation, outIndex)), nil
+}
+
+func derivationToScalar(derivation types.KeyDerivation, outIndex int) types.Key {
+	return hashToScalar(derivation[:], varint(uint64(outIndex)))
+}
+
+// getSubaddress returns the (view, spend) public keys of the index-th
+// subaddress: D = B + m*G and C = a*D with m the subaddress secret key.
+func getSubaddress(keys *types.AccountKey, index uint32) (addr types.AccountAddress, err error) {
+	if index == 0 {
+		return keys.Addr, nil
+	}
+	spend := types.Key(keys.Addr.SpendPublicKey)
+	B, err := decodePoint(&spend)
+	if err != nil {
+		return addr, err
+	}
+	m := GetSubaddressSecretKey(keys.ViewSKey, index)
+	M := geScalarMultBase(types.Key(m))
+	D := geAdd(&B, &M)
+	C := geScalarMult(types.Key(keys.ViewSKey), &D)
+	addr.SpendPublicKey = types.PublicKey(p3ToKey(&D))
+	addr.ViewPublicKey = types.PublicKey(p3ToKey(&C))
+	return addr, nil
+}
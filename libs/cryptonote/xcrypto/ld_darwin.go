@@ -1,3 +1,5 @@
+// +build !nacl,!js,!nocgo
+
 package xcrypto
 
 // #cgo LDFLAGS: -L./libs_darwin -lxcrypto -lboost_system -lboost_filesystem -lboost_thread -lboost_date_time -lboost_system -lboost_regex -lboost_chrono -lsodium -lssl -lcrypto  -lstdc++ -ldl -lpthread
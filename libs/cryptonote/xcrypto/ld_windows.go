@@ -1,3 +1,5 @@
+// +build !nacl,!js,!nocgo
+
 package xcrypto
 
 // #cgo LDFLAGS:-lxcrypto -lboost_system-mt -lboost_filesystem-mt -lboost_thread-mt -lboost_date_time-mt -lboost_system-mt -lboost_regex-mt -lboost_chrono-mt -lsodium -lssl -lcrypto  -lstdc++ -lpthread -lws2_32 -lcrypt32 -lIphlpapi -lhidapi
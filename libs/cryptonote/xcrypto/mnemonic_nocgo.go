@@ -0,0 +1,110 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"sync"
+)
+
+const (
+	mnemonicLanguage     = "English"
+	mnemonicPrefixLength = 3
+	mnemonicSeedWords    = 24
+)
+
+var (
+	englishOnce     sync.Once
+	englishPrefixes map[string]uint32
+)
+
+func englishPrefixIndex() map[string]uint32 {
+	englishOnce.Do(func() {
+		englishPrefixes = make(map[string]uint32, len(englishWords))
+		for i, w := range englishWords {
+			englishPrefixes[wordPrefix(w)] = uint32(i)
+		}
+	})
+	return englishPrefixes
+}
+
+func wordPrefix(w string) string {
+	r := []rune(w)
+	if len(r) > mnemonicPrefixLength {
+		r = r[:mnemonicPrefixLength]
+	}
+	return string(r)
+}
+
+func checksumIndex(words []string) int {
+	var trimmed strings.Builder
+	for _, w := range words {
+		trimmed.WriteString(wordPrefix(w))
+	}
+	return int(crc32.ChecksumIEEE([]byte(trimmed.String())) % uint32(len(words)))
+}
+
+// mnemonicEncode is electrum_words::bytes_to_words for a 32 byte key.
+func mnemonicEncode(src []byte, lang string) (string, error) {
+	if lang != mnemonicLanguage {
+		return "", fmt.Errorf("unsupported mnemonic language %q", lang)
+	}
+	if len(src)%4 != 0 {
+		return "", fmt.Errorf("invalid mnemonic source length %d", len(src))
+	}
+
+	n := uint32(len(englishWords))
+	words := make([]string, 0, len(src)/4*3+1)
+	for i := 0; i < len(src); i += 4 {
+		v := binary.LittleEndian.Uint32(src[i:])
+		w1 := v % n
+		w2 := (v/n + w1) % n
+		w3 := (v/n/n + w2) % n
+		words = append(words, englishWords[w1], englishWords[w2], englishWords[w3])
+	}
+	words = append(words, words[checksumIndex(words)])
+	return strings.Join(words, " "), nil
+}
+
+// mnemonicDecode is electrum_words::words_to_bytes for a 24 word seed with
+// an optional checksum word.
+func mnemonicDecode(s string) ([]byte, error) {
+	words := strings.Fields(s)
+	if len(words) != mnemonicSeedWords && len(words) != mnemonicSeedWords+1 {
+		return nil, fmt.Errorf("invalid mnemonic word count %d", len(words))
+	}
+
+	index := englishPrefixIndex()
+	indices := make([]uint32, mnemonicSeedWords)
+	for i := 0; i < mnemonicSeedWords; i++ {
+		idx, ok := index[wordPrefix(words[i])]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic word %q", words[i])
+		}
+		indices[i] = idx
+	}
+
+	if len(words) == mnemonicSeedWords+1 {
+		seed := words[:mnemonicSeedWords]
+		if wordPrefix(seed[checksumIndex(seed)]) != wordPrefix(words[mnemonicSeedWords]) {
+			return nil, fmt.Errorf("invalid mnemonic checksum")
+		}
+	}
+
+	n := uint32(len(englishWords))
+	dst := make([]byte, 0, mnemonicSeedWords/3*4)
+	for i := 0; i < mnemonicSeedWords; i += 3 {
+		w1, w2, w3 := indices[i], indices[i+1], indices[i+2]
+		v := w1 + n*((n-w1+w2)%n) + n*n*((n-w2+w3)%n)
+		if v%n != w1 {
+			return nil, fmt.Errorf("invalid mnemonic")
+		}
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], v)
+		dst = append(dst, b[:]...)
+	}
+	return dst, nil
+}
@@ -0,0 +1,212 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"fmt"
+
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/edwards25519"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+)
+
+// ---------------------------------------------------------------------------------
+
+// GenerateRingSignature --
+// Only the ring of size one used by the chain fits the single Signature
+// returned here.
+func GenerateRingSignature(prefix types.Hash, keyImage types.KeyImage, pks []types.PublicKey, sec types.SecretKey, secIndex uint) (*types.Signature, error) {
+	if len(pks) == 0 {
+		return nil, fmt.Errorf("pubkeys zero")
+	}
+	if len(pks) != 1 || secIndex != 0 {
+		return nil, fmt.Errorf("x_generate_ring_signature: unsupported ring size %d", len(pks))
+	}
+
+	ki := types.Key(keyImage)
+	if _, err := decodePoint(&ki); err != nil {
+		return nil, fmt.Errorf("x_generate_ring_signature: invalid key image")
+	}
+
+	// buf = prefix || k*G || k*Hp(P)
+	k := randomScalar()
+	kG := geScalarMultBase(k)
+	hp := hashToP3(types.Key(pks[0]))
+	kH := geScalarMult(k, &hp)
+	a, b := p3ToKey(&kG), p3ToKey(&kH)
+	h := hashToScalar(prefix[:], a[:], b[:])
+
+	var sig types.Signature
+	sig.C = types.EcScalar(h)
+	sig.R = types.EcScalar(scMulSub(h, types.Key(sec), k))
+	return &sig, nil
+}
+
+// CheckRingSignature true means RingSignature ok.
+func CheckRingSignature(prefix types.Hash, keyImage types.KeyImage, pks []types.PublicKey, sig *types.Signature) bool {
+	if len(pks) == 0 {
+		panic("pubkeys zero")
+	}
+	if len(pks) != 1 {
+		return false
+	}
+
+	ki := types.Key(keyImage)
+	image, err := decodePoint(&ki)
+	if err != nil || !inPrimeSubgroup(&image) {
+		return false
+	}
+	var imagePre [8]edwards25519.CachedGroupElement
+	edwards25519.GePrecompute(&imagePre, &image)
+
+	c, r := types.Key(sig.C), types.Key(sig.R)
+	if !scCheck(&c) || !scCheck(&r) {
+		return false
+	}
+	pk := types.Key(pks[0])
+	P, err := decodePoint(&pk)
+	if err != nil {
+		return false
+	}
+	a := geDoubleScalarMultBase(c, &P, r)
+	hp := hashToP3(pk)
+	b := geDoubleScalarMultPrecomp(r, &hp, c, &imagePre)
+	h := hashToScalar(prefix[:], a[:], b[:])
+	h = scSub(h, c)
+	return scIsZero(&h)
+}
+
+func ScalarmultKey(p, a types.Key) (ret types.Key, err error) {
+	P, err := decodePoint(&p)
+	if err != nil {
+		return ret, err
+	}
+	r := geScalarMult(a, &P)
+	return p3ToKey(&r), nil
+}
+
+func ScalarmultBase(a types.Key) (ret types.Key) {
+	edwards25519.ScReduce32(ek(&a))
+	r := geScalarMultBase(a)
+	return p3ToKey(&r)
+}
+
+func SkpkGen() (sk types.Key, pk types.Key) {
+	sk = randomScalar()
+	return sk, ScalarmultBase(sk)
+}
+
+func ScalarmultH(a types.Key) (ret types.Key) {
+	r := geScalarMult(a, &geH)
+	return p3ToKey(&r)
+}
+func ZeroCommit(amount types.Lk_amount) (ret types.Key, err error) {
+	one := keyIdentity
+	return GenC(one, amount)
+}
+func CheckKey(key types.PublicKey) bool {
+	k := types.Key(key)
+	_, err := decodePoint(&k)
+	return err == nil
+}
+
+// EcdhDecode decode ecdhTuple info
+func EcdhDecode(masked *types.EcdhTuple, sharedSec types.Key, shortAmount bool) bool {
+	if shortAmount {
+		masked.Mask = genCommitmentMask(sharedSec)
+		xor8(&masked.Amount, ecdhHash(sharedSec))
+		return true
+	}
+	sharedSec1 := hashToScalar(sharedSec[:])
+	sharedSec2 := hashToScalar(sharedSec1[:])
+	masked.Mask = scSub(masked.Mask, sharedSec1)
+	masked.Amount = scSub(masked.Amount, sharedSec2)
+	return true
+}
+
+// EcdhEncode encode ecdhTuple info
+func EcdhEncode(unmasked *types.EcdhTuple, sharedSec types.Key, shortAmount bool) bool {
+	if shortAmount {
+		unmasked.Mask = keyZero
+		xor8(&unmasked.Amount, ecdhHash(sharedSec))
+		return true
+	}
+	sharedSec1 := hashToScalar(sharedSec[:])
+	sharedSec2 := hashToScalar(sharedSec1[:])
+	unmasked.Mask = scAdd(unmasked.Mask, sharedSec1)
+	unmasked.Amount = scAdd(unmasked.Amount, sharedSec2)
+	return true
+}
+
+func ecdhHash(k types.Key) types.Key {
+	return keccak([]byte("amount"), k[:])
+}
+
+func xor8(v *types.Key, k types.Key) {
+	for i := 0; i < 8; i++ {
+		v[i] ^= k[i]
+	}
+}
+
+func genCommitmentMask(sk types.Key) types.Key {
+	return hashToScalar([]byte("commitment_mask"), sk[:])
+}
+
+//Computes 8P
+func Scalarmult8(p types.Key) (ret types.Key, err error) {
+	P, err := decodePoint(&p)
+	if err != nil {
+		return ret, err
+	}
+	r := geMul8(&P)
+	return p3ToKey(&r), nil
+}
+
+func ScAdd(a, b types.EcScalar) (ret types.Key) {
+	return scAdd(types.Key(a), types.Key(b))
+}
+
+func ScSub(a, b types.EcScalar) (ret types.Key) {
+	return scSub(types.Key(a), types.Key(b))
+}
+
+func SkGen() (ret types.Key) {
+	return randomScalar()
+}
+
+func GenC(a types.Key, amount types.Lk_amount) (ret types.Key, err error) {
+	return AddKeys2(a, d2h(uint64(amount)), keyH)
+}
+func AddKeys(a, b types.Key) (ret types.Key, err error) {
+	A, err := decodePoint(&a)
+	if err != nil {
+		return ret, err
+	}
+	B, err := decodePoint(&b)
+	if err != nil {
+		return ret, err
+	}
+	r := geAdd(&A, &B)
+	return p3ToKey(&r), nil
+}
+
+// AddKeys2 computes a*G + b*B.
+func AddKeys2(a, b, B types.Key) (ret types.Key, err error) {
+	P, err := decodePoint(&B)
+	if err != nil {
+		return ret, err
+	}
+	return geDoubleScalarMultBase(b, &P, a), nil
+}
+
+func addKeyV(keys types.KeyV) (types.Key, error) {
+	var sum edwards25519.ExtendedGroupElement
+	sum.Zero()
+	for i := range keys {
+		P, err := decodePoint(&keys[i])
+		if err != nil {
+			return keyZero, err
+		}
+		sum = geAdd(&sum, &P)
+	}
+	return p3ToKey(&sum), nil
+}
@@ -0,0 +1,415 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/edwards25519"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+)
+
+// MLSAG signatures and RingCT verification, following src/ringct/rctSigs.cc.
+
+// mlsagGen is MLSAG_Gen with dsRows double spendable rows.
+func mlsagGen(message types.Key, pk types.KeyM, xx types.KeyV, kLRki *types.MultisigKLRki, mscout *types.Key, index int, dsRows int) (*types.MgSig, error) {
+	cols := len(pk)
+	if cols < 2 {
+		return nil, fmt.Errorf("Error! What is c if cols = 1!")
+	}
+	if index >= cols {
+		return nil, fmt.Errorf("Index out of range")
+	}
+	rows := len(pk[0])
+	if rows < 1 {
+		return nil, fmt.Errorf("Empty pk")
+	}
+	for i := 1; i < cols; i++ {
+		if len(pk[i]) != rows {
+			return nil, fmt.Errorf("pk is not rectangular")
+		}
+	}
+	if len(xx) != rows {
+		return nil, fmt.Errorf("Bad xx size")
+	}
+	if dsRows > rows {
+		return nil, fmt.Errorf("Bad dsRows size")
+	}
+	if (kLRki == nil) != (mscout == nil) {
+		return nil, fmt.Errorf("Only one of kLRki/mscout is present")
+	}
+	if kLRki != nil && dsRows != 1 {
+		return nil, fmt.Errorf("Multisig requires exactly 1 dsRows")
+	}
+
+	rv := &types.MgSig{
+		II: make(types.KeyV, dsRows),
+		Ss: make(types.KeyM, cols),
+	}
+	alpha := make(types.KeyV, rows)
+	Ip := make([][8]edwards25519.CachedGroupElement, dsRows)
+	toHash := make(types.KeyV, 1+3*dsRows+2*(rows-dsRows))
+	toHash[0] = message
+	for i := 0; i < dsRows; i++ {
+		toHash[3*i+1] = pk[index][i]
+		if kLRki != nil {
+			// multisig
+			alpha[i] = kLRki.K
+			toHash[3*i+2] = kLRki.L
+			toHash[3*i+3] = kLRki.R
+			rv.II[i] = kLRki.Ki
+		} else {
+			Hi := hashToP3(pk[index][i])
+			alpha[i] = randomScalar()
+			aG := geScalarMultBase(alpha[i])
+			aHP := geScalarMult(alpha[i], &Hi)
+			II := geScalarMult(xx[i], &Hi)
+			toHash[3*i+2] = p3ToKey(&aG)
+			toHash[3*i+3] = p3ToKey(&aHP)
+			rv.II[i] = p3ToKey(&II)
+		}
+		p, err := decodePoint(&rv.II[i])
+		if err != nil {
+			return nil, err
+		}
+		edwards25519.GePrecompute(&Ip[i], &p)
+	}
+	ndsRows := 3 * dsRows
+	for i, ii := dsRows, 0; i < rows; i, ii = i+1, ii+1 {
+		alpha[i] = randomScalar()
+		aG := geScalarMultBase(alpha[i])
+		toHash[ndsRows+2*ii+1] = pk[index][i]
+		toHash[ndsRows+2*ii+2] = p3ToKey(&aG)
+	}
+
+	c := hashKeysToScalar(toHash...)
+	cOld := c
+	i := (index + 1) % cols
+	if i == 0 {
+		rv.Cc = cOld
+	}
+	for i != index {
+		rv.Ss[i] = make(types.KeyV, rows)
+		for j := 0; j < rows; j++ {
+			rv.Ss[i][j] = randomScalar()
+		}
+		for j := 0; j < dsRows; j++ {
+			L, R, err := mlsagRowLR(rv.Ss[i][j], cOld, pk[i][j], &Ip[j])
+			if err != nil {
+				return nil, err
+			}
+			toHash[3*j+1] = pk[i][j]
+			toHash[3*j+2] = L
+			toHash[3*j+3] = R
+		}
+		for j, ii := dsRows, 0; j < rows; j, ii = j+1, ii+1 {
+			L, err := AddKeys2(rv.Ss[i][j], cOld, pk[i][j])
+			if err != nil {
+				return nil, err
+			}
+			toHash[ndsRows+2*ii+1] = pk[i][j]
+			toHash[ndsRows+2*ii+2] = L
+		}
+		c = hashKeysToScalar(toHash...)
+		cOld = c
+		i = (i + 1) % cols
+		if i == 0 {
+			rv.Cc = cOld
+		}
+	}
+	rv.Ss[index] = make(types.KeyV, rows)
+	for j := 0; j < rows; j++ {
+		rv.Ss[index][j] = scMulSub(c, xx[j], alpha[j])
+	}
+	if mscout != nil {
+		*mscout = c
+	}
+	return rv, nil
+}
+
+// mlsagRowLR returns L = ss*G + c*pk and R = ss*Hp(pk) + c*II.
+func mlsagRowLR(ss, c, pk types.Key, Ip *[8]edwards25519.CachedGroupElement) (types.Key, types.Key, error) {
+	L, err := AddKeys2(ss, c, pk)
+	if err != nil {
+		return L, keyZero, err
+	}
+	Hi := hashToP3(pk)
+	if p3ToKey(&Hi) == keyIdentity {
+		return L, keyZero, fmt.Errorf("Data hashed to point at infinity")
+	}
+	R := geDoubleScalarMultPrecomp(ss, &Hi, c, Ip)
+	return L, R, nil
+}
+
+// mlsagVer is MLSAG_Ver with dsRows double spendable rows.
+func mlsagVer(message types.Key, pk types.KeyM, rv *types.MgSig, dsRows int) bool {
+	cols := len(pk)
+	if cols < 2 {
+		return false
+	}
+	rows := len(pk[0])
+	if rows < 1 {
+		return false
+	}
+	for i := 1; i < cols; i++ {
+		if len(pk[i]) != rows {
+			return false
+		}
+	}
+	if len(rv.II) != dsRows || len(rv.Ss) != cols || dsRows > rows {
+		return false
+	}
+	for i := 0; i < cols; i++ {
+		if len(rv.Ss[i]) != rows {
+			return false
+		}
+		for j := 0; j < rows; j++ {
+			if !scCheck(&rv.Ss[i][j]) {
+				return false
+			}
+		}
+	}
+	if !scCheck(&rv.Cc) {
+		return false
+	}
+
+	Ip := make([][8]edwards25519.CachedGroupElement, dsRows)
+	for i := 0; i < dsRows; i++ {
+		p, err := decodePoint(&rv.II[i])
+		if err != nil {
+			return false
+		}
+		edwards25519.GePrecompute(&Ip[i], &p)
+	}
+	ndsRows := 3 * dsRows
+	toHash := make(types.KeyV, 1+3*dsRows+2*(rows-dsRows))
+	toHash[0] = message
+	cOld := rv.Cc
+	for i := 0; i < cols; i++ {
+		for j := 0; j < dsRows; j++ {
+			L, R, err := mlsagRowLR(rv.Ss[i][j], cOld, pk[i][j], &Ip[j])
+			if err != nil {
+				return false
+			}
+			toHash[3*j+1] = pk[i][j]
+			toHash[3*j+2] = L
+			toHash[3*j+3] = R
+		}
+		for j, ii := dsRows, 0; j < rows; j, ii = j+1, ii+1 {
+			L, err := AddKeys2(rv.Ss[i][j], cOld, pk[i][j])
+			if err != nil {
+				return false
+			}
+			toHash[ndsRows+2*ii+1] = pk[i][j]
+			toHash[ndsRows+2*ii+2] = L
+		}
+		c := hashKeysToScalar(toHash...)
+		if c == keyZero {
+			return false
+		}
+		cOld = c
+	}
+	c := scSub(cOld, rv.Cc)
+	return scIsZero(&c)
+}
+
+// proveRctMGSimple is rct::proveRctMGSimple: a simple MLSAG over the
+// (dest, mask - Cout) matrix.
+func proveRctMGSimple(message types.Key, pubs types.CtkeyV, inSk types.Ctkey, a, Cout types.Key, kLRki *types.MultisigKLRki, mscout *types.Key, index uint32) (*types.MgSig, error) {
+	cols := len(pubs)
+	if cols < 1 {
+		return nil, fmt.Errorf("Empty pubs")
+	}
+	C, err := decodePoint(&Cout)
+	if err != nil {
+		return nil, err
+	}
+	sk := types.KeyV{inSk.Dest, scSub(inSk.Mask, a)}
+	M := make(types.KeyM, cols)
+	for i := 0; i < cols; i++ {
+		p, err := decodePoint(&pubs[i].Mask)
+		if err != nil {
+			return nil, err
+		}
+		d := geSub(&p, &C)
+		M[i] = types.KeyV{pubs[i].Dest, p3ToKey(&d)}
+	}
+	return mlsagGen(message, M, sk, kLRki, mscout, int(index), 1)
+}
+
+// verRctMGSimple is rct::verRctMGSimple.
+func verRctMGSimple(message types.Key, mg *types.MgSig, pubs types.CtkeyV, Cout types.Key) bool {
+	cols := len(pubs)
+	if cols < 1 {
+		return false
+	}
+	C, err := decodePoint(&Cout)
+	if err != nil {
+		return false
+	}
+	M := make(types.KeyM, cols)
+	for i := 0; i < cols; i++ {
+		p, err := decodePoint(&pubs[i].Mask)
+		if err != nil {
+			return false
+		}
+		d := geSub(&p, &C)
+		M[i] = types.KeyV{pubs[i].Dest, p3ToKey(&d)}
+	}
+	return mlsagVer(message, M, mg, 1)
+}
+
+func isRctBulletproof(t uint8) bool {
+	return t == uint8(types.RCTTypeBulletproof) || t == uint8(types.RCTTypeBulletproof2)
+}
+
+// serializeRctSigBase is rctSigBase::serialize_rctsig_base in binary form.
+func serializeRctSigBase(rv *types.RctSig, inputs, outputs int) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(rv.Type)
+	if rv.Type == uint8(types.RCTTypeNull) {
+		return buf.Bytes(), nil
+	}
+	if rv.Type != uint8(types.RCTTypeFull) && rv.Type != uint8(types.RCTTypeSimple) && !isRctBulletproof(rv.Type) {
+		return nil, fmt.Errorf("unknown rct type %d", rv.Type)
+	}
+	buf.Write(varint(uint64(rv.TxnFee)))
+	if rv.Type == uint8(types.RCTTypeSimple) {
+		if len(rv.PseudoOuts) != inputs {
+			return nil, fmt.Errorf("Mismatched sizes of pseudoOuts and inputs")
+		}
+		for i := range rv.PseudoOuts {
+			buf.Write(rv.PseudoOuts[i][:])
+		}
+	}
+	if len(rv.EcdhInfo) != outputs {
+		return nil, fmt.Errorf("Mismatched sizes of ecdhInfo and outputs")
+	}
+	for i := range rv.EcdhInfo {
+		if rv.Type == uint8(types.RCTTypeBulletproof2) {
+			buf.Write(rv.EcdhInfo[i].Amount[:8])
+		} else {
+			buf.Write(rv.EcdhInfo[i].Mask[:])
+			buf.Write(rv.EcdhInfo[i].Amount[:])
+		}
+	}
+	if len(rv.OutPk) != outputs {
+		return nil, fmt.Errorf("Mismatched sizes of outPk and outputs")
+	}
+	for i := range rv.OutPk {
+		buf.Write(rv.OutPk[i].Mask[:])
+	}
+	return buf.Bytes(), nil
+}
+
+// getPreMlsagHash is rct::get_pre_mlsag_hash.
+func getPreMlsagHash(rv *types.RctSig) (types.Key, error) {
+	if len(rv.MixRing) == 0 {
+		return keyZero, fmt.Errorf("Empty mixRing")
+	}
+	inputs := len(rv.MixRing)
+	if rv.Type != uint8(types.RCTTypeSimple) && !isRctBulletproof(rv.Type) {
+		inputs = len(rv.MixRing[0])
+	}
+	base, err := serializeRctSigBase(rv, inputs, len(rv.EcdhInfo))
+	if err != nil {
+		return keyZero, err
+	}
+
+	var kv types.KeyV
+	if isRctBulletproof(rv.Type) {
+		for _, p := range rv.P.Bulletproofs {
+			// V are not hashed as they're expanded from outPk.mask
+			// (and thus hashed as part of rctSigBase above)
+			kv = append(kv, p.A, p.S, p.T1, p.T2, p.Taux, p.Mu)
+			kv = append(kv, p.L...)
+			kv = append(kv, p.R...)
+			kv = append(kv, p.Aa, p.B, p.T)
+		}
+	} else {
+		for _, r := range rv.P.RangeSigs {
+			for n := 0; n < types.KEY64; n++ {
+				kv = append(kv, r.Asig.S0[n])
+			}
+			for n := 0; n < types.KEY64; n++ {
+				kv = append(kv, r.Asig.S1[n])
+			}
+			kv = append(kv, r.Asig.Ee)
+			for n := 0; n < types.KEY64; n++ {
+				kv = append(kv, r.Ci[n])
+			}
+		}
+	}
+	return keccakKeys(rv.Message, keccak(base), keccakKeys(kv...)), nil
+}
+
+// verRctSemanticsSimple is rct::verRctSemanticsSimple for a single rctSig.
+func verRctSemanticsSimple(rv *types.RctSig, bits int) bool {
+	bulletproof := isRctBulletproof(rv.Type)
+	if rv.Type != uint8(types.RCTTypeSimple) && !bulletproof {
+		return false
+	}
+	// range signatures are not supported by libxcrypto either
+	if !bulletproof {
+		return false
+	}
+	amounts := 0
+	for i := range rv.P.Bulletproofs {
+		amounts += len(rv.P.Bulletproofs[i].V)
+	}
+	if len(rv.OutPk) != amounts || len(rv.P.PseudoOuts) != len(rv.P.MGs) || len(rv.PseudoOuts) != 0 {
+		return false
+	}
+	if len(rv.OutPk) != len(rv.EcdhInfo) {
+		return false
+	}
+
+	masks := make(types.KeyV, len(rv.OutPk))
+	for i := range rv.OutPk {
+		masks[i] = rv.OutPk[i].Mask
+	}
+	sumOutpks, err := addKeyV(masks)
+	if err != nil {
+		return false
+	}
+	sumOutpks, err = AddKeys(ScalarmultH(d2h(uint64(rv.TxnFee))), sumOutpks)
+	if err != nil {
+		return false
+	}
+	sumPseudoOuts, err := addKeyV(rv.P.PseudoOuts)
+	if err != nil || sumPseudoOuts != sumOutpks {
+		return false
+	}
+	for i := range rv.P.Bulletproofs {
+		if !verifyBulletproof(&rv.P.Bulletproofs[i], bits) {
+			return false
+		}
+	}
+	return true
+}
+
+// verRctNonSemanticsSimple is rct::verRctNonSemanticsSimple.
+func verRctNonSemanticsSimple(rv *types.RctSig) bool {
+	bulletproof := isRctBulletproof(rv.Type)
+	if rv.Type != uint8(types.RCTTypeSimple) && !bulletproof {
+		return false
+	}
+	pseudoOuts := rv.PseudoOuts
+	if bulletproof {
+		pseudoOuts = rv.P.PseudoOuts
+	}
+	if len(pseudoOuts) != len(rv.MixRing) || len(rv.P.MGs) != len(rv.MixRing) {
+		return false
+	}
+	message, err := getPreMlsagHash(rv)
+	if err != nil {
+		return false
+	}
+	for i := range rv.MixRing {
+		if !verRctMGSimple(message, &rv.P.MGs[i], rv.MixRing[i], pseudoOuts[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"fmt"
+
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+)
+
+// The nocgo build keeps the Tlv* names of the cgo bridge but works on the Go
+// values directly, there is no tlv round trip to a C library.
+
+const (
+	bulletproofBits    = 64
+	bulletproofBits128 = 128
+)
+
+func TlvVerRctNotSemanticsSimple(rctsign *types.RctSig) bool {
+	return verRctNonSemanticsSimple(rctsign)
+}
+
+func TlvVerRctSimple(rctsign *types.RctSig) (error, bool) {
+	if !verRctSemanticsSimple(rctsign, bulletproofBits) {
+		return nil, false
+	}
+	return nil, verRctNonSemanticsSimple(rctsign)
+}
+func TlvProveRangeBulletproof(amounts types.KeyV, sk types.KeyV) (b *types.Bulletproof, c types.KeyV, masks types.KeyV, err error) {
+	return proveRangeBulletproof(amounts, sk, bulletproofBits)
+}
+
+func TlvProveRangeBulletproof128(amounts types.KeyV, sk types.KeyV) (b *types.Bulletproof, c types.KeyV, masks types.KeyV, err error) {
+	return proveRangeBulletproof(amounts, sk, bulletproofBits128)
+}
+
+func TlvProveRctMGSimple(message types.Key, pubs types.CtkeyV, inSk types.Ctkey, a, Count types.Key, mscout *types.Key, kLRki *types.MultisigKLRki, index uint32) (sig *types.MgSig, err error) {
+	if kLRki == nil {
+		mscout = nil
+	}
+	return proveRctMGSimple(message, pubs, inSk, a, Count, kLRki, mscout, index)
+}
+
+func TlvGetPreMlsagHash(rctsign *types.RctSig) (key types.Key, err error) {
+	return getPreMlsagHash(rctsign)
+}
+
+func TlvAddKeyV(a types.KeyV) (sum types.Key, err error) {
+	return addKeyV(a)
+}
+func TlvVerBulletproof(bp *types.Bulletproof) (bool, error) {
+	return verifyBulletproof(bp, bulletproofBits), nil
+}
+
+func TlvVerBulletproof128(bp *types.Bulletproof) (bool, error) {
+	return verifyBulletproof(bp, bulletproofBits128), nil
+}
+
+func TlvGetSubaddress(keys *types.AccountKey, index uint32) (addr types.AccountAddress, err error) {
+	return getSubaddress(keys, index)
+}
+
+// for test---------------------------------------------------------------------------------
+
+func TlvKeyVTest(keysie int) error {
+	cKeyV := make(types.KeyV, keysie)
+	for i := 0; i < keysie; i++ {
+		cKeyV[i] = types.Key{121, 201, 148, 20, 165, 225, 8, 37, 186, 117, 239, 0, 3, 148, 76, 241, 86, 55, 38, 123, 182, 35, 115, 126, 76, 56, 186, 191, 23, 80, 177, 49}
+	}
+	data := make([]byte, cKeyV.TlvSize())
+	_, err := cKeyV.TlvEncode(data)
+	if err != nil {
+		return err
+	}
+	to := types.KeyV{}
+	to.TlvDecode(data)
+	if !to.IsEqual(&cKeyV) {
+		return fmt.Errorf("not equal")
+	}
+	return nil
+}
+func TlvRctSign(rctsign *types.RctSig) error {
+	TlvVerRctSimple(rctsign)
+	return nil
+}
+
+func TlvRctsigForTest(rctsign *types.RctSig) (*types.RctSig, error) {
+	data := make([]byte, rctsign.TlvSize())
+	_, err := rctsign.TlvEncode(data)
+	if err != nil {
+		return nil, err
+	}
+	to := &types.RctSig{}
+	to.TlvDecode(data)
+	return to, nil
+}
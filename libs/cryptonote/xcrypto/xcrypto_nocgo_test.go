@@ -0,0 +1,158 @@
+// +build nacl js nocgo
+
+package xcrypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+)
+
+func TestKeccakNocgo(t *testing.T) {
+	for _, pair := range hashpair {
+		data, err := hex.DecodeString(pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := keccak(data)
+		if hex.EncodeToString(h[:]) != pair[0] {
+			t.Fatalf("keccak(%s) = %x, want %s", pair[1], h, pair[0])
+		}
+	}
+}
+
+func TestVerRctSimpleNocgo(t *testing.T) {
+	rctsig := DefualtTestRctsig()
+	rctsig.PseudoOuts = types.KeyV{}
+	if err, ok := TlvVerRctSimple(rctsig); err != nil || !ok {
+		t.Fatalf("TlvVerRctSimple fail: %v", err)
+	}
+
+	rctsig.TxnFee++
+	if _, ok := TlvVerRctSimple(rctsig); ok {
+		t.Fatalf("TlvVerRctSimple accepts a wrong fee")
+	}
+	rctsig.TxnFee--
+
+	rctsig.P.MGs[1].Ss[3][1][0] ^= 1
+	if TlvVerRctNotSemanticsSimple(rctsig) {
+		t.Fatalf("TlvVerRctNotSemanticsSimple accepts a tampered mlsag")
+	}
+}
+
+func TestBulletproofNocgo(t *testing.T) {
+	amounts := types.KeyV{{0x01}, {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, {0x10, 0x27}}
+	sk := types.KeyV{SkGen(), SkGen(), SkGen()}
+	bp, c, masks, err := TlvProveRangeBulletproof(amounts, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bp.L) != 8 || len(c) != 3 || len(masks) != 3 {
+		t.Fatalf("unexpected proof sizes L=%d C=%d masks=%d", len(bp.L), len(c), len(masks))
+	}
+	for i := range c {
+		commit, _ := AddKeys2(masks[i], amounts[i], keyH)
+		c8, err := Scalarmult8(c[i])
+		if err != nil || c8 != commit {
+			t.Fatalf("C[%d] does not commit to the amount", i)
+		}
+	}
+	if ok, _ := TlvVerBulletproof(bp); !ok {
+		t.Fatalf("TlvVerBulletproof fail")
+	}
+	bp.T[0] ^= 1
+	if ok, _ := TlvVerBulletproof(bp); ok {
+		t.Fatalf("TlvVerBulletproof accepts a tampered proof")
+	}
+
+	wide := types.KeyV{{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}
+	bp, _, _, err = TlvProveRangeBulletproof128(wide, types.KeyV{SkGen()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := TlvVerBulletproof128(bp); !ok {
+		t.Fatalf("TlvVerBulletproof128 fail")
+	}
+	if ok, _ := TlvVerBulletproof(bp); ok {
+		t.Fatalf("TlvVerBulletproof accepts a 128 bits proof")
+	}
+}
+
+func TestProveRctMGSimpleNocgo(t *testing.T) {
+	const ring, real = 4, 2
+	var amount types.Lk_amount = 1000
+	pubs := make(types.CtkeyV, ring)
+	var inSk types.Ctkey
+	for i := range pubs {
+		sk, pk := SkpkGen()
+		mask := SkGen()
+		commit, err := GenC(mask, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs[i] = types.Ctkey{Dest: pk, Mask: commit}
+		if i == real {
+			inSk = types.Ctkey{Dest: sk, Mask: mask}
+		}
+	}
+	a := SkGen()
+	cout, err := GenC(a, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := SkGen()
+	mg, err := TlvProveRctMGSimple(message, pubs, inSk, a, cout, nil, nil, real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ki, _ := GenerateKeyImage(types.PublicKey(pubs[real].Dest), types.SecretKey(inSk.Dest))
+	if mg.II[0] != types.Key(ki) {
+		t.Fatalf("key image mismatch")
+	}
+	if !verRctMGSimple(message, mg, pubs, cout) {
+		t.Fatalf("verRctMGSimple fail")
+	}
+	message[0] ^= 1
+	if verRctMGSimple(message, mg, pubs, cout) {
+		t.Fatalf("verRctMGSimple accepts a wrong message")
+	}
+}
+
+func TestEcdhNocgo(t *testing.T) {
+	shared := SkGen()
+	for _, short := range []bool{false, true} {
+		mask := SkGen()
+		amount := d2h(123456789)
+		tuple := types.EcdhTuple{Mask: mask, Amount: amount}
+		EcdhEncode(&tuple, shared, short)
+		EcdhDecode(&tuple, shared, short)
+		if tuple.Amount != amount {
+			t.Fatalf("short=%v: amount not restored", short)
+		}
+		if !short && tuple.Mask != mask {
+			t.Fatalf("mask not restored")
+		}
+		if short && tuple.Mask != genCommitmentMask(shared) {
+			t.Fatalf("mask not derived from the shared secret")
+		}
+	}
+}
+
+func TestRingSignatureNocgo(t *testing.T) {
+	sk, pk := SkpkGen()
+	ki, _ := GenerateKeyImage(types.PublicKey(pk), types.SecretKey(sk))
+	prefix := types.Hash(SkGen())
+	pks := []types.PublicKey{types.PublicKey(pk)}
+	sig, err := GenerateRingSignature(prefix, ki, pks, types.SecretKey(sk), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CheckRingSignature(prefix, ki, pks, sig) {
+		t.Fatalf("CheckRingSignature fail")
+	}
+	prefix[0] ^= 1
+	if CheckRingSignature(prefix, ki, pks, sig) {
+		t.Fatalf("CheckRingSignature accepts a wrong prefix")
+	}
+}
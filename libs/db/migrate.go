@@ -0,0 +1,102 @@
+package db
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// CopyDB copies the keys of src from start (inclusive) to the end into dst
+// with batched writes. A nil start copies all keys.
+// flushed is called after each batch is written with the last key written and
+// the number of keys copied so far, a copy can be resumed after the last key
+// with NextKey. An error returned by flushed stops the copy.
+func CopyDB(src, dst DB, start []byte, flushed func(lastKey []byte, count uint64) error) (uint64, error) {
+	itr := src.Iterator(start, nil)
+	defer itr.Close()
+
+	var (
+		batch   = dst.NewBatch()
+		size    int
+		pending int
+		count   uint64
+		lastKey []byte
+	)
+	flush := func() error {
+		if err := batch.Commit(); err != nil {
+			return err
+		}
+		batch.Reset()
+		size, pending = 0, 0
+		if flushed != nil {
+			return flushed(lastKey, count)
+		}
+		return nil
+	}
+	for ; itr.Valid(); itr.Next() {
+		lastKey = cp(itr.Key())
+		value := cp(itr.Value())
+		batch.Set(lastKey, value)
+		size += len(lastKey) + len(value)
+		pending++
+		count++
+		// keys count too, the values may be empty
+		if size >= IdealBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if pending > 0 {
+		if err := flush(); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// NextKey returns the smallest key after key.
+func NextKey(key []byte) []byte {
+	next := make([]byte, len(key)+1)
+	copy(next, key)
+	return next
+}
+
+// Checksum returns the number of keys of db and a digest of its keys and
+// values in iteration order, two databases with the same content have the
+// same checksum whatever their backend.
+func Checksum(db DB) (uint64, []byte) {
+	itr := db.Iterator(nil, nil)
+	defer itr.Close()
+
+	h := sha256.New()
+	count := uint64(0)
+	for ; itr.Valid(); itr.Next() {
+		writeChecksumItem(h, itr.Key())
+		writeChecksumItem(h, itr.Value())
+		count++
+	}
+	return count, h.Sum(nil)
+}
+
+func writeChecksumItem(h hash.Hash, bz []byte) {
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(bz)))
+	h.Write(size[:n])
+	h.Write(bz)
+}
+
+// VerifyCopy checks that dst holds the same keys and values as src.
+func VerifyCopy(src, dst DB) error {
+	srcCount, srcSum := Checksum(src)
+	dstCount, dstSum := Checksum(dst)
+	if srcCount != dstCount {
+		return fmt.Errorf("key count mismatch, source %d, destination %d", srcCount, dstCount)
+	}
+	if !bytes.Equal(srcSum, dstSum) {
+		return fmt.Errorf("checksum mismatch, source %X, destination %X", srcSum, dstSum)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fillMigrateDB(db DB, n int) {
+	for i := 0; i < n; i++ {
+		db.Set([]byte(fmt.Sprintf("key-%06d", i)), make([]byte, i%1024))
+	}
+}
+
+func TestCopyDB(t *testing.T) {
+	for _, backend := range []DBBackendType{GoLevelDBBackend, BoltBackend} {
+		t.Run(string(backend), func(t *testing.T) {
+			src := NewMemDB()
+			fillMigrateDB(src, 1000)
+			dst := newTempDB(t, backend)
+			defer dst.Close()
+
+			flushes := 0
+			count, err := CopyDB(src, dst, nil, func(lastKey []byte, count uint64) error {
+				flushes++
+				return nil
+			})
+			require.Nil(t, err)
+			assert.Equal(t, uint64(1000), count)
+			assert.True(t, flushes > 1, "expecting batched writes")
+			assert.Nil(t, VerifyCopy(src, dst))
+
+			dst.Delete([]byte("key-000500"))
+			assert.NotNil(t, VerifyCopy(src, dst), "expecting a count mismatch")
+			dst.Set([]byte("key-000500"), []byte("x"))
+			assert.NotNil(t, VerifyCopy(src, dst), "expecting a checksum mismatch")
+		})
+	}
+}
+
+func TestCopyDBResume(t *testing.T) {
+	src := NewMemDB()
+	fillMigrateDB(src, 1000)
+	dst := NewMemDB()
+
+	errStop := errors.New("stop")
+	var checkpoint []byte
+	copied, err := CopyDB(src, dst, nil, func(lastKey []byte, count uint64) error {
+		checkpoint = lastKey
+		return errStop
+	})
+	require.Equal(t, errStop, err)
+	require.NotNil(t, checkpoint)
+	require.True(t, copied < 1000)
+
+	count, err := CopyDB(src, dst, NextKey(checkpoint), nil)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(1000), copied+count)
+	assert.Nil(t, VerifyCopy(src, dst))
+}
+
+func TestChecksum(t *testing.T) {
+	db1, db2 := NewMemDB(), NewMemDB()
+	n, sum := Checksum(db1)
+	assert.Equal(t, uint64(0), n)
+
+	// key and value boundaries are part of the checksum
+	db1.Set([]byte("ab"), []byte("c"))
+	db2.Set([]byte("a"), []byte("bc"))
+	n1, sum1 := Checksum(db1)
+	n2, sum2 := Checksum(db2)
+	assert.Equal(t, n1, n2)
+	assert.NotEqual(t, sum1, sum2)
+	assert.NotEqual(t, sum, sum1)
+}
@@ -334,7 +334,7 @@ func shareEphPubKey(conn io.ReadWriteCloser, locEphPub *[32]byte) (remEphPub *[3
 		},
 		func(_ int) (val interface{}, err error, abort bool) {
 			var _remEphPub [32]byte
-			var _, err2 = ser.DecodeReaderWithType(conn, &_remEphPub, 1024*1024) // TODO
+			var _, err2 = ser.DecodeReaderWithType(unbufferedReader{conn}, &_remEphPub, 1024*1024) // TODO
 			if err2 != nil {
 				return nil, err2, true // abort
 			} else {
@@ -474,3 +474,17 @@ func incrNonce(nonce *[24]byte) {
 		}
 	}
 }
+
+// unbufferedReader reads the underlying connection byte by byte, so that the
+// decoder does not buffer ahead into the first sealed frame of the remote.
+type unbufferedReader struct {
+	io.Reader
+}
+
+func (r unbufferedReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r.Reader, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
@@ -1,6 +1,7 @@
 package conn
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	cmn "github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
 )
 
 type kvstoreConn struct {
@@ -90,6 +92,33 @@ func TestSecretConnectionHandshake(t *testing.T) {
 	}
 }
 
+// streamConn reads the bytes sent by the remote as a stream, like a net.Conn
+// does, and discards the writes.
+type streamConn struct {
+	r io.Reader
+}
+
+func (c streamConn) Read(p []byte) (int, error)  { return c.r.Read(p) }
+func (c streamConn) Write(p []byte) (int, error) { return len(p), nil }
+func (c streamConn) Close() error                { return nil }
+
+func TestShareEphPubKeyStopsAtKey(t *testing.T) {
+	var remEphPub [32]byte
+	copy(remEphPub[:], cmn.RandBytes(32))
+	frame := []byte("first sealed frame of the remote")
+
+	buf := new(bytes.Buffer)
+	_, err := ser.EncodeWriterWithType(buf, &remEphPub)
+	require.Nil(t, err)
+	buf.Write(frame)
+
+	var locEphPub [32]byte
+	got, err := shareEphPubKey(streamConn{buf}, &locEphPub)
+	require.Nil(t, err)
+	assert.Equal(t, remEphPub, *got)
+	assert.Equal(t, frame, buf.Bytes(), "read past the ephemeral key")
+}
+
 func TestSecretConnectionReadWrite(t *testing.T) {
 	fooConn, barConn := makeKVStoreConnPair()
 	fooWrites, barWrites := []string{}, []string{}
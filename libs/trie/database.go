@@ -82,6 +82,8 @@ type Database struct {
 	nodesSize     common.StorageSize // Storage size of the nodes cache (exc. flushlist)
 	preimagesSize common.StorageSize // Storage size of the preimages cache
 
+	pruning bool // Whether the persisted nodes are reference counted
+
 	lock sync.RWMutex
 }
 
@@ -429,9 +431,16 @@ func (db *Database) Reference(child common.Hash, parent common.Hash) {
 
 // reference is the private locked version of Reference.
 func (db *Database) reference(child common.Hash, parent common.Hash) {
-	// If the node does not exist, it's a node pulled from disk, skip
+	// If the node does not exist, it's a node pulled from disk, skip. When
+	// pruning, the reference is still recorded to be counted on disk.
 	node, ok := db.nodes[child]
 	if !ok {
+		if db.pruning && db.nodes[parent] != nil && parent != (common.EmptyHash) {
+			if db.nodes[parent].children == nil {
+				db.nodes[parent].children = make(map[common.Hash]uint16)
+			}
+			db.nodes[parent].children[child] = 1
+		}
 		return
 	}
 	// If the reference already exists, only duplicate for roots
@@ -629,11 +638,18 @@ func (db *Database) Commit(node common.Hash, report bool) error {
 	}
 	// Move the trie itself into the batch, flushing if enough data is accumulated
 	nodes, storage := len(db.nodes), db.nodesSize
-	if err := db.commit(node, batch); err != nil {
+	var refs *nodeRefs
+	if db.pruning {
+		refs = newNodeRefs(db.diskdb)
+	}
+	if err := db.commit(node, batch, refs); err != nil {
 		log.Error("Failed to commit trie from trie database", "err", err)
 		db.lock.RUnlock()
 		return err
 	}
+	if refs != nil {
+		refs.write(batch)
+	}
 	// Write batch ready, unlock for readers during persistence
 	if err := batch.Commit(); err != nil {
 		log.Error("Failed to write trie to disk", "err", err)
@@ -669,19 +685,29 @@ func (db *Database) Commit(node common.Hash, report bool) error {
 	return nil
 }
 
-// commit is the private locked version of Commit.
-func (db *Database) commit(hash common.Hash, batch dbm.Batch) error {
+// commit is the private locked version of Commit. When pruning, refs collects
+// the reference records of the persisted nodes.
+func (db *Database) commit(hash common.Hash, batch dbm.Batch, refs *nodeRefs) error {
 	// If the node does not exist, it's a previously committed node
 	node, ok := db.nodes[hash]
 	if !ok {
 		return nil
 	}
+	// A node already on disk is persisted with its subtrie and counted
+	if refs != nil && refs.stored(hash) {
+		return nil
+	}
 	for _, child := range node.childs() {
-		if err := db.commit(child, batch); err != nil {
+		if err := db.commit(child, batch, refs); err != nil {
 			return err
 		}
 	}
 	batch.Set(hash[:], node.ser())
+	if refs != nil {
+		if err := refs.add(hash, node); err != nil {
+			return err
+		}
+	}
 	// If we've reached an optimal batch size, commit and start over
 	if batch.ValueSize() >= dbm.IdealBatchSize {
 		if err := batch.Commit(); err != nil {
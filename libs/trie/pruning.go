@@ -0,0 +1,232 @@
+package trie
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+)
+
+// refKeyPrefix is the database key prefix of the reference counts of the
+// nodes persisted with pruning enabled.
+var refKeyPrefix = []byte("trie-ref-")
+
+// nodeRef is the reference record of a persisted node: the number of persisted
+// parents and retained roots pointing to it, and the children that cannot be
+// found by decoding the node (storage roots and code of account leaves).
+//
+// Nodes without a record were persisted before pruning was enabled (or by
+// state sync), they are never counted nor deleted.
+type nodeRef struct {
+	count    uint64
+	raw      bool
+	external []common.Hash
+
+	dirty   bool
+	deleted bool
+}
+
+func (ref *nodeRef) encode() []byte {
+	buf := make([]byte, binary.MaxVarintLen64+1, binary.MaxVarintLen64+1+len(ref.external)*common.HashLength)
+	n := binary.PutUvarint(buf, ref.count)
+	if ref.raw {
+		buf[n] = 1
+	} else {
+		buf[n] = 0
+	}
+	buf = buf[:n+1]
+	for _, child := range ref.external {
+		buf = append(buf, child[:]...)
+	}
+	return buf
+}
+
+func decodeNodeRef(buf []byte) (*nodeRef, error) {
+	count, n := binary.Uvarint(buf)
+	if n <= 0 || len(buf) < n+1 || (len(buf)-n-1)%common.HashLength != 0 {
+		return nil, fmt.Errorf("invalid trie node reference record %x", buf)
+	}
+	ref := &nodeRef{count: count, raw: buf[n] == 1}
+	for buf = buf[n+1:]; len(buf) > 0; buf = buf[common.HashLength:] {
+		ref.external = append(ref.external, common.BytesToHash(buf[:common.HashLength]))
+	}
+	return ref, nil
+}
+
+func refKey(hash common.Hash) []byte {
+	return append(append(make([]byte, 0, len(refKeyPrefix)+common.HashLength), refKeyPrefix...), hash[:]...)
+}
+
+// nodeRefs caches the reference records updated by a commit or a release
+// until they are written with the nodes.
+type nodeRefs struct {
+	diskdb dbm.DB
+	refs   map[common.Hash]*nodeRef
+}
+
+func newNodeRefs(diskdb dbm.DB) *nodeRefs {
+	return &nodeRefs{diskdb: diskdb, refs: make(map[common.Hash]*nodeRef)}
+}
+
+// get returns the reference record of a node, or nil if the node is not
+// reference counted.
+func (r *nodeRefs) get(hash common.Hash) (*nodeRef, error) {
+	if ref, ok := r.refs[hash]; ok {
+		if ref.deleted {
+			return nil, nil
+		}
+		return ref, nil
+	}
+	buf := r.diskdb.Get(refKey(hash))
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	ref, err := decodeNodeRef(buf)
+	if err != nil {
+		return nil, err
+	}
+	r.refs[hash] = ref
+	return ref, nil
+}
+
+// stored reports whether a node is already persisted.
+func (r *nodeRefs) stored(hash common.Hash) bool {
+	if ref, ok := r.refs[hash]; ok {
+		return !ref.deleted
+	}
+	return r.diskdb.Has(hash[:])
+}
+
+// add records a node persisted by the current commit and counts its
+// references to its children.
+func (r *nodeRefs) add(hash common.Hash, node *cachedNode) error {
+	ref := &nodeRef{dirty: true}
+	_, ref.raw = node.node.(rawNode)
+	for child := range node.children {
+		ref.external = append(ref.external, child)
+	}
+	r.refs[hash] = ref
+
+	for _, child := range node.childs() {
+		if err := r.inc(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *nodeRefs) inc(hash common.Hash) error {
+	ref, err := r.get(hash)
+	if ref == nil || err != nil {
+		return err
+	}
+	ref.count++
+	ref.dirty = true
+	return nil
+}
+
+// write moves the updated records into batch.
+func (r *nodeRefs) write(batch dbm.Batch) {
+	for hash, ref := range r.refs {
+		switch {
+		case ref.deleted:
+			batch.Delete(refKey(hash))
+		case ref.dirty:
+			batch.Set(refKey(hash), ref.encode())
+		}
+	}
+}
+
+// EnablePruning makes the database count the references to the nodes it
+// persists, so that the tries of old roots can be deleted with Release. It
+// must be called before the first commit of the database.
+func (db *Database) EnablePruning() {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.pruning = true
+}
+
+// Retain adds a reference to a committed root, keeping its trie on disk until
+// the reference is dropped by Release.
+func (db *Database) Retain(root common.Hash) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	refs := newNodeRefs(db.diskdb)
+	if err := refs.inc(root); err != nil {
+		return err
+	}
+	batch := db.diskdb.NewBatch()
+	refs.write(batch)
+	return batch.Commit()
+}
+
+// Release drops a reference to a root added by Retain, and deletes from disk
+// the nodes of its trie that are not referenced anymore.
+func (db *Database) Release(root common.Hash) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	var (
+		refs    = newNodeRefs(db.diskdb)
+		batch   = db.diskdb.NewBatch()
+		queue   = []common.Hash{root}
+		deleted = 0
+	)
+	for len(queue) > 0 {
+		hash := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		ref, err := refs.get(hash)
+		if err != nil {
+			return err
+		}
+		if ref == nil || ref.count == 0 {
+			continue
+		}
+		ref.count--
+		ref.dirty = true
+		if ref.count > 0 {
+			continue
+		}
+		// No more references, delete the node and cascade
+		if !ref.raw {
+			if blob := db.diskdb.Get(hash[:]); len(blob) > 0 {
+				n, err := decodeNode(hash[:], blob, 0)
+				if err != nil {
+					return err
+				}
+				queue = appendNodeChildren(n, queue)
+			}
+		}
+		queue = append(queue, ref.external...)
+		batch.Delete(hash[:])
+		ref.deleted = true
+		deleted++
+	}
+	refs.write(batch)
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+	log.Debug("Released trie from disk database", "root", root, "nodes", deleted)
+	return nil
+}
+
+// appendNodeChildren appends the hashes of the nodes referenced by a decoded
+// trie node, embedded nodes included.
+func appendNodeChildren(n node, children []common.Hash) []common.Hash {
+	switch n := n.(type) {
+	case *shortNode:
+		return appendNodeChildren(n.Val, children)
+	case *fullNode:
+		for i := 0; i < 16; i++ {
+			children = appendNodeChildren(n.Children[i], children)
+		}
+	case hashNode:
+		children = append(children, common.BytesToHash(n))
+	}
+	return children
+}
@@ -0,0 +1,203 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+)
+
+// countDiskNodes returns the number of nodes and reference records on disk.
+func countDiskNodes(diskdb dbm.DB) (nodes int, refs int) {
+	itr := diskdb.Iterator(nil, nil)
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		switch {
+		case len(itr.Key()) == common.HashLength:
+			nodes++
+		case bytes.HasPrefix(itr.Key(), refKeyPrefix):
+			refs++
+		}
+	}
+	return nodes, refs
+}
+
+func commitPruned(t *testing.T, triedb *Database, trie *Trie, onleaf LeafCallback) common.Hash {
+	root, err := trie.Commit(onleaf)
+	if err != nil {
+		t.Fatalf("trie commit failed: %v", err)
+	}
+	if err := triedb.Commit(root, false); err != nil {
+		t.Fatalf("database commit failed: %v", err)
+	}
+	if err := triedb.Retain(root); err != nil {
+		t.Fatalf("retain failed: %v", err)
+	}
+	return root
+}
+
+func checkPrunedTrie(t *testing.T, triedb *Database, root common.Hash, vals map[string]string) {
+	trie, err := New(root, triedb)
+	if err != nil {
+		t.Fatalf("failed to open trie %x: %v", root, err)
+	}
+	for key, val := range vals {
+		got, err := trie.TryGet([]byte(key))
+		if err != nil {
+			t.Fatalf("trie %x: get %q failed: %v", root, key, err)
+		}
+		if string(got) != val {
+			t.Fatalf("trie %x: get %q = %q, want %q", root, key, got, val)
+		}
+	}
+}
+
+func fillPruningTrie(trie *Trie, vals map[string]string, n int) {
+	for i := 0; i < n; i++ {
+		key, val := fmt.Sprintf("key-%04d", i), fmt.Sprintf("value-%04d-%s", i, bytes.Repeat([]byte{'x'}, 32))
+		vals[key] = val
+		updateString(trie, key, val)
+	}
+}
+
+func TestPruning(t *testing.T) {
+	diskdb := dbm.NewMemDB()
+	triedb := NewDatabase(diskdb)
+	triedb.EnablePruning()
+
+	vals1 := make(map[string]string)
+	trie, _ := New(common.EmptyHash, triedb)
+	fillPruningTrie(trie, vals1, 200)
+	root1 := commitPruned(t, triedb, trie, nil)
+	nodes1, _ := countDiskNodes(diskdb)
+
+	vals2 := make(map[string]string)
+	for key, val := range vals1 {
+		vals2[key] = val
+	}
+	// Same trie shape, with a new path from the root to the updated leaf
+	trie, _ = New(root1, triedb)
+	vals2["key-0100"] = strings.Replace(vals1["key-0100"], "x", "y", -1)
+	updateString(trie, "key-0100", vals2["key-0100"])
+	root2 := commitPruned(t, triedb, trie, nil)
+	nodes2, _ := countDiskNodes(diskdb)
+	if nodes2 <= nodes1 {
+		t.Fatalf("expected new nodes for the second root, have %d then %d", nodes1, nodes2)
+	}
+
+	// Only the nodes of the first root not shared with the second are deleted
+	if err := triedb.Release(root1); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if nodes, _ := countDiskNodes(diskdb); nodes != nodes1 {
+		t.Errorf("nodes after release: have %d, want %d", nodes, nodes1)
+	}
+	if _, err := New(root1, triedb); err == nil {
+		t.Errorf("released root %x still on disk", root1)
+	}
+	checkPrunedTrie(t, triedb, root2, vals2)
+
+	if err := triedb.Release(root2); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if nodes, refs := countDiskNodes(diskdb); nodes != 0 || refs != 0 {
+		t.Errorf("disk not empty after releasing all roots: %d nodes, %d reference records", nodes, refs)
+	}
+}
+
+func TestPruningRetainedTwice(t *testing.T) {
+	diskdb := dbm.NewMemDB()
+	triedb := NewDatabase(diskdb)
+	triedb.EnablePruning()
+
+	vals := make(map[string]string)
+	trie, _ := New(common.EmptyHash, triedb)
+	fillPruningTrie(trie, vals, 50)
+	root := commitPruned(t, triedb, trie, nil)
+
+	// The same root committed again, e.g. by an empty block
+	trie, _ = New(root, triedb)
+	if again := commitPruned(t, triedb, trie, nil); again != root {
+		t.Fatalf("root changed: %x != %x", again, root)
+	}
+	if err := triedb.Release(root); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	checkPrunedTrie(t, triedb, root, vals)
+
+	if err := triedb.Release(root); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if nodes, _ := countDiskNodes(diskdb); nodes != 0 {
+		t.Errorf("disk not empty after releasing all references: %d nodes", nodes)
+	}
+}
+
+func TestPruningUnmanagedNodes(t *testing.T) {
+	diskdb := dbm.NewMemDB()
+	triedb := NewDatabase(diskdb)
+
+	// Nodes persisted before pruning was enabled are kept
+	vals := make(map[string]string)
+	trie, _ := New(common.EmptyHash, triedb)
+	fillPruningTrie(trie, vals, 100)
+	root1, _ := trie.Commit(nil)
+	triedb.Commit(root1, false)
+	nodes1, _ := countDiskNodes(diskdb)
+
+	triedb.EnablePruning()
+	trie, _ = New(root1, triedb)
+	updateString(trie, "key-0050", "updated")
+	root2 := commitPruned(t, triedb, trie, nil)
+	if err := triedb.Release(root2); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if nodes, refs := countDiskNodes(diskdb); nodes != nodes1 || refs != 0 {
+		t.Errorf("have %d nodes and %d reference records, want %d and 0", nodes, refs, nodes1)
+	}
+	checkPrunedTrie(t, triedb, root1, vals)
+}
+
+func TestPruningExternalReferences(t *testing.T) {
+	diskdb := dbm.NewMemDB()
+	triedb := NewDatabase(diskdb)
+	triedb.EnablePruning()
+
+	code := []byte("contract code")
+	codeHash := crypto.Keccak256Hash(code)
+	onleaf := func(leaf []byte, parent common.Hash) error {
+		if bytes.Equal(leaf, codeHash[:]) {
+			triedb.Reference(codeHash, parent)
+		}
+		return nil
+	}
+
+	trie, _ := New(common.EmptyHash, triedb)
+	fillPruningTrie(trie, make(map[string]string), 20)
+	triedb.InsertBlob(codeHash, code)
+	trie.Update([]byte("owner-a"), codeHash[:])
+	root1 := commitPruned(t, triedb, trie, onleaf)
+
+	// The second leaf references the code from disk
+	trie, _ = New(root1, triedb)
+	trie.Delete([]byte("owner-a"))
+	trie.Update([]byte("owner-b"), codeHash[:])
+	root2 := commitPruned(t, triedb, trie, onleaf)
+
+	if err := triedb.Release(root1); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if !diskdb.Has(codeHash[:]) {
+		t.Fatalf("code referenced by %x deleted", root2)
+	}
+	if err := triedb.Release(root2); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if diskdb.Has(codeHash[:]) {
+		t.Errorf("code not deleted with its last reference")
+	}
+}
@@ -0,0 +1,114 @@
+package lightclient
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/libs/trie"
+	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+var (
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+	// emptyRoot is the root of an empty storage trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+)
+
+// proofDB returns the nodes of a proof keyed by their hash.
+func proofDB(proof []hexutil.Bytes) dbm.DB {
+	db := dbm.NewMemDB()
+	for _, node := range proof {
+		db.Set(crypto.Keccak256(node), node)
+	}
+	return db
+}
+
+// VerifyAccountProof verifies the account and storage proofs of an eth_getProof
+// result against the state root committed in the last trusted header, which
+// must be the header of the block after the proven one. It returns the proven
+// account, or nil if the proof shows that the account does not exist.
+func (v *Verifier) VerifyAccountProof(res *rtypes.AccountResult) (*state.Account, error) {
+	header := v.Header()
+	if header == nil || header.Height != uint64(res.BlockNumber)+1 {
+		return nil, fmt.Errorf("state of block %d is committed in header %d, not trusted", res.BlockNumber, res.BlockNumber+1)
+	}
+	if !v.forks.IsActive(types.ForkStateRoot, header.Height) {
+		return nil, fmt.Errorf("header %d does not commit to the state root, the %q fork is not active", header.Height, types.ForkStateRoot)
+	}
+	if res.StateRoot != header.StateHash {
+		return nil, fmt.Errorf("state root %v of block %d, header %d commits to %v", res.StateRoot, res.BlockNumber, header.Height, header.StateHash)
+	}
+	return verifyAccountProof(header.StateHash, res)
+}
+
+// verifyAccountProof verifies the account and storage proofs of res against root.
+func verifyAccountProof(root common.Hash, res *rtypes.AccountResult) (*state.Account, error) {
+	enc, _, err := trie.VerifyProof(root, crypto.Keccak256(res.Address.Bytes()), proofDB(res.AccountProof))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof of account %s: %v", res.Address.String(), err)
+	}
+	if len(enc) == 0 {
+		if res.Balance != nil && res.Balance.ToInt().Sign() != 0 || res.Nonce != 0 ||
+			(res.CodeHash != common.EmptyHash && res.CodeHash != emptyCodeHash) {
+			return nil, fmt.Errorf("account %s proven absent but returned with a state", res.Address.String())
+		}
+		for _, slot := range res.StorageProof {
+			if len(slot.Value) > 0 {
+				return nil, fmt.Errorf("storage slot %v of the absent account %s returned with a value", slot.Key, res.Address.String())
+			}
+		}
+		return nil, nil
+	}
+
+	var account state.Account
+	if err := ser.DecodeBytes(enc, &account); err != nil {
+		return nil, fmt.Errorf("invalid account %s: %v", res.Address.String(), err)
+	}
+	switch {
+	case res.Balance == nil || account.Balance.Cmp(res.Balance.ToInt()) != 0:
+		return nil, fmt.Errorf("balance of account %s does not match the proof", res.Address.String())
+	case account.Nonce != uint64(res.Nonce):
+		return nil, fmt.Errorf("nonce of account %s does not match the proof", res.Address.String())
+	case !bytes.Equal(account.CodeHash, res.CodeHash.Bytes()):
+		return nil, fmt.Errorf("code hash of account %s does not match the proof", res.Address.String())
+	case account.Root != res.StorageHash:
+		return nil, fmt.Errorf("storage hash of account %s does not match the proof", res.Address.String())
+	}
+
+	for _, slot := range res.StorageProof {
+		if err := verifyStorageProof(account.Root, slot); err != nil {
+			return nil, fmt.Errorf("account %s: %v", res.Address.String(), err)
+		}
+	}
+	return &account, nil
+}
+
+func verifyStorageProof(root common.Hash, slot rtypes.StorageResult) error {
+	if root == common.EmptyHash || root == emptyRoot {
+		if len(slot.Value) > 0 {
+			return fmt.Errorf("storage slot %v of an empty storage returned with a value", slot.Key)
+		}
+		return nil
+	}
+	enc, _, err := trie.VerifyProof(root, crypto.Keccak256(slot.Key.Bytes()), proofDB(slot.Proof))
+	if err != nil {
+		return fmt.Errorf("invalid proof of storage slot %v: %v", slot.Key, err)
+	}
+	var value []byte
+	if len(enc) > 0 {
+		if _, value, _, err = ser.Split(enc); err != nil {
+			return fmt.Errorf("invalid value of storage slot %v: %v", slot.Key, err)
+		}
+	}
+	if !bytes.Equal(value, slot.Value) {
+		return fmt.Errorf("value of storage slot %v does not match the proof", slot.Key)
+	}
+	return nil
+}
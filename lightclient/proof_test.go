@@ -0,0 +1,137 @@
+package lightclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func toProof(nodes [][]byte) []hexutil.Bytes {
+	proof := make([]hexutil.Bytes, len(nodes))
+	for i := range nodes {
+		proof[i] = nodes[i]
+	}
+	return proof
+}
+
+// accountResult builds the eth_getProof result of an account.
+func accountResult(t *testing.T, statedb *state.StateDB, addr common.Address, keys ...common.Hash) *rtypes.AccountResult {
+	accountProof, err := statedb.GetProof(addr)
+	require.Nil(t, err)
+	res := &rtypes.AccountResult{
+		Address:      addr,
+		AccountProof: toProof(accountProof),
+		Balance:      (*hexutil.Big)(statedb.GetBalance(addr)),
+		CodeHash:     statedb.GetCodeHash(addr),
+		Nonce:        hexutil.Uint64(statedb.GetNonce(addr)),
+		StorageHash:  statedb.GetStorageRoot(addr),
+	}
+	for _, key := range keys {
+		storageProof, err := statedb.GetStorageProof(addr, key)
+		require.Nil(t, err)
+		res.StorageProof = append(res.StorageProof, rtypes.StorageResult{
+			Key:   key,
+			Value: statedb.GetState(addr, key),
+			Proof: toProof(storageProof),
+		})
+	}
+	return res
+}
+
+func TestVerifyAccountProofRoot(t *testing.T) {
+	db := state.NewDatabase(dbm.NewMemDB())
+	statedb, _ := state.New(common.EmptyHash, db)
+	for i := byte(1); i < 100; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		statedb.AddBalance(addr, big.NewInt(int64(i)))
+		statedb.SetNonce(addr, uint64(i))
+		statedb.SetState(addr, common.BytesToHash([]byte{i}), []byte{i, i})
+	}
+	root, err := statedb.Commit(false, 1)
+	require.Nil(t, err)
+	statedb, _ = state.New(root, db)
+
+	addr, key := common.BytesToAddress([]byte{42}), common.BytesToHash([]byte{42})
+	res := accountResult(t, statedb, addr, key, common.BytesToHash([]byte{43}))
+	account, err := verifyAccountProof(root, res)
+	require.Nil(t, err)
+	require.NotNil(t, account)
+	assert.Equal(t, uint64(42), account.Nonce)
+
+	res.Balance = (*hexutil.Big)(big.NewInt(1000))
+	_, err = verifyAccountProof(root, res)
+	assert.NotNil(t, err, "expecting a balance mismatch")
+
+	res = accountResult(t, statedb, addr, key)
+	res.StorageProof[0].Value = []byte{1}
+	_, err = verifyAccountProof(root, res)
+	assert.NotNil(t, err, "expecting a storage value mismatch")
+
+	res = accountResult(t, statedb, addr)
+	_, err = verifyAccountProof(common.BytesToHash([]byte{1}), res)
+	assert.NotNil(t, err, "expecting a proof of another root")
+
+	// absent account
+	res = accountResult(t, statedb, common.BytesToAddress([]byte{200}))
+	account, err = verifyAccountProof(root, res)
+	require.Nil(t, err)
+	assert.Nil(t, account)
+}
+
+func TestVerifierAccountProof(t *testing.T) {
+	db := state.NewDatabase(dbm.NewMemDB())
+	statedb, _ := state.New(common.EmptyHash, db)
+	addr := common.BytesToAddress([]byte{42})
+	statedb.AddBalance(addr, big.NewInt(42))
+	root, err := statedb.Commit(false, 1)
+	require.Nil(t, err)
+	statedb, _ = state.New(root, db)
+	res := accountResult(t, statedb, addr)
+	res.BlockNumber = 1
+	res.StateRoot = root
+
+	forks := types.Forks{{Name: types.ForkStateRoot, Height: 2}}
+	vals, privVals := types.RandValidatorSet(4, 10)
+	header := &types.Header{
+		ChainID:        testChainID,
+		Height:         2,
+		ValidatorsHash: common.BytesToHash(vals.Hash()),
+		StateHash:      root,
+	}
+	commit := makeCommit(t, types.BlockID{Hash: header.Hash()}, 2, vals, privVals)
+
+	v := NewVerifier(testChainID, forks, 1, vals)
+	_, err = v.VerifyAccountProof(res)
+	assert.NotNil(t, err, "expecting no trusted header")
+
+	require.Nil(t, v.VerifyHeader(header, commit, vals))
+	account, err := v.VerifyAccountProof(res)
+	require.Nil(t, err)
+	assert.Equal(t, int64(42), account.Balance.Int64())
+
+	// a root other than the one of the header
+	res.StateRoot = common.BytesToHash([]byte{1})
+	_, err = v.VerifyAccountProof(res)
+	assert.NotNil(t, err, "expecting a state root mismatch")
+
+	// the state of a block not followed by the trusted header
+	res.StateRoot = root
+	res.BlockNumber = 2
+	_, err = v.VerifyAccountProof(res)
+	assert.NotNil(t, err, "expecting a block mismatch")
+
+	// headers before the fork do not commit to the state root
+	v = NewVerifier(testChainID, nil, 1, vals)
+	require.Nil(t, v.VerifyHeader(header, commit, vals))
+	res.BlockNumber = 1
+	_, err = v.VerifyAccountProof(res)
+	assert.NotNil(t, err, "expecting no state root before the fork")
+}
@@ -0,0 +1,136 @@
+// Package lightclient verifies the blocks and the state served by an untrusted
+// RPC node, starting from a trusted height and validator set.
+//
+// Headers are verified with the Commit of their block, i.e. the LastCommit of
+// the next block (debug_getBlockBinary), signed by more than 2/3 of the voting
+// power of their validator set (validators). A new validator set is accepted
+// when more than 2/3 of the trusted set also signed the header.
+//
+// From the stateroot fork on, the StateHash of a header is the root of the
+// state trie after the previous block, the account and storage proofs of
+// eth_getProof for a block are verified against the trusted header of the next
+// block.
+package lightclient
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// Verifier verifies the headers following its trusted header, and trusts them
+// once verified.
+type Verifier struct {
+	chainID string
+	forks   types.Forks
+
+	mtx        sync.RWMutex
+	height     uint64
+	header     *types.Header
+	validators *types.ValidatorSet
+}
+
+// NewVerifier returns a verifier trusting the validator set of the block at
+// height, e.g. loaded from the genesis or from a checkpoint. forks is the
+// schedule of protocol upgrades of the chain consensus params.
+func NewVerifier(chainID string, forks types.Forks, height uint64, validators *types.ValidatorSet) *Verifier {
+	return &Verifier{
+		chainID:    chainID,
+		forks:      forks,
+		height:     height,
+		validators: validators.Copy(),
+	}
+}
+
+// Height returns the height of the last trusted header.
+func (v *Verifier) Height() uint64 {
+	v.mtx.RLock()
+	defer v.mtx.RUnlock()
+	return v.height
+}
+
+// Header returns the last header verified, or nil if none was.
+func (v *Verifier) Header() *types.Header {
+	v.mtx.RLock()
+	defer v.mtx.RUnlock()
+	if v.header == nil {
+		return nil
+	}
+	return types.CopyHeader(v.header)
+}
+
+// Validators returns the validator set of the last trusted header.
+func (v *Verifier) Validators() *types.ValidatorSet {
+	v.mtx.RLock()
+	defer v.mtx.RUnlock()
+	return v.validators.Copy()
+}
+
+// VerifyHeader verifies a header above the trusted one with the commit of its
+// block and its validator set, and trusts it if valid.
+func (v *Verifier) VerifyHeader(header *types.Header, commit *types.Commit, validators *types.ValidatorSet) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	if header.ChainID != v.chainID {
+		return fmt.Errorf("header of chain %s, expected %s", header.ChainID, v.chainID)
+	}
+	if header.Height <= v.height {
+		return fmt.Errorf("header %d is not above the trusted header %d", header.Height, v.height)
+	}
+	if header.Recover > 0 {
+		// Recovered blocks are not signed by their validator set.
+		return fmt.Errorf("header %d of a recovered block cannot be verified, the verifier must be reset", header.Height)
+	}
+	if !bytes.Equal(header.ValidatorsHash.Bytes(), validators.Hash()) {
+		return fmt.Errorf("wrong validator set for header %d: hash %X, expected %v", header.Height, validators.Hash(), header.ValidatorsHash)
+	}
+	if commit.BlockID.Hash != header.Hash() {
+		return fmt.Errorf("commit of block %v, expected %v", commit.BlockID.Hash, header.Hash())
+	}
+	if err := validators.VerifyCommit(v.chainID, commit.BlockID, header.Height, commit); err != nil {
+		return err
+	}
+	if !bytes.Equal(validators.Hash(), v.validators.Hash()) {
+		if err := verifyTrusted(v.chainID, v.validators, commit); err != nil {
+			return fmt.Errorf("validator set change at header %d: %v", header.Height, err)
+		}
+	}
+
+	v.height = header.Height
+	v.header = types.CopyHeader(header)
+	v.validators = validators.Copy()
+	return nil
+}
+
+// verifyTrusted checks that more than 2/3 of the voting power of the trusted
+// validator set signed the commit.
+func verifyTrusted(chainID string, trusted *types.ValidatorSet, commit *types.Commit) error {
+	var (
+		tallied int64
+		seen    = make(map[int]bool)
+	)
+	for _, precommit := range commit.Precommits {
+		if precommit == nil || precommit.Type != types.VoteTypePrecommit {
+			continue
+		}
+		idx, val := trusted.GetByAddress(precommit.ValidatorAddress)
+		if val == nil || seen[idx] {
+			continue
+		}
+		if !val.PubKey.VerifyBytes(precommit.SignBytes(chainID), precommit.Signature) {
+			return fmt.Errorf("invalid signature of trusted validator %v", val.Address)
+		}
+		if !commit.BlockID.Equals(precommit.BlockID) {
+			continue
+		}
+		seen[idx] = true
+		tallied += val.VotingPower
+	}
+	if tallied > trusted.TotalVotingPower()*2/3 {
+		return nil
+	}
+	return fmt.Errorf("insufficient trusted voting power: got %v, needed %v", tallied, trusted.TotalVotingPower()*2/3+1)
+}
@@ -0,0 +1,97 @@
+package lightclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChainID = "lightclient"
+
+func makeSignedHeader(t *testing.T, height uint64, vals *types.ValidatorSet, privVals []types.PrivValidator) (*types.Header, *types.Commit) {
+	header := &types.Header{
+		ChainID:        testChainID,
+		Height:         height,
+		ValidatorsHash: common.BytesToHash(vals.Hash()),
+	}
+	blockID := types.BlockID{Hash: header.Hash()}
+	return header, makeCommit(t, blockID, height, vals, privVals)
+}
+
+// makeCommit returns the commit of blockID signed by all the privVals, given in
+// the order of vals.
+func makeCommit(t *testing.T, blockID types.BlockID, height uint64, vals *types.ValidatorSet, privVals []types.PrivValidator) *types.Commit {
+	voteSet := types.NewVoteSet(testChainID, height, 0, types.VoteTypePrecommit, vals)
+	for i, pv := range privVals {
+		vote := &types.Vote{
+			ValidatorAddress: pv.GetAddress(),
+			ValidatorIndex:   i,
+			ValidatorSize:    vals.Size(),
+			Height:           height,
+			Type:             types.VoteTypePrecommit,
+			BlockID:          blockID,
+			Timestamp:        time.Now().UTC(),
+		}
+		require.Nil(t, pv.SignVote(testChainID, vote))
+		_, err := voteSet.AddVote(vote)
+		require.Nil(t, err)
+	}
+	return voteSet.MakeCommit()
+}
+
+func TestVerifyHeader(t *testing.T) {
+	vals, privVals := types.RandValidatorSet(4, 10)
+	v := NewVerifier(testChainID, nil, 1, vals)
+
+	header, commit := makeSignedHeader(t, 2, vals, privVals)
+	require.Nil(t, v.VerifyHeader(header, commit, vals))
+	assert.Equal(t, uint64(2), v.Height())
+
+	// already trusted
+	assert.NotNil(t, v.VerifyHeader(header, commit, vals))
+
+	// commit of another block
+	header, commit = makeSignedHeader(t, 3, vals, privVals)
+	other, _ := makeSignedHeader(t, 3, vals, privVals)
+	other.Time = 1
+	assert.NotNil(t, v.VerifyHeader(other, commit, vals))
+
+	// validator set not matching the header
+	otherVals, _ := types.RandValidatorSet(4, 10)
+	assert.NotNil(t, v.VerifyHeader(header, commit, otherVals))
+
+	// recovered block
+	recovered := types.CopyHeader(header)
+	recovered.Recover = 1
+	assert.NotNil(t, v.VerifyHeader(recovered, commit, vals))
+
+	require.Nil(t, v.VerifyHeader(header, commit, vals))
+	assert.Equal(t, uint64(3), v.Height())
+}
+
+func TestVerifyHeaderValidatorChange(t *testing.T) {
+	vals, privVals := types.RandValidatorSet(4, 10)
+	v := NewVerifier(testChainID, nil, 1, vals)
+
+	// an unrelated set is not trusted
+	otherVals, otherPrivVals := types.RandValidatorSet(4, 10)
+	header, commit := makeSignedHeader(t, 2, otherVals, otherPrivVals)
+	assert.NotNil(t, v.VerifyHeader(header, commit, otherVals))
+
+	// a set signed by the trusted validators is
+	newVal, newPrivVal := types.RandValidator(false, 10)
+	newVals := vals.Copy()
+	require.True(t, newVals.Add(newVal))
+	newPrivVals := make([]types.PrivValidator, newVals.Size())
+	for _, pv := range append(privVals, newPrivVal) {
+		idx, _ := newVals.GetByAddress(pv.GetAddress())
+		newPrivVals[idx] = pv
+	}
+	header, commit = makeSignedHeader(t, 2, newVals, newPrivVals)
+	require.Nil(t, v.VerifyHeader(header, commit, newVals))
+	assert.Equal(t, newVals.Hash(), v.Validators().Hash())
+}
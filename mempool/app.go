@@ -24,6 +24,12 @@ type App interface {
 	GetNonce(addr common.Address) uint64
 	GetBalance(addr common.Address) *big.Int
 	CheckTx(tx types.Tx, checkType bool) error
+	// ResetCheckState sets the account of addr in the check state back to
+	// the committed state, for its transactions to be checked again.
+	ResetCheckState(addr common.Address)
+	// NewBlockCheck returns a check of the transactions of a new block, in
+	// the order they are included, against a copy of the committed state.
+	NewBlockCheck() func(tx types.Tx) error
 }
 
 type mockApp struct {
@@ -50,6 +56,15 @@ func (mApp *mockApp) GetBalance(addr common.Address) *big.Int {
 	return v
 }
 
+// ResetCheckState is a noop, the mock app has no committed state.
+func (mApp *mockApp) ResetCheckState(addr common.Address) {}
+
+// NewBlockCheck returns a check passing every transaction, the mock app has no
+// committed state.
+func (mApp *mockApp) NewBlockCheck() func(tx types.Tx) error {
+	return func(tx types.Tx) error { return nil }
+}
+
 func (mApp *mockApp) CheckTx(tx types.Tx, checkBasic bool) error {
 	if !checkBasic {
 		mApp.mtx.Lock()
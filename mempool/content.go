@@ -0,0 +1,128 @@
+package mempool
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/clist"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// PoolTx is an executable transaction of the mempool along with the time it
+// entered the good transaction lists.
+type PoolTx struct {
+	Tx    types.Tx
+	Since time.Time
+}
+
+// QueuedAccount holds the futureTxs of an account, which can't be executed
+// until the transactions with the nonces between Nonce and the lowest queued
+// one arrive.
+type QueuedAccount struct {
+	Nonce    uint64     // Next nonce of the account, including the pending transactions
+	LastSeen time.Time  // Last transaction queued for the account, the lifetime counts from here
+	Txs      []types.Tx // Queued transactions sorted by nonce
+}
+
+// Gap returns the number of missing nonces keeping the queued transactions
+// from being executed.
+func (q *QueuedAccount) Gap() uint64 {
+	if len(q.Txs) == 0 {
+		return 0
+	}
+	lowest := q.Txs[0].(types.RegularTx).Nonce()
+	if lowest < q.Nonce {
+		return 0
+	}
+	return lowest - q.Nonce
+}
+
+// Content is a snapshot of the transactions in the mempool.
+type Content struct {
+	Pending map[common.Address][]*PoolTx      // goodTxs and specGoodTxs by sender, sorted by nonce
+	UTXO    []*PoolTx                         // utxo transactions without account input
+	Queued  map[common.Address]*QueuedAccount // futureTxs by sender
+}
+
+// Content retrieves the data content of the mempool, returning all the pending
+// as well as queued transactions grouped by account.
+func (mem *Mempool) Content() *Content {
+	return mem.content(nil)
+}
+
+// ContentFrom retrieves the data content of the mempool, returning the pending
+// as well as queued transactions of the given account.
+func (mem *Mempool) ContentFrom(addr common.Address) *Content {
+	return mem.content(&addr)
+}
+
+func (mem *Mempool) content(only *common.Address) *Content {
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+
+	content := &Content{
+		Pending: make(map[common.Address][]*PoolTx),
+		Queued:  make(map[common.Address]*QueuedAccount),
+	}
+	for _, list := range []*clist.CList{mem.goodTxs, mem.specGoodTxs, mem.utxoTxs} {
+		for e := list.Front(); e != nil; e = e.Next() {
+			tx := e.Value.(*mempoolTx).tx
+			ptx := &PoolTx{Tx: tx}
+			if since, ok := mem.goodTxBeats.Load(tx.Hash()); ok {
+				ptx.Since = since.(time.Time)
+			}
+			from, ok := txSender(tx)
+			if !ok {
+				if only == nil {
+					content.UTXO = append(content.UTXO, ptx)
+				}
+				continue
+			}
+			if only != nil && from != *only {
+				continue
+			}
+			content.Pending[from] = append(content.Pending[from], ptx)
+		}
+	}
+	for _, txs := range content.Pending {
+		sort.SliceStable(txs, func(i, j int) bool {
+			return txNonce(txs[i].Tx) < txNonce(txs[j].Tx)
+		})
+	}
+	for from, list := range mem.futureTxs {
+		if list.Empty() || (only != nil && from != *only) {
+			continue
+		}
+		queued := &QueuedAccount{
+			Nonce:    mem.app.GetNonce(from),
+			LastSeen: mem.beats[from],
+		}
+		for _, tx := range list.Flatten() {
+			queued.Txs = append(queued.Txs, tx)
+		}
+		content.Queued[from] = queued
+	}
+	return content
+}
+
+// txSender returns the account a transaction of the mempool is sent from,
+// utxo transactions without account input have none.
+func txSender(tx types.Tx) (common.Address, bool) {
+	if utx, ok := tx.(*types.UTXOTransaction); ok && utx.UTXOKind()&types.Ain != types.Ain {
+		return common.EmptyAddress, false
+	}
+	from, err := tx.From()
+	if err != nil || from == common.EmptyAddress {
+		return common.EmptyAddress, false
+	}
+	return from, true
+}
+
+// txNonce returns the account nonce of a transaction with a sender.
+func txNonce(tx types.Tx) uint64 {
+	if n, ok := tx.(interface{ Nonce() uint64 }); ok {
+		return n.Nonce()
+	}
+	return 0
+}
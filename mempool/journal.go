@@ -0,0 +1,117 @@
+package mempool
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// errNoActiveJournal is returned if a transaction is attempted to be inserted
+// into the journal, but no such file is currently open.
+var errNoActiveJournal = errors.New("no active journal")
+
+// txJournal is a rotating log of transactions with the aim of storing locally
+// submitted transactions to allow them to survive node restarts.
+type txJournal struct {
+	path   string         // Filesystem path to store the transactions at
+	writer io.WriteCloser // Output stream to write new transactions into
+}
+
+// newTxJournal creates a new transaction journal at path.
+func newTxJournal(path string) *txJournal {
+	return &txJournal{
+		path: path,
+	}
+}
+
+// load parses a transaction journal dump from disk, handing its transactions
+// to add in order. It returns the number of transactions read and the number
+// of them add failed for. A partially written transaction, left by a node
+// which was killed, ends the journal with an error.
+func (journal *txJournal) load(add func(types.Tx) error) (total int, dropped int, err error) {
+	// Skip the parsing if the journal file doesn't exist at all
+	if _, err := os.Stat(journal.path); os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	input, err := os.Open(journal.path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer input.Close()
+
+	r := bufio.NewReader(input)
+	for {
+		if _, err = r.Peek(1); err == io.EOF {
+			return total, dropped, nil
+		} else if err != nil {
+			return total, dropped, err
+		}
+		var tx types.Tx
+		if err = ser.DecodeWithType(r, &tx); err != nil {
+			return total, dropped, err
+		}
+		total++
+		if add(tx) != nil {
+			dropped++
+		}
+	}
+}
+
+// insert adds the specified transaction to the local disk journal.
+func (journal *txJournal) insert(tx types.Tx) error {
+	if journal.writer == nil {
+		return errNoActiveJournal
+	}
+	_, err := ser.EncodeWriterWithType(journal.writer, tx)
+	return err
+}
+
+// rotate regenerates the transaction journal from txs, the local transactions
+// currently in the mempool, and opens it for appending.
+func (journal *txJournal) rotate(txs []types.Tx) error {
+	// Close the current journal (if any is open)
+	if journal.writer != nil {
+		if err := journal.writer.Close(); err != nil {
+			return err
+		}
+		journal.writer = nil
+	}
+	// Generate a new journal with the contents of the current pool
+	replacement, err := os.OpenFile(journal.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if _, err = ser.EncodeWriterWithType(replacement, tx); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	replacement.Close()
+
+	// Replace the live journal with the newly generated one
+	if err = os.Rename(journal.path+".new", journal.path); err != nil {
+		return err
+	}
+	sink, err := os.OpenFile(journal.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	journal.writer = sink
+	return nil
+}
+
+// close flushes the transaction journal contents to disk and closes the file.
+func (journal *txJournal) close() error {
+	var err error
+
+	if journal.writer != nil {
+		err = journal.writer.Close()
+		journal.writer = nil
+	}
+	return err
+}
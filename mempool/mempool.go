@@ -1,6 +1,7 @@
 package mempool
 
 import (
+	"path/filepath"
 	"runtime"
 	"sort"
 	"sync"
@@ -75,6 +76,11 @@ var canAddFutureTxType = map[string]struct{}{
 	types.TxUTXO:            struct{}{},
 }
 
+var canReplaceTxType = map[string]struct{}{
+	types.TxNormal: struct{}{},
+	types.TxToken:  struct{}{},
+}
+
 var (
 	// BroadcastTxFunc is called when we broadcast tx
 	BroadcastTxFunc = defaultBroadcastTx
@@ -99,6 +105,7 @@ type Mempool struct {
 	proxyMtx             sync.Mutex
 	utxoTxs              *clist.CList // for utxo input purelly
 	goodTxs              *clist.CList // concurrent linked-list of good txs
+	pending              *txPending   // goodTxs indexed by account and gas price for Reap
 	specGoodTxs          *clist.CList //for updatavalidators Tx and MultiSignAccount Tx
 	futureTxs            map[common.Address]*txList
 	futureTxsCount       int
@@ -108,8 +115,12 @@ type Mempool struct {
 	notifiedTxsAvailable bool
 	txsAvailable         chan struct{} // fires once for each height, when the mempool is not empty
 	sw                   p2p.P2PManager
+	eventBus             types.TxEventPublisher
+	eventBusMtx          sync.RWMutex
 	//broadcastTxChan      chan types.Tx
 	broadcastTxChan chan *RecieveMessage
+	// executable txs to publish on the event bus, out of proxyMtx
+	pendingTxChan chan types.Tx
 
 	// Keep a cache of already-seen txs.
 	// This reduces the pressure on the proxyApp.
@@ -128,6 +139,9 @@ type Mempool struct {
 
 	// goodTxBeats records the elapsed time since this goodTx entered goodTx list
 	goodTxBeats sync.Map
+
+	journal *txJournal               // Journal of local transaction to back up to disk
+	locals  map[common.Hash]struct{} // Hashes of the journaled local transactions
 }
 
 // MemFunc sets an optional parameter on the Mempool.
@@ -141,17 +155,21 @@ func NewMempool(config *cfg.MempoolConfig, height uint64, sw p2p.P2PManager, opt
 		config:          config,
 		utxoTxs:         clist.New(),
 		goodTxs:         clist.New(),
+		pending:         newTxPending(),
 		specGoodTxs:     clist.New(),
 		futureTxs:       make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
 		height:          height,
 		rechecking:      0,
 		sw:              sw,
+		eventBus:        types.NopEventBus{},
 		broadcastTxChan: make(chan *RecieveMessage, config.BroadcastChanSize),
+		pendingTxChan:   make(chan types.Tx, config.BroadcastChanSize),
 		logger:          log.NewNopLogger(),
 		metrics:         NopMetrics(),
 		quit:            make(chan bool),
 		sem:             sem,
+		locals:          make(map[common.Hash]struct{}),
 	}
 
 	if config.CacheSize > 0 {
@@ -169,6 +187,7 @@ func NewMempool(config *cfg.MempoolConfig, height uint64, sw p2p.P2PManager, opt
 
 	go mempool.loop()
 	go mempool.broadcastTxRoutine()
+	go mempool.publishPendingTxRoutine()
 
 	return mempool
 }
@@ -185,6 +204,13 @@ func (mem *Mempool) loop() {
 
 	rebroadcast := time.NewTicker(GoodTxRebroadcastTime)
 	defer rebroadcast.Stop()
+
+	rejournal := mem.config.Rejournal
+	if rejournal < time.Second {
+		rejournal = time.Second
+	}
+	journal := time.NewTicker(rejournal)
+	defer journal.Stop()
 	// Keep waiting for and reacting to the various events
 	for {
 		select {
@@ -244,8 +270,103 @@ func (mem *Mempool) loop() {
 			if count > 0 {
 				mem.logger.Info("rebroadcast goodTx", "count", count)
 			}
+
+		// Handle local transaction journal rotation
+		case <-journal.C:
+			mem.proxyMtx.Lock()
+			if mem.journal != nil {
+				if err := mem.journal.rotate(mem.localTxs()); err != nil {
+					mem.logger.Warn("Failed to rotate local tx journal", "err", err)
+				}
+			}
+			mem.proxyMtx.Unlock()
+		}
+	}
+}
+
+// InitWAL opens the journal of local transactions in WalDir and replays it
+// through CheckTx, so the transactions submitted through this node survive a
+// restart. Transactions which can't be added anymore, e.g. because they were
+// included in a block meanwhile, are dropped from the journal. Journaling is
+// disabled if WalPath is empty.
+// NOTE: must be called after SetApp and before the mempool receives txs.
+func (mem *Mempool) InitWAL() error {
+	if mem.config.WalPath == "" {
+		return nil
+	}
+	walDir := mem.config.WalDir()
+	if err := cmn.EnsureDir(walDir, 0700); err != nil {
+		return err
+	}
+	journal := newTxJournal(filepath.Join(walDir, "journal"))
+	mem.proxyMtx.Lock()
+	mem.journal = journal
+	mem.proxyMtx.Unlock()
+
+	total, dropped, err := journal.load(func(tx types.Tx) error {
+		return mem.AddTx("", tx)
+	})
+	if err != nil {
+		mem.logger.Warn("Failed to load local tx journal", "err", err)
+	}
+	mem.logger.Info("Loaded local tx journal", "transactions", total, "dropped", dropped)
+
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+	return journal.rotate(mem.localTxs())
+}
+
+// CloseWAL closes the journal of local transactions.
+func (mem *Mempool) CloseWAL() {
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+
+	if mem.journal == nil {
+		return
+	}
+	if err := mem.journal.close(); err != nil {
+		mem.logger.Error("Failed to close local tx journal", "err", err)
+	}
+	mem.journal = nil
+}
+
+// journalTx adds a locally submitted transaction to the journal. It must be
+// called with proxyMtx held.
+func (mem *Mempool) journalTx(tx types.Tx) {
+	if mem.journal == nil {
+		return
+	}
+	mem.locals[tx.Hash()] = struct{}{}
+	if err := mem.journal.insert(tx); err != nil && err != errNoActiveJournal {
+		mem.logger.Warn("Failed to journal local transaction", "hash", tx.Hash(), "err", err)
+	}
+}
+
+// localTxs returns the journaled transactions still in the mempool, in an
+// order they can be added again. The ones which left the mempool are
+// forgotten. It must be called with proxyMtx held.
+func (mem *Mempool) localTxs() []types.Tx {
+	txs := make([]types.Tx, 0, len(mem.locals))
+	locals := make(map[common.Hash]struct{}, len(mem.locals))
+	collect := func(tx types.Tx) {
+		hash := tx.Hash()
+		if _, ok := mem.locals[hash]; ok {
+			txs = append(txs, tx)
+			locals[hash] = struct{}{}
+		}
+	}
+	for _, list := range []*clist.CList{mem.specGoodTxs, mem.goodTxs, mem.utxoTxs} {
+		for e := list.Front(); e != nil; e = e.Next() {
+			collect(e.Value.(*mempoolTx).tx)
 		}
 	}
+	for _, list := range mem.futureTxs {
+		for _, tx := range list.Flatten() {
+			collect(tx)
+		}
+	}
+	mem.locals = locals
+	return txs
 }
 
 //Stop ...
@@ -289,6 +410,19 @@ func (mem *Mempool) SetApp(a App) {
 	mem.app = a
 }
 
+// SetEventBus sets the event bus the executable transactions are published on.
+func (mem *Mempool) SetEventBus(b types.TxEventPublisher) {
+	mem.eventBusMtx.Lock()
+	mem.eventBus = b
+	mem.eventBusMtx.Unlock()
+}
+
+func (mem *Mempool) getEventBus() types.TxEventPublisher {
+	mem.eventBusMtx.RLock()
+	defer mem.eventBusMtx.RUnlock()
+	return mem.eventBus
+}
+
 // SetLogger sets the Logger.
 func (mem *Mempool) SetLogger(l log.Logger) {
 	mem.logger = l
@@ -374,7 +508,7 @@ func (mem *Mempool) addUTXOTx(tx types.Tx) (err error) {
 	if isOnlyUtxoInput {
 		mem.addPureUtxoTx(tx)
 	} else {
-		if mem.goodTxs.Len() >= mem.config.Size {
+		if mem.goodTxs.Len() >= mem.config.Size && !mem.evictGoodTx(tx) {
 			err = mem.addFutureTx(tx)
 			return err
 		}
@@ -385,7 +519,7 @@ func (mem *Mempool) addUTXOTx(tx types.Tx) (err error) {
 
 func (mem *Mempool) addLocalTx(tx types.Tx) (err error) {
 	if err = mem.app.CheckTx(tx, StateCheck); err == nil {
-		if mem.goodTxs.Len() < mem.config.Size {
+		if mem.goodTxs.Len() < mem.config.Size || mem.evictGoodTx(tx) {
 			mem.addGoodTx(tx, true)
 		} else {
 			err = mem.addFutureTx(tx)
@@ -393,10 +527,104 @@ func (mem *Mempool) addLocalTx(tx types.Tx) (err error) {
 	} else if err == types.ErrNonceTooHigh {
 		// delete cache should be processed outside	(same as addUTXOTx)
 		err = mem.addFutureTx(tx)
+	} else if err == types.ErrNonceTooLow {
+		err = mem.replaceGoodTx(tx)
 	}
 	return err
 }
 
+// replaceGoodTx replaces the goodTx with the same sender and nonce as tx, if tx
+// pays at least PriceBump percent more gas price. ErrNonceTooLow is returned
+// if there's no such goodTx.
+func (mem *Mempool) replaceGoodTx(tx types.Tx) error {
+	if _, exist := canReplaceTxType[tx.TypeName()]; !exist {
+		return types.ErrNonceTooLow
+	}
+	rtx, ok := tx.(types.RegularTx)
+	if !ok {
+		return types.ErrNonceTooLow
+	}
+	from, _ := tx.From()
+	old := mem.pending.Get(from, rtx.Nonce())
+	if old == nil {
+		return types.ErrNonceTooLow
+	}
+	if !priceBumped(old.tx, rtx, mem.config.PriceBump) {
+		return types.ErrReplaceUnderpriced
+	}
+	// Check the account again with tx in place of old, the transactions
+	// following old must stay affordable.
+	mem.app.ResetCheckState(from)
+	for _, ptx := range mem.pending.AccountTxs(from) {
+		next := ptx.tx
+		if ptx == old {
+			next = rtx
+		}
+		if err := mem.app.CheckTx(next, StateCheck); err != nil {
+			mem.recheckAccount(from)
+			return err
+		}
+	}
+	mem.removeGoodTx(old)
+	mem.cache.Delete(old.tx.Hash())
+	mem.addGoodTx(tx, false)
+	mem.logger.Debug("Replaced good transaction", "old", old.tx.Hash(), "hash", tx.Hash(), "from", from, "nonce", rtx.Nonce(), "price", rtx.GasPrice())
+	return nil
+}
+
+// evictGoodTx makes room in a full goodTxs for tx, by dropping the cheapest
+// last transaction of another account if tx pays a higher gas price. The nonce
+// of the dropped transaction can be reused at once.
+func (mem *Mempool) evictGoodTx(tx types.Tx) bool {
+	rtx, ok := tx.(types.RegularTx)
+	if !ok {
+		return false
+	}
+	from, _ := tx.From()
+	cheapest := mem.pending.Cheapest(from)
+	if cheapest == nil || cheapest.tx.GasPrice().Cmp(rtx.GasPrice()) >= 0 {
+		return false
+	}
+	mem.removeGoodTx(cheapest)
+	mem.cache.Delete(cheapest.tx.Hash())
+	mem.recheckAccount(cheapest.from)
+	mem.logger.Debug("Evicted underpriced good transaction", "hash", cheapest.tx.Hash(), "price", cheapest.tx.GasPrice(), "by", tx.Hash(), "byPrice", rtx.GasPrice())
+	return true
+}
+
+// recheckAccount sets the check state of an account back to the committed
+// state and checks its goodTxs again in nonce order, after one of them was
+// dropped or replaced. The goodTxs failing the check are removed like on
+// recheckTxs.
+func (mem *Mempool) recheckAccount(from common.Address) {
+	mem.app.ResetCheckState(from)
+	for _, ptx := range mem.pending.AccountTxs(from) {
+		err := mem.app.CheckTx(ptx.tx, StateCheck)
+		if err == nil {
+			continue
+		}
+		mem.removeGoodTx(ptx)
+		if err == types.ErrNonceTooHigh {
+			// nonce too high, move goodTxs to futureTxs
+			err = mem.addFutureTx(ptx.tx)
+		}
+		if err != nil {
+			mem.cache.Delete(ptx.tx.Hash())
+		}
+		mem.logger.Debug("removeGoodTx when recheck account", "hash", ptx.tx.Hash(), "err", err)
+	}
+}
+
+// removeGoodTx removes an indexed transaction from goodTxs.
+func (mem *Mempool) removeGoodTx(ptx *pendingTx) {
+	hash := ptx.tx.Hash()
+	mem.goodTxs.Remove(ptx.elem)
+	ptx.elem.DetachPrev()
+	mem.pending.Remove(hash)
+	mem.goodTxBeats.Delete(hash) // remove goodTx enter time
+	mem.metrics.Size.Set(float64(mem.GoodTxsSize()))
+}
+
 func (mem *Mempool) addLocalSpecTx(tx types.Tx) (err error) {
 	if err = mem.app.CheckTx(tx, StateCheck); err == nil {
 		if mem.specGoodTxs.Len() < mem.config.SpecSize {
@@ -476,14 +704,19 @@ func (mem *Mempool) AddTx(peerID string, tx types.Tx) (err error) {
 	}
 	if err != nil {
 		mem.cache.Delete(tx.Hash())
-	} else if mem.config.Broadcast {
+		return err
+	}
+	if peerID == "" {
+		mem.journalTx(tx)
+	}
+	if mem.config.Broadcast {
 		select {
 		case mem.broadcastTxChan <- &RecieveMessage{PeerID: peerID, Tx: tx}:
 		default:
 			mem.logger.Info("broadcastTxChan is full", "size", mem.config.BroadcastChanSize, "hash", tx.Hash())
 		}
 	}
-	return err
+	return nil
 }
 
 //@Note: Caller should print the error log
@@ -529,6 +762,30 @@ func (mem *Mempool) broadcastTxRoutine() {
 	}
 }
 
+// publishPendingTxRoutine publishes the executable txs, so that slow subscribers
+// of the event bus do not hold up CheckTx and Update.
+func (mem *Mempool) publishPendingTxRoutine() {
+	for {
+		select {
+		case tx := <-mem.pendingTxChan:
+			mem.getEventBus().PublishEventPendingTx(types.EventDataPendingTx{Tx: tx})
+		case <-mem.quit:
+			return
+		}
+	}
+}
+
+// publishPendingTx queues the tx for publishing, it is dropped and counted by
+// the DroppedPendingTxs metric if the queue is full.
+func (mem *Mempool) publishPendingTx(tx types.Tx) {
+	select {
+	case mem.pendingTxChan <- tx:
+	default:
+		mem.metrics.DroppedPendingTxs.Add(1)
+		mem.logger.Error("pendingTxChan is full, pending tx not published", "size", mem.config.BroadcastChanSize, "hash", tx.Hash())
+	}
+}
+
 func defaultBroadcastTx(peerID string, tx types.Tx, sw p2p.P2PManager, logger log.Logger) {
 	msg := TxHashMessage{Hashs: []common.Hash{tx.Hash()}, Kind: TxHashNotify}
 	data, err := ser.EncodeToBytesWithType(&msg)
@@ -547,6 +804,7 @@ func (mem *Mempool) addSpecGoodTx(tx types.Tx) {
 	memTx := &mempoolTx{tx: tx, addtime: &addtime}
 	mem.specGoodTxs.PushBack(memTx)
 	mem.logger.Debug("Added Specgood transaction", "hash", tx.Hash(), "type", tx.TypeName())
+	mem.publishPendingTx(tx)
 	mem.notifyTxsAvailable()
 }
 
@@ -557,6 +815,7 @@ func (mem *Mempool) addPureUtxoTx(tx types.Tx) {
 	memTx := &mempoolTx{tx: tx}
 	mem.utxoTxs.PushBack(memTx)
 	mem.logger.Debug("Added pure utxo transaction", "hash", tx.Hash(), "type", tx.TypeName())
+	mem.publishPendingTx(tx)
 	mem.notifyTxsAvailable()
 }
 
@@ -566,16 +825,20 @@ func (mem *Mempool) addGoodTx(tx types.Tx, notPromote bool) {
 	mem.goodTxBeats.Store(tx.Hash(), time.Now())
 
 	memTx := &mempoolTx{tx: tx}
-	mem.goodTxs.PushBack(memTx)
+	elem := mem.goodTxs.PushBack(memTx)
+	from, _ := tx.From()
+	if rtx, ok := tx.(types.RegularTx); ok {
+		mem.pending.Add(from, rtx, elem)
+	}
 	mem.logger.Debug("Added good transaction", "hash", tx.Hash(), "type", tx.TypeName())
 	mem.metrics.Size.Set(float64(mem.GoodTxsSize()))
+	mem.publishPendingTx(tx)
 	mem.notifyTxsAvailable()
 
 	if _, exist := canPromoteTxType[tx.TypeName()]; !exist {
 		return
 	}
 	if notPromote {
-		from, _ := tx.From()
 		mem.promoteExecutables([]common.Address{from})
 	}
 }
@@ -587,10 +850,15 @@ func (mem *Mempool) addTofutureTxs(from common.Address, tx types.RegularTx) erro
 		mem.beats[from] = time.Now()
 	}
 
-	inserted, _ := mem.futureTxs[from].Add(tx)
+	inserted, old := mem.futureTxs[from].Add(tx, mem.config.PriceBump)
 	if !inserted {
-		mem.logger.Warn("futureTxs Add tx duplicate cached", "nonce", tx.Nonce(), "hash", tx.Hash())
-		return types.ErrTxDuplicate
+		mem.logger.Warn("futureTxs Add tx underpriced replacement", "nonce", tx.Nonce(), "hash", tx.Hash())
+		return types.ErrReplaceUnderpriced
+	}
+	if old != nil {
+		mem.cache.Delete(old.Hash())
+		mem.logger.Debug("Replaced future transaction", "old", old.Hash(), "hash", tx.Hash(), "from", from, "nonce", tx.Nonce())
+		return nil
 	}
 	mem.logger.Debug("Added future transaction", "hash", tx.Hash(), "type", tx.TypeName(), "from", from, "nonce", tx.Nonce())
 	mem.futureTxsCount++
@@ -790,7 +1058,7 @@ func (mem *Mempool) Reap(maxTxs int) types.Txs {
 	specTxs := mem.collectTxs(mem.specGoodTxs, mem.config.SpecSize) // get all special txs
 
 	maxTxs = maxTxs - len(specTxs) - len(utxoTxs)
-	txs := mem.collectTxs(mem.goodTxs, maxTxs)
+	txs := mem.collectPendingTxs(maxTxs)
 	mem.logger.Info("Reap end", "utxoTxs", len(utxoTxs), "specTxs", len(specTxs), "txsLen", len(txs), "maxTxs", maxTxs)
 	txs = append(txs, utxoTxs...)
 	txs = append(txs, specTxs...)
@@ -817,6 +1085,46 @@ func (mem *Mempool) collectTxs(txList *clist.CList, maxTxs int) types.Txs {
 	return txs
 }
 
+// collectPendingTxs collects goodTxs by gas price, keeping the transactions of
+// an account in nonce order. The transactions of different accounts change
+// places, so each one is checked again against the block built so far: a
+// transaction failing the check is left out with the later ones of its
+// account, it would fail the processing of the block.
+// maxTxs: -1 means uncapped, 0 means none
+func (mem *Mempool) collectPendingTxs(maxTxs int) types.Txs {
+	if maxTxs <= 0 {
+		return make([]types.Tx, 0)
+	}
+	utxoTxCount := 0
+	txs := make([]types.Tx, 0, cmn.MinInt(mem.pending.Len(), maxTxs))
+	set := mem.pending.txsByPriceAndNonce()
+	check := mem.app.NewBlockCheck()
+	for len(txs) < maxTxs {
+		tx := set.Peek()
+		if tx == nil {
+			break
+		}
+		if tx.TypeName() == types.TxUTXO {
+			if utxoTxCount >= mem.config.UTXOSize {
+				// Skip the account, its later nonces can't be included either
+				set.Pop()
+				continue
+			}
+		}
+		if err := check(tx); err != nil {
+			mem.logger.Info("Reap: leave out transaction failing the block check", "hash", tx.Hash(), "err", err)
+			set.Pop()
+			continue
+		}
+		if tx.TypeName() == types.TxUTXO {
+			utxoTxCount++
+		}
+		txs = append(txs, tx)
+		set.Shift()
+	}
+	return txs
+}
+
 // Update informs the mempool that the given txs were committed and can be discarded.
 // NOTE: this should be called *after* block is committed by consensus.
 // NOTE: unsafe; Lock/Unlock must be managed by caller
@@ -864,6 +1172,7 @@ func (mem *Mempool) filterTxs(blockTxsMap map[string]struct{}) {
 			// Remove the tx if it's alredy in a block.
 			if _, ok := blockTxsMap[memTx.tx.Hash().String()]; ok {
 				txsList.Remove(e)
+				mem.pending.Remove(memTx.tx.Hash())
 				mem.cache.DelayDelete(memTx.tx.Hash())
 				mem.goodTxBeats.Delete(memTx.tx.Hash()) // remove goodTx enter time
 				e.DetachPrev()
@@ -879,6 +1188,7 @@ func (mem *Mempool) filterTxs(blockTxsMap map[string]struct{}) {
 			interval := time.Since(t.(time.Time))
 			if interval >= GoodTxDropTime { // need to drop goodTx
 				txsList.Remove(e)
+				mem.pending.Remove(memTx.tx.Hash())
 				mem.cache.Delete(memTx.tx.Hash())
 				mem.goodTxBeats.Delete(memTx.tx.Hash()) // remove goodTx enter time
 				mem.logger.Info("filterTxs: Drop GoodTx for Timeout", "hash", memTx.tx.Hash(), "Beat", t.(time.Time))
@@ -930,6 +1240,7 @@ func (mem *Mempool) recheckTxs() {
 			}
 			txsList.Remove(e)
 			e.DetachPrev()
+			mem.pending.Remove(memTx.tx.Hash())
 			mem.goodTxBeats.Delete(memTx.tx.Hash()) // remove goodTx enter time
 			if err != nil {
 				mem.cache.Delete(memTx.tx.Hash())
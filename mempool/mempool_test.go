@@ -5,23 +5,33 @@ import (
 	crand "crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/lianxiangcloud/linkchain/accounts/keystore"
+	"github.com/lianxiangcloud/linkchain/app"
+	"github.com/lianxiangcloud/linkchain/blockchain"
 	"github.com/lianxiangcloud/linkchain/config"
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
 	lktypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
 	"github.com/lianxiangcloud/linkchain/libs/log"
 	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/libs/txmgr"
 
 	//"github.com/lianxiangcloud/linkchain/libs/p2p"
+	"github.com/lianxiangcloud/linkchain/state"
 	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -312,6 +322,367 @@ func TestReap(t *testing.T) {
 	fmt.Println(mem.Stats())
 }
 
+// testPrice returns percent percent of ParGasPrice.
+func testPrice(percent int64) *big.Int {
+	return big.NewInt(types.ParGasPrice / 100 * percent)
+}
+
+// testGenPricedEtx builds a signed transfer paying the given gas price.
+func testGenPricedEtx(from, to *keystore.Key, nonce uint64, amount *big.Int, price *big.Int) types.Tx {
+	tx := types.NewTransaction(nonce, to.Address, amount, 0, nil, nil).WithGasPrice(price)
+	if err := tx.Sign(types.GlobalSTDSigner, from.PrivateKey); err != nil {
+		panic(err)
+	}
+	return tx
+}
+
+func testNewMockMempool(cfg *config.MempoolConfig, app *mockApp) *Mempool {
+	mem := NewMempool(cfg, 0, nil)
+	app.mempool = mem
+	mem.app = app
+	return mem
+}
+
+// testNewApp returns an application on memory databases, the accounts are
+// funded by the genesis state and the fee market fork is active.
+func testNewApp(t *testing.T, accounts []*keystore.Key) *app.LinkApplication {
+	alloc := make(map[string]types.GenesisAccount, len(accounts))
+	for _, key := range accounts {
+		alloc[key.Address.Hex()] = types.GenesisAccount{Balance: testBalance}
+	}
+	stateDB := dbm.NewMemDB()
+	st, err := state.New(common.EmptyHash, state.NewKeyValueDBWithCache(stateDB, 0, true, 0))
+	require.Nil(t, err)
+	app.AllocGenesisAccounts(st, alloc)
+	stateHash := st.IntermediateRoot(false)
+	trieRoot, err := st.Commit(false, types.BlockHeightZero)
+	require.Nil(t, err)
+	st.Database().TrieDB().Commit(trieRoot, false)
+
+	blockStore := blockchain.NewBlockStore(dbm.NewMemDB())
+	block := &types.Block{
+		Header:     &types.Header{Height: types.BlockHeightZero, StateHash: stateHash},
+		Data:       &types.Data{},
+		LastCommit: &types.Commit{},
+	}
+	blockStore.SaveBlock(block, block.MakePartSet(types.DefaultConsensusParams().BlockGossip.BlockPartSizeBytes), nil, nil,
+		&types.TxsResult{TrieRoot: trieRoot, StateHash: stateHash})
+
+	utxoStore := utxo.NewUtxoStore(dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB())
+	balanceRecord := blockchain.NewBalanceRecordStore(dbm.NewMemDB(), false)
+	appHandle, err := app.NewLinkApplication(stateDB, blockStore, utxoStore, txmgr.NewCrossState(dbm.NewMemDB(), blockStore),
+		types.NewEventBus(), true, balanceRecord, nil, nil)
+	require.Nil(t, err)
+	appHandle.SetForks(types.Forks{{Name: types.ForkFeeMarket, Height: types.BlockHeightZero}})
+	return appHandle
+}
+
+var testBalance = new(big.Int).Mul(big.NewInt(1e18), big.NewInt(1e6))
+
+// testNewAppMempool returns a mempool checking the transactions with a real
+// application, see testNewApp.
+func testNewAppMempool(t *testing.T, cfg *config.MempoolConfig, accounts []*keystore.Key) (*Mempool, *app.LinkApplication) {
+	appHandle := testNewApp(t, accounts)
+	mem := NewMempool(cfg, 0, nil)
+	mem.SetApp(appHandle)
+	appHandle.SetMempool(mem)
+	return mem, appHandle
+}
+
+func TestReapByGasPrice(t *testing.T) {
+	accounts := testGetAccounts(4)
+	mem, _ := testNewAppMempool(t, config.DefaultMempoolConfig(), accounts)
+	defer mem.Stop()
+
+	a, b, c, to := accounts[0], accounts[1], accounts[2], accounts[3]
+	txs := []types.Tx{
+		testGenPricedEtx(a, to, 0, big.NewInt(10), testPrice(100)),
+		testGenPricedEtx(a, to, 1, big.NewInt(10), testPrice(100)),
+		testGenPricedEtx(b, to, 0, big.NewInt(10), testPrice(500)),
+		testGenPricedEtx(c, to, 0, big.NewInt(10), testPrice(100)),
+		testGenPricedEtx(c, to, 1, big.NewInt(10), testPrice(800)),
+	}
+	for _, tx := range txs {
+		require.Nil(t, mem.AddTx("", tx))
+	}
+
+	// b pays most, c1 pays more than a but has to wait for c0, a arrived before c
+	want := []types.Tx{txs[2], txs[0], txs[1], txs[3], txs[4]}
+	reaped := mem.Reap(len(txs))
+	require.Equal(t, len(want), len(reaped))
+	for i := range want {
+		assert.Equal(t, want[i].Hash(), reaped[i].Hash(), "i=%d", i)
+	}
+	assert.Equal(t, 2, len(mem.Reap(2)))
+}
+
+// blockedTxPublisher stands for a subscriber of the event bus that does not
+// read until it is released.
+type blockedTxPublisher struct {
+	entered   chan struct{}
+	release   chan struct{}
+	published chan types.Tx
+}
+
+func (p *blockedTxPublisher) PublishEventPendingTx(event types.EventDataPendingTx) error {
+	p.entered <- struct{}{}
+	<-p.release
+	p.published <- event.Tx
+	return nil
+}
+
+func TestPublishPendingTx(t *testing.T) {
+	accounts := testGetAccounts(2)
+	cfg := config.DefaultMempoolConfig()
+	cfg.BroadcastChanSize = 1
+	mem, _ := testNewAppMempool(t, cfg, accounts)
+	defer mem.Stop()
+	dropped := generic.NewCounter("dropped")
+	mem.metrics = &Metrics{Size: discard.NewGauge(), DroppedPendingTxs: dropped}
+	publisher := &blockedTxPublisher{
+		entered:   make(chan struct{}, 5),
+		release:   make(chan struct{}),
+		published: make(chan types.Tx, 5),
+	}
+	mem.SetEventBus(publisher)
+
+	txs := make([]types.Tx, 5)
+	for i := range txs {
+		txs[i] = testGenPricedEtx(accounts[0], accounts[1], uint64(i), big.NewInt(10), testPrice(100))
+	}
+	require.Nil(t, mem.AddTx("", txs[0]))
+	<-publisher.entered
+
+	// the txs are added while the publishing is blocked, the ones over the queue are dropped
+	for _, tx := range txs[1:4] {
+		require.Nil(t, mem.AddTx("", tx))
+	}
+	assert.Equal(t, 4, mem.GoodTxsSize())
+	assert.Equal(t, float64(2), dropped.Value())
+
+	close(publisher.release)
+	assert.Equal(t, txs[0].Hash(), (<-publisher.published).Hash())
+	assert.Equal(t, txs[1].Hash(), (<-publisher.published).Hash())
+
+	// the queue is empty again, the next tx is published after the dropped ones
+	require.Nil(t, mem.AddTx("", txs[4]))
+	assert.Equal(t, txs[4].Hash(), (<-publisher.published).Hash())
+	assert.Equal(t, float64(2), dropped.Value())
+}
+
+func TestGasPriceBeforeFeeMarket(t *testing.T) {
+	accounts := testGetAccounts(2)
+	mem, appHandle := testNewAppMempool(t, config.DefaultMempoolConfig(), accounts)
+	defer mem.Stop()
+	appHandle.SetForks(nil)
+
+	from, to := accounts[0], accounts[1]
+	assert.Equal(t, types.ErrGasLimitOrGasPrice, mem.AddTx("", testGenPricedEtx(from, to, 0, big.NewInt(10), testPrice(200))))
+	require.Nil(t, mem.AddTx("", testGenPricedEtx(from, to, 0, big.NewInt(10), testPrice(100))))
+}
+
+// blockCheckApp is a mock app failing the block check of a transaction.
+type blockCheckApp struct {
+	*mockApp
+	fail common.Hash
+}
+
+func (a *blockCheckApp) NewBlockCheck() func(tx types.Tx) error {
+	return func(tx types.Tx) error {
+		if tx.Hash() == a.fail {
+			return types.ErrInsufficientFunds
+		}
+		return nil
+	}
+}
+
+func TestReapBlockCheck(t *testing.T) {
+	mock := testNewMockApp(3)
+	mem := testNewMockMempool(config.DefaultMempoolConfig(), mock)
+	defer mem.Stop()
+
+	a, b, to := mock.accounts[0], mock.accounts[1], mock.accounts[2]
+	txs := []types.Tx{
+		testGenPricedEtx(a, to, 0, big.NewInt(10), testPrice(100)),
+		testGenPricedEtx(b, to, 0, big.NewInt(10), testPrice(100)),
+		testGenPricedEtx(b, to, 1, big.NewInt(10), testPrice(100)),
+	}
+	for _, tx := range txs {
+		require.Nil(t, mem.AddTx("", tx))
+	}
+	mem.app = &blockCheckApp{mockApp: mock, fail: txs[1].Hash()}
+
+	// the later transaction of b can't be included without the failing one
+	reaped := mem.Reap(len(txs))
+	require.Equal(t, 1, len(reaped))
+	assert.Equal(t, txs[0].Hash(), reaped[0].Hash())
+	assert.Equal(t, len(txs), mem.GoodTxsSize())
+}
+
+func TestReplaceGoodTx(t *testing.T) {
+	accounts := testGetAccounts(2)
+	mem, appHandle := testNewAppMempool(t, config.DefaultMempoolConfig(), accounts)
+	defer mem.Stop()
+
+	from, to := accounts[0], accounts[1]
+	first := testGenPricedEtx(from, to, 0, big.NewInt(10), testPrice(100)).(*types.Transaction)
+	second := testGenPricedEtx(from, to, 1, big.NewInt(10), testPrice(100)).(*types.Transaction)
+	require.Nil(t, mem.AddTx("", first))
+	require.Nil(t, mem.AddTx("", second))
+
+	// a replacement has to pay at least PriceBump percent more
+	assert.Equal(t, types.ErrReplaceUnderpriced, mem.AddTx("", testGenPricedEtx(from, to, 0, big.NewInt(11), testPrice(100))))
+	assert.Equal(t, types.ErrReplaceUnderpriced, mem.AddTx("", testGenPricedEtx(from, to, 0, big.NewInt(11), testPrice(105))))
+
+	// the later transactions have to stay affordable
+	spent := new(big.Int).Add(first.Cost(), second.Cost())
+	unaffordable := testGenPricedEtx(from, to, 0, new(big.Int).Sub(testBalance, second.Cost()), testPrice(110))
+	assert.Equal(t, types.ErrInsufficientFunds, mem.AddTx("", unaffordable))
+	assert.Equal(t, new(big.Int).Sub(testBalance, spent), appHandle.GetBalance(from.Address))
+	assert.Equal(t, uint64(2), appHandle.GetNonce(from.Address))
+
+	// the check state pays for the replacement instead of the replaced
+	replacement := testGenPricedEtx(from, to, 0, big.NewInt(11), testPrice(110)).(*types.Transaction)
+	require.Nil(t, mem.AddTx("", replacement))
+	assert.Equal(t, 2, mem.GoodTxsSize())
+	assert.False(t, mem.cache.Exists(first.Hash()))
+	spent = new(big.Int).Add(replacement.Cost(), second.Cost())
+	assert.Equal(t, new(big.Int).Sub(testBalance, spent), appHandle.GetBalance(from.Address))
+	assert.Equal(t, uint64(2), appHandle.GetNonce(from.Address))
+
+	reaped := mem.Reap(10)
+	require.Equal(t, 2, len(reaped))
+	assert.Equal(t, replacement.Hash(), reaped[0].Hash())
+	assert.Equal(t, second.Hash(), reaped[1].Hash())
+
+	// future transactions are replaced the same way
+	future := testGenPricedEtx(from, to, 5, big.NewInt(10), testPrice(100))
+	require.Nil(t, mem.AddTx("", future))
+	assert.Equal(t, types.ErrReplaceUnderpriced, mem.AddTx("", testGenPricedEtx(from, to, 5, big.NewInt(11), testPrice(100))))
+	require.Nil(t, mem.AddTx("", testGenPricedEtx(from, to, 5, big.NewInt(11), testPrice(200))))
+	_, _, queued := mem.Stats()
+	assert.Equal(t, 1, queued)
+	assert.False(t, mem.cache.Exists(future.Hash()))
+}
+
+func TestEvictGoodTx(t *testing.T) {
+	cfg := config.DefaultMempoolConfig()
+	cfg.Size = 2
+	accounts := testGetAccounts(5)
+	mem, appHandle := testNewAppMempool(t, cfg, accounts)
+	defer mem.Stop()
+
+	to := accounts[4]
+	first := testGenPricedEtx(accounts[0], to, 0, big.NewInt(10), testPrice(100))
+	second := testGenPricedEtx(accounts[1], to, 0, big.NewInt(10), testPrice(100))
+	require.Nil(t, mem.AddTx("", first))
+	require.Nil(t, mem.AddTx("", second))
+
+	// an equal price doesn't evict anything
+	require.Nil(t, mem.AddTx("", testGenPricedEtx(accounts[2], to, 0, big.NewInt(10), testPrice(100))))
+	_, pending, queued := mem.Stats()
+	assert.Equal(t, 2, pending)
+	assert.Equal(t, 1, queued)
+
+	// a higher price evicts the latest of the cheapest
+	urgent := testGenPricedEtx(accounts[3], to, 0, big.NewInt(10), testPrice(200))
+	require.Nil(t, mem.AddTx("", urgent))
+	assert.Equal(t, 2, mem.GoodTxsSize())
+	assert.False(t, mem.cache.Exists(second.Hash()))
+
+	// the evicted transaction is no longer paid for by the check state
+	assert.Equal(t, uint64(0), appHandle.GetNonce(accounts[1].Address))
+	assert.Equal(t, testBalance, appHandle.GetBalance(accounts[1].Address))
+
+	reaped := mem.Reap(10)
+	require.Equal(t, 2, len(reaped))
+	assert.Equal(t, urgent.Hash(), reaped[0].Hash())
+	assert.Equal(t, first.Hash(), reaped[1].Hash())
+}
+
+func TestMempoolJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mempool_journal")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.DefaultMempoolConfig()
+	cfg.WalPath = dir
+	app := testNewMockApp(2)
+	mem := testNewMockMempool(cfg, app)
+	require.Nil(t, mem.InitWAL())
+
+	from, to := app.accounts[0], app.accounts[1]
+	txs := []types.Tx{
+		testGenPricedEtx(from, to, 0, big.NewInt(10), testPrice(100)),
+		testGenPricedEtx(from, to, 1, big.NewInt(10), testPrice(100)),
+		testGenPricedEtx(from, to, 3, big.NewInt(10), testPrice(100)),
+	}
+	for _, tx := range txs {
+		require.Nil(t, mem.AddTx("", tx))
+	}
+	// transactions of peers are not journaled
+	require.Nil(t, mem.AddTx("peer", testGenPricedEtx(to, from, 0, big.NewInt(10), testPrice(100))))
+	mem.CloseWAL()
+	mem.Stop()
+
+	// restart after the first transaction got included in a block
+	restarted := testNewMockApp(0)
+	restarted.accounts = app.accounts
+	for addr, balance := range app.balance {
+		restarted.balance[addr] = balance
+	}
+	restarted.nonce[from.Address] = 1
+	mem = testNewMockMempool(cfg, restarted)
+	defer mem.Stop()
+	require.Nil(t, mem.InitWAL())
+	defer mem.CloseWAL()
+
+	_, pending, queued := mem.Stats()
+	assert.Equal(t, 1, pending)
+	assert.Equal(t, 1, queued)
+	assert.Equal(t, txs[1].Hash(), mem.Reap(10)[0].Hash())
+
+	var journaled []common.Hash
+	total, dropped, err := newTxJournal(filepath.Join(dir, "journal")).load(func(tx types.Tx) error {
+		journaled = append(journaled, tx.Hash())
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 0, dropped)
+	assert.Equal(t, []common.Hash{txs[1].Hash(), txs[2].Hash()}, journaled)
+}
+
+func TestMempoolContent(t *testing.T) {
+	app := testNewMockApp(3)
+	mem := testNewMockMempool(config.DefaultMempoolConfig(), app)
+	defer mem.Stop()
+
+	from, other, to := app.accounts[0], app.accounts[1], app.accounts[2]
+	require.Nil(t, mem.AddTx("", testGenPricedEtx(from, to, 1, big.NewInt(10), testPrice(100))))
+	require.Nil(t, mem.AddTx("", testGenPricedEtx(from, to, 0, big.NewInt(10), testPrice(100))))
+	require.Nil(t, mem.AddTx("", testGenPricedEtx(from, to, 4, big.NewInt(10), testPrice(100))))
+	require.Nil(t, mem.AddTx("", testGenPricedEtx(other, to, 0, big.NewInt(10), testPrice(100))))
+
+	content := mem.Content()
+	require.Equal(t, 2, len(content.Pending))
+	require.Equal(t, 2, len(content.Pending[from.Address]))
+	for i, ptx := range content.Pending[from.Address] {
+		assert.Equal(t, uint64(i), ptx.Tx.(types.RegularTx).Nonce())
+		assert.False(t, ptx.Since.IsZero())
+	}
+	require.Equal(t, 1, len(content.Queued))
+	queued := content.Queued[from.Address]
+	assert.Equal(t, uint64(2), queued.Nonce)
+	assert.Equal(t, uint64(2), queued.Gap())
+	assert.Equal(t, 1, len(queued.Txs))
+
+	content = mem.ContentFrom(other.Address)
+	assert.Equal(t, 1, len(content.Pending))
+	assert.Equal(t, 1, len(content.Pending[other.Address]))
+	assert.Equal(t, 0, len(content.Queued))
+}
+
 // func TestBenchAdd(t *testing.T) {
 // 	testMempoolBench(1, 20)
 // }
@@ -13,6 +13,8 @@ import (
 type Metrics struct {
 	// Size of the mempool.
 	Size metrics.Gauge
+	// Number of pending txs not published on the event bus.
+	DroppedPendingTxs metrics.Counter
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -23,12 +25,18 @@ func PrometheusMetrics() *Metrics {
 			Name:      "size",
 			Help:      "Size of the mempool (number of uncommitted transactions).",
 		}, []string{}),
+		DroppedPendingTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: "mempool",
+			Name:      "dropped_pending_txs",
+			Help:      "Number of pending transactions not published to the subscribers, the publishing queue was full.",
+		}, []string{}),
 	}
 }
 
 // NopMetrics returns no-op Metrics.
 func NopMetrics() *Metrics {
 	return &Metrics{
-		Size: discard.NewGauge(),
+		Size:              discard.NewGauge(),
+		DroppedPendingTxs: discard.NewCounter(),
 	}
 }
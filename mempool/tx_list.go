@@ -18,6 +18,7 @@ package mempool
 
 import (
 	"container/heap"
+	"math/big"
 	"sort"
 
 	"github.com/lianxiangcloud/linkchain/types"
@@ -217,6 +218,14 @@ func (m *txSortedMap) Len() int {
 // sorted internal representation. The result of the sorting is cached in case
 // it's requested again before any modifications are made to the contents.
 func (m *txSortedMap) Flatten() types.Transactions {
+	// Copy the cache to prevent accidental modifications
+	cache := m.flatten()
+	txs := make(types.Transactions, len(cache))
+	copy(txs, cache)
+	return txs
+}
+
+func (m *txSortedMap) flatten() types.Transactions {
 	// If the sorting was not cached yet, create and cache it
 	if m.cache == nil {
 		m.cache = make(types.Transactions, 0, len(m.items))
@@ -225,10 +234,17 @@ func (m *txSortedMap) Flatten() types.Transactions {
 		}
 		sort.Sort(types.TxByNonce(m.cache))
 	}
-	// Copy the cache to prevent accidental modifications
-	txs := make(types.Transactions, len(m.cache))
-	copy(txs, m.cache)
-	return txs
+	return m.cache
+}
+
+// LastElement returns the transaction with the highest nonce, or nil if the
+// map is empty.
+func (m *txSortedMap) LastElement() types.RegularTx {
+	cache := m.flatten()
+	if len(cache) == 0 {
+		return nil
+	}
+	return cache[len(cache)-1]
 }
 
 // txList is a "list" of transactions belonging to an account, sorted by account
@@ -258,12 +274,12 @@ func (l *txList) Overlaps(tx types.RegularTx) bool {
 // Add tries to insert a new transaction into the list, returning whether the
 // transaction was accepted, and if yes, any previous transaction it replaced.
 //
-// If the new transaction is accepted into the list, the lists' cost and gas
-// thresholds are also potentially updated.
-func (l *txList) Add(tx types.RegularTx) (bool, types.RegularTx) {
+// A transaction with the same nonce is only replaced if the new one pays at
+// least priceBump percent more gas price.
+func (l *txList) Add(tx types.RegularTx, priceBump uint64) (bool, types.RegularTx) {
 	// If there's an older better transaction, abort
 	old := l.txs.Get(tx.Nonce())
-	if old != nil {
+	if old != nil && !priceBumped(old, tx, priceBump) {
 		return false, nil
 	}
 	// Otherwise overwrite the old transaction with the current one
@@ -311,6 +327,17 @@ func (l *txList) Ready(start, end uint64) types.Transactions {
 	return l.txs.Ready(start, end)
 }
 
+// Get retrieves the transaction with the given nonce, or nil if there is none.
+func (l *txList) Get(nonce uint64) types.RegularTx {
+	return l.txs.Get(nonce)
+}
+
+// LastElement returns the transaction with the highest nonce, or nil if the
+// list is empty.
+func (l *txList) LastElement() types.RegularTx {
+	return l.txs.LastElement()
+}
+
 // Len returns the length of the transaction list.
 func (l *txList) Len() int {
 	return l.txs.Len()
@@ -327,3 +354,11 @@ func (l *txList) Empty() bool {
 func (l *txList) Flatten() types.Transactions {
 	return l.txs.Flatten()
 }
+
+// priceBumped returns whether tx pays a higher gas price than old, by at least
+// priceBump percent.
+func priceBumped(old, tx types.RegularTx, priceBump uint64) bool {
+	threshold := new(big.Int).Mul(old.GasPrice(), new(big.Int).SetUint64(100+priceBump))
+	threshold.Div(threshold, big.NewInt(100))
+	return old.GasPrice().Cmp(tx.GasPrice()) < 0 && threshold.Cmp(tx.GasPrice()) <= 0
+}
@@ -0,0 +1,222 @@
+package mempool
+
+import (
+	"container/heap"
+
+	"github.com/lianxiangcloud/linkchain/libs/clist"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// pendingTx is a transaction of goodTxs along with its position in the list.
+type pendingTx struct {
+	tx    types.RegularTx
+	from  common.Address
+	elem  *clist.CElement
+	seq   uint64 // arrival order, breaks gas price ties
+	loose bool   // no account nonce, e.g. a utxo transaction calling a contract
+}
+
+// txPending indexes the account transactions of goodTxs. The transactions of
+// an account are nonce sorted in a txList, the accounts themselves are handed
+// out by gas price. Transactions without an account nonce stand on their own.
+type txPending struct {
+	accounts map[common.Address]*txList
+	all      map[common.Hash]*pendingTx
+	loose    map[common.Hash]*pendingTx
+	seq      uint64
+}
+
+func newTxPending() *txPending {
+	return &txPending{
+		accounts: make(map[common.Address]*txList),
+		all:      make(map[common.Hash]*pendingTx),
+		loose:    make(map[common.Hash]*pendingTx),
+	}
+}
+
+// Add indexes a transaction stored at elem of goodTxs.
+func (p *txPending) Add(from common.Address, tx types.RegularTx, elem *clist.CElement) {
+	p.seq++
+	ptx := &pendingTx{tx: tx, from: from, elem: elem, seq: p.seq}
+	p.all[tx.Hash()] = ptx
+	if utx, ok := tx.(*types.UTXOTransaction); ok && utx.UTXOKind()&types.Ain != types.Ain {
+		ptx.loose = true
+		p.loose[tx.Hash()] = ptx
+		return
+	}
+	list := p.accounts[from]
+	if list == nil {
+		list = newTxList(false)
+		p.accounts[from] = list
+	}
+	list.txs.Put(tx)
+}
+
+// Remove drops a transaction from the index, it's a noop for transactions
+// which are not indexed.
+func (p *txPending) Remove(hash common.Hash) {
+	ptx := p.all[hash]
+	if ptx == nil {
+		return
+	}
+	delete(p.all, hash)
+	if ptx.loose {
+		delete(p.loose, hash)
+		return
+	}
+	list := p.accounts[ptx.from]
+	list.Remove(ptx.tx)
+	if list.Empty() {
+		delete(p.accounts, ptx.from)
+	}
+}
+
+// Get retrieves the indexed transaction of an account with the given nonce.
+func (p *txPending) Get(from common.Address, nonce uint64) *pendingTx {
+	list := p.accounts[from]
+	if list == nil {
+		return nil
+	}
+	if tx := list.Get(nonce); tx != nil {
+		return p.all[tx.Hash()]
+	}
+	return nil
+}
+
+// AccountTxs returns the indexed transactions of an account in nonce order.
+func (p *txPending) AccountTxs(from common.Address) []*pendingTx {
+	list := p.accounts[from]
+	if list == nil {
+		return nil
+	}
+	txs := list.Flatten()
+	ptxs := make([]*pendingTx, len(txs))
+	for i, tx := range txs {
+		ptxs[i] = p.all[tx.Hash()]
+	}
+	return ptxs
+}
+
+// Cheapest returns the lowest priced transaction among the highest nonce
+// transactions of all accounts but exclude. Only the last transaction of an
+// account can be dropped without invalidating the ones following it, utxo
+// transactions are never dropped as they hold key images. On equal gas price
+// the latest arrival is the cheapest.
+func (p *txPending) Cheapest(exclude common.Address) *pendingTx {
+	var cheapest *pendingTx
+	for from, list := range p.accounts {
+		if from == exclude {
+			continue
+		}
+		ptx := p.all[list.LastElement().Hash()]
+		if ptx.tx.TypeName() == types.TxUTXO {
+			continue
+		}
+		if cheapest == nil {
+			cheapest = ptx
+			continue
+		}
+		switch ptx.tx.GasPrice().Cmp(cheapest.tx.GasPrice()) {
+		case -1:
+			cheapest = ptx
+		case 0:
+			if ptx.seq > cheapest.seq {
+				cheapest = ptx
+			}
+		}
+	}
+	return cheapest
+}
+
+// Len returns the number of indexed transactions.
+func (p *txPending) Len() int {
+	return len(p.all)
+}
+
+// txsByPriceAndNonce returns the indexed transactions in a profit maximizing
+// sorted order, while supporting removing entire batches of transactions for
+// non-executable accounts.
+func (p *txPending) txsByPriceAndNonce() *txsByPriceAndNonce {
+	t := &txsByPriceAndNonce{
+		txs:   make(map[common.Address][]*pendingTx, len(p.accounts)),
+		heads: make(priceHeap, 0, len(p.accounts)+len(p.loose)),
+	}
+	for from, list := range p.accounts {
+		txs := list.txs.flatten()
+		ptxs := make([]*pendingTx, len(txs))
+		for i, tx := range txs {
+			ptxs[i] = p.all[tx.Hash()]
+		}
+		t.heads = append(t.heads, ptxs[0])
+		t.txs[from] = ptxs[1:]
+	}
+	for _, ptx := range p.loose {
+		t.heads = append(t.heads, ptx)
+	}
+	heap.Init(&t.heads)
+	return t
+}
+
+// priceHeap is a heap.Interface implementation over transactions for retrieving
+// price-sorted transactions, the earlier arrival first on equal prices.
+type priceHeap []*pendingTx
+
+func (h priceHeap) Len() int      { return len(h) }
+func (h priceHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h priceHeap) Less(i, j int) bool {
+	switch h[i].tx.GasPrice().Cmp(h[j].tx.GasPrice()) {
+	case 1:
+		return true
+	case -1:
+		return false
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h *priceHeap) Push(x interface{}) {
+	*h = append(*h, x.(*pendingTx))
+}
+
+func (h *priceHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// txsByPriceAndNonce represents a set of transactions that can return
+// transactions in a profit-maximizing sorted order, while supporting removing
+// entire batches of transactions for non-executable accounts.
+type txsByPriceAndNonce struct {
+	txs   map[common.Address][]*pendingTx // Per account nonce-sorted list of transactions
+	heads priceHeap                       // Next transaction for each unique account (price heap)
+}
+
+// Peek returns the next transaction by price.
+func (t *txsByPriceAndNonce) Peek() types.RegularTx {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0].tx
+}
+
+// Shift replaces the current best head with the next one from the same account.
+func (t *txsByPriceAndNonce) Shift() {
+	from := t.heads[0].from
+	if txs, ok := t.txs[from]; ok && len(txs) > 0 && !t.heads[0].loose {
+		t.heads[0], t.txs[from] = txs[0], txs[1:]
+		heap.Fix(&t.heads, 0)
+	} else {
+		heap.Pop(&t.heads)
+	}
+}
+
+// Pop removes the best transaction, *not* replacing it with the next one from
+// the same account. This should be used when a transaction cannot be executed
+// and hence all subsequent ones should be discarded from the same account.
+func (t *txsByPriceAndNonce) Pop() {
+	heap.Pop(&t.heads)
+}
@@ -107,6 +107,10 @@ func (p *prometheusMetric) SetCurrentProposerPubkey(pubkey crypto.PubKey) {
 }
 
 func (p *prometheusMetric) ProposerPubkeyEquals() bool {
+	// the node is no validator, or it is no node at all like the import command
+	if p.pubkey == nil {
+		return false
+	}
 	return p.pubkey.Equals(p.currentBlockProposerPubkey)
 }
 
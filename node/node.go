@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"net/http"
 	_ "net/http/pprof"
+	"runtime"
 	"time"
 
+	"errors"
 	"fmt"
 
 	"github.com/lianxiangcloud/linkchain/accounts"
@@ -17,6 +19,7 @@ import (
 	cs "github.com/lianxiangcloud/linkchain/consensus"
 	"github.com/lianxiangcloud/linkchain/evidence"
 	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
 	dbm "github.com/lianxiangcloud/linkchain/libs/db"
 	"github.com/lianxiangcloud/linkchain/libs/hexutil"
 	"github.com/lianxiangcloud/linkchain/libs/log"
@@ -26,8 +29,10 @@ import (
 	"github.com/lianxiangcloud/linkchain/libs/txmgr"
 	mempl "github.com/lianxiangcloud/linkchain/mempool"
 	"github.com/lianxiangcloud/linkchain/metrics"
+	"github.com/lianxiangcloud/linkchain/privval"
 	"github.com/lianxiangcloud/linkchain/rpc/service"
 	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/statesync"
 	"github.com/lianxiangcloud/linkchain/types"
 	"github.com/lianxiangcloud/linkchain/utxo"
 	"github.com/lianxiangcloud/linkchain/version"
@@ -63,12 +68,43 @@ func DefaultMetricsProvider() (*cs.Metrics, *mempl.Metrics) {
 	return cs.PrometheusMetrics(), mempl.PrometheusMetrics()
 }
 
+// PrivValidatorPassphrase returns the passphrase of an encrypted
+// priv_validator_file, it is called by DefaultNewNode.
+var PrivValidatorPassphrase func() (string, error)
+
 // DefaultNewNode returns a node with default settings for the
 // PrivValidator, and DBProvider.
 // It implements NodeProvider.
 func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
+	var privValidator types.PrivValidator
+	if config.PrivValidatorListenAddr != "" {
+		// the validator key is held by a remote signer
+		pvsc, err := newSocketPV(config, logger)
+		if err != nil {
+			return nil, err
+		}
+		if err := pvsc.Start(); err != nil {
+			return nil, fmt.Errorf("Error starting the remote signer listener: %v", err)
+		}
+		privValidator = pvsc
+	} else {
+		pv := types.LoadOrGenFilePV(config.PrivValidatorFile())
+		if pv.IsLocked() {
+			if PrivValidatorPassphrase == nil {
+				return nil, fmt.Errorf("No passphrase to unlock the encrypted %s", config.PrivValidatorFile())
+			}
+			passphrase, err := PrivValidatorPassphrase()
+			if err != nil {
+				return nil, err
+			}
+			if err := pv.Unlock(passphrase); err != nil {
+				return nil, fmt.Errorf("Failed to unlock %s: %v", config.PrivValidatorFile(), err)
+			}
+		}
+		privValidator = pv
+	}
 	return NewNode(config,
-		types.LoadOrGenFilePV(config.PrivValidatorFile()),
+		privValidator,
 		DefaultDBProvider,
 		DefaultMetricsProvider,
 		logger,
@@ -100,12 +136,14 @@ type Node struct {
 	// services
 	eventBus         *types.EventBus // pub/sub for services
 	stateDB          dbm.DB
-	blockStore       *bc.BlockStore         // store the blockchain to disk
-	bcReactor        *bc.BlockchainReactor  // for fast-syncing
-	mempoolReactor   *mempl.MempoolReactor  // for gossipping transactions
-	consensusState   *cs.ConsensusState     // latest consensus state
-	consensusReactor *cs.ConsensusReactor   // for participating in the consensus
-	evidencePool     *evidence.EvidencePool // tracking evidence
+	blockStore       *bc.BlockStore              // store the blockchain to disk
+	addressIndexer   *bc.AddressIndexer          // index the transactions by address, optional
+	bcReactor        *bc.BlockchainReactor       // for fast-syncing
+	mempoolReactor   *mempl.MempoolReactor       // for gossipping transactions
+	consensusState   *cs.ConsensusState          // latest consensus state
+	consensusReactor *cs.ConsensusReactor        // for participating in the consensus
+	evidencePool     *evidence.EvidencePool      // tracking evidence
+	stateSyncReactor *statesync.StateSyncReactor // for joining from a state snapshot
 	syncManager      *sync.SyncHeightManager
 	// rpc
 	//rpcContext *service.Context
@@ -125,6 +163,41 @@ func makeAccountManager(config *cfg.Config) (*accounts.Manager, error) {
 	return accounts.NewManager(backends...), nil
 }
 
+// newSocketPV returns a SocketPV for the remote signer pinned in config.
+func newSocketPV(config *cfg.Config, logger log.Logger) (*privval.SocketPV, error) {
+	if config.PrivValidatorPubKey == "" {
+		return nil, errors.New("missing priv_validator_pubkey of the remote signer")
+	}
+	pubKey, err := privval.PubKeyFromHex(config.PrivValidatorPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid priv_validator_pubkey: %v", err)
+	}
+	var signerKey crypto.PubKey
+	if config.PrivValidatorSignerKey != "" {
+		if signerKey, err = privval.PubKeyFromHex(config.PrivValidatorSignerKey); err != nil {
+			return nil, fmt.Errorf("Invalid priv_validator_signer_key: %v", err)
+		}
+	} else if proto, _ := cmn.ProtocolAndAddress(config.PrivValidatorListenAddr); proto == "tcp" {
+		return nil, errors.New("missing priv_validator_signer_key of the remote signer")
+	}
+	nodeKey, err := privval.LoadOrGenNodeKey(config.NodeKeyFile())
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Waiting for the remote signer", "addr", config.PrivValidatorListenAddr, "nodeKey", privval.PubKeyHex(nodeKey.PubKey()))
+	return privval.NewSocketPV(logger.With("module", "privval"), config.PrivValidatorListenAddr, nodeKey, signerKey, pubKey), nil
+}
+
+// loadNodeKey returns the key identifying the node in the p2p network and to
+// the bootnodes. It is the validator key, unless the validator key is held by
+// a remote signer: the node key of node_key_file is used instead.
+func loadNodeKey(config *cfg.Config, privValidator types.PrivValidator) (crypto.PrivKey, error) {
+	if _, ok := privValidator.(*privval.SocketPV); ok {
+		return privval.LoadOrGenNodeKey(config.NodeKeyFile())
+	}
+	return privValidator.GetPrikey(), nil
+}
+
 // NewNode returns a new, ready to go.
 func NewNode(config *cfg.Config,
 	privValidator types.PrivValidator,
@@ -133,13 +206,16 @@ func NewNode(config *cfg.Config,
 	logger log.Logger) (*Node, error) {
 	var seeds []*p2pcmn.Node
 	var localNodeType types.NodeType
-	var err error
+	nodeKey, err := loadNodeKey(config, privValidator)
+	if err != nil {
+		return nil, err
+	}
 	if len(config.BootNodeSvr.Addrs) != 0 {
 		bootnode.UpdateBootNode(config.BootNodeSvr.Addrs, logger)
 	}
 	var bootNodeAddr = bootnode.GetBestBootNode()
 	if len(bootNodeAddr) != 0 && config.IsTestMode == false {
-		seeds, localNodeType, err = bootnode.GetSeeds(bootNodeAddr, privValidator.GetPrikey(), logger)
+		seeds, localNodeType, err = bootnode.GetSeeds(bootNodeAddr, nodeKey, logger)
 	}
 	if err != nil {
 		logger.Error("GetSeeds failed")
@@ -228,6 +304,18 @@ func NewNode(config *cfg.Config,
 	eventBus := types.NewEventBus()
 	eventBus.SetLogger(logger.With("module", "events"))
 
+	// Make AddressIndexer
+	var addressIndexer *bc.AddressIndexer
+	if config.AddressIndex {
+		addressIndexDB, err := dbProvider(&DBContext{"address_index", config})
+		if err != nil {
+			return nil, err
+		}
+		addressIndexer = bc.NewAddressIndexer(addressIndexDB, blockStore, balanceRecord)
+		addressIndexer.SetLogger(logger.With("module", "addrindex"))
+		addressIndexer.SetEventBus(eventBus)
+	}
+
 	// Make Evidence Reactor
 	evidenceLogger := logger.With("module", "evidence")
 	evidenceStore := evidence.NewEvidenceStore(evidenceDB)
@@ -259,12 +347,29 @@ func NewNode(config *cfg.Config,
 	utxoStore.SetLogger(logger.With("module", "utxoStore"))
 
 	//create app
-	isTrie := config.FullNode
+	stateRetention, err := config.StateRetentionMode()
+	if err != nil {
+		return nil, err
+	}
+	isTrie := stateRetention != cfg.StateRetentionKV
 	appHandle, err := app.NewLinkApplication(newDB, blockStore, utxoStore, txService, eventBus, isTrie, balanceRecord, app.SetPoceeds, app.AllocAward)
 	if err != nil {
 		return nil, err
 	}
 	appHandle.SetLogger(logger.With("module", "app"))
+	if err := appHandle.SetForks(status.ConsensusParams.Forks); err != nil {
+		return nil, fmt.Errorf("%v, state_retention must not be %s", err, cfg.StateRetentionKV)
+	}
+	if stateRetention == cfg.StateRetentionPruned {
+		if err := appHandle.SetStatePruning(config.StateRetentionBlocks); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info("State retention", "mode", stateRetention, "blocks", config.StateRetentionBlocks)
+	if config.ParallelExec {
+		appHandle.SetParallelExec(runtime.NumCPU())
+		logger.Info("Parallel transaction execution", "workers", runtime.NumCPU())
+	}
 
 	// make block executor for update consensus status
 	blockExec := cs.NewBlockExecutor(statusDB, logger, evidencePool)
@@ -307,7 +412,7 @@ func NewNode(config *cfg.Config,
 	}
 	p2pLogger := logger.With("module", "p2p")
 	localNodeInfo := MakeNodeInfo(status.ChainID, localNodeType, config.Moniker, config.RPC.HTTPEndpoint)
-	p2pmanager, err := p2p.NewP2pManager(p2pLogger, privValidator.GetPrikey(), config.P2P,
+	p2pmanager, err := p2p.NewP2pManager(p2pLogger, nodeKey, config.P2P,
 		localNodeInfo, seeds, p2pDB)
 	if err != nil {
 		logger.Warn("NewP2pManager failed")
@@ -329,9 +434,12 @@ func NewNode(config *cfg.Config,
 	)
 	mempool.SetLogger(mempoolLogger)
 	mempool.SetApp(appHandle)
+	mempool.SetEventBus(eventBus)
 	appHandle.SetMempool(mempool)
 	appHandle.SetConm(p2pmanager.GetConManager())
-	//mempool.InitWAL() // no need to have the mempool wal during tests
+	if err := mempool.InitWAL(); err != nil {
+		return nil, err
+	}
 	mempoolReactor := mempl.NewMempoolReactor(config.Mempool, mempool)
 	mempoolReactor.SetLogger(mempoolLogger)
 
@@ -350,8 +458,22 @@ func NewNode(config *cfg.Config,
 		}
 	}
 
+	// A new full node may restore the state of a recent block from peers,
+	// it then fast-syncs the remaining blocks.
+	stateSync := config.StateSync.Enable && blockStore.Height() == types.BlockHeightZero
+	if stateSync && !isTrie {
+		logger.Warn("State sync requires full_node, replaying all blocks instead")
+		stateSync = false
+	}
+	if stateSync && (config.StateSync.TrustHeight == 0 || config.StateSync.TrustHash == "") {
+		return nil, errors.New("State sync requires the state_sync.trust_height and trust_hash of a trusted block")
+	}
+	stateSyncReactor := statesync.NewStateSyncReactor(config.StateSync, status.ChainID, appHandle, blockStore,
+		utxoStore, newDB, statusDB, isTrie, stateSync, p2pmanager)
+	stateSyncReactor.SetLogger(logger.With("module", "statesync"))
+
 	// Make BlockchainReactor
-	bcReactor := bc.NewBlockchainReactor(status.Copy(), blockExec, appHandle, fastSync, p2pmanager)
+	bcReactor := bc.NewBlockchainReactor(status.Copy(), blockExec, appHandle, fastSync && !stateSync, p2pmanager)
 	bcReactor.SetLogger(logger.With("module", "blockchain"))
 	// bcReactor.KeepFastSync(isTrie)
 
@@ -377,7 +499,7 @@ func NewNode(config *cfg.Config,
 	if privValidator != nil {
 		consensusState.SetPrivValidator(privValidator)
 	}
-	consensusReactor := cs.NewConsensusReactor(consensusState, fastSync, p2pmanager)
+	consensusReactor := cs.NewConsensusReactor(consensusState, fastSync || stateSync, p2pmanager)
 	consensusReactor.SetLogger(consensusLogger)
 
 	consensusReactor.SetReceiveP2pTx(!isTrie)
@@ -385,6 +507,7 @@ func NewNode(config *cfg.Config,
 	p2pmanager.AddReactor("BLOCKCHAIN", bcReactor)
 	p2pmanager.AddReactor("CONSENSUS", consensusReactor)
 	p2pmanager.AddReactor("EVIDENCE", evidenceReactor)
+	p2pmanager.AddReactor("STATESYNC", stateSyncReactor)
 
 	// Filter peers by addr or pubkey with an ABCI query.
 	// If the query return code is OK, add peer.
@@ -410,6 +533,9 @@ func NewNode(config *cfg.Config,
 	rpcContext.SetUTXO(utxoStore)
 	rpcContext.SetEventBus(eventBus)
 	rpcContext.SetTxService(txService)
+	if addressIndexer != nil {
+		rpcContext.SetAddressIndexer(addressIndexer)
+	}
 	//rpcContext.SetCoinbase(common.HexToAddress(coinbase))
 	rpcService := service.New(config.RPC, rpcContext)
 
@@ -431,11 +557,13 @@ func NewNode(config *cfg.Config,
 
 		stateDB:          statusDB,
 		blockStore:       blockStore,
+		addressIndexer:   addressIndexer,
 		bcReactor:        bcReactor,
 		mempoolReactor:   mempoolReactor,
 		consensusState:   consensusState,
 		consensusReactor: consensusReactor,
 		evidencePool:     evidencePool,
+		stateSyncReactor: stateSyncReactor,
 		eventBus:         eventBus,
 		rpcService:       rpcService,
 		syncManager:      syncManager,
@@ -452,6 +580,12 @@ func (n *Node) OnStart() error {
 		return err
 	}
 
+	if n.addressIndexer != nil {
+		if err = n.addressIndexer.Start(); err != nil {
+			return err
+		}
+	}
+
 	if err = n.rpcService.Start(); err != nil {
 		n.Logger.Warn("rpc service start fail", "err", err)
 		return err
@@ -477,14 +611,22 @@ func (n *Node) OnStop() {
 	n.Logger.Info("Stopping Node")
 
 	// first stop the non-reactor services
+	if n.addressIndexer != nil {
+		n.addressIndexer.Stop()
+	}
 	n.eventBus.Stop()
 
 	// second stop the reactors
 	// TODO: gracefully disconnect from peers.
 	n.p2pmanager.Stop()
 	n.syncManager.Stop()
+	n.mempoolReactor.Mempool.CloseWAL()
 
 	n.rpcService.Stop()
+
+	if pvsc, ok := n.privValidator.(*privval.SocketPV); ok {
+		pvsc.Stop()
+	}
 }
 
 // RunForever waits for an interrupt signal and stops the node.
@@ -549,6 +691,7 @@ func MakeNodeInfo(chainID string, nodeType types.NodeType, moniker string, httpE
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
 			mempl.MempoolChannel,
 			evidence.EvidenceChannel,
+			statesync.StateSyncChannel,
 		},
 		Moniker: moniker,
 		Other: []string{
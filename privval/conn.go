@@ -0,0 +1,101 @@
+package privval
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+	p2pconn "github.com/lianxiangcloud/linkchain/libs/p2p/conn"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+)
+
+// maxMsgSize is the maximum size of a message, far above a signed proposal.
+const maxMsgSize = 64 * 1024
+
+// listen listens on a "tcp://" or "unix://" address.
+func listen(addr string) (net.Listener, error) {
+	proto, address := cmn.ProtocolAndAddress(addr)
+	return net.Listen(proto, address)
+}
+
+// secureConn encrypts and authenticates the TCP connections with a
+// SecretConnection, the remote end has to authenticate with remoteKey. The
+// UNIX socket connections are left as is, their access is controlled by the
+// file permissions.
+func secureConn(conn net.Conn, privKey crypto.PrivKey, remoteKey crypto.PubKey) (net.Conn, error) {
+	if _, ok := conn.(*net.UnixConn); ok {
+		return conn, nil
+	}
+	if remoteKey == nil {
+		conn.Close()
+		return nil, errors.New("no public key of the remote end configured")
+	}
+	sc, err := p2pconn.MakeSecretConnection(conn, privKey)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !remoteKey.Equals(sc.RemotePubKey()) {
+		sc.Close()
+		return nil, fmt.Errorf("unknown remote end %X, expected %X", sc.RemotePubKey().Bytes(), remoteKey.Bytes())
+	}
+	return sc, nil
+}
+
+// PubKeyFromHex decodes a public key in the hex encoding of PubKeyHex.
+func PubKeyFromHex(s string) (crypto.PubKey, error) {
+	bz, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.PubKeyFromBytes(bz)
+}
+
+// PubKeyHex encodes a public key for the configurations pinning it.
+func PubKeyHex(pubKey crypto.PubKey) string {
+	return hexutil.Encode(pubKey.Bytes())
+}
+
+// writeMsg writes a length prefixed message.
+func writeMsg(conn net.Conn, msg SocketPVMsg, timeout time.Duration) error {
+	bz, err := ser.EncodeToBytesWithType(msg)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 4+len(bz))
+	binary.BigEndian.PutUint32(buf, uint32(len(bz)))
+	copy(buf[4:], bz)
+
+	if timeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(timeout))
+	}
+	_, err = conn.Write(buf)
+	return err
+}
+
+// readMsg reads a message written by writeMsg.
+func readMsg(conn net.Conn, timeout time.Duration) (msg SocketPVMsg, err error) {
+	if timeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+	}
+	var size [4]byte
+	if _, err = io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxMsgSize {
+		return nil, fmt.Errorf("message exceeds max size (%d > %d)", n, maxMsgSize)
+	}
+	bz := make([]byte, n)
+	if _, err = io.ReadFull(conn, bz); err != nil {
+		return nil, err
+	}
+	err = ser.DecodeBytesWithType(bz, &msg)
+	return msg, err
+}
@@ -0,0 +1,87 @@
+package privval
+
+import (
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// SocketPVMsg is sent between a SocketPV and a RemoteSigner.
+type SocketPVMsg interface{}
+
+func RegisterSocketPVMsgs() {
+	ser.RegisterInterface((*SocketPVMsg)(nil), nil)
+	ser.RegisterConcrete(&PubKeyRequest{}, "privval/PubKeyRequest", nil)
+	ser.RegisterConcrete(&PubKeyResponse{}, "privval/PubKeyResponse", nil)
+	ser.RegisterConcrete(&SignVoteRequest{}, "privval/SignVoteRequest", nil)
+	ser.RegisterConcrete(&SignedVoteResponse{}, "privval/SignedVoteResponse", nil)
+	ser.RegisterConcrete(&SignProposalRequest{}, "privval/SignProposalRequest", nil)
+	ser.RegisterConcrete(&SignedProposalResponse{}, "privval/SignedProposalResponse", nil)
+	ser.RegisterConcrete(&SignHeartbeatRequest{}, "privval/SignHeartbeatRequest", nil)
+	ser.RegisterConcrete(&SignedHeartbeatResponse{}, "privval/SignedHeartbeatResponse", nil)
+	ser.RegisterConcrete(&SignDataRequest{}, "privval/SignDataRequest", nil)
+	ser.RegisterConcrete(&SignedDataResponse{}, "privval/SignedDataResponse", nil)
+	ser.RegisterConcrete(&PingRequest{}, "privval/PingRequest", nil)
+	ser.RegisterConcrete(&PingResponse{}, "privval/PingResponse", nil)
+}
+
+// PubKeyRequest requests the public key of the validator.
+type PubKeyRequest struct{}
+
+// PubKeyResponse is the public key of the validator.
+type PubKeyResponse struct {
+	PubKey crypto.PubKey
+}
+
+// SignVoteRequest requests the signature of a vote.
+type SignVoteRequest struct {
+	ChainID string
+	Vote    *types.Vote
+}
+
+// SignedVoteResponse is the signed vote, or the reason the signer refused it.
+type SignedVoteResponse struct {
+	Vote  *types.Vote
+	Error string
+}
+
+// SignProposalRequest requests the signature of a proposal.
+type SignProposalRequest struct {
+	ChainID  string
+	Proposal *types.Proposal
+}
+
+// SignedProposalResponse is the signed proposal, or the reason the signer refused it.
+type SignedProposalResponse struct {
+	Proposal *types.Proposal
+	Error    string
+}
+
+// SignHeartbeatRequest requests the signature of a heartbeat.
+type SignHeartbeatRequest struct {
+	ChainID   string
+	Heartbeat *types.Heartbeat
+}
+
+// SignedHeartbeatResponse is the signed heartbeat, or the reason the signer refused it.
+type SignedHeartbeatResponse struct {
+	Heartbeat *types.Heartbeat
+	Error     string
+}
+
+// SignDataRequest requests the signature of some data, e.g. of a multisign tx.
+type SignDataRequest struct {
+	Data []byte
+}
+
+// SignedDataResponse is the signature of the data, or the reason the signer refused it.
+type SignedDataResponse struct {
+	Signature []byte
+	Error     string
+}
+
+// PingRequest checks that the signer is alive.
+type PingRequest struct{}
+
+// PingResponse answers a PingRequest.
+type PingResponse struct{}
@@ -0,0 +1,42 @@
+package privval
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+)
+
+// NodeKey is the persisted key identifying a node whose validator key is held
+// by a remote signer.
+type NodeKey struct {
+	PrivKey crypto.PrivKey `json:"priv_key"`
+}
+
+// LoadOrGenNodeKey loads the node key from filePath, or generates a new one
+// and saves it to filePath.
+func LoadOrGenNodeKey(filePath string) (crypto.PrivKey, error) {
+	if cmn.FileExists(filePath) {
+		bz, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		nodeKey := &NodeKey{}
+		if err := ser.UnmarshalJSON(bz, nodeKey); err != nil {
+			return nil, fmt.Errorf("Error reading NodeKey from %v: %v", filePath, err)
+		}
+		return nodeKey.PrivKey, nil
+	}
+
+	nodeKey := &NodeKey{PrivKey: crypto.GenPrivKeyEd25519()}
+	bz, err := ser.MarshalJSONIndent(nodeKey, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := cmn.WriteFileAtomic(filePath, bz, 0600); err != nil {
+		return nil, err
+	}
+	return nodeKey.PrivKey, nil
+}
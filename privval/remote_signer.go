@@ -0,0 +1,221 @@
+package privval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+const defaultDialRetryInterval = 1 * time.Second
+
+// RemoteSigner dials the address a SocketPV listens on, and serves its
+// requests with a local PrivValidator such as a FilePV.
+type RemoteSigner struct {
+	cmn.BaseService
+
+	addr              string
+	chainID           string
+	connKey           crypto.PrivKey
+	nodeKey           crypto.PubKey
+	privVal           types.PrivValidator
+	dialRetryInterval time.Duration
+
+	mtx  sync.Mutex
+	conn net.Conn
+}
+
+// NewRemoteSigner returns a RemoteSigner signing for chainID only. The TCP
+// connections are authenticated with connKey, which must not be the key of
+// the validator, and made to the node holding nodeKey only.
+func NewRemoteSigner(logger log.Logger, chainID, addr string, privVal types.PrivValidator, connKey crypto.PrivKey, nodeKey crypto.PubKey) *RemoteSigner {
+	rs := &RemoteSigner{
+		addr:              addr,
+		chainID:           chainID,
+		connKey:           connKey,
+		nodeKey:           nodeKey,
+		privVal:           privVal,
+		dialRetryInterval: defaultDialRetryInterval,
+	}
+	rs.BaseService = *cmn.NewBaseService(logger, "RemoteSigner", rs)
+	return rs
+}
+
+// OnStart implements cmn.Service.
+func (rs *RemoteSigner) OnStart() error {
+	go rs.serveRoutine()
+	return nil
+}
+
+// OnStop implements cmn.Service.
+func (rs *RemoteSigner) OnStop() {
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	if rs.conn != nil {
+		rs.conn.Close()
+		rs.conn = nil
+	}
+}
+
+// serveRoutine connects to the node, serves its requests, and connects again
+// when the connection is lost, until the signer is stopped.
+func (rs *RemoteSigner) serveRoutine() {
+	for {
+		conn, err := rs.connect()
+		if err != nil {
+			rs.Logger.Debug("Failed to connect to the node", "addr", rs.addr, "err", err)
+			select {
+			case <-rs.Quit():
+				return
+			case <-time.After(rs.dialRetryInterval):
+			}
+			continue
+		}
+		rs.Logger.Info("Connected to the node", "addr", rs.addr)
+
+		err = rs.serve(conn)
+		select {
+		case <-rs.Quit():
+			return
+		default:
+		}
+		rs.Logger.Error("Node connection lost", "addr", rs.addr, "err", err)
+	}
+}
+
+func (rs *RemoteSigner) connect() (net.Conn, error) {
+	conn, err := cmn.Connect(rs.addr)
+	if err != nil {
+		return nil, err
+	}
+	if conn, err = secureConn(conn, rs.connKey, rs.nodeKey); err != nil {
+		return nil, err
+	}
+
+	rs.mtx.Lock()
+	defer rs.mtx.Unlock()
+	if !rs.IsRunning() {
+		conn.Close()
+		return nil, errors.New("remote signer stopped")
+	}
+	rs.conn = conn
+	return conn, nil
+}
+
+func (rs *RemoteSigner) serve(conn net.Conn) error {
+	defer conn.Close()
+	for {
+		req, err := readMsg(conn, 0)
+		if err != nil {
+			return err
+		}
+		res, err := rs.handleRequest(req)
+		if err != nil {
+			return err
+		}
+		if err := writeMsg(conn, res, 0); err != nil {
+			return err
+		}
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func (rs *RemoteSigner) checkChainID(chainID string) error {
+	if chainID != rs.chainID {
+		return fmt.Errorf("remote signer: chain %s, expected %s", chainID, rs.chainID)
+	}
+	return nil
+}
+
+// handleRequest signs a request, the refusals are returned in the response.
+func (rs *RemoteSigner) handleRequest(req SocketPVMsg) (SocketPVMsg, error) {
+	switch r := req.(type) {
+	case *PubKeyRequest:
+		return &PubKeyResponse{PubKey: rs.privVal.GetPubKey()}, nil
+
+	case *SignVoteRequest:
+		if r.Vote == nil {
+			return nil, errors.New("missing vote")
+		}
+		err := rs.checkChainID(r.ChainID)
+		if err == nil {
+			err = rs.privVal.SignVote(r.ChainID, r.Vote)
+		}
+		if err != nil {
+			rs.Logger.Error("Refused to sign vote", "height", r.Vote.Height, "round", r.Vote.Round, "err", err)
+			return &SignedVoteResponse{Error: err.Error()}, nil
+		}
+		return &SignedVoteResponse{Vote: r.Vote}, nil
+
+	case *SignProposalRequest:
+		if r.Proposal == nil {
+			return nil, errors.New("missing proposal")
+		}
+		err := rs.checkChainID(r.ChainID)
+		if err == nil {
+			err = rs.privVal.SignProposal(r.ChainID, r.Proposal)
+		}
+		if err != nil {
+			rs.Logger.Error("Refused to sign proposal", "height", r.Proposal.Height, "round", r.Proposal.Round, "err", err)
+			return &SignedProposalResponse{Error: err.Error()}, nil
+		}
+		return &SignedProposalResponse{Proposal: r.Proposal}, nil
+
+	case *SignHeartbeatRequest:
+		if r.Heartbeat == nil {
+			return nil, errors.New("missing heartbeat")
+		}
+		err := rs.checkChainID(r.ChainID)
+		if err == nil {
+			err = rs.privVal.SignHeartbeat(r.ChainID, r.Heartbeat)
+		}
+		if err != nil {
+			return &SignedHeartbeatResponse{Error: err.Error()}, nil
+		}
+		return &SignedHeartbeatResponse{Heartbeat: r.Heartbeat}, nil
+
+	case *SignDataRequest:
+		if err := checkSignData(r.Data); err != nil {
+			rs.Logger.Error("Refused to sign data", "err", err)
+			return &SignedDataResponse{Error: err.Error()}, nil
+		}
+		sig, err := rs.privVal.SignData(r.Data)
+		return &SignedDataResponse{Signature: sig, Error: errString(err)}, nil
+
+	case *PingRequest:
+		return &PingResponse{}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown remote signer request %T", req)
+	}
+}
+
+// checkSignData returns an error unless data are the sign bytes of a multisign
+// tx, the only data the validator key signs besides the consensus messages. The
+// sign bytes of votes, proposals and heartbeats are refused, they are signed by
+// their requests only, which check for double signing.
+func checkSignData(data []byte) error {
+	var info types.MultiSignMainInfo
+	if err := ser.DecodeBytes(data, &info); err != nil {
+		return fmt.Errorf("data are not a multisign tx: %v", err)
+	}
+	bz, err := types.GenMultiSignBytes(info)
+	if err != nil || !bytes.Equal(bz, data) {
+		return errors.New("data are not the sign bytes of a multisign tx")
+	}
+	return nil
+}
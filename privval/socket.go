@@ -0,0 +1,286 @@
+package privval
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+const (
+	defaultAcceptDeadline = 60 * time.Second
+	defaultConnTimeout    = 3 * time.Second
+)
+
+// SocketPVOption sets an optional parameter on the SocketPV.
+type SocketPVOption func(*SocketPV)
+
+// SocketPVAcceptDeadline sets how long the SocketPV waits for the remote
+// signer to connect when started.
+func SocketPVAcceptDeadline(deadline time.Duration) SocketPVOption {
+	return func(pv *SocketPV) { pv.acceptDeadline = deadline }
+}
+
+// SocketPVConnTimeout sets the timeout of a request to the remote signer, and
+// how long the SocketPV waits for the signer to reconnect.
+func SocketPVConnTimeout(timeout time.Duration) SocketPVOption {
+	return func(pv *SocketPV) { pv.connTimeout = timeout }
+}
+
+// SocketPV implements PrivValidator with a remote signer connecting to the
+// address it listens on. The validator key stays in the signer, which also
+// prevents double signing, the SocketPV only forwards what is to be signed.
+type SocketPV struct {
+	cmn.BaseService
+
+	addr           string
+	nodeKey        crypto.PrivKey
+	signerKey      crypto.PubKey
+	pubKey         crypto.PubKey
+	acceptDeadline time.Duration
+	connTimeout    time.Duration
+
+	listener net.Listener
+
+	mtx  sync.Mutex
+	conn net.Conn
+}
+
+var _ types.PrivValidator = (*SocketPV)(nil)
+
+// NewSocketPV returns a SocketPV listening on a "tcp://" or "unix://" address
+// for the signer holding the validator key pubKey. The TCP connections are
+// authenticated with the node key, and accepted from the signer connecting
+// with signerKey only.
+func NewSocketPV(logger log.Logger, addr string, nodeKey crypto.PrivKey, signerKey, pubKey crypto.PubKey, options ...SocketPVOption) *SocketPV {
+	pv := &SocketPV{
+		addr:           addr,
+		nodeKey:        nodeKey,
+		signerKey:      signerKey,
+		pubKey:         pubKey,
+		acceptDeadline: defaultAcceptDeadline,
+		connTimeout:    defaultConnTimeout,
+	}
+	pv.BaseService = *cmn.NewBaseService(logger, "SocketPV", pv)
+	for _, option := range options {
+		option(pv)
+	}
+	return pv
+}
+
+// OnStart implements cmn.Service, it waits for the remote signer to connect.
+func (pv *SocketPV) OnStart() error {
+	ln, err := listen(pv.addr)
+	if err != nil {
+		return err
+	}
+	pv.listener = ln
+
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	if err := pv.connect(pv.acceptDeadline); err != nil {
+		ln.Close()
+		return err
+	}
+	return nil
+}
+
+// OnStop implements cmn.Service.
+func (pv *SocketPV) OnStop() {
+	pv.listener.Close()
+
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	if pv.conn != nil {
+		pv.conn.Close()
+		pv.conn = nil
+	}
+}
+
+// connect accepts a connection of the remote signer and checks that it holds
+// the validator key. Connections failing the checks are dropped until
+// the deadline, such as a stale one of a signer being stopped. It must be
+// called with mtx held.
+func (pv *SocketPV) connect(deadline time.Duration) error {
+	if ln, ok := pv.listener.(interface{ SetDeadline(time.Time) error }); ok {
+		ln.SetDeadline(time.Now().Add(deadline))
+	}
+	var lastErr error
+	for {
+		conn, err := pv.listener.Accept()
+		if err != nil {
+			if lastErr != nil {
+				return lastErr
+			}
+			return fmt.Errorf("no remote signer connected on %s: %v", pv.addr, err)
+		}
+		if lastErr = pv.handshake(conn); lastErr == nil {
+			return nil
+		}
+		pv.Logger.Error("Remote signer rejected", "remote", conn.RemoteAddr(), "err", lastErr)
+	}
+}
+
+// handshake authenticates the connection and requests the key of the signer.
+func (pv *SocketPV) handshake(conn net.Conn) (err error) {
+	if conn, err = secureConn(conn, pv.nodeKey, pv.signerKey); err != nil {
+		return fmt.Errorf("remote signer handshake failed: %v", err)
+	}
+
+	res, err := pv.roundTrip(conn, &PubKeyRequest{})
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("remote signer public key request failed: %v", err)
+	}
+	pubKeyRes, ok := res.(*PubKeyResponse)
+	if !ok || pubKeyRes.PubKey == nil {
+		conn.Close()
+		return fmt.Errorf("unexpected remote signer response %T", res)
+	}
+	if !pv.pubKey.Equals(pubKeyRes.PubKey) {
+		conn.Close()
+		return fmt.Errorf("remote signer holds %v, expected %v", pubKeyRes.PubKey.Address(), pv.pubKey.Address())
+	}
+
+	pv.conn = conn
+	pv.Logger.Info("Remote signer connected", "remote", conn.RemoteAddr(), "addr", pv.pubKey.Address())
+	return nil
+}
+
+func (pv *SocketPV) roundTrip(conn net.Conn, req SocketPVMsg) (SocketPVMsg, error) {
+	if err := writeMsg(conn, req, pv.connTimeout); err != nil {
+		return nil, err
+	}
+	return readMsg(conn, pv.connTimeout)
+}
+
+// request sends a request to the remote signer, waiting for it to reconnect
+// if the connection was lost.
+func (pv *SocketPV) request(req SocketPVMsg) (SocketPVMsg, error) {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+
+	if !pv.IsRunning() {
+		return nil, errors.New("remote signer not started")
+	}
+	if pv.conn == nil {
+		if err := pv.connect(pv.connTimeout); err != nil {
+			return nil, err
+		}
+	}
+	res, err := pv.roundTrip(pv.conn, req)
+	if err != nil {
+		pv.Logger.Error("Remote signer connection lost", "err", err)
+		pv.conn.Close()
+		pv.conn = nil
+		return nil, fmt.Errorf("remote signer request failed: %v", err)
+	}
+	return res, nil
+}
+
+// GetAddress returns the address of the validator.
+// Implements PrivValidator.
+func (pv *SocketPV) GetAddress() crypto.Address {
+	return pv.GetPubKey().Address()
+}
+
+// GetPubKey returns the public key of the validator.
+// Implements PrivValidator.
+func (pv *SocketPV) GetPubKey() crypto.PubKey {
+	return pv.pubKey
+}
+
+// UpdatePrikey does nothing, the validator key is held by the remote signer.
+// Implements PrivValidator.
+func (pv *SocketPV) UpdatePrikey(priv crypto.PrivKey) {
+}
+
+// GetPrikey returns nil, the validator key never leaves the remote signer.
+// Implements PrivValidator.
+func (pv *SocketPV) GetPrikey() crypto.PrivKey {
+	return nil
+}
+
+// SignData signs a piece of data. Implements PrivValidator.
+func (pv *SocketPV) SignData(data []byte) ([]byte, error) {
+	res, err := pv.request(&SignDataRequest{Data: data})
+	if err != nil {
+		return nil, err
+	}
+	signed, ok := res.(*SignedDataResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected remote signer response %T", res)
+	}
+	if signed.Error != "" {
+		return nil, errors.New(signed.Error)
+	}
+	return signed.Signature, nil
+}
+
+// SignVote signs a canonical representation of the vote, along with the
+// chainID. Implements PrivValidator.
+func (pv *SocketPV) SignVote(chainID string, vote *types.Vote) error {
+	res, err := pv.request(&SignVoteRequest{ChainID: chainID, Vote: vote})
+	if err != nil {
+		return err
+	}
+	signed, ok := res.(*SignedVoteResponse)
+	if !ok || (signed.Error == "" && signed.Vote == nil) {
+		return fmt.Errorf("unexpected remote signer response %T", res)
+	}
+	if signed.Error != "" {
+		return errors.New(signed.Error)
+	}
+	vote.Timestamp = signed.Vote.Timestamp
+	vote.Signature = signed.Vote.Signature
+	return nil
+}
+
+// SignVoteWithoutSave is SignVote, the remote signer always records the votes
+// it signs. Implements PrivValidator.
+func (pv *SocketPV) SignVoteWithoutSave(chainID string, vote *types.Vote) error {
+	return pv.SignVote(chainID, vote)
+}
+
+// SignProposal signs a canonical representation of the proposal, along with
+// the chainID. Implements PrivValidator.
+func (pv *SocketPV) SignProposal(chainID string, proposal *types.Proposal) error {
+	res, err := pv.request(&SignProposalRequest{ChainID: chainID, Proposal: proposal})
+	if err != nil {
+		return err
+	}
+	signed, ok := res.(*SignedProposalResponse)
+	if !ok || (signed.Error == "" && signed.Proposal == nil) {
+		return fmt.Errorf("unexpected remote signer response %T", res)
+	}
+	if signed.Error != "" {
+		return errors.New(signed.Error)
+	}
+	proposal.Timestamp = signed.Proposal.Timestamp
+	proposal.Signature = signed.Proposal.Signature
+	return nil
+}
+
+// SignHeartbeat signs a canonical representation of the heartbeat, along with
+// the chainID. Implements PrivValidator.
+func (pv *SocketPV) SignHeartbeat(chainID string, heartbeat *types.Heartbeat) error {
+	res, err := pv.request(&SignHeartbeatRequest{ChainID: chainID, Heartbeat: heartbeat})
+	if err != nil {
+		return err
+	}
+	signed, ok := res.(*SignedHeartbeatResponse)
+	if !ok || (signed.Error == "" && signed.Heartbeat == nil) {
+		return fmt.Errorf("unexpected remote signer response %T", res)
+	}
+	if signed.Error != "" {
+		return errors.New(signed.Error)
+	}
+	heartbeat.Signature = signed.Heartbeat.Signature
+	return nil
+}
@@ -0,0 +1,190 @@
+package privval
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChainID = "privval"
+
+func freeTCPAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer ln.Close()
+	return fmt.Sprintf("tcp://%s", ln.Addr().String())
+}
+
+// testKeys are the connection keys of a node and its signer.
+type testKeys struct {
+	node, signer crypto.PrivKey
+}
+
+func newTestKeys() testKeys {
+	return testKeys{node: crypto.GenPrivKeyEd25519(), signer: crypto.GenPrivKeyEd25519()}
+}
+
+// startSocketPair starts a SocketPV and a RemoteSigner signing with a FilePV.
+func startSocketPair(t *testing.T, addr string) (*SocketPV, *RemoteSigner, *types.FilePV, testKeys) {
+	_, pvFile := cmn.Tempfile("priv_validator_")
+	filePV := types.GenFilePV(pvFile)
+	filePV.Save()
+
+	keys := newTestKeys()
+	pvsc := newTestSocketPV(addr, keys, filePV.GetPubKey())
+	started := make(chan error, 1)
+	go func() { started <- pvsc.Start() }()
+
+	rs := startRemoteSigner(t, addr, filePV, keys)
+	require.Nil(t, <-started)
+	return pvsc, rs, filePV, keys
+}
+
+func newTestSocketPV(addr string, keys testKeys, pubKey crypto.PubKey) *SocketPV {
+	return NewSocketPV(log.NewNopLogger(), addr, keys.node, keys.signer.PubKey(), pubKey,
+		SocketPVAcceptDeadline(5*time.Second), SocketPVConnTimeout(time.Second))
+}
+
+func startRemoteSigner(t *testing.T, addr string, privVal types.PrivValidator, keys testKeys) *RemoteSigner {
+	rs := NewRemoteSigner(log.NewNopLogger(), testChainID, addr, privVal, keys.signer, keys.node.PubKey())
+	rs.dialRetryInterval = 10 * time.Millisecond
+	require.Nil(t, rs.Start())
+	return rs
+}
+
+func newTestVote(addr crypto.Address, height uint64, round int, hash byte) *types.Vote {
+	return &types.Vote{
+		ValidatorAddress: addr,
+		Height:           height,
+		Round:            round,
+		Timestamp:        time.Now().UTC(),
+		Type:             types.VoteTypePrecommit,
+		BlockID:          types.BlockID{Hash: cmn.BytesToHash([]byte{hash})},
+	}
+}
+
+func testSigning(t *testing.T, addr string) {
+	pvsc, rs, filePV, _ := startSocketPair(t, addr)
+	defer pvsc.Stop()
+	defer rs.Stop()
+
+	assert.Equal(t, filePV.GetAddress(), pvsc.GetAddress())
+	assert.True(t, filePV.GetPubKey().Equals(pvsc.GetPubKey()))
+
+	vote := newTestVote(pvsc.GetAddress(), 1, 0, 1)
+	require.Nil(t, pvsc.SignVote(testChainID, vote))
+	assert.True(t, pvsc.GetPubKey().VerifyBytes(vote.SignBytes(testChainID), vote.Signature))
+
+	proposal := &types.Proposal{Height: 2, Round: 0, Timestamp: time.Now().UTC(), POLRound: -1}
+	require.Nil(t, pvsc.SignProposal(testChainID, proposal))
+	assert.True(t, pvsc.GetPubKey().VerifyBytes(proposal.SignBytes(testChainID), proposal.Signature))
+
+	// double signing and regressions are refused by the signer
+	require.Nil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 3, 0, 1)))
+	assert.NotNil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 3, 0, 2)))
+	assert.NotNil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 1, 0, 1)))
+
+	// other chains
+	assert.NotNil(t, pvsc.SignVote("other", newTestVote(pvsc.GetAddress(), 4, 0, 1)))
+
+	// only the sign bytes of multisign txs are signed as data
+	data, err := types.GenMultiSignBytes(types.MultiSignMainInfo{AccountNonce: 1, SupportTxType: types.TxUpdateValidatorsType})
+	require.Nil(t, err)
+	sig, err := pvsc.SignData(data)
+	require.Nil(t, err)
+	signature, err := crypto.SignatureFromBytes(sig)
+	require.Nil(t, err)
+	assert.True(t, pvsc.GetPubKey().VerifyBytes(data, signature))
+
+	// a conflicting vote can not be signed as data
+	conflicting := newTestVote(pvsc.GetAddress(), 3, 0, 3)
+	for _, data := range [][]byte{
+		conflicting.SignBytes(testChainID),
+		proposal.SignBytes(testChainID),
+		(&types.Heartbeat{Height: 5}).SignBytes(testChainID),
+		{0xc1, 0x01},
+		append(data, 0x00),
+	} {
+		_, err := pvsc.SignData(data)
+		assert.NotNil(t, err, "signed %x", data)
+	}
+}
+
+func TestSocketPVUnix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "privval")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	testSigning(t, "unix://"+filepath.Join(dir, "signer.sock"))
+}
+
+func TestSocketPVTCP(t *testing.T) {
+	testSigning(t, freeTCPAddr(t))
+}
+
+func TestSocketPVReconnect(t *testing.T) {
+	addr := freeTCPAddr(t)
+	pvsc, rs, filePV, keys := startSocketPair(t, addr)
+	defer pvsc.Stop()
+
+	require.Nil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 1, 0, 1)))
+	rs.Stop()
+
+	// the request on the lost connection fails, the next one waits for the signer
+	assert.NotNil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 2, 0, 1)))
+	rs = startRemoteSigner(t, addr, filePV, keys)
+	defer rs.Stop()
+	require.Nil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 2, 0, 1)))
+
+	// a signer with another key is rejected
+	rs.Stop()
+	assert.NotNil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 3, 0, 1)))
+	other := startRemoteSigner(t, addr, types.NewMockPV(), keys)
+	defer other.Stop()
+	assert.NotNil(t, pvsc.SignVote(testChainID, newTestVote(pvsc.GetAddress(), 3, 0, 1)))
+}
+
+func TestSocketPVUnknownPeers(t *testing.T) {
+	addr := freeTCPAddr(t)
+	_, pvFile := cmn.Tempfile("priv_validator_")
+	filePV := types.GenFilePV(pvFile)
+	filePV.Save()
+	keys := newTestKeys()
+
+	// a signer authenticating with another key
+	pvsc := newTestSocketPV(addr, keys, filePV.GetPubKey())
+	pvsc.acceptDeadline = time.Second
+	unknownSigner := testKeys{node: keys.node, signer: crypto.GenPrivKeyEd25519()}
+	started := make(chan error, 1)
+	go func() { started <- pvsc.Start() }()
+	rs := startRemoteSigner(t, addr, filePV, unknownSigner)
+	assert.NotNil(t, <-started)
+	rs.Stop()
+
+	// a node authenticating with another key
+	unknownNode := testKeys{node: crypto.GenPrivKeyEd25519(), signer: keys.signer}
+	pvsc = newTestSocketPV(addr, unknownNode, filePV.GetPubKey())
+	pvsc.acceptDeadline = time.Second
+	go func() { started <- pvsc.Start() }()
+	rs = startRemoteSigner(t, addr, filePV, keys)
+	assert.NotNil(t, <-started)
+	rs.Stop()
+
+	// no pinned key of the signer
+	pvsc = NewSocketPV(log.NewNopLogger(), addr, keys.node, nil, filePV.GetPubKey(), SocketPVAcceptDeadline(time.Second))
+	go func() { started <- pvsc.Start() }()
+	rs = startRemoteSigner(t, addr, filePV, keys)
+	assert.NotNil(t, <-started)
+	rs.Stop()
+}
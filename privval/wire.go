@@ -0,0 +1,10 @@
+package privval
+
+import (
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+func init() {
+	RegisterSocketPVMsgs()
+	types.RegisterBlockAmino()
+}
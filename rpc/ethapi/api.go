@@ -32,6 +32,7 @@ import (
 	"github.com/lianxiangcloud/linkchain/libs/math"
 	"github.com/lianxiangcloud/linkchain/libs/rpc"
 	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/mempool"
 	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
 	"github.com/lianxiangcloud/linkchain/types"
 )
@@ -362,6 +363,122 @@ func (s *PublicMempoolAPI) Status() map[string]hexutil.Uint {
 	}
 }
 
+// Content returns the transactions contained within the mempool. The pending
+// and queued ones are grouped by sender and nonce, utxo transactions without
+// sender by hash. For every account with queued transactions, waiting tells
+// the nonce they wait for.
+func (s *PublicMempoolAPI) Content() *rpcPoolContent {
+	return formatPoolContent(s.b.TxPoolContent(), formatPoolTx)
+}
+
+// ContentFrom returns the transactions contained within the mempool sent by
+// addr, along with the nonce its queued transactions wait for.
+func (s *PublicMempoolAPI) ContentFrom(addr common.Address) map[string]interface{} {
+	content := formatPoolContent(s.b.TxPoolContentFrom(addr), formatPoolTx)
+	from := addr.Hex()
+	result := map[string]interface{}{
+		"pending": map[string]interface{}{},
+		"queued":  map[string]interface{}{},
+	}
+	if txs, ok := content.Pending[from]; ok {
+		result["pending"] = txs
+	}
+	if txs, ok := content.Queued[from]; ok {
+		result["queued"] = txs
+		result["waiting"] = content.Waiting[from]
+	}
+	return result
+}
+
+// Inspect retrieves the content of the mempool and flattens it into an easily
+// inspectable list, including how long the pending transactions have been
+// executable.
+func (s *PublicMempoolAPI) Inspect() *rpcPoolContent {
+	return formatPoolContent(s.b.TxPoolContent(), inspectPoolTx)
+}
+
+// rpcPoolContent is the mempool content, by sender and nonce or by hash.
+type rpcPoolContent struct {
+	Pending map[string]map[string]interface{} `json:"pending"`
+	Queued  map[string]map[string]interface{} `json:"queued"`
+	UTXO    map[string]interface{}            `json:"utxo"`
+	Waiting map[string]*rpcQueuedAccount      `json:"waiting"`
+}
+
+// rpcQueuedAccount tells why the queued transactions of an account can't be
+// executed yet.
+type rpcQueuedAccount struct {
+	Nonce    hexutil.Uint64 `json:"nonce"`    // Next nonce of the account
+	Gap      hexutil.Uint64 `json:"gap"`      // Number of nonces missing before the lowest queued one
+	LastSeen time.Time      `json:"lastSeen"` // Last transaction queued, the queue lifetime counts from here
+}
+
+func formatPoolContent(content *mempool.Content, format func(tx types.Tx, since time.Time) interface{}) *rpcPoolContent {
+	pending := make(map[string]map[string]interface{}, len(content.Pending))
+	for from, txs := range content.Pending {
+		dump := make(map[string]interface{}, len(txs))
+		for _, ptx := range txs {
+			dump[fmt.Sprintf("%d", poolTxNonce(ptx.Tx))] = format(ptx.Tx, ptx.Since)
+		}
+		pending[from.Hex()] = dump
+	}
+	utxo := make(map[string]interface{}, len(content.UTXO))
+	for _, ptx := range content.UTXO {
+		utxo[ptx.Tx.Hash().Hex()] = format(ptx.Tx, ptx.Since)
+	}
+	queued := make(map[string]map[string]interface{}, len(content.Queued))
+	waiting := make(map[string]*rpcQueuedAccount, len(content.Queued))
+	for from, account := range content.Queued {
+		dump := make(map[string]interface{}, len(account.Txs))
+		for _, tx := range account.Txs {
+			dump[fmt.Sprintf("%d", poolTxNonce(tx))] = format(tx, time.Time{})
+		}
+		queued[from.Hex()] = dump
+		waiting[from.Hex()] = &rpcQueuedAccount{
+			Nonce:    hexutil.Uint64(account.Nonce),
+			Gap:      hexutil.Uint64(account.Gap()),
+			LastSeen: account.LastSeen,
+		}
+	}
+	return &rpcPoolContent{
+		Pending: pending,
+		Queued:  queued,
+		UTXO:    utxo,
+		Waiting: waiting,
+	}
+}
+
+func formatPoolTx(tx types.Tx, since time.Time) interface{} {
+	return rtypes.NewRPCTx(tx, nil)
+}
+
+func inspectPoolTx(tx types.Tx, since time.Time) interface{} {
+	var summary string
+	switch tx := tx.(type) {
+	case *types.UTXOTransaction:
+		summary = fmt.Sprintf("utxo: %v wei fee", tx.Fee)
+	case types.RegularTx:
+		to := "contract creation"
+		if tx.To() != nil {
+			to = tx.To().Hex()
+		}
+		summary = fmt.Sprintf("%s: %v wei + %v gas × %v wei", to, tx.Value(), tx.Gas(), tx.GasPrice())
+	default:
+		summary = tx.TypeName()
+	}
+	if !since.IsZero() {
+		summary += fmt.Sprintf(", pending for %v", time.Since(since).Round(time.Second))
+	}
+	return summary
+}
+
+func poolTxNonce(tx types.Tx) uint64 {
+	if n, ok := tx.(interface{ Nonce() uint64 }); ok {
+		return n.Nonce()
+	}
+	return 0
+}
+
 type PublicNetAPI struct {
 	b              Backend
 	networkVersion uint64
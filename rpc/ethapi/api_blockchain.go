@@ -74,6 +74,24 @@ func (s *PublicBlockChainAPI) GenesisBlockNumber(ctx context.Context) hexutil.Ui
 	return hexutil.Uint64(types.BlockHeightZero)
 }
 
+// GetForkSchedule returns the protocol upgrades scheduled in the genesis.
+func (s *PublicBlockChainAPI) GetForkSchedule(ctx context.Context) ([]*rtypes.RPCFork, error) {
+	header, err := s.b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	forks := s.b.ForkSchedule()
+	result := make([]*rtypes.RPCFork, 0, len(forks))
+	for _, fork := range forks {
+		result = append(result, &rtypes.RPCFork{
+			Name:   fork.Name,
+			Height: hexutil.Uint64(fork.Height),
+			Active: header.Height >= fork.Height,
+		})
+	}
+	return result, nil
+}
+
 // Get current black list
 func (s *PublicBlockChainAPI) Blacklist() []common.Address {
 	return types.BlacklistInstance.GetBlackAddrs()
@@ -194,6 +212,76 @@ func (s *PublicBlockChainAPI) GetStorageRoot(ctx context.Context, address common
 	return res.String(), state.Error()
 }
 
+// GetProof returns the Merkle proofs of an account and of some of its storage
+// slots against the state trie root after the given block. The state trie root
+// is returned with the proofs, from the stateroot fork on it is the StateHash
+// of the next block header. The rpc.LatestBlockNumber meta block number is also
+// allowed.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNr rpc.BlockNumber) (*rtypes.AccountResult, error) {
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, fmt.Errorf("no proofs of the pending state")
+	}
+	if blockNr == rpc.LatestBlockNumber {
+		header, err := s.b.HeaderByNumber(ctx, blockNr)
+		if err != nil {
+			return nil, err
+		}
+		blockNr = rpc.BlockNumber(header.Height)
+	}
+	txsResult, err := s.b.GetTxsResult(ctx, uint64(blockNr))
+	if err != nil {
+		return nil, err
+	}
+	if txsResult.TrieRoot == common.EmptyHash {
+		return nil, fmt.Errorf("no state trie to prove against, the node is not a full node")
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	accountProof, err := state.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+	exist := state.Exist(address)
+	storageProof := make([]rtypes.StorageResult, len(storageKeys))
+	for i, key := range storageKeys {
+		slot := common.HexToHash(key)
+		storageProof[i].Key = slot
+		// the account proof proves the absence of the slots of a missing account
+		if !exist {
+			continue
+		}
+		proof, err := state.GetStorageProof(address, slot)
+		if err != nil {
+			return nil, err
+		}
+		storageProof[i].Value = state.GetState(address, slot)
+		storageProof[i].Proof = toHexSlice(proof)
+	}
+
+	return &rtypes.AccountResult{
+		Address:      address,
+		AccountProof: toHexSlice(accountProof),
+		Balance:      (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:     state.GetCodeHash(address),
+		Nonce:        hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:  state.GetStorageRoot(address),
+		StorageProof: storageProof,
+		BlockNumber:  hexutil.Uint64(blockNr),
+		StateRoot:    txsResult.TrieRoot,
+	}, state.Error()
+}
+
+func toHexSlice(b [][]byte) []hexutil.Bytes {
+	r := make([]hexutil.Bytes, len(b))
+	for i := range b {
+		r[i] = hexutil.Bytes(b[i])
+	}
+	return r
+}
+
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
 	From         common.Address     `json:"from"`
@@ -405,6 +493,17 @@ func (s *PublicBlockChainAPI) GetMaxOutputIndex(ctx context.Context, token commo
 	return hexutil.Uint64(uint64(s.b.GetMaxOutputIndex(ctx, token)))
 }
 
+// GetMaxOutputIndexes get the max UTXO output index of every token after the block, the tokens
+// without outputs are left out. A wallet restored from a height starts its output indexes with them.
+// Only the blocks from the first one the node kept the indexes of to the latest one are served
+func (s *PublicBlockChainAPI) GetMaxOutputIndexes(ctx context.Context, blockNr rpc.BlockNumber) (map[string]int64, error) {
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, fmt.Errorf("block %d not found", blockNr)
+	}
+	return s.b.GetMaxOutputIndexesAt(ctx, header.Height)
+}
+
 type OutputArg struct {
 	Token common.Address `json:"token"`
 	Index hexutil.Uint64 `json:"index"`
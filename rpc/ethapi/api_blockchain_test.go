@@ -85,3 +85,19 @@ func getTestTxs() ([]types.Tx, []types.TxEntry) {
 	}
 	return txs, txsEntry
 }
+
+func TestGetForkSchedule(t *testing.T) {
+	b := &MockBackend{}
+	s := NewPublicBlockChainAPI(b)
+	assert := assert.New(t)
+
+	b.On("ForkSchedule").Return(types.Forks{{Name: types.ForkIstanbul, Height: 10}, {Name: types.ForkBerlin, Height: 20}}).Once()
+	b.On("HeaderByNumber", mock.Anything, rpc.LatestBlockNumber).Return(&types.Header{Height: 15}, nil).Once()
+	forks, err := s.GetForkSchedule(nil)
+	assert.Nil(err, "error")
+	assert.Equal(2, len(forks), "not equal")
+	assert.Equal(types.ForkIstanbul, forks[0].Name, "not equal")
+	assert.True(forks[0].Active, "istanbul is not active")
+	assert.Equal(types.ForkBerlin, forks[1].Name, "not equal")
+	assert.False(forks[1].Active, "berlin is active")
+}
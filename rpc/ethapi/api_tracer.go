@@ -0,0 +1,276 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/math"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/vm"
+	"github.com/lianxiangcloud/linkchain/vm/evm"
+	"github.com/lianxiangcloud/linkchain/vm/wasm"
+)
+
+const (
+	// callTracer selects vm.CallTracer instead of the default struct logger.
+	callTracer = "callTracer"
+
+	// defaultTraceTimeout bounds the execution of debug_traceCall.
+	defaultTraceTimeout = 5 * time.Second
+)
+
+// TraceConfig holds extra parameters to trace functions.
+type TraceConfig struct {
+	*evm.LogConfig
+	// Tracer is empty for the struct logger, or "callTracer".
+	Tracer *string
+}
+
+// StructLogRes stores a structured log emitted by the EVM while replaying a
+// transaction in debug mode
+type StructLogRes struct {
+	Pc      uint64             `json:"pc"`
+	Op      string             `json:"op"`
+	Gas     uint64             `json:"gas"`
+	GasCost uint64             `json:"gasCost"`
+	Depth   int                `json:"depth"`
+	Error   string             `json:"error,omitempty"`
+	Stack   *[]string          `json:"stack,omitempty"`
+	Memory  *[]string          `json:"memory,omitempty"`
+	Storage *map[string]string `json:"storage,omitempty"`
+}
+
+// HostLogRes stores a host function call made by a WASM contract while
+// replaying a transaction in debug mode
+type HostLogRes struct {
+	Contract common.Address `json:"contract"`
+	Name     string         `json:"name"`
+	Args     []uint64       `json:"args"`
+	Ret      uint64         `json:"ret"`
+	Gas      uint64         `json:"gas"`
+	GasCost  uint64         `json:"gasCost"`
+	Depth    int            `json:"depth"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// ExecutionResult groups all structured logs emitted by the vms
+// while replaying a transaction in debug mode as well as transaction
+// execution status, the amount of gas used and the return value
+type ExecutionResult struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	ReturnValue string         `json:"returnValue"`
+	StructLogs  []StructLogRes `json:"structLogs"`
+	HostLogs    []HostLogRes   `json:"hostLogs,omitempty"`
+}
+
+// TxTraceResult is the result of a single transaction trace of a block.
+type TxTraceResult struct {
+	TxHash common.Hash `json:"txHash"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// FormatLogs formats EVM returned structured logs for json output
+func FormatLogs(logs []evm.StructLog) []StructLogRes {
+	formatted := make([]StructLogRes, len(logs))
+	for index, trace := range logs {
+		formatted[index] = StructLogRes{
+			Pc:      trace.Pc,
+			Op:      trace.Op.String(),
+			Gas:     trace.Gas,
+			GasCost: trace.GasCost,
+			Depth:   trace.Depth,
+			Error:   trace.ErrorString(),
+		}
+		if trace.Stack != nil {
+			stack := make([]string, len(trace.Stack))
+			for i, stackValue := range trace.Stack {
+				stack[i] = fmt.Sprintf("%x", math.PaddedBigBytes(stackValue, 32))
+			}
+			formatted[index].Stack = &stack
+		}
+		if trace.Memory != nil {
+			memory := make([]string, 0, (len(trace.Memory)+31)/32)
+			for i := 0; i+32 <= len(trace.Memory); i += 32 {
+				memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
+			}
+			formatted[index].Memory = &memory
+		}
+		if trace.Storage != nil {
+			storage := make(map[string]string)
+			for i, storageValue := range trace.Storage {
+				storage[fmt.Sprintf("%x", i)] = fmt.Sprintf("%x", storageValue)
+			}
+			formatted[index].Storage = &storage
+		}
+	}
+	return formatted
+}
+
+// FormatHostLogs formats WASM host call logs for json output
+func FormatHostLogs(logs []wasm.HostLog) []HostLogRes {
+	formatted := make([]HostLogRes, len(logs))
+	for index, trace := range logs {
+		formatted[index] = HostLogRes{
+			Contract: trace.Contract,
+			Name:     trace.Name,
+			Args:     trace.Args,
+			Ret:      trace.Ret,
+			Gas:      trace.Gas,
+			GasCost:  trace.GasCost,
+			Depth:    trace.Depth,
+			Error:    trace.ErrorString(),
+		}
+	}
+	return formatted
+}
+
+// PrivateDebugAPI is the collection of debug APIs re-executing transactions,
+// which are too expensive to be exposed publicly.
+type PrivateDebugAPI struct {
+	b Backend
+}
+
+// NewPrivateDebugAPI creates a new API definition for the private debug methods.
+func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
+	return &PrivateDebugAPI{b: b}
+}
+
+// TraceTransaction returns the trace of the transaction with the given hash,
+// re-executing the block it belongs to up to it.
+//
+// The default tracer returns the structured logs of EVM opcodes and WASM host
+// function calls. The callTracer returns the call tree, as a single frame for a
+// single top level call and as a list when a UTXO transaction calls several
+// contracts.
+func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	tx, entry := api.b.GetTx(hash)
+	if tx == nil || entry == nil {
+		return nil, fmt.Errorf("transaction %x not found", hash)
+	}
+	block, err := api.b.BlockByNumber(ctx, rpc.BlockNumber(entry.BlockHeight))
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block #%d not found", entry.BlockHeight)
+	}
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	index := int(entry.Index)
+	results, err := api.b.TraceBlock(ctx, block, index, func(idx int, tx types.Tx) evm.Config {
+		if idx != index {
+			return evm.Config{}
+		}
+		return evm.Config{Debug: true, Tracer: tracer}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) <= index {
+		return nil, fmt.Errorf("transaction index %d out of range", index)
+	}
+	res := results[index]
+	return formatTrace(tracer, res.Gas, res.ReturnValue, res.VmErr), nil
+}
+
+// TraceBlockByNumber returns the traces of all the transactions of the block,
+// see TraceTransaction for the output of the tracers.
+func (api *PrivateDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*TxTraceResult, error) {
+	if number == rpc.PendingBlockNumber {
+		return nil, fmt.Errorf("pending block is not traceable")
+	}
+	block, err := api.b.BlockByNumber(ctx, number)
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+
+	tracers := make([]evm.Tracer, len(block.Data.Txs))
+	for i := range tracers {
+		if tracers[i], err = newTracer(config); err != nil {
+			return nil, err
+		}
+	}
+	results, err := api.b.TraceBlock(ctx, block, -1, func(idx int, tx types.Tx) evm.Config {
+		return evm.Config{Debug: true, Tracer: tracers[idx]}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	traces := make([]*TxTraceResult, len(results))
+	for i, res := range results {
+		traces[i] = &TxTraceResult{
+			TxHash: res.Hash,
+			Result: formatTrace(tracers[i], res.Gas, res.ReturnValue, res.VmErr),
+		}
+		if res.VmErr != nil {
+			traces[i].Error = res.VmErr.Error()
+		}
+	}
+	return traces, nil
+}
+
+// TraceCall executes the given call on the state of the given block and
+// returns its trace, see TraceTransaction for the output of the tracers.
+func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, config *TraceConfig) (interface{}, error) {
+	tracer, err := newTracer(config)
+	if err != nil {
+		return nil, err
+	}
+	vmCfg := evm.Config{Debug: true, Tracer: tracer}
+	ret, gas, _, _, failed, err := NewPublicBlockChainAPI(api.b).doCall(ctx, args, blockNr, vmCfg, defaultTraceTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var vmerr error
+	if failed {
+		vmerr = types.ExecutionReverted
+	}
+	return formatTrace(tracer, gas, ret, vmerr), nil
+}
+
+// newTracer returns the tracer selected by config. Both of them follow EVM
+// and WASM contracts.
+func newTracer(config *TraceConfig) (evm.Tracer, error) {
+	if config == nil {
+		return vm.NewStructLogger(nil), nil
+	}
+	if config.Tracer == nil || *config.Tracer == "" {
+		return vm.NewStructLogger(config.LogConfig), nil
+	}
+	switch *config.Tracer {
+	case callTracer:
+		return vm.NewCallTracer(), nil
+	default:
+		return nil, fmt.Errorf("unsupported tracer %q", *config.Tracer)
+	}
+}
+
+func formatTrace(tracer evm.Tracer, gas uint64, ret []byte, vmerr error) interface{} {
+	switch tracer := tracer.(type) {
+	case *vm.CallTracer:
+		frames := tracer.Frames()
+		if len(frames) == 1 {
+			return frames[0]
+		}
+		if frames == nil {
+			frames = []*types.CallFrame{}
+		}
+		return frames
+	case *vm.StructLogger:
+		return &ExecutionResult{
+			Gas:         gas,
+			Failed:      vmerr != nil,
+			ReturnValue: fmt.Sprintf("%x", ret),
+			StructLogs:  FormatLogs(tracer.StructLogs()),
+			HostLogs:    FormatHostLogs(tracer.HostLogs()),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package ethapi
+
+import (
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/app"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTraceTransaction(t *testing.T) {
+	b := &MockBackend{}
+	s := NewPrivateDebugAPI(b)
+	assert := assert.New(t)
+
+	block := getTestBlock()
+	tx := block.Data.Txs[0]
+	_, entrys := getTestTxs()
+
+	b.On("GetTx", mock.Anything).Return(nil, nil).Once()
+	_, err := s.TraceTransaction(nil, common.HexToHash("0x1"), nil)
+	assert.NotNil(err, "unknown tx traced")
+
+	b.On("GetTx", tx.Hash()).Return(tx, &entrys[0]).Once()
+	b.On("BlockByNumber", mock.Anything, mock.Anything).Return(block, nil).Once()
+	b.On("TraceBlock", mock.Anything, block, 0, mock.Anything).Return([]*app.TxTraceResult{{Hash: tx.Hash(), Gas: 21000}}, nil).Once()
+	v, err := s.TraceTransaction(nil, tx.Hash(), nil)
+	assert.Nil(err, "error")
+	res, ok := v.(*ExecutionResult)
+	assert.True(ok, "default tracer is not the struct logger")
+	assert.Equal(uint64(21000), res.Gas, "not equal")
+	assert.False(res.Failed, "failed")
+
+	tracer := callTracer
+	b.On("GetTx", tx.Hash()).Return(tx, &entrys[0]).Once()
+	b.On("BlockByNumber", mock.Anything, mock.Anything).Return(block, nil).Once()
+	b.On("TraceBlock", mock.Anything, block, 0, mock.Anything).Return([]*app.TxTraceResult{{Hash: tx.Hash()}}, nil).Once()
+	_, err = s.TraceTransaction(nil, tx.Hash(), &TraceConfig{Tracer: &tracer})
+	assert.Nil(err, "error")
+}
+
+func TestNewTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer, err := newTracer(nil)
+	assert.Nil(err, "error")
+	assert.IsType(&vm.StructLogger{}, tracer, "not equal")
+
+	name := callTracer
+	tracer, err = newTracer(&TraceConfig{Tracer: &name})
+	assert.Nil(err, "error")
+	assert.IsType(&vm.CallTracer{}, tracer, "not equal")
+
+	name = "jsTracer"
+	_, err = newTracer(&TraceConfig{Tracer: &name})
+	assert.NotNil(err, "unsupported tracer accepted")
+}
@@ -22,8 +22,10 @@ import (
 	"math/big"
 
 	"github.com/lianxiangcloud/linkchain/accounts"
+	"github.com/lianxiangcloud/linkchain/app"
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/mempool"
 	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
 	"github.com/lianxiangcloud/linkchain/state"
 	"github.com/lianxiangcloud/linkchain/types"
@@ -60,11 +62,14 @@ type Backend interface {
 	GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error)
 	GetReceipts(ctx context.Context, blockNr uint64) types.Receipts
 	GetVM(ctx context.Context, msg types.Message, state *state.StateDB, header *types.Header, vmCfg evm.Config) (vm.VmInterface, func() error, error)
+	TraceBlock(ctx context.Context, block *types.Block, end int, vmConfig app.VmConfigFunc) ([]*app.TxTraceResult, error)
 	Block(heightPtr *uint64) (*rtypes.ResultBlock, error)
 	GetMaxOutputIndex(ctx context.Context, token common.Address) int64
 	GetBlockTokenOutputSeq(ctx context.Context, blockHeight uint64) map[string]int64
+	GetMaxOutputIndexesAt(ctx context.Context, blockHeight uint64) (map[string]int64, error)
 	GetOutput(ctx context.Context, token common.Address, index uint64) (*types.UTXOOutputData, error)
 	GetUTXOGas() uint64
+	ForkSchedule() types.Forks
 	GetTxsResult(ctx context.Context, blockNr uint64) (*types.TxsResult, error)
 
 	// TxPool API
@@ -73,6 +78,8 @@ type Backend interface {
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
 	Stats() (int, int, int)
+	TxPoolContent() *mempool.Content
+	TxPoolContentFrom(addr common.Address) *mempool.Content
 
 	// NetAPI
 	NetInfo() (*rtypes.ResultNetInfo, error)
@@ -103,6 +110,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicDebugAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPrivateDebugAPI(apiBackend),
+			Public:    false,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
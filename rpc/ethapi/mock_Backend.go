@@ -7,8 +7,12 @@ import (
 
 	accounts "github.com/lianxiangcloud/linkchain/accounts"
 
+	app "github.com/lianxiangcloud/linkchain/app"
+
 	common "github.com/lianxiangcloud/linkchain/libs/common"
 
+	mempool "github.com/lianxiangcloud/linkchain/mempool"
+
 	context "context"
 
 	evm "github.com/lianxiangcloud/linkchain/vm/evm"
@@ -231,6 +235,29 @@ func (_m *MockBackend) GetBlockTokenOutputSeq(ctx context.Context, blockHeight u
 	return r0
 }
 
+// GetMaxOutputIndexesAt provides a mock function with given fields: ctx, blockHeight
+func (_m *MockBackend) GetMaxOutputIndexesAt(ctx context.Context, blockHeight uint64) (map[string]int64, error) {
+	ret := _m.Called(ctx, blockHeight)
+
+	var r0 map[string]int64
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) map[string]int64); ok {
+		r0 = rf(ctx, blockHeight)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, blockHeight)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMaxOutputIndex provides a mock function with given fields: ctx, token
 func (_m *MockBackend) GetMaxOutputIndex(ctx context.Context, token common.Address) int64 {
 	ret := _m.Called(ctx, token)
@@ -440,6 +467,22 @@ func (_m *MockBackend) GetTxsResult(ctx context.Context, blockNr uint64) (*types
 	return r0, r1
 }
 
+// ForkSchedule provides a mock function with given fields:
+func (_m *MockBackend) ForkSchedule() types.Forks {
+	ret := _m.Called()
+
+	var r0 types.Forks
+	if rf, ok := ret.Get(0).(func() types.Forks); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(types.Forks)
+		}
+	}
+
+	return r0
+}
+
 // GetUTXOGas provides a mock function with given fields:
 func (_m *MockBackend) GetUTXOGas() uint64 {
 	ret := _m.Called()
@@ -708,6 +751,61 @@ func (_m *MockBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	return r0, r1
 }
 
+// TraceBlock provides a mock function with given fields: ctx, block, end, vmConfig
+func (_m *MockBackend) TraceBlock(ctx context.Context, block *types.Block, end int, vmConfig app.VmConfigFunc) ([]*app.TxTraceResult, error) {
+	ret := _m.Called(ctx, block, end, vmConfig)
+
+	var r0 []*app.TxTraceResult
+	if rf, ok := ret.Get(0).(func(context.Context, *types.Block, int, app.VmConfigFunc) []*app.TxTraceResult); ok {
+		r0 = rf(ctx, block, end, vmConfig)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*app.TxTraceResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *types.Block, int, app.VmConfigFunc) error); ok {
+		r1 = rf(ctx, block, end, vmConfig)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TxPoolContent provides a mock function with given fields:
+func (_m *MockBackend) TxPoolContent() *mempool.Content {
+	ret := _m.Called()
+
+	var r0 *mempool.Content
+	if rf, ok := ret.Get(0).(func() *mempool.Content); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mempool.Content)
+		}
+	}
+
+	return r0
+}
+
+// TxPoolContentFrom provides a mock function with given fields: addr
+func (_m *MockBackend) TxPoolContentFrom(addr common.Address) *mempool.Content {
+	ret := _m.Called(addr)
+
+	var r0 *mempool.Content
+	if rf, ok := ret.Get(0).(func(common.Address) *mempool.Content); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mempool.Content)
+		}
+	}
+
+	return r0
+}
+
 // Validators provides a mock function with given fields: heightPtr
 func (_m *MockBackend) Validators(heightPtr *uint64) (*rtypes.ResultValidators, error) {
 	ret := _m.Called(heightPtr)
@@ -0,0 +1,524 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+var (
+	// deadline is the time a polling filter survives without being polled.
+	deadline = 5 * time.Minute
+
+	errFilterNotFound = errors.New("filter not found")
+)
+
+// filter is a helper struct that holds meta information over the filter type
+// and associated subscription in the event system.
+type filter struct {
+	typ      Type
+	deadline *time.Timer // filter is inactive when deadline triggers
+	hashes   []common.Hash
+	crit     FilterCriteria
+	logs     []*types.Log
+	s        *Subscription // associated subscription in event system
+}
+
+// PublicFilterAPI offers support to create and manage filters. This will allow external clients to retrieve various
+// information related to the chain such as blocks, transactions and logs.
+type PublicFilterAPI struct {
+	backend   Backend
+	events    *EventSystem
+	filtersMu sync.Mutex
+	filters   map[rpc.ID]*filter
+}
+
+// NewPublicFilterAPI returns a new PublicFilterAPI instance.
+func NewPublicFilterAPI(backend Backend, events *EventSystem) *PublicFilterAPI {
+	api := &PublicFilterAPI{
+		backend: backend,
+		events:  events,
+		filters: make(map[rpc.ID]*filter),
+	}
+	go api.timeoutLoop()
+
+	return api
+}
+
+// timeoutLoop runs every 5 minutes and deletes filters that have not been recently used.
+// It is started when the api is created.
+func (api *PublicFilterAPI) timeoutLoop() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-api.events.quit:
+			return
+		}
+		api.filtersMu.Lock()
+		for id, f := range api.filters {
+			select {
+			case <-f.deadline.C:
+				delete(api.filters, id)
+				f.s.Unsubscribe()
+			default:
+				continue
+			}
+		}
+		api.filtersMu.Unlock()
+	}
+}
+
+// installFilter keeps track of the subscription of a polling filter, consume
+// collects its events until it's uninstalled.
+func (api *PublicFilterAPI) installFilter(f *filter, consume func(f *filter) bool) rpc.ID {
+	api.filtersMu.Lock()
+	api.filters[f.s.ID] = f
+	api.filtersMu.Unlock()
+
+	go func() {
+		for {
+			if !consume(f) {
+				api.filtersMu.Lock()
+				delete(api.filters, f.s.ID)
+				api.filtersMu.Unlock()
+				return
+			}
+		}
+	}()
+	return f.s.ID
+}
+
+// NewPendingTransactionFilter creates a filter that fetches pending transaction hashes
+// as transactions become executable in the transaction pool.
+//
+// It is part of the filter package because this filter can be used through the
+// `eth_getFilterChanges` polling method that is also used for log filters.
+func (api *PublicFilterAPI) NewPendingTransactionFilter() (rpc.ID, error) {
+	if api.backend.EventBus() == nil {
+		return "", rpc.ErrNotificationsUnsupported
+	}
+	pendingTxs := make(chan []common.Hash)
+	pendingTxSub := api.events.SubscribePendingTxs(pendingTxs)
+
+	f := &filter{typ: PendingTransactionsSubscription, deadline: time.NewTimer(deadline), hashes: make([]common.Hash, 0), s: pendingTxSub}
+	return api.installFilter(f, func(f *filter) bool {
+		select {
+		case ph := <-pendingTxs:
+			api.filtersMu.Lock()
+			f.hashes = append(f.hashes, ph...)
+			api.filtersMu.Unlock()
+			return true
+		case <-pendingTxSub.Err():
+			return false
+		}
+	}), nil
+}
+
+// NewPendingTransactions creates a subscription that is triggered each time a transaction
+// becomes executable in the transaction pool.
+func (api *PublicFilterAPI) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	if api.backend.EventBus() == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		txHashes := make(chan []common.Hash, 128)
+		pendingTxSub := api.events.SubscribePendingTxs(txHashes)
+
+		for {
+			select {
+			case hashes := <-txHashes:
+				// To keep the original behaviour, send a single tx hash in one notification.
+				for _, h := range hashes {
+					notifier.Notify(rpcSub.ID, h)
+				}
+			case <-rpcSub.Err():
+				pendingTxSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				pendingTxSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// NewBlockFilter creates a filter that fetches blocks that are committed to the chain.
+// It is part of the filter package since polling goes with eth_getFilterChanges.
+func (api *PublicFilterAPI) NewBlockFilter() (rpc.ID, error) {
+	if api.backend.EventBus() == nil {
+		return "", rpc.ErrNotificationsUnsupported
+	}
+	blocks := make(chan *types.Block)
+	blockSub := api.events.SubscribeNewBlocks(blocks)
+
+	f := &filter{typ: BlocksSubscription, deadline: time.NewTimer(deadline), hashes: make([]common.Hash, 0), s: blockSub}
+	return api.installFilter(f, func(f *filter) bool {
+		select {
+		case b := <-blocks:
+			api.filtersMu.Lock()
+			f.hashes = append(f.hashes, b.Hash())
+			api.filtersMu.Unlock()
+			return true
+		case <-blockSub.Err():
+			return false
+		}
+	}), nil
+}
+
+// NewHeads send a notification each time a new block is committed to the chain.
+func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	if api.backend.EventBus() == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		blocks := make(chan *types.Block, 128)
+		blocksSub := api.events.SubscribeNewBlocks(blocks)
+
+		for {
+			select {
+			case b := <-blocks:
+				notifier.Notify(rpcSub.ID, rtypes.NewRPCBlock(b, false, false))
+			case <-rpcSub.Err():
+				blocksSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				blocksSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// Logs creates a subscription that fires for all new log that match the given filter criteria.
+func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
+	if api.backend.EventBus() == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	var (
+		rpcSub      = notifier.CreateSubscription()
+		matchedLogs = make(chan []*types.Log, 128)
+	)
+	logsSub := api.events.SubscribeLogs(crit, matchedLogs)
+
+	go func() {
+		for {
+			select {
+			case logs := <-matchedLogs:
+				for _, log := range logs {
+					notifier.Notify(rpcSub.ID, log)
+				}
+			case <-rpcSub.Err(): // client send an unsubscribe request
+				logsSub.Unsubscribe()
+				return
+			case <-notifier.Closed(): // connection dropped
+				logsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// NewFilter creates a new filter and returns the filter id. It can be
+// used to retrieve logs when the state changes. This method cannot be
+// used to fetch logs that are already stored in the state.
+//
+// Default criteria for the from and to block are "latest".
+// Using "latest" as block number will return logs for committed blocks.
+//
+// In case "fromBlock" > "toBlock" an error is returned.
+func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
+	if api.backend.EventBus() == nil {
+		return "", rpc.ErrNotificationsUnsupported
+	}
+	if crit.FromBlock != nil && crit.ToBlock != nil && crit.ToBlock.ToInt().Sign() >= 0 && crit.FromBlock.ToInt().Cmp(crit.ToBlock.ToInt()) > 0 {
+		return "", fmt.Errorf("invalid from and to block combination: from > to")
+	}
+	logs := make(chan []*types.Log)
+	logsSub := api.events.SubscribeLogs(crit, logs)
+
+	f := &filter{typ: LogsSubscription, crit: crit, deadline: time.NewTimer(deadline), logs: make([]*types.Log, 0), s: logsSub}
+	return api.installFilter(f, func(f *filter) bool {
+		select {
+		case l := <-logs:
+			api.filtersMu.Lock()
+			f.logs = append(f.logs, l...)
+			api.filtersMu.Unlock()
+			return true
+		case <-logsSub.Err():
+			return false
+		}
+	}), nil
+}
+
+// GetLogs returns logs matching the given argument that are stored within the state.
+func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+	return returnLogs(api.logs(ctx, crit))
+}
+
+// logs runs crit against the stored blocks, missing bounds default to the latest block.
+func (api *PublicFilterAPI) logs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.ToInt().Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.ToInt().Int64()
+	}
+	// Create and run the filter to get all the logs
+	filter := New(api.backend, begin, end, crit.Addresses, crit.Topics)
+	return filter.Logs(ctx)
+}
+
+// UninstallFilter removes the filter with the given filter id.
+func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) bool {
+	api.filtersMu.Lock()
+	f, found := api.filters[id]
+	if found {
+		delete(api.filters, id)
+	}
+	api.filtersMu.Unlock()
+	if found {
+		f.s.Unsubscribe()
+	}
+
+	return found
+}
+
+// GetFilterLogs returns the logs for the filter with the given id.
+// If the filter could not be found an empty array of logs is returned.
+func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Log, error) {
+	api.filtersMu.Lock()
+	f, found := api.filters[id]
+	api.filtersMu.Unlock()
+
+	if !found || f.typ != LogsSubscription {
+		return nil, errFilterNotFound
+	}
+	return returnLogs(api.logs(ctx, f.crit))
+}
+
+// GetFilterChanges returns the logs for the filter with the given id since
+// last time it was called. This can be used for polling.
+//
+// For pending transaction and block filters the result is []common.Hash.
+// (pending)Log filters return []Log.
+func (api *PublicFilterAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
+	api.filtersMu.Lock()
+	defer api.filtersMu.Unlock()
+
+	if f, found := api.filters[id]; found {
+		if !f.deadline.Stop() {
+			// timer expired but filter is not yet removed in timeout loop
+			// receive timer value and reset timer
+			<-f.deadline.C
+		}
+		f.deadline.Reset(deadline)
+
+		switch f.typ {
+		case PendingTransactionsSubscription, BlocksSubscription:
+			hashes := f.hashes
+			f.hashes = nil
+			return returnHashes(hashes), nil
+		case LogsSubscription:
+			logs := f.logs
+			f.logs = nil
+			return returnLogs(logs, nil)
+		}
+	}
+
+	return []interface{}{}, errFilterNotFound
+}
+
+// returnHashes is a helper that will return an empty hash array case the given hash array is nil,
+// otherwise the given hashes array is returned.
+func returnHashes(hashes []common.Hash) []common.Hash {
+	if hashes == nil {
+		return []common.Hash{}
+	}
+	return hashes
+}
+
+// returnLogs is a helper that will return an empty log array in case the given logs array is nil,
+// otherwise the given logs array is returned.
+func returnLogs(logs []*types.Log, err error) ([]*types.Log, error) {
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		return []*types.Log{}, nil
+	}
+	return logs, nil
+}
+
+// UnmarshalJSON sets *args fields with given data. Besides the ethereum
+// "address" field the addresses can be given in "addrs", as the lk namespace
+// does. Block numbers accept the "earliest", "latest" and "pending" tags,
+// pending logs don't exist so "pending" is treated as "latest".
+func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
+	type input struct {
+		FromBlock *rpc.BlockNumber `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber `json:"toBlock"`
+		Addrs     []common.Address `json:"addrs"`
+		Addresses interface{}      `json:"address"`
+		Topics    []interface{}    `json:"topics"`
+	}
+
+	var raw input
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	args.FromBlock = blockNumberToBig(raw.FromBlock)
+	args.ToBlock = blockNumberToBig(raw.ToBlock)
+	args.Addresses = raw.Addrs
+
+	if raw.Addresses != nil {
+		// raw.Address can contain a single address or an array of addresses
+		switch rawAddr := raw.Addresses.(type) {
+		case []interface{}:
+			for i, addr := range rawAddr {
+				if strAddr, ok := addr.(string); ok {
+					addr, err := decodeAddress(strAddr)
+					if err != nil {
+						return fmt.Errorf("invalid address at index %d: %v", i, err)
+					}
+					args.Addresses = append(args.Addresses, addr)
+				} else {
+					return fmt.Errorf("non-string address at index %d", i)
+				}
+			}
+		case string:
+			addr, err := decodeAddress(rawAddr)
+			if err != nil {
+				return fmt.Errorf("invalid address: %v", err)
+			}
+			args.Addresses = append(args.Addresses, addr)
+		default:
+			return errors.New("invalid addresses in query")
+		}
+	}
+
+	// topics is an array consisting of strings and/or arrays of strings.
+	// JSON null values are converted to common.Hash{} and ignored by the filter manager.
+	args.Topics = nil
+	if len(raw.Topics) > 0 {
+		args.Topics = make([][]common.Hash, len(raw.Topics))
+		for i, t := range raw.Topics {
+			switch topic := t.(type) {
+			case nil:
+				// ignore topic when matching logs
+
+			case string:
+				// match specific topic
+				top, err := decodeTopic(topic)
+				if err != nil {
+					return err
+				}
+				args.Topics[i] = []common.Hash{top}
+
+			case []interface{}:
+				// or case e.g. [null, "topic0", "topic1"]
+				for _, rawTopic := range topic {
+					if rawTopic == nil {
+						// null component, match all
+						args.Topics[i] = nil
+						break
+					}
+					if topic, ok := rawTopic.(string); ok {
+						parsed, err := decodeTopic(topic)
+						if err != nil {
+							return err
+						}
+						args.Topics[i] = append(args.Topics[i], parsed)
+					} else {
+						return fmt.Errorf("invalid topic(s)")
+					}
+				}
+			default:
+				return fmt.Errorf("invalid topic(s)")
+			}
+		}
+	}
+
+	return nil
+}
+
+func blockNumberToBig(number *rpc.BlockNumber) *hexutil.Big {
+	if number == nil {
+		return nil
+	}
+	if *number == rpc.PendingBlockNumber {
+		return (*hexutil.Big)(big.NewInt(rpc.LatestBlockNumber.Int64()))
+	}
+	return (*hexutil.Big)(big.NewInt(number.Int64()))
+}
+
+func decodeAddress(s string) (common.Address, error) {
+	b, err := hexutil.Decode(s)
+	if err == nil && len(b) != common.AddressLength {
+		err = fmt.Errorf("hex has invalid length %d after decoding; expected %d for address", len(b), common.AddressLength)
+	}
+	return common.BytesToAddress(b), err
+}
+
+func decodeTopic(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err == nil && len(b) != common.HashLength {
+		err = fmt.Errorf("hex has invalid length %d after decoding; expected %d for topic", len(b), common.HashLength)
+	}
+	return common.BytesToHash(b), err
+}
@@ -0,0 +1,304 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/libs/pubsub"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// Type determines the kind of filter and is used to put the filter in to
+// the correct bucket when added.
+type Type byte
+
+const (
+	// UnknownSubscription indicates an unknown subscription type
+	UnknownSubscription Type = iota
+	// LogsSubscription queries for new logs
+	LogsSubscription
+	// PendingTransactionsSubscription queries tx hashes for pending
+	// transactions entering the pending state
+	PendingTransactionsSubscription
+	// BlocksSubscription queries blocks that are committed
+	BlocksSubscription
+	// LastIndexSubscription keeps track of the last index
+	LastIndexSubscription
+)
+
+const (
+	// eventChanSize is the size of the channels subscribed to the event bus.
+	eventChanSize = 128
+	// subscriberName is the name the event system subscribes to the event bus with.
+	subscriberName = "rpc-filters"
+)
+
+type subscription struct {
+	id        rpc.ID
+	typ       Type
+	created   time.Time
+	logsCrit  FilterCriteria
+	logs      chan []*types.Log
+	hashes    chan []common.Hash
+	blocks    chan *types.Block
+	installed chan struct{} // closed when the filter is installed
+	err       chan error    // closed when the filter is uninstalled
+}
+
+// EventSystem creates subscriptions, processes events of the event bus and
+// broadcasts them to the subscriptions which match the subscription criteria.
+type EventSystem struct {
+	backend Backend
+
+	logsCh  chan interface{}   // Channel to receive new log events
+	blockCh chan interface{}   // Channel to receive new block events
+	txCh    chan interface{}   // Channel to receive new pending transaction events
+	install chan *subscription // install filter for event notification
+	// remove filter for event notification
+	uninstall chan *subscription
+
+	mtx     sync.Mutex
+	started bool
+	quit    chan struct{}
+}
+
+// NewEventSystem creates a new manager that listens for events of the event
+// bus of backend, once started.
+func NewEventSystem(backend Backend) *EventSystem {
+	return &EventSystem{
+		backend:   backend,
+		logsCh:    make(chan interface{}, eventChanSize),
+		blockCh:   make(chan interface{}, eventChanSize),
+		txCh:      make(chan interface{}, eventChanSize),
+		install:   make(chan *subscription),
+		uninstall: make(chan *subscription),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start subscribes to the event bus, which has to be running, and starts
+// dispatching its events to the installed subscriptions. Without an event bus
+// it's a noop, the filter api refuses to install filters then.
+func (es *EventSystem) Start() error {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	eventBus := es.backend.EventBus()
+	if eventBus == nil {
+		log.Warn("rpc filters: no event bus, filters disabled")
+		return nil
+	}
+	ctx := context.Background()
+	for _, sub := range []struct {
+		query pubsub.Query
+		ch    chan interface{}
+	}{
+		{types.EventQueryLog, es.logsCh},
+		{types.EventQueryNewBlock, es.blockCh},
+		{types.EventQueryPendingTx, es.txCh},
+	} {
+		if err := eventBus.Subscribe(ctx, subscriberName, sub.query, sub.ch); err != nil {
+			eventBus.UnsubscribeAll(ctx, subscriberName)
+			return err
+		}
+	}
+	es.started = true
+	go es.eventLoop()
+	return nil
+}
+
+// Stop unsubscribes from the event bus and ends all subscriptions.
+func (es *EventSystem) Stop() {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	if !es.started {
+		return
+	}
+	es.backend.EventBus().UnsubscribeAll(context.Background(), subscriberName)
+	close(es.quit)
+	es.started = false
+}
+
+// Subscription is created when the client registers itself for a particular event.
+type Subscription struct {
+	ID        rpc.ID
+	f         *subscription
+	es        *EventSystem
+	unsubOnce sync.Once
+}
+
+// Err returns a channel that is closed when unsubscribed.
+func (sub *Subscription) Err() <-chan error {
+	return sub.f.err
+}
+
+// Unsubscribe uninstalls the subscription from the event broadcast loop.
+func (sub *Subscription) Unsubscribe() {
+	sub.unsubOnce.Do(func() {
+	uninstallLoop:
+		for {
+			// write uninstall request and consume logs/hashes. This prevents
+			// the eventLoop broadcast method to deadlock when writing to the
+			// filter event channel while the subscription loop is waiting for
+			// this method to return (and thus not reading these events).
+			select {
+			case sub.es.uninstall <- sub.f:
+				break uninstallLoop
+			case <-sub.es.quit:
+				break uninstallLoop
+			case <-sub.f.logs:
+			case <-sub.f.hashes:
+			case <-sub.f.blocks:
+			}
+		}
+
+		// wait for filter to be uninstalled in work loop before returning
+		// this ensures that the manager won't use the event channel which
+		// will probably be closed by the client asap after this method returns.
+		select {
+		case <-sub.Err():
+		case <-sub.es.quit:
+		}
+	})
+}
+
+// subscribe installs the subscription in the event broadcast loop.
+func (es *EventSystem) subscribe(sub *subscription) *Subscription {
+	select {
+	case es.install <- sub:
+		<-sub.installed
+	case <-es.quit:
+	}
+	return &Subscription{ID: sub.id, f: sub, es: es}
+}
+
+// SubscribeLogs creates a subscription that will write all logs matching the
+// given criteria to the given logs channel.
+func (es *EventSystem) SubscribeLogs(crit FilterCriteria, logs chan []*types.Log) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       LogsSubscription,
+		logsCrit:  crit,
+		created:   time.Now(),
+		logs:      logs,
+		hashes:    make(chan []common.Hash),
+		blocks:    make(chan *types.Block),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribeNewBlocks creates a subscription that writes the blocks that are
+// committed to the chain.
+func (es *EventSystem) SubscribeNewBlocks(blocks chan *types.Block) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       BlocksSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		blocks:    blocks,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribePendingTxs creates a subscription that writes transaction hashes for
+// transactions that enter the transaction pool.
+func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       PendingTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    hashes,
+		blocks:    make(chan *types.Block),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+type filterIndex map[Type]map[rpc.ID]*subscription
+
+// broadcast event to filters that match criteria.
+func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
+	switch e := ev.(type) {
+	case types.EventDataLog:
+		if len(e.Logs) == 0 {
+			return
+		}
+		for _, f := range filters[LogsSubscription] {
+			if matchedLogs := filterLogs(e.Logs, f.logsCrit.FromBlock.ToInt(), f.logsCrit.ToBlock.ToInt(), f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
+				f.logs <- matchedLogs
+			}
+		}
+	case types.EventDataNewBlock:
+		if e.Block == nil {
+			return
+		}
+		for _, f := range filters[BlocksSubscription] {
+			f.blocks <- e.Block
+		}
+	case types.EventDataPendingTx:
+		hashes := []common.Hash{e.Tx.Hash()}
+		for _, f := range filters[PendingTransactionsSubscription] {
+			f.hashes <- hashes
+		}
+	default:
+		log.Warn("rpc filters: unexpected event", "event", ev)
+	}
+}
+
+// eventLoop (un)installs filters and processes the events of the event bus.
+func (es *EventSystem) eventLoop() {
+	index := make(filterIndex)
+	for i := UnknownSubscription; i < LastIndexSubscription; i++ {
+		index[i] = make(map[rpc.ID]*subscription)
+	}
+
+	for {
+		select {
+		case ev := <-es.logsCh:
+			es.broadcast(index, ev)
+		case ev := <-es.blockCh:
+			es.broadcast(index, ev)
+		case ev := <-es.txCh:
+			es.broadcast(index, ev)
+
+		case f := <-es.install:
+			index[f.typ][f.id] = f
+			close(f.installed)
+
+		case f := <-es.uninstall:
+			delete(index[f.typ], f.id)
+			close(f.err)
+
+		case <-es.quit:
+			return
+		}
+	}
+}
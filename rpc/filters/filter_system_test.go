@@ -0,0 +1,171 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/bloombits"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBackend struct {
+	eventBus *types.EventBus
+}
+
+func (b *testBackend) EventBus() *types.EventBus { return b.eventBus }
+
+func (b *testBackend) HeaderByHeight(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	return nil, nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, blockNr uint64) types.Receipts { return nil }
+
+func (b *testBackend) BloomStatus() (uint64, uint64) { return 4096, 0 }
+
+func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {}
+
+func newTestFilterAPI(t *testing.T) (*PublicFilterAPI, *types.EventBus, func()) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	backend := &testBackend{eventBus: eventBus}
+	events := NewEventSystem(backend)
+	require.NoError(t, events.Start())
+	stop := func() {
+		events.Stop()
+		eventBus.Stop()
+	}
+	return NewPublicFilterAPI(backend, events), eventBus, stop
+}
+
+// pollChanges polls the filter until it returned n results or a second passed.
+func pollChanges(t *testing.T, api *PublicFilterAPI, id rpc.ID, n int) interface{} {
+	var (
+		changes interface{}
+		err     error
+	)
+	for i := 0; i < 100; i++ {
+		changes, err = api.GetFilterChanges(id)
+		require.NoError(t, err)
+		switch c := changes.(type) {
+		case []common.Hash:
+			if len(c) >= n {
+				return c
+			}
+		case []*types.Log:
+			if len(c) >= n {
+				return c
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("filter %s: no changes after 1s", id)
+	return nil
+}
+
+func TestPendingTxFilter(t *testing.T) {
+	api, eventBus, stop := newTestFilterAPI(t)
+	defer stop()
+
+	id, err := api.NewPendingTransactionFilter()
+	require.NoError(t, err)
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	require.NoError(t, eventBus.PublishEventPendingTx(types.EventDataPendingTx{Tx: tx}))
+
+	hashes := pollChanges(t, api, id, 1).([]common.Hash)
+	assert.Equal(t, []common.Hash{tx.Hash()}, hashes)
+
+	// the changes are handed out once
+	changes, err := api.GetFilterChanges(id)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	assert.True(t, api.UninstallFilter(id))
+	assert.False(t, api.UninstallFilter(id))
+	_, err = api.GetFilterChanges(id)
+	assert.Equal(t, errFilterNotFound, err)
+}
+
+func TestBlockFilter(t *testing.T) {
+	api, eventBus, stop := newTestFilterAPI(t)
+	defer stop()
+
+	id, err := api.NewBlockFilter()
+	require.NoError(t, err)
+
+	block := types.NewBlock(&types.Header{Height: 7})
+	require.NoError(t, eventBus.PublishEventNewBlock(types.EventDataNewBlock{Block: block}))
+
+	hashes := pollChanges(t, api, id, 1).([]common.Hash)
+	assert.Equal(t, []common.Hash{block.Hash()}, hashes)
+}
+
+func TestLogFilter(t *testing.T) {
+	api, eventBus, stop := newTestFilterAPI(t)
+	defer stop()
+
+	var (
+		addr  = common.Address{2}
+		topic = common.Hash{3}
+		logs  = []*types.Log{
+			{Address: addr, Topics: []common.Hash{topic}, BlockNumber: 5},
+			{Address: common.Address{4}, Topics: []common.Hash{topic}, BlockNumber: 5},
+			{Address: addr, Topics: []common.Hash{{5}}, BlockNumber: 5},
+			{Address: addr, Topics: []common.Hash{topic}, BlockNumber: 6},
+		}
+	)
+	var crit FilterCriteria
+	require.NoError(t, json.Unmarshal([]byte(`{"toBlock":"0x5","address":"`+addr.Hex()+`","topics":["`+topic.Hex()+`"]}`), &crit))
+
+	id, err := api.NewFilter(crit)
+	require.NoError(t, err)
+
+	require.NoError(t, eventBus.PublishEventLog(types.EventDataLog{Logs: logs}))
+
+	found := pollChanges(t, api, id, 1).([]*types.Log)
+	assert.Equal(t, logs[:1], found)
+}
+
+func TestFilterCriteriaUnmarshalJSON(t *testing.T) {
+	var (
+		addr0  = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		addr1  = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		topic0 = common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000003")
+		topic1 = common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000004")
+	)
+
+	var crit FilterCriteria
+	require.NoError(t, json.Unmarshal([]byte(`{}`), &crit))
+	assert.Nil(t, crit.FromBlock)
+	assert.Nil(t, crit.ToBlock)
+	assert.Empty(t, crit.Addresses)
+	assert.Empty(t, crit.Topics)
+
+	crit = FilterCriteria{}
+	require.NoError(t, json.Unmarshal([]byte(`{"fromBlock":"0x10","toBlock":"pending"}`), &crit))
+	assert.Equal(t, int64(16), crit.FromBlock.ToInt().Int64())
+	assert.Equal(t, rpc.LatestBlockNumber.Int64(), crit.ToBlock.ToInt().Int64())
+
+	// the ethereum address field and the addrs field of the lk namespace
+	crit = FilterCriteria{}
+	require.NoError(t, json.Unmarshal([]byte(`{"address":["`+addr0.Hex()+`","`+addr1.Hex()+`"]}`), &crit))
+	assert.Equal(t, []common.Address{addr0, addr1}, crit.Addresses)
+	crit = FilterCriteria{}
+	require.NoError(t, json.Unmarshal([]byte(`{"addrs":["`+addr0.Hex()+`"]}`), &crit))
+	assert.Equal(t, []common.Address{addr0}, crit.Addresses)
+
+	crit = FilterCriteria{}
+	require.NoError(t, json.Unmarshal([]byte(`{"topics":[null,"`+topic0.Hex()+`",["`+topic0.Hex()+`","`+topic1.Hex()+`"],[null,"`+topic1.Hex()+`"]]}`), &crit))
+	assert.Equal(t, [][]common.Hash{nil, {topic0}, {topic0, topic1}, nil}, crit.Topics)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"address":"0x01"}`), &FilterCriteria{}))
+	assert.Error(t, json.Unmarshal([]byte(`{"topics":["0x01"]}`), &FilterCriteria{}))
+	assert.Error(t, json.Unmarshal([]byte(`{"topics":[1]}`), &FilterCriteria{}))
+}
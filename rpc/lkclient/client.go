@@ -0,0 +1,227 @@
+// Package lkclient provides a client for the eth namespace of the linkchain RPC
+// API, implementing the backends of the contract bindings.
+package lkclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi/bind"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+// Client defines typed wrappers for the linkchain RPC API.
+type Client struct {
+	c *rpc.Client
+}
+
+var (
+	_ bind.ContractBackend       = (*Client)(nil)
+	_ bind.PendingContractCaller = (*Client)(nil)
+	_ bind.DeployBackend         = (*Client)(nil)
+)
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connects a client to the given URL, ctx bounds the connection.
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client that uses the given RPC client.
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c}
+}
+
+// Close closes the underlying RPC connection.
+func (lc *Client) Close() {
+	lc.c.Close()
+}
+
+// CodeAt returns the contract code of the given account at the block
+// blockNumber, the latest block if nil.
+func (lc *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	err := lc.c.CallContext(ctx, &result, "eth_getCode", account, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// PendingCodeAt returns the contract code of the given account in the pending state.
+func (lc *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	var result hexutil.Bytes
+	err := lc.c.CallContext(ctx, &result, "eth_getCode", account, "pending")
+	return result, err
+}
+
+// PendingNonceAt returns the account nonce of the given account in the pending
+// state. The transactions still in the mempool are not counted.
+func (lc *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var result hexutil.Uint64
+	err := lc.c.CallContext(ctx, &result, "eth_getTransactionCount", account, "pending")
+	return uint64(result), err
+}
+
+// TransactionReceipt returns the receipt of a transaction by transaction hash.
+// Note that the receipt is not available for pending transactions, the error
+// is types.NotFound then.
+func (lc *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var r *rpcReceipt
+	if err := lc.c.CallContext(ctx, &r, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, types.NotFound
+	}
+	return r.toReceipt(), nil
+}
+
+// rpcReceipt is the receipt returned by eth_getTransactionReceipt.
+type rpcReceipt struct {
+	TxHash            common.Hash     `json:"transactionHash"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	Logs              []*types.Log    `json:"logs"`
+	Bloom             types.Bloom     `json:"logsBloom"`
+	Status            hexutil.Uint    `json:"status"`
+	VMErr             string          `json:"vmerr"`
+	Root              hexutil.Bytes   `json:"root"`
+}
+
+func (r *rpcReceipt) toReceipt() *types.Receipt {
+	receipt := &types.Receipt{
+		PostState:         r.Root,
+		Status:            uint64(r.Status),
+		VMErr:             r.VMErr,
+		CumulativeGasUsed: uint64(r.CumulativeGasUsed),
+		Bloom:             r.Bloom,
+		Logs:              r.Logs,
+		TxHash:            r.TxHash,
+		GasUsed:           uint64(r.GasUsed),
+	}
+	if r.ContractAddress != nil {
+		receipt.ContractAddress = *r.ContractAddress
+	}
+	return receipt
+}
+
+// SuggestGasPrice retrieves the gas price the transactions must pay.
+func (lc *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var hex hexutil.Big
+	if err := lc.c.CallContext(ctx, &hex, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&hex), nil
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction
+// based on the current pending state of the chain.
+func (lc *Client) EstimateGas(ctx context.Context, msg types.CallMsg) (uint64, error) {
+	var hex hexutil.Uint64
+	err := lc.c.CallContext(ctx, &hex, "eth_estimateGas", toCallArg(msg))
+	if err != nil {
+		return 0, err
+	}
+	return uint64(hex), nil
+}
+
+// CallContract executes a message call transaction, which is directly executed
+// in the VM of the node, but never mined into the blockchain.
+//
+// blockNumber selects the block height at which the call runs. It can be nil,
+// in which case the code is taken from the latest known block.
+func (lc *Client) CallContract(ctx context.Context, msg types.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := lc.c.CallContext(ctx, &hex, "eth_call", toCallArg(msg), toBlockNumArg(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+// PendingCallContract executes a message call transaction using the EVM.
+// The state seen by the contract call is the pending state.
+func (lc *Client) PendingCallContract(ctx context.Context, msg types.CallMsg) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := lc.c.CallContext(ctx, &hex, "eth_call", toCallArg(msg), "pending")
+	if err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+// SendTransaction injects a signed transaction into the mempool for execution.
+//
+// If the transaction was a contract creation use the TransactionReceipt method
+// to get the contract address after the transaction has been mined.
+func (lc *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	data, err := ser.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	return lc.c.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes(data))
+}
+
+// FilterLogs executes a filter query.
+func (lc *Client) FilterLogs(ctx context.Context, q types.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	err := lc.c.CallContext(ctx, &result, "eth_getLogs", toFilterArg(q))
+	return result, err
+}
+
+// SubscribeFilterLogs subscribes to the results of a streaming filter query.
+func (lc *Client) SubscribeFilterLogs(ctx context.Context, q types.FilterQuery, ch chan<- types.Log) (types.Subscription, error) {
+	return lc.c.EthSubscribe(ctx, ch, "logs", toFilterArg(q))
+}
+
+func toFilterArg(q types.FilterQuery) interface{} {
+	arg := map[string]interface{}{
+		"address": q.Addresses,
+		"topics":  q.Topics,
+	}
+	if q.FromBlock == nil {
+		arg["fromBlock"] = "0x0"
+	} else {
+		arg["fromBlock"] = toBlockNumArg(q.FromBlock)
+	}
+	arg["toBlock"] = toBlockNumArg(q.ToBlock)
+	return arg
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(number)
+}
+
+func toCallArg(msg types.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
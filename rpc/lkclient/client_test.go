@@ -0,0 +1,119 @@
+package lkclient_test
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+	"github.com/lianxiangcloud/linkchain/accounts/abi/bind"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/rpc/lkclient"
+	"github.com/lianxiangcloud/linkchain/simulated"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+var testBalance, _ = new(big.Int).SetString("0xfffffffffffffffffffffffffff", 0)
+
+// newTestClient returns a client of the rpc apis of a simulated backend, served in process
+func newTestClient(t *testing.T) (*simulated.Backend, *lkclient.Client, *bind.TransactOpts) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth := bind.NewKeyedTransactor(key)
+	b, err := simulated.NewBackend(map[string]types.GenesisAccount{auth.From.Hex(): {Balance: testBalance}})
+	require.NoError(t, err)
+
+	server := rpc.NewServer()
+	for _, api := range b.APIs() {
+		require.NoError(t, server.RegisterName(api.Namespace, api.Service))
+	}
+	return b, lkclient.NewClient(rpc.DialInProc(server)), auth
+}
+
+func TestClient(t *testing.T) {
+	b, client, auth := newTestClient(t)
+	defer b.Close()
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bin, err := ioutil.ReadFile("../../test/token/sol/SimpleToken.bin")
+	require.NoError(t, err)
+	abiJSON, err := ioutil.ReadFile("../../test/token/sol/SimpleToken.abi")
+	require.NoError(t, err)
+	parsed, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	require.NoError(t, err)
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, types.ParGasPrice, gasPrice.Int64())
+
+	address, tx, token, err := bind.DeployContract(auth, parsed, common.FromHex(strings.TrimSpace(string(bin))), client)
+	require.NoError(t, err)
+	_, err = client.TransactionReceipt(ctx, tx.Hash())
+	assert.Equal(t, types.NotFound, err)
+
+	_, err = b.Commit()
+	require.NoError(t, err)
+	deployed, err := bind.WaitDeployed(ctx, client, tx)
+	require.NoError(t, err)
+	assert.Equal(t, address, deployed)
+	nonce, err := client.PendingNonceAt(ctx, auth.From)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nonce)
+
+	balanceOf := func(addr common.Address, pending bool) *big.Int {
+		balance := new(big.Int)
+		require.NoError(t, token.Call(&bind.CallOpts{Pending: pending, From: auth.From, Context: ctx}, &balance, "balanceOf", addr))
+		return balance
+	}
+	supply := balanceOf(auth.From, false)
+	require.True(t, supply.Sign() > 0)
+	assert.Equal(t, supply, balanceOf(auth.From, true))
+
+	logs := make(chan types.Log, 1)
+	query := types.FilterQuery{Addresses: []common.Address{address}}
+	sub, err := client.SubscribeFilterLogs(ctx, query, logs)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tx, err = token.Transact(&bind.TransactOpts{From: auth.From, Signer: auth.Signer, Context: ctx}, "transfer", to, big.NewInt(100))
+	require.NoError(t, err)
+	_, err = b.Commit()
+	require.NoError(t, err)
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	assert.Equal(t, tx.Hash(), receipt.TxHash)
+	require.Len(t, receipt.Logs, 1)
+	assert.Equal(t, big.NewInt(100), balanceOf(to, false))
+
+	select {
+	case l := <-logs:
+		assert.Equal(t, tx.Hash(), l.TxHash)
+		assert.Equal(t, parsed.Events["Transfer"].Id(), l.Topics[0])
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-ctx.Done():
+		t.Fatal("no log of the transfer")
+	}
+
+	// the Transfer of the initial supply by the constructor and the one of the transfer
+	filtered, err := client.FilterLogs(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, *receipt.Logs[0], filtered[1])
+
+	code, err := client.CodeAt(ctx, address, big.NewInt(0))
+	require.NoError(t, err)
+	assert.Empty(t, code)
+}
@@ -193,6 +193,51 @@ func NewBalanceRecordsWithBlockMsg(blockHeight uint64, bbr *types.BlockBalanceRe
 	}
 }
 
+// RPCAddressTx is a transaction touching an address as returned by
+// lk_getAddressTransactions.
+type RPCAddressTx struct {
+	BlockHeight hexutil.Uint64 `json:"blockHeight"`
+	TxIndex     hexutil.Uint   `json:"transactionIndex"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	TokenID     common.Address `json:"tokenId"`
+	Direction   string         `json:"direction"` // in, out or self
+}
+
+// AddressTxs is a page of the transactions touching an address, Cursor
+// requests the next page and is nil on the last one.
+type AddressTxs struct {
+	Txs    []*RPCAddressTx `json:"transactions"`
+	Cursor *hexutil.Bytes  `json:"cursor"`
+}
+
+// RPCFork is a scheduled protocol upgrade as returned by eth_getForkSchedule.
+type RPCFork struct {
+	Name   string         `json:"name"`
+	Height hexutil.Uint64 `json:"height"`
+	Active bool           `json:"active"` // whether the upgrade applies to the chain head
+}
+
+// AccountResult is an account with its Merkle proof in the state trie, and the
+// proofs of some of its storage slots, as returned by eth_getProof.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+	BlockNumber  hexutil.Uint64  `json:"blockNumber"` // block after which the state is proven
+	StateRoot    common.Hash     `json:"stateRoot"`   // root of the state trie the proofs are against
+}
+
+// StorageResult is a storage slot with its Merkle proof in the storage trie of an account.
+type StorageResult struct {
+	Key   common.Hash     `json:"key"`
+	Value hexutil.Bytes   `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
 type ITX interface{}
 type txsAlias Txs
 type Txs []ITX
@@ -10,6 +10,8 @@ import (
 	"github.com/lianxiangcloud/linkchain/libs/bloombits"
 
 	"github.com/lianxiangcloud/linkchain/accounts"
+	"github.com/lianxiangcloud/linkchain/app"
+	"github.com/lianxiangcloud/linkchain/blockchain"
 	"github.com/lianxiangcloud/linkchain/bootnode"
 	"github.com/lianxiangcloud/linkchain/config"
 	cs "github.com/lianxiangcloud/linkchain/consensus"
@@ -17,6 +19,7 @@ import (
 	cmn "github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/math"
 	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/mempool"
 	"github.com/lianxiangcloud/linkchain/metrics"
 	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
 	"github.com/lianxiangcloud/linkchain/state"
@@ -27,6 +30,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+var errAddressIndexDisabled = errors.New("address index disabled")
+
 type ApiBackend struct {
 	s             *Service
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
@@ -400,6 +405,10 @@ func (b *ApiBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.Blo
 		return b.context().app.GetLatestStateDB(), b.context().app.GetPendingBlock().Head(), nil
 	}
 
+	if err := b.context().app.CheckStateRetained(height - 1); err != nil {
+		return nil, nil, err
+	}
+
 	meta := bc.LoadBlockMeta(height)
 	if meta == nil {
 		b.s.logger.Warn("ApiBackend StateAndHeaderByNumber: blockstore LoadBlockMeta fail", "height", height)
@@ -433,9 +442,11 @@ func (b *ApiBackend) GetVM(ctx context.Context, msg types.Message, state *state.
 	vmenv := vm.NewVM()
 	evmGasRate := config.EvmGasRate
 	contextEvm := evm.NewEVMContext(header, b.context().blockStore, nil, evmGasRate)
+	contextEvm.Forks = b.ForkSchedule()
 	vmenv.AddVm(&contextEvm, state, vmCfg)
 	wasmGasRate := config.WasmGasRate
 	contextWasm := wasm.NewWASMContext(header, b.context().blockStore, nil, wasmGasRate)
+	contextWasm.Forks = b.ForkSchedule()
 	vmenv.AddVm(&contextWasm, state, vmCfg)
 
 	// realvm := vmenv.GetRealVm(state, *msg.To())
@@ -449,6 +460,19 @@ func (b *ApiBackend) GetVM(ctx context.Context, msg types.Message, state *state.
 	return realvm, vmError, nil
 }
 
+// TraceBlock re-executes the transactions of block, up to the end-th one, on top of
+// the state of its parent block.
+func (b *ApiBackend) TraceBlock(ctx context.Context, block *types.Block, end int, vmConfig app.VmConfigFunc) ([]*app.TxTraceResult, error) {
+	if block.Height <= types.BlockHeightZero {
+		return nil, fmt.Errorf("genesis block is not traceable")
+	}
+	statedb, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(block.Height-1))
+	if err != nil {
+		return nil, err
+	}
+	return app.TraceBlock(block, statedb, b.context().blockStore, b.ForkSchedule(), end, vmConfig)
+}
+
 func (b *ApiBackend) GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error) {
 	bc := b.context().blockStore
 	block := bc.LoadBlockByHash(blockHash)
@@ -475,11 +499,26 @@ func (b *ApiBackend) GetBlockBalanceRecords(height uint64) *types.BlockBalanceRe
 	return brs.Get(height)
 }
 
+// AddressTxs returns the indexed transactions touching addr, see blockchain.AddressIndexer.
+func (b *ApiBackend) AddressTxs(addr common.Address, fromHeight, toHeight uint64, cursor []byte, limit int) ([]*blockchain.AddressTx, []byte, error) {
+	ai := b.context().addrIndex
+	if ai == nil {
+		return nil, nil, errAddressIndexDisabled
+	}
+	txs, next := ai.AddressTxs(addr, fromHeight, toHeight, cursor, limit)
+	return txs, next, nil
+}
+
 // GetMaxOutputIndex get max UTXO output index by token
 func (b *ApiBackend) GetMaxOutputIndex(ctx context.Context, token common.Address) int64 {
 	return b.context().utxo.GetMaxUtxoOutputSeq(token)
 }
 
+// GetMaxOutputIndexesAt get the max UTXO output index of every token after the block at blockHeight
+func (b *ApiBackend) GetMaxOutputIndexesAt(ctx context.Context, blockHeight uint64) (map[string]int64, error) {
+	return b.context().utxo.MaxUtxoOutputSeqsAt(blockHeight)
+}
+
 func (b *ApiBackend) GetBlockTokenOutputSeq(ctx context.Context, blockHeight uint64) map[string]int64 {
 	return b.context().utxo.GetBlockTokenUtxoOutputSeq(blockHeight)
 }
@@ -528,6 +567,14 @@ func (b *ApiBackend) Stats() (int, int, int) {
 	return b.context().mempool.Stats()
 }
 
+func (b *ApiBackend) TxPoolContent() *mempool.Content {
+	return b.context().mempool.Content()
+}
+
+func (b *ApiBackend) TxPoolContentFrom(addr common.Address) *mempool.Content {
+	return b.context().mempool.ContentFrom(addr)
+}
+
 func (b *ApiBackend) PrometheusMetrics() string {
 	return metrics.PrometheusMetricInstance.GetMetrics()
 }
@@ -536,6 +583,11 @@ func (b *ApiBackend) GetUTXOGas() uint64 {
 	return b.context().app.GetUTXOGas()
 }
 
+// ForkSchedule returns the protocol upgrades scheduled in the genesis.
+func (b *ApiBackend) ForkSchedule() types.Forks {
+	return b.context().app.ForkSchedule()
+}
+
 func (b *ApiBackend) GetTxsResult(ctx context.Context, blockNr uint64) (*types.TxsResult, error) {
 	bc := b.context().blockStore
 	latest := bc.Height()
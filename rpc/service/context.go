@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/lianxiangcloud/linkchain/accounts"
+	"github.com/lianxiangcloud/linkchain/blockchain"
 	cs "github.com/lianxiangcloud/linkchain/consensus"
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
@@ -11,6 +12,7 @@ import (
 	"github.com/lianxiangcloud/linkchain/libs/log"
 	"github.com/lianxiangcloud/linkchain/libs/p2p"
 	"github.com/lianxiangcloud/linkchain/libs/txmgr"
+	"github.com/lianxiangcloud/linkchain/mempool"
 	"github.com/lianxiangcloud/linkchain/state"
 	"github.com/lianxiangcloud/linkchain/types"
 )
@@ -22,12 +24,16 @@ type App interface {
 	GetLatestStateDB() *state.StateDB
 	GetPendingBlock() *types.Block
 	GetUTXOGas() uint64
+	CheckStateRetained(height uint64) error
+	ForkSchedule() types.Forks
 }
 
 type Mempool interface {
 	AddTx(peerID string, tx types.Tx) error
 	//PendingTxs(nums int) (types.Txs, error)
 	Stats() (int, int, int)
+	Content() *mempool.Content
+	ContentFrom(addr common.Address) *mempool.Content
 }
 
 type Consensus interface {
@@ -57,11 +63,17 @@ type BalanceRecordStore interface {
 	Get(blockHeight uint64) *types.BlockBalanceRecords
 }
 
+// AddressIndexer looks up the transactions touching an address
+type AddressIndexer interface {
+	AddressTxs(addr common.Address, fromHeight, toHeight uint64, cursor []byte, limit int) ([]*blockchain.AddressTx, []byte)
+}
+
 //UtxoStore utxo storage
 type UtxoStore interface {
 	GetUtxoOutput(token common.Address, index uint64) (*types.UTXOOutputData, error)
 	GetMaxUtxoOutputSeq(token common.Address) int64
 	GetBlockTokenUtxoOutputSeq(blockHeight uint64) map[string]int64
+	MaxUtxoOutputSeqsAt(height uint64) (map[string]int64, error)
 }
 
 type Context struct {
@@ -70,6 +82,7 @@ type Context struct {
 	stateDB    dbm.DB
 	blockStore BlockStore
 	brs        BalanceRecordStore
+	addrIndex  AddressIndexer
 	mempool    Mempool
 	app        App
 	triedb     state.Database
@@ -131,6 +144,10 @@ func (c *Context) SetBalanceRecordStore(brs BalanceRecordStore) {
 	c.brs = brs
 }
 
+func (c *Context) SetAddressIndexer(ai AddressIndexer) {
+	c.addrIndex = ai
+}
+
 func (c *Context) SetMempool(mem Mempool) {
 	c.mempool = mem
 }
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/lianxiangcloud/linkchain/blockchain"
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/hexutil"
 	"github.com/lianxiangcloud/linkchain/libs/log"
@@ -380,6 +382,71 @@ func (ps *PubsubApi) GetLogs(ctx context.Context, crit filters.FilterCriteria) (
 	return returnLogs(logs), err
 }
 
+const (
+	defaultAddressTxsLimit = 100
+	maxAddressTxsLimit     = 1000
+)
+
+// GetAddressTransactions returns the transactions touching address in the blocks
+// from fromHeight to toHeight: the account, token and utxo transactions it sends
+// or receives with its account and, with the balance records, the internal
+// transfers of contracts. A page holds at most limit transactions, the cursor of
+// a page requests the next one.
+func (ps *PubsubApi) GetAddressTransactions(address common.Address, fromHeight, toHeight rpc.BlockNumber, cursor *hexutil.Bytes, limit *hexutil.Uint) (*rtypes.AddressTxs, error) {
+	n := defaultAddressTxsLimit
+	if limit != nil {
+		n = int(*limit)
+		if n <= 0 || n > maxAddressTxsLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxAddressTxsLimit)
+		}
+	}
+	var c []byte
+	if cursor != nil && len(*cursor) > 0 {
+		if len(*cursor) != blockchain.AddressTxCursorLen {
+			return nil, errors.New("invalid cursor")
+		}
+		c = *cursor
+	}
+
+	height := ps.context().blockStore.Height()
+	from, to := height, height
+	if fromHeight >= 0 {
+		from = uint64(fromHeight)
+	}
+	if toHeight >= 0 {
+		to = uint64(toHeight)
+	}
+	result := &rtypes.AddressTxs{Txs: make([]*rtypes.RPCAddressTx, 0)}
+	if from > to {
+		return result, nil
+	}
+
+	txs, next, err := ps.backend().AddressTxs(address, from, to, c, n)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		direction := "self"
+		switch tx.Direction {
+		case blockchain.AddressTxIn:
+			direction = "in"
+		case blockchain.AddressTxOut:
+			direction = "out"
+		}
+		result.Txs = append(result.Txs, &rtypes.RPCAddressTx{
+			BlockHeight: hexutil.Uint64(tx.Height),
+			TxIndex:     hexutil.Uint(tx.TxIndex),
+			TxHash:      tx.TxHash,
+			TokenID:     tx.Token,
+			Direction:   direction,
+		})
+	}
+	if next != nil {
+		result.Cursor = (*hexutil.Bytes)(&next)
+	}
+	return result, nil
+}
+
 func (ps *PubsubApi) Validators(ctx context.Context, number rpc.BlockNumber) (*rtypes.ResultValidators, error) {
 	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
 		return ps.backend().Validators(nil)
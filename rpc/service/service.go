@@ -9,6 +9,7 @@ import (
 	"github.com/lianxiangcloud/linkchain/libs/log"
 	"github.com/lianxiangcloud/linkchain/libs/rpc"
 	"github.com/lianxiangcloud/linkchain/rpc/ethapi"
+	"github.com/lianxiangcloud/linkchain/rpc/filters"
 	"golang.org/x/time/rate"
 )
 
@@ -29,6 +30,7 @@ type Service struct {
 	apis     []rpc.API
 	pubsub   *PubsubApi
 	bloom    *BloomService
+	events   *filters.EventSystem
 	evmLimit *rate.Limiter
 }
 
@@ -44,6 +46,13 @@ func New(conf *config.RPCConfig, ctx *Context) *Service {
 	s.backend = backend
 	s.apis = ethapi.GetAPIs(s.backend)
 	s.bloom = NewBloomService(s)
+	s.events = filters.NewEventSystem(s.backend)
+	s.apis = append(s.apis, rpc.API{
+		Namespace: "eth",
+		Version:   "1.0",
+		Service:   filters.NewPublicFilterAPI(s.backend, s.events),
+		Public:    true,
+	})
 
 	s.pubsub = &PubsubApi{
 		s:     s,
@@ -64,6 +73,10 @@ func (s *Service) Start() error {
 	if err := s.bloom.Start(); err != nil {
 		return err
 	}
+	if err := s.events.Start(); err != nil {
+		s.bloom.Stop()
+		return err
+	}
 
 	if err := s.startIPC(); err != nil {
 		return err
@@ -87,6 +100,7 @@ func (s *Service) Stop() {
 	s.stopWS()
 	s.stopHTTP()
 	s.stopIPC()
+	s.events.Stop()
 	s.bloom.Stop()
 }
 
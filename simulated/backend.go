@@ -0,0 +1,474 @@
+// Package simulated runs a linkchain node in process, on memory databases, for
+// the tests of contracts and applications. Blocks are produced on demand by
+// Commit with a single validator, there is no networking and no consensus.
+package simulated
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/accounts"
+	"github.com/lianxiangcloud/linkchain/app"
+	bc "github.com/lianxiangcloud/linkchain/blockchain"
+	cfg "github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/event"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/libs/txmgr"
+	mempl "github.com/lianxiangcloud/linkchain/mempool"
+	"github.com/lianxiangcloud/linkchain/metrics"
+	"github.com/lianxiangcloud/linkchain/rpc/ethapi"
+	"github.com/lianxiangcloud/linkchain/rpc/filters"
+	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
+	"github.com/lianxiangcloud/linkchain/rpc/service"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
+)
+
+const (
+	// ChainID is the chain id of the simulated chain
+	ChainID = "simulated"
+
+	// genesisTime is the time of the genesis block, the same as the one of lkchain init
+	genesisTime = uint64(1507737600)
+	// blockInterval is the time between two blocks, unless adjusted by AdjustTime
+	blockInterval = time.Second
+)
+
+var errNoConsensus = errors.New("no consensus in the simulated backend")
+
+// Backend is a chain of a single validator producing a block on every Commit.
+// It implements ethapi.Backend, so that the rpc apis run on it in process.
+type Backend struct {
+	*service.ApiBackend
+
+	mu         sync.Mutex
+	params     types.ConsensusParams
+	pv         *types.MockPV
+	validator  *types.Validator
+	app        *app.LinkApplication
+	blockStore *bc.BlockStore
+	mempool    *mempl.Mempool
+	eventBus   *types.EventBus
+	events     *filters.EventSystem
+	chainAPI   *ethapi.PublicBlockChainAPI
+	lastCommit *types.Commit
+	timeShift  time.Duration
+}
+
+var (
+	_ ethapi.Backend       = (*Backend)(nil)
+	_ types.ContractCaller = (*Backend)(nil)
+	_ types.GasEstimator   = (*Backend)(nil)
+	_ types.LogFilterer    = (*Backend)(nil)
+
+	_ types.PendingContractCaller = (*Backend)(nil)
+	_ types.TransactionSender     = (*Backend)(nil)
+)
+
+// NewBackend creates the genesis block holding the accounts of alloc, keyed by
+// their hex addresses like the alloc accounts of the genesis file, and returns
+// the backend of the chain.
+func NewBackend(alloc map[string]types.GenesisAccount) (*Backend, error) {
+	logger := log.NewNopLogger()
+	params := *types.DefaultConsensusParams()
+
+	stateDB := dbm.NewMemDB()
+	blockStore := bc.NewBlockStore(dbm.NewMemDB())
+	if err := saveGenesisBlock(stateDB, blockStore, alloc, params); err != nil {
+		return nil, err
+	}
+	crossState := txmgr.NewCrossState(dbm.NewMemDB(), blockStore)
+	blockStore.SetCrossState(crossState)
+	utxoStore := utxo.NewUtxoStore(dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB())
+	utxoStore.SetLogger(logger)
+	balanceRecord := bc.NewBalanceRecordStore(dbm.NewMemDB(), false)
+
+	pv := types.NewMockPV()
+	validator := types.NewValidator(pv.GetPubKey(), common.EmptyAddress, 10)
+	metrics.PrometheusMetricInstance.Init(cfg.DefaultConfig(), pv.GetPubKey(), logger)
+	metrics.PrometheusMetricInstance.SetRole(types.NodeValidator)
+	metrics.PrometheusMetricInstance.SetCurrentProposerPubkey(pv.GetPubKey())
+
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(logger)
+	if err := eventBus.Start(); err != nil {
+		return nil, err
+	}
+
+	appHandle, err := app.NewLinkApplication(stateDB, blockStore, utxoStore, crossState, eventBus, true, balanceRecord, nil, nil)
+	if err != nil {
+		eventBus.Stop()
+		return nil, err
+	}
+	appHandle.SetLastChangedVals(types.BlockHeightZero, []*types.Validator{validator})
+	if err := appHandle.SetForks(params.Forks); err != nil {
+		eventBus.Stop()
+		return nil, err
+	}
+
+	mempoolConfig := cfg.DefaultMempoolConfig()
+	mempoolConfig.Broadcast = false
+	mempool := mempl.NewMempool(mempoolConfig, blockStore.Height(), nil)
+	mempool.SetApp(appHandle)
+	mempool.SetEventBus(eventBus)
+	appHandle.SetMempool(mempool)
+
+	rpcContext := service.NewContext()
+	rpcContext.SetLogger(logger)
+	rpcContext.SetAccountManager(accounts.NewManager())
+	rpcContext.SetBlockstore(blockStore)
+	rpcContext.SetBalanceRecordStore(balanceRecord)
+	rpcContext.SetTrieDB(stateDB, true)
+	rpcContext.SetPubKey(pv.GetPubKey())
+	rpcContext.SetMempool(mempool)
+	rpcContext.SetApp(appHandle)
+	rpcContext.SetUTXO(utxoStore)
+	rpcContext.SetEventBus(eventBus)
+	rpcContext.SetTxService(crossState)
+
+	b := &Backend{
+		ApiBackend: service.NewApiBackend(service.New(&cfg.RPCConfig{}, rpcContext)),
+		params:     params,
+		pv:         pv,
+		validator:  validator,
+		app:        appHandle,
+		blockStore: blockStore,
+		mempool:    mempool,
+		eventBus:   eventBus,
+		lastCommit: &types.Commit{},
+	}
+	b.chainAPI = ethapi.NewPublicBlockChainAPI(b)
+	b.events = filters.NewEventSystem(b)
+	if err := b.events.Start(); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// saveGenesisBlock saves the genesis block of the accounts of alloc, like lkchain init does
+func saveGenesisBlock(stateDB dbm.DB, blockStore *bc.BlockStore, alloc map[string]types.GenesisAccount, params types.ConsensusParams) error {
+	st, err := state.New(common.EmptyHash, state.NewKeyValueDBWithCache(stateDB, 0, true, 0))
+	if err != nil {
+		return err
+	}
+	app.AllocGenesisAccounts(st, alloc)
+
+	stateHash := st.IntermediateRoot(false)
+	trieRoot, err := st.Commit(false, types.BlockHeightZero)
+	if err != nil {
+		return err
+	}
+	st.Database().TrieDB().Commit(trieRoot, false)
+
+	block := &types.Block{
+		Header: &types.Header{
+			ChainID:   ChainID,
+			Height:    types.BlockHeightZero,
+			Time:      genesisTime,
+			StateHash: stateHash,
+			GasLimit:  params.BlockSize.MaxGas,
+		},
+		Data:       &types.Data{},
+		LastCommit: &types.Commit{},
+	}
+	blockStore.SaveInitHeight(types.BlockHeightZero)
+	blockStore.SaveBlock(block, block.MakePartSet(params.BlockGossip.BlockPartSizeBytes), nil, nil,
+		&types.TxsResult{TrieRoot: trieRoot, StateHash: stateHash})
+	return nil
+}
+
+// APIs returns the eth rpc apis running on the backend, to serve them in process
+// with rpc.DialInProc.
+func (b *Backend) APIs() []rpc.API {
+	return append(ethapi.GetAPIs(b), rpc.API{
+		Namespace: "eth",
+		Version:   "1.0",
+		Service:   filters.NewPublicFilterAPI(b, b.events),
+		Public:    true,
+	})
+}
+
+// Close stops the event dispatching and the mempool of the backend.
+func (b *Backend) Close() error {
+	b.events.Stop()
+	b.mempool.Stop()
+	return b.eventBus.Stop()
+}
+
+// Commit produces a block of the transactions of the mempool and commits it,
+// the block is signed by the validator of the backend.
+func (b *Backend) Commit() (*types.Block, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parent := b.app.Block()
+	height := parent.Height + 1
+	blockTime := parent.Time() + uint64((blockInterval+b.timeShift)/time.Second)
+
+	block := b.app.CreateBlock(height, b.params.BlockSize.MaxTxs, b.params.BlockSize.MaxGas, blockTime)
+	if block == nil {
+		return nil, fmt.Errorf("create block %d failed", height)
+	}
+	block.LastCommit = b.lastCommit
+	b.app.PreRunBlock(block)
+	if !b.app.CheckBlock(block) {
+		return nil, fmt.Errorf("check block %d failed", height)
+	}
+
+	blockParts := block.MakePartSet(b.params.BlockGossip.BlockPartSizeBytes)
+	blockID := types.BlockID{Hash: block.Hash(), PartsHeader: blockParts.Header()}
+	seenCommit, err := b.signCommit(height, blockID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := b.app.CommitBlock(block, blockParts, seenCommit, false); err != nil {
+		return nil, err
+	}
+
+	b.lastCommit = seenCommit
+	b.timeShift = 0
+	b.eventBus.PublishEventNewBlock(types.EventDataNewBlock{Block: block})
+	b.eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{Header: block.Header})
+	return block, nil
+}
+
+// signCommit returns the commit of the block signed by the validator of the backend.
+func (b *Backend) signCommit(height uint64, blockID types.BlockID) (*types.Commit, error) {
+	valSet := types.NewValidatorSet([]*types.Validator{b.validator})
+	voteSet := types.NewVoteSet(ChainID, height, 0, types.VoteTypePrecommit, valSet)
+	vote := &types.Vote{
+		ValidatorAddress: b.pv.GetAddress(),
+		ValidatorIndex:   0,
+		ValidatorSize:    valSet.Size(),
+		Height:           height,
+		Type:             types.VoteTypePrecommit,
+		BlockID:          blockID,
+		Timestamp:        time.Now().UTC(),
+	}
+	if err := b.pv.SignVote(ChainID, vote); err != nil {
+		return nil, err
+	}
+	if _, err := voteSet.AddVote(vote); err != nil {
+		return nil, err
+	}
+	return voteSet.MakeCommit(), nil
+}
+
+// AdjustTime moves the time of the next block forward by adjustment, the blocks
+// following it keep the shift.
+func (b *Backend) AdjustTime(adjustment time.Duration) error {
+	if adjustment < 0 {
+		return fmt.Errorf("negative time adjustment %v", adjustment)
+	}
+	b.mu.Lock()
+	b.timeShift += adjustment
+	b.mu.Unlock()
+	return nil
+}
+
+// SendTransaction adds the signed tx to the mempool, it is included in the block of the next Commit.
+func (b *Backend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.SendTx(ctx, tx)
+}
+
+// TransactionReceipt returns the receipt of a committed transaction, nil if
+// the transaction is not committed yet.
+func (b *Backend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, _, _, _ := b.GetTransactionReceipt(txHash)
+	return receipt, nil
+}
+
+// CodeAt returns the code of the contract on the state of the block blockNumber,
+// the latest one if nil.
+func (b *Backend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	blockNr := rpc.LatestBlockNumber
+	if blockNumber != nil {
+		blockNr = rpc.BlockNumber(blockNumber.Int64())
+	}
+	return b.chainAPI.GetCode(ctx, contract, blockNr)
+}
+
+// PendingCodeAt returns the code of the contract on the pending state.
+func (b *Backend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
+	return b.chainAPI.GetCode(ctx, contract, rpc.PendingBlockNumber)
+}
+
+// PendingNonceAt returns the nonce of the account, including the transactions of the mempool.
+func (b *Backend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.GetPoolNonce(ctx, account)
+}
+
+// SuggestGasPrice returns the gas price the transactions must pay.
+func (b *Backend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return b.SuggestPrice(ctx)
+}
+
+// CallContract executes the call on the state of the block blockNumber, the
+// latest one if nil, the same way the eth_call rpc does.
+func (b *Backend) CallContract(ctx context.Context, call types.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	blockNr := rpc.LatestBlockNumber
+	if blockNumber != nil {
+		blockNr = rpc.BlockNumber(blockNumber.Int64())
+	}
+	return b.chainAPI.Call(ctx, toCallArgs(call), blockNr)
+}
+
+// PendingCallContract executes the call on the pending state.
+func (b *Backend) PendingCallContract(ctx context.Context, call types.CallMsg) ([]byte, error) {
+	return b.chainAPI.Call(ctx, toCallArgs(call), rpc.PendingBlockNumber)
+}
+
+// EstimateGas returns the gas the call needs on the pending state, the same way
+// the eth_estimateGas rpc does.
+func (b *Backend) EstimateGas(ctx context.Context, call types.CallMsg) (uint64, error) {
+	gas, err := b.chainAPI.EstimateGas(ctx, toCallArgs(call))
+	return uint64(gas), err
+}
+
+func toCallArgs(call types.CallMsg) ethapi.CallArgs {
+	args := ethapi.CallArgs{
+		From: call.From,
+		To:   call.To,
+		Gas:  hexutil.Uint64(call.Gas),
+		Data: call.Data,
+	}
+	if call.GasPrice != nil {
+		args.GasPrice = hexutil.Big(*call.GasPrice)
+	}
+	if call.Value != nil {
+		args.Value = hexutil.Big(*call.Value)
+	}
+	return args
+}
+
+// FilterLogs returns the logs of the committed blocks matching the query.
+func (b *Backend) FilterLogs(ctx context.Context, query types.FilterQuery) ([]types.Log, error) {
+	from, to := int64(types.BlockHeightZero), int64(rpc.LatestBlockNumber)
+	if query.FromBlock != nil {
+		from = query.FromBlock.Int64()
+	}
+	if query.ToBlock != nil {
+		to = query.ToBlock.Int64()
+	}
+	logs, err := filters.New(b, from, to, query.Addresses, query.Topics).Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]types.Log, len(logs))
+	for i, l := range logs {
+		res[i] = *l
+	}
+	return res, nil
+}
+
+// SubscribeFilterLogs sends the logs of the blocks committed from now on which
+// match the query to ch.
+func (b *Backend) SubscribeFilterLogs(ctx context.Context, query types.FilterQuery, ch chan<- types.Log) (types.Subscription, error) {
+	crit := filters.FilterCriteria{
+		FromBlock: (*hexutil.Big)(query.FromBlock),
+		ToBlock:   (*hexutil.Big)(query.ToBlock),
+		Addresses: query.Addresses,
+		Topics:    query.Topics,
+	}
+	sink := make(chan []*types.Log)
+	sub := b.events.SubscribeLogs(crit, sink)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case logs := <-sink:
+				for _, l := range logs {
+					select {
+					case ch <- *l:
+					case <-quit:
+						return nil
+					}
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// EVMAllowed does not limit the calls, unlike the rpc service.
+func (b *Backend) EVMAllowed() bool {
+	return true
+}
+
+// StopTheWorld is not supported without consensus.
+func (b *Backend) StopTheWorld() bool {
+	return false
+}
+
+// StartTheWorld is not supported without consensus.
+func (b *Backend) StartTheWorld() bool {
+	return false
+}
+
+// ConsensusState is not supported without consensus.
+func (b *Backend) ConsensusState() (*rtypes.ResultConsensusState, error) {
+	return nil, errNoConsensus
+}
+
+// DumpConsensusState is not supported without consensus.
+func (b *Backend) DumpConsensusState() (*rtypes.ResultDumpConsensusState, error) {
+	return nil, errNoConsensus
+}
+
+// Validators returns the single validator of the backend.
+func (b *Backend) Validators(heightPtr *uint64) (*rtypes.ResultValidators, error) {
+	height := b.blockStore.Height()
+	if heightPtr != nil {
+		if *heightPtr > height {
+			return nil, fmt.Errorf("Height must be less than or equal to the current blockchain height")
+		}
+		height = *heightPtr
+	}
+	return &rtypes.ResultValidators{
+		BlockHeight:       height,
+		LastHeightChanged: types.BlockHeightZero,
+		Validators:        []*types.Validator{b.validator},
+	}, nil
+}
+
+// Status returns the latest block and the validator of the backend.
+func (b *Backend) Status() (*rtypes.ResultStatus, error) {
+	block := b.app.Block()
+	return &rtypes.ResultStatus{
+		SyncInfo: rtypes.SyncInfo{
+			LatestBlockHash:   block.Hash().Bytes(),
+			LatestAppHash:     block.ParentHash.Bytes(),
+			LatestBlockHeight: block.Height,
+			LatestBlockTime:   time.Unix(int64(block.Time()), 0),
+		},
+		ValidatorInfo: rtypes.ValidatorInfo{
+			Address:     b.validator.Address,
+			PubKey:      b.validator.PubKey,
+			VotingPower: b.validator.VotingPower,
+		},
+	}, nil
+}
+
+// NetInfo returns no peers.
+func (b *Backend) NetInfo() (*rtypes.ResultNetInfo, error) {
+	return &rtypes.ResultNetInfo{}, nil
+}
+
+// GetSeeds returns no seeds.
+func (b *Backend) GetSeeds() []rtypes.Node {
+	return nil
+}
@@ -0,0 +1,170 @@
+package simulated
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/rpc"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+var testBalance, _ = new(big.Int).SetString("0xfffffffffffffffffffffffffff", 0)
+
+func newTestBackend(t *testing.T) (*Backend, *ecdsa.PrivateKey, common.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	b, err := NewBackend(map[string]types.GenesisAccount{addr.Hex(): {Balance: testBalance}})
+	require.NoError(t, err)
+	return b, key, addr
+}
+
+// sendTx signs and sends the call msg as a transaction of key, commits it and returns its receipt
+func sendTx(t *testing.T, b *Backend, key *ecdsa.PrivateKey, msg types.CallMsg) *types.Receipt {
+	ctx := context.Background()
+	gas, err := b.EstimateGas(ctx, msg)
+	require.NoError(t, err)
+	nonce, err := b.GetPoolNonce(ctx, msg.From)
+	require.NoError(t, err)
+	gasPrice, err := b.SuggestPrice(ctx)
+	require.NoError(t, err)
+
+	var tx *types.Transaction
+	if msg.To == nil {
+		tx = types.NewContractCreation(nonce, msg.Value, gas, gasPrice, msg.Data)
+	} else {
+		tx = types.NewTransaction(nonce, *msg.To, msg.Value, gas, gasPrice, msg.Data)
+	}
+	require.NoError(t, tx.Sign(types.GlobalSTDSigner, key))
+	require.NoError(t, b.SendTransaction(ctx, tx))
+
+	block, err := b.Commit()
+	require.NoError(t, err)
+	receipt, _, height, _ := b.GetTransactionReceipt(tx.Hash())
+	require.NotNil(t, receipt, "tx not committed")
+	assert.Equal(t, block.Height, height)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, receipt.VMErr)
+	return receipt
+}
+
+func TestBackendEVM(t *testing.T) {
+	b, key, owner := newTestBackend(t)
+	defer b.Close()
+	ctx := context.Background()
+
+	code, err := ioutil.ReadFile("../test/token/sol/SimpleToken.bin")
+	require.NoError(t, err)
+	abiJSON, err := ioutil.ReadFile("../test/token/sol/SimpleToken.abi")
+	require.NoError(t, err)
+	token, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	require.NoError(t, err)
+
+	receipt := sendTx(t, b, key, types.CallMsg{From: owner, Value: big.NewInt(0), Data: common.FromHex(strings.TrimSpace(string(code)))})
+	contract := receipt.ContractAddress
+	require.NotEqual(t, common.EmptyAddress, contract)
+
+	balanceOf := func(addr common.Address, blockNumber *big.Int) *big.Int {
+		input, err := token.Pack("balanceOf", addr)
+		require.NoError(t, err)
+		out, err := b.CallContract(ctx, types.CallMsg{From: owner, To: &contract, Data: input}, blockNumber)
+		require.NoError(t, err)
+		balance := new(big.Int)
+		require.NoError(t, token.Unpack(&balance, "balanceOf", out))
+		return balance
+	}
+	supply := balanceOf(owner, nil)
+	require.True(t, supply.Sign() > 0)
+	deployHeight := b.blockStore.Height()
+
+	logs := make(chan types.Log, 1)
+	query := types.FilterQuery{Addresses: []common.Address{contract}}
+	sub, err := b.SubscribeFilterLogs(ctx, query, logs)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	input, err := token.Pack("transfer", to, big.NewInt(100))
+	require.NoError(t, err)
+	receipt = sendTx(t, b, key, types.CallMsg{From: owner, To: &contract, Value: big.NewInt(0), Data: input})
+	require.Len(t, receipt.Logs, 1)
+
+	select {
+	case l := <-logs:
+		assert.Equal(t, receipt.Logs[0].TxHash, l.TxHash)
+		assert.Equal(t, token.Events["Transfer"].Id(), l.Topics[0])
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log of the transfer")
+	}
+
+	filtered, err := b.FilterLogs(ctx, query)
+	require.NoError(t, err)
+	// the Transfer of the initial supply by the constructor and the one of the transfer
+	require.Len(t, filtered, 2)
+	assert.Equal(t, receipt.Logs[0].TxHash, filtered[1].TxHash)
+
+	assert.Equal(t, big.NewInt(100), balanceOf(to, nil))
+	assert.Equal(t, new(big.Int).Sub(supply, big.NewInt(100)), balanceOf(owner, nil))
+	// the state of the block of the deployment
+	assert.Equal(t, supply, balanceOf(owner, new(big.Int).SetUint64(deployHeight)))
+}
+
+func TestBackendWASM(t *testing.T) {
+	b, key, owner := newTestBackend(t)
+	defer b.Close()
+	ctx := context.Background()
+
+	code, err := ioutil.ReadFile("../vm/wasm/wasm-run/transfer.wasm")
+	require.NoError(t, err)
+	// the contract transfers 125 of its balance to 0x01 on every call, its init included
+	receipt := sendTx(t, b, key, types.CallMsg{From: owner, Value: big.NewInt(10000), Data: code})
+	contract := receipt.ContractAddress
+	require.NotEqual(t, common.EmptyAddress, contract)
+
+	sendTx(t, b, key, types.CallMsg{From: owner, To: &contract, Value: big.NewInt(0), Data: []byte("a|a")})
+	st, _, err := b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2*125), st.GetBalance(common.HexToAddress("0x01")))
+	assert.Equal(t, big.NewInt(10000-2*125), st.GetBalance(contract))
+}
+
+func TestBackendCommit(t *testing.T) {
+	b, _, _ := newTestBackend(t)
+	defer b.Close()
+
+	block1, err := b.Commit()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), block1.Height)
+
+	require.NoError(t, b.AdjustTime(time.Hour))
+	assert.Error(t, b.AdjustTime(-time.Second))
+	block2, err := b.Commit()
+	require.NoError(t, err)
+	assert.Equal(t, block1.Hash(), block2.ParentHash)
+	assert.Equal(t, block1.Time()+uint64((time.Hour+blockInterval)/time.Second), block2.Time())
+	// the commit of a block is the last commit of the next one
+	assert.Equal(t, block1.Hash(), block2.LastCommit.BlockID.Hash)
+
+	block3, err := b.Commit()
+	require.NoError(t, err)
+	assert.Equal(t, block2.Time()+uint64(blockInterval/time.Second), block3.Time())
+
+	status, err := b.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), status.SyncInfo.LatestBlockHeight)
+	vals, err := b.Validators(nil)
+	require.NoError(t, err)
+	assert.Len(t, vals.Validators, 1)
+}
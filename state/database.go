@@ -64,6 +64,15 @@ type TrieDB interface {
 	Commit(node common.Hash, report bool) error
 }
 
+// PrunableTrieDB is a TrieDB able to delete from disk the tries of the state
+// roots that are not retained anymore.
+type PrunableTrieDB interface {
+	TrieDB
+	EnablePruning()
+	Retain(root common.Hash) error
+	Release(root common.Hash) error
+}
+
 // NewDatabase creates a backing store for state. The returned database is safe for
 // concurrent use and retains cached trie nodes in memory. The pool is an optional
 // intermediate trie-node memory pool between the low level storage layer and the
@@ -93,6 +93,10 @@ type (
 	resetObjectChange struct {
 		prev *stateObject
 	}
+	mergeObjectChange struct {
+		account *common.Address
+		prev    *stateObject
+	}
 	suicideChange struct {
 		account     *common.Address
 		prev        bool // whether account had already suicided
@@ -144,6 +148,15 @@ type (
 		token   *common.Address
 		prev    *big.Int
 	}
+
+	// Changes to the access list
+	accessListAddAccountChange struct {
+		address *common.Address
+	}
+	accessListAddSlotChange struct {
+		address *common.Address
+		slot    *common.Hash
+	}
 )
 
 func (ch createObjectChange) revert(s *StateDB) {
@@ -163,6 +176,18 @@ func (ch resetObjectChange) dirtied() *common.Address {
 	return nil
 }
 
+func (ch mergeObjectChange) revert(s *StateDB) {
+	if ch.prev == nil {
+		delete(s.stateObjects, *ch.account)
+		return
+	}
+	s.setStateObject(ch.prev)
+}
+
+func (ch mergeObjectChange) dirtied() *common.Address {
+	return ch.account
+}
+
 func (ch suicideChange) revert(s *StateDB) {
 	obj := s.getStateObject(*ch.account)
 	if obj != nil {
@@ -267,3 +292,28 @@ func (ch tokenBalanceChange) revert(s *StateDB) {
 func (ch tokenBalanceChange) dirtied() *common.Address {
 	return ch.account
 }
+
+func (ch accessListAddAccountChange) revert(s *StateDB) {
+	/*
+		One important invariant here, is that whenever a (addr, slot) is added, if the
+		addr is not already present, the add causes two journal entries:
+		- one for the address,
+		- one for the (address,slot)
+		Therefore, when unrolling the change, we can always blindly delete the
+		(addr) at this point, since no storage adds can remain when come upon
+		a single (addr) change.
+	*/
+	s.accessList.DeleteAddress(*ch.address)
+}
+
+func (ch accessListAddAccountChange) dirtied() *common.Address {
+	return nil
+}
+
+func (ch accessListAddSlotChange) revert(s *StateDB) {
+	s.accessList.DeleteSlot(*ch.address, *ch.slot)
+}
+
+func (ch accessListAddSlotChange) dirtied() *common.Address {
+	return nil
+}
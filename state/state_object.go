@@ -351,6 +351,10 @@ func (c *stateObject) ReturnGas(gas *big.Int) {}
 
 func (c *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject := newObject(db, c.address, c.data)
+	stateObject.data.Tokens = make(map[common.Address]*big.Int, len(c.data.Tokens))
+	for token, balance := range c.data.Tokens {
+		stateObject.data.Tokens[token] = balance
+	}
 	if c.trie != nil {
 		stateObject.trie = db.db.CopyTrie(c.trie)
 	}
@@ -76,11 +76,23 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// Storage values at the start of the current transaction, recorded on
+	// their first write. Reset by Prepare.
+	txOrigins map[common.Address]Storage
+
+	// Per-transaction access list
+	accessList *accessList
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
 	validRevisions []revision
 	nextRevisionId int
+
+	// The state a speculative copy copies its objects from on first access.
+	parent *StateDB
+	// Accounts accessed since TrackAccess, nil if not tracked.
+	accessed map[common.Address]struct{}
 }
 
 // Create a new state from a given trie.
@@ -96,6 +108,8 @@ func New(root common.Hash, db Database) (*StateDB, error) {
 		stateObjectsDirty: make(map[common.Address]struct{}),
 		logs:              make(map[common.Hash][]*types.Log),
 		preimages:         make(map[common.Hash][]byte),
+		txOrigins:         make(map[common.Address]Storage),
+		accessList:        newAccessList(),
 		journal:           newJournal(),
 	}, nil
 }
@@ -127,6 +141,8 @@ func (s *StateDB) Reset(root common.Hash) error {
 	s.logs = make(map[common.Hash][]*types.Log)
 	s.logSize = 0
 	s.preimages = make(map[common.Hash][]byte)
+	s.txOrigins = make(map[common.Address]Storage)
+	s.accessList = newAccessList()
 	s.clearJournalAndRefund()
 	return nil
 }
@@ -298,13 +314,13 @@ func (s *StateDB) GetState(addr common.Address, hash common.Hash) []byte {
 	return nil
 }
 
-// GetCommittedState retrieves a value from the given account's committed storage trie.
+// GetCommittedState retrieves the value the given account's storage slot had
+// at the start of the current transaction.
 func (s *StateDB) GetCommittedState(addr common.Address, hash common.Hash) []byte {
-	stateObject := s.getStateObject(addr)
-	if stateObject != nil {
-		return stateObject.GetCommittedState(s.db, hash)
+	if value, ok := s.txOrigins[addr][hash]; ok {
+		return value
 	}
-	return nil
+	return s.GetState(addr, hash)
 }
 
 func (s *StateDB) GetStorageRoot(addr common.Address) common.Hash {
@@ -331,6 +347,33 @@ func (s *StateDB) StorageTrie(addr common.Address) Trie {
 	return cpy.updateTrie(s.db)
 }
 
+// proofList collects the nodes of a Merkle proof in path order.
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
+// GetProof returns the Merkle proof of an account in the state trie.
+func (s *StateDB) GetProof(addr common.Address) ([][]byte, error) {
+	var proof proofList
+	err := s.trie.Prove(crypto.Keccak256(addr.Bytes()), 0, &proof)
+	return [][]byte(proof), err
+}
+
+// GetStorageProof returns the Merkle proof of a storage slot in the storage
+// trie of an account.
+func (s *StateDB) GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error) {
+	var proof proofList
+	tr := s.StorageTrie(addr)
+	if tr == nil {
+		return proof, fmt.Errorf("storage trie of %s does not exist", addr.String())
+	}
+	err := tr.Prove(crypto.Keccak256(key.Bytes()), 0, &proof)
+	return [][]byte(proof), err
+}
+
 func (s *StateDB) HasSuicided(addr common.Address) bool {
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
@@ -398,6 +441,14 @@ func (s *StateDB) SetCode(addr common.Address, code []byte) {
 func (s *StateDB) SetState(addr common.Address, key common.Hash, value []byte) {
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
+		origin, ok := s.txOrigins[addr]
+		if !ok {
+			origin = make(Storage)
+			s.txOrigins[addr] = origin
+		}
+		if _, ok := origin[key]; !ok {
+			origin[key] = stateObject.GetState(s.db, key)
+		}
 		stateObject.SetState(s.db, key, value)
 	}
 }
@@ -476,6 +527,9 @@ func (s *StateDB) deleteStateObject(stateObject *stateObject) {
 
 // Retrieve a state object given by the address. Returns nil if not found.
 func (s *StateDB) getStateObject(addr common.Address) (stateObject *stateObject) {
+	if s.accessed != nil {
+		s.accessed[addr] = struct{}{}
+	}
 	// Prefer 'live' objects.
 	if obj := s.stateObjects[addr]; obj != nil {
 		if obj.deleted {
@@ -483,6 +537,16 @@ func (s *StateDB) getStateObject(addr common.Address) (stateObject *stateObject)
 		}
 		return obj
 	}
+	if s.parent != nil {
+		if obj := s.parent.stateObjects[addr]; obj != nil {
+			if obj.deleted {
+				return nil
+			}
+			obj = obj.deepCopy(s)
+			s.setStateObject(obj)
+			return obj
+		}
+	}
 
 	// Load the object from the database.
 	enc, err := s.trie.TryGet(addr[:])
@@ -601,6 +665,8 @@ func (s *StateDB) Copy() *StateDB {
 		logs:              make(map[common.Hash][]*types.Log, len(s.logs)),
 		logSize:           s.logSize,
 		preimages:         make(map[common.Hash][]byte),
+		txOrigins:         make(map[common.Address]Storage, len(s.txOrigins)),
+		accessList:        s.accessList.Copy(),
 		journal:           newJournal(),
 	}
 	// Copy the dirty states, logs, and preimages
@@ -635,9 +701,74 @@ func (s *StateDB) Copy() *StateDB {
 	for hash, preimage := range s.preimages {
 		state.preimages[hash] = preimage
 	}
+	for addr, origin := range s.txOrigins {
+		state.txOrigins[addr] = origin.Copy()
+	}
 	return state
 }
 
+// SpeculativeCopy returns a copy of the state to execute a transaction on
+// speculatively. Unlike Copy the objects of s are copied on first access, so
+// s must not be modified while the copy is in use. The accounts accessed
+// through the copy are tracked, see AccessedAccounts.
+func (s *StateDB) SpeculativeCopy() *StateDB {
+	return &StateDB{
+		db:                s.db,
+		trie:              s.db.CopyTrie(s.trie),
+		stateObjects:      make(map[common.Address]*stateObject),
+		stateObjectsDirty: make(map[common.Address]struct{}),
+		logs:              make(map[common.Hash][]*types.Log),
+		preimages:         make(map[common.Hash][]byte),
+		txOrigins:         make(map[common.Address]Storage),
+		accessList:        newAccessList(),
+		journal:           newJournal(),
+		parent:            s,
+		accessed:          make(map[common.Address]struct{}),
+	}
+}
+
+// MergeSpeculative applies the transaction executed on c, a speculative copy
+// of s, to s: the accounts it modified replace the ones of s and its logs
+// are added as the logs of the current transaction of s. The result equals
+// executing the transaction on s only if s was not modified since c was
+// taken in any of the accounts c accessed.
+func (s *StateDB) MergeSpeculative(c *StateDB) {
+	for addr := range c.journal.dirties {
+		obj, ok := c.stateObjects[addr]
+		if !ok {
+			continue
+		}
+		account := addr
+		s.journal.append(mergeObjectChange{account: &account, prev: s.stateObjects[addr]})
+		obj.db = s
+		s.setStateObject(obj)
+	}
+	if c.refund > 0 {
+		s.AddRefund(c.refund)
+	}
+	for _, l := range c.logs[c.thash] {
+		s.AddLog(l)
+	}
+	for hash, preimage := range c.preimages {
+		s.AddPreimage(hash, preimage)
+	}
+	s.setError(c.dbErr)
+}
+
+// TrackAccess starts tracking the accounts accessed through s, dropping the
+// ones tracked so far.
+func (s *StateDB) TrackAccess() {
+	s.accessed = make(map[common.Address]struct{})
+}
+
+// AccessedAccounts stops tracking the accounts accessed through s and
+// returns the ones accessed since TrackAccess or SpeculativeCopy.
+func (s *StateDB) AccessedAccounts() map[common.Address]struct{} {
+	accessed := s.accessed
+	s.accessed = nil
+	return accessed
+}
+
 // Snapshot returns an identifier for the current revision of the state.
 func (s *StateDB) Snapshot() int {
 	id := s.nextRevisionId
@@ -708,6 +839,8 @@ func (s *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 	s.thash = thash
 	s.bhash = bhash
 	s.txIndex = ti
+	s.txOrigins = make(map[common.Address]Storage)
+	s.accessList = newAccessList()
 }
 
 // TxHash get the current Transaction Hash.
@@ -775,3 +908,64 @@ func (s *StateDB) Commit(deleteEmptyObjects bool, height uint64) (root common.Ha
 	log.Info("Trie cache stats after commit", "misses", trie.CacheMisses(), "unloads", trie.CacheUnloads())
 	return root, err
 }
+
+// PrepareAccessList handles the preparatory steps for executing a state transition with
+// regards to EIP-2929 and EIP-2930:
+//
+// - Add sender to access list
+// - Add destination to access list
+// - Add precompiles to access list
+// - Add the contents of the optional tx access list
+//
+// Unlike geth the list is not cleared here but in Prepare, as a transaction may
+// run several calls which share a single snapshot.
+func (s *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
+	s.AddAddressToAccessList(sender)
+	if dst != nil {
+		s.AddAddressToAccessList(*dst)
+	}
+	for _, addr := range precompiles {
+		s.AddAddressToAccessList(addr)
+	}
+	for _, el := range list {
+		s.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			s.AddSlotToAccessList(el.Address, key)
+		}
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list
+func (s *StateDB) AddAddressToAccessList(addr common.Address) {
+	if s.accessList.AddAddress(addr) {
+		s.journal.append(accessListAddAccountChange{&addr})
+	}
+}
+
+// AddSlotToAccessList adds the given (address, slot)-tuple to the access list
+func (s *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	addrMod, slotMod := s.accessList.AddSlot(addr, slot)
+	if addrMod {
+		// In practice, this should not happen, since there is no way to enter the
+		// scope of 'address' without having the 'address' become already added
+		// to the access list (via call-variant, create, etc).
+		// Better safe than sorry, though
+		s.journal.append(accessListAddAccountChange{&addr})
+	}
+	if slotMod {
+		s.journal.append(accessListAddSlotChange{
+			address: &addr,
+			slot:    &slot,
+		})
+	}
+}
+
+// AddressInAccessList returns true if the given address is in the access list.
+func (s *StateDB) AddressInAccessList(addr common.Address) bool {
+	return s.accessList.ContainsAddress(addr)
+}
+
+// SlotInAccessList returns true if the given (address, slot)-tuple is in the access list.
+func (s *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressPresent bool, slotPresent bool) {
+	return s.accessList.Contains(addr, slot)
+}
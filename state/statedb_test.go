@@ -499,3 +499,49 @@ func TestCopyOfCopy(t *testing.T) {
 		t.Fatalf("2nd copy fail, expected 42, got %v", got)
 	}
 }
+
+func TestSpeculativeCopy(t *testing.T) {
+	var (
+		tokenAddress = common.HexToAddress("0x1E")
+		addr1        = common.HexToAddress("0x01")
+		addr2        = common.HexToAddress("0x02")
+		addr3        = common.HexToAddress("0x03")
+	)
+	newState := func() *StateDB {
+		s, _ := New(common.EmptyHash, NewDatabase(dbm.NewMemDB()))
+		s.AddBalance(addr1, big.NewInt(1))
+		s.AddTokenBalance(addr1, tokenAddress, big.NewInt(1))
+		s.Finalise(false)
+		return s
+	}
+	apply := func(s *StateDB) {
+		s.Prepare(common.Hash{1}, common.Hash{}, 0)
+		s.AddTokenBalance(addr1, tokenAddress, big.NewInt(2))
+		s.SetState(addr2, common.Hash{2}, []byte{2})
+		s.AddLog(&types.Log{Address: addr2})
+	}
+
+	orig, spec := newState(), newState()
+	cpy := spec.SpeculativeCopy()
+	apply(cpy)
+	if got := spec.GetTokenBalance(addr1, tokenAddress); got.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("token balance modified by the copy: have %v, want 1", got)
+	}
+	cpy.GetBalance(addr3)
+	accessed := cpy.AccessedAccounts()
+	for _, addr := range []common.Address{addr1, addr2, addr3} {
+		if _, ok := accessed[addr]; !ok {
+			t.Errorf("account %x not tracked", addr)
+		}
+	}
+
+	apply(orig)
+	spec.Prepare(common.Hash{1}, common.Hash{}, 0)
+	spec.MergeSpeculative(cpy)
+	if have, want := spec.IntermediateRoot(false), orig.IntermediateRoot(false); have != want {
+		t.Errorf("root mismatch: have %x, want %x", have, want)
+	}
+	if have, want := spec.Logs(), orig.Logs(); !reflect.DeepEqual(have, want) {
+		t.Errorf("logs mismatch: have %v, want %v", have, want)
+	}
+}
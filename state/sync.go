@@ -18,8 +18,11 @@ package state
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
 	"github.com/lianxiangcloud/linkchain/libs/ser"
 	"github.com/lianxiangcloud/linkchain/libs/trie"
 )
@@ -39,3 +42,65 @@ func NewStateSync(root common.Hash, database trie.DatabaseReader) *trie.Sync {
 	syncer = trie.NewSync(root, database, callback)
 	return syncer
 }
+
+// VerifyState walks the account trie and the storage tries of the state at
+// root in db, and checks that every node hashes to the key it is stored at and
+// that the contract codes are there. It fails on the first missing node.
+func VerifyState(root common.Hash, db dbm.DB) error {
+	triedb := trie.NewDatabase(hashCheckedDB{db})
+	return verifyTrie(triedb, root, func(leaf []byte) error {
+		var obj Account
+		if err := ser.Decode(bytes.NewReader(leaf), &obj); err != nil {
+			return err
+		}
+		if err := verifyTrie(triedb, obj.Root, nil); err != nil {
+			return err
+		}
+		codeHash := common.BytesToHash(obj.CodeHash)
+		if codeHash == common.EmptyHash || codeHash == emptyCode {
+			return nil
+		}
+		if code, err := db.Load(codeHash[:]); err != nil || crypto.Keccak256Hash(code) != codeHash {
+			return fmt.Errorf("missing code %x", codeHash)
+		}
+		return nil
+	})
+}
+
+// verifyTrie streams the nodes of the trie at root, only the path to the
+// current node is held in memory.
+func verifyTrie(triedb *trie.Database, root common.Hash, onLeaf func(leaf []byte) error) error {
+	if root == common.EmptyHash {
+		return nil
+	}
+	t, err := trie.New(root, triedb)
+	if err != nil {
+		return err
+	}
+	it := trie.NewIterator(t.NodeIterator(nil))
+	for it.Next() {
+		if onLeaf != nil {
+			if err := onLeaf(it.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Err
+}
+
+// hashCheckedDB fails to load the trie nodes not hashing to their key, so that
+// a node corrupted on disk is reported as missing.
+type hashCheckedDB struct {
+	dbm.DB
+}
+
+func (db hashCheckedDB) Load(key []byte) ([]byte, error) {
+	blob, err := db.DB.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) == 0 || !bytes.Equal(crypto.Keccak256(blob), key) {
+		return nil, fmt.Errorf("missing trie node %x", key)
+	}
+	return blob, nil
+}
@@ -0,0 +1,483 @@
+package statesync
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	bc "github.com/lianxiangcloud/linkchain/blockchain"
+	cfg "github.com/lianxiangcloud/linkchain/config"
+	cs "github.com/lianxiangcloud/linkchain/consensus"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+	lctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/libs/p2p"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
+)
+
+const (
+	// StateSyncChannel is a channel for state snapshots, trie nodes and UTXO data
+	StateSyncChannel = byte(0x60)
+
+	// a snapshot carries a block, its receipts and the next block
+	maxMsgSize = 3 * types.MaxBlockSizeBytes
+
+	// soft limit of the node data returned by one response
+	softResponseLimit = 2 * 1024 * 1024
+
+	maxNodeDataPerMsg    = 384
+	maxUtxoOutputsPerMsg = 1024
+	maxKeyImagesPerMsg   = 8192
+
+	responseChanSize = 1000
+)
+
+// snapshotApp is the part of the application that state sync reloads
+// once the stores have been restored.
+type snapshotApp interface {
+	Height() uint64
+	Reload() error
+	ForkSchedule() types.Forks
+	CheckBlock(block *types.Block) bool
+}
+
+type blockchainReactor interface {
+	// for when we switch from state sync to fast sync
+	RestartFastSync(cs.NewStatus) error
+}
+
+// StateSyncReactor serves the state of the latest blocks to peers and, for a
+// new node, restores the state of a recent block from peers before handing
+// off to fast sync and consensus.
+type StateSyncReactor struct {
+	p2p.BaseReactor
+	sw     p2p.P2PManager
+	config *cfg.StateSyncConfig
+
+	chainID    string
+	app        snapshotApp
+	blockStore *bc.BlockStore
+	utxoStore  *utxo.UtxoStore
+	stateDB    dbm.DB
+	statusDB   dbm.DB
+
+	// only full nodes keep the state trie a snapshot is verified against
+	serve     bool
+	stateSync bool
+
+	syncing    uint32
+	responseCh chan peerResponse
+
+	spentMtx sync.Mutex
+	spent    *spentKeyImages
+
+	// the snapshot served last, hashing its UTXO set walks the whole store
+	snapshotMtx sync.Mutex
+	snapshot    *Snapshot
+}
+
+// NewStateSyncReactor returns new reactor instance.
+// A state sync is run on start if stateSync is set.
+func NewStateSyncReactor(config *cfg.StateSyncConfig, chainID string, app snapshotApp, blockStore *bc.BlockStore,
+	utxoStore *utxo.UtxoStore, stateDB dbm.DB, statusDB dbm.DB, isTrie bool, stateSync bool, p2pmanager p2p.P2PManager) *StateSyncReactor {
+
+	ssR := &StateSyncReactor{
+		sw:         p2pmanager,
+		config:     config,
+		chainID:    chainID,
+		app:        app,
+		blockStore: blockStore,
+		utxoStore:  utxoStore,
+		stateDB:    stateDB,
+		statusDB:   statusDB,
+		serve:      isTrie,
+		stateSync:  stateSync,
+		responseCh: make(chan peerResponse, responseChanSize),
+	}
+	ssR.BaseReactor = *p2p.NewBaseReactor("StateSyncReactor", ssR)
+	return ssR
+}
+
+// OnStart implements cmn.Service.
+func (ssR *StateSyncReactor) OnStart() error {
+	if err := ssR.BaseReactor.OnStart(); err != nil {
+		return err
+	}
+	if height := ssR.statusDB.Get(unverifiedSnapshotKey); len(height) > 0 {
+		return fmt.Errorf("the state restored at height %s was not checked against the next block, remove the data and sync again", height)
+	}
+	if ssR.stateSync {
+		atomic.StoreUint32(&ssR.syncing, 1)
+		go ssR.syncRoutine()
+	}
+	return nil
+}
+
+// IsSyncing returns true while the reactor is restoring a snapshot.
+func (ssR *StateSyncReactor) IsSyncing() bool {
+	return atomic.LoadUint32(&ssR.syncing) == 1
+}
+
+// GetChannels implements Reactor
+func (ssR *StateSyncReactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  StateSyncChannel,
+			Priority:            5,
+			SendQueueCapacity:   100,
+			RecvBufferCapacity:  1024 * 1024,
+			RecvMessageCapacity: maxMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor by asking a new peer for its snapshot while syncing.
+func (ssR *StateSyncReactor) AddPeer(peer p2p.Peer) {
+	if ssR.IsSyncing() {
+		peer.TrySend(StateSyncChannel, encodeMsg(&ssSnapshotRequestMessage{ssR.config.TrustHeight}))
+	}
+}
+
+// RemovePeer implements Reactor.
+func (ssR *StateSyncReactor) RemovePeer(peer p2p.Peer, reason interface{}) {
+	// nothing to do, requests to the peer time out
+}
+
+// Receive implements Reactor by answering the requests of the peers and
+// passing their responses to the sync routine.
+func (ssR *StateSyncReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		ssR.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err)
+		ssR.sw.StopPeerForError(src, err)
+		return
+	}
+
+	ssR.Logger.Debug("Receive", "src", src, "chID", chID, "msg", msg, "size", len(msgBytes))
+
+	switch msg := msg.(type) {
+	case *ssSnapshotRequestMessage:
+		if !ssR.serve || ssR.IsSyncing() {
+			return
+		}
+		snapshot, err := ssR.loadSnapshot(msg.Height)
+		if err != nil {
+			ssR.Logger.Info("Peer asking for a snapshot we don't have", "src", src, "height", msg.Height, "err", err)
+			return
+		}
+		src.TrySend(StateSyncChannel, encodeMsg(&ssSnapshotResponseMessage{Snapshot: snapshot}))
+	case *ssNodeDataRequestMessage:
+		if !ssR.serve || ssR.IsSyncing() {
+			return
+		}
+		data := loadNodeData(ssR.stateDB, msg.Hashes)
+		src.TrySend(StateSyncChannel, encodeMsg(&ssNodeDataResponseMessage{Data: data}))
+	case *ssUtxoOutputsRequestMessage:
+		if !ssR.serve || ssR.IsSyncing() {
+			return
+		}
+		outputs := ssR.loadUtxoOutputs(msg.Token, msg.From, msg.Count)
+		src.TrySend(StateSyncChannel, encodeMsg(&ssUtxoOutputsResponseMessage{Token: msg.Token, From: msg.From, Outputs: outputs}))
+	case *ssKeyImagesRequestMessage:
+		if !ssR.serve || ssR.IsSyncing() {
+			return
+		}
+		kImgs, next, err := ssR.loadKeyImages(msg.Height, msg.Start)
+		if err != nil {
+			ssR.Logger.Info("Peer asking for key images we don't have", "src", src, "height", msg.Height, "err", err)
+			return
+		}
+		src.TrySend(StateSyncChannel, encodeMsg(&ssKeyImagesResponseMessage{Height: msg.Height, Start: msg.Start, KeyImages: kImgs, Next: next}))
+	case *ssSnapshotResponseMessage, *ssNodeDataResponseMessage, *ssUtxoOutputsResponseMessage, *ssKeyImagesResponseMessage:
+		if !ssR.IsSyncing() {
+			return
+		}
+		select {
+		case ssR.responseCh <- peerResponse{peerID: src.ID(), msg: msg}:
+		default:
+			// dropped, the request will time out
+		}
+	default:
+		ssR.Logger.Error(cmn.Fmt("Unknown message type %v", reflect.TypeOf(msg)))
+	}
+}
+
+// loadSnapshot returns the snapshot of the block at height, which has to be
+// below the top of the block store: the UTXO store is saved after the block
+// store, so the top-1 block is the latest block both stores have.
+func (ssR *StateSyncReactor) loadSnapshot(height uint64) (*Snapshot, error) {
+	if height <= types.BlockHeightZero || height >= ssR.blockStore.Height() {
+		return nil, fmt.Errorf("no block to snapshot at height %d", height)
+	}
+	ssR.snapshotMtx.Lock()
+	defer ssR.snapshotMtx.Unlock()
+	if ssR.snapshot != nil && ssR.snapshot.Height() == height {
+		return ssR.snapshot, nil
+	}
+
+	block := ssR.blockStore.LoadBlock(height)
+	seenCommit := ssR.blockStore.LoadSeenCommit(height)
+	next := ssR.blockStore.LoadBlock(height + 1)
+	nextCommit := ssR.blockStore.LoadSeenCommit(height + 1)
+	if block == nil || seenCommit == nil || next == nil || nextCommit == nil {
+		return nil, types.ErrUnknownBlock
+	}
+	txsResult, err := ssR.blockStore.LoadTxsResult(height)
+	if err != nil {
+		return nil, err
+	}
+	if blob, err := ssR.stateDB.Load(txsResult.TrieRoot[:]); err != nil || len(blob) == 0 {
+		return nil, fmt.Errorf("state at height %d pruned", height)
+	}
+	status, err := cs.LoadStatusByHeight(ssR.statusDB, height)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSeqs, err := ssR.utxoStore.MaxUtxoOutputSeqsAt(height)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Block:      block,
+		SeenCommit: seenCommit,
+		Receipts:   make([]*types.ReceiptForStorage, 0),
+		TxsResult:  txsResult,
+		Status:     status,
+		Next:       next,
+		NextCommit: nextCommit,
+		UtxoSeqs:   makeUtxoSeqs(maxSeqs),
+	}
+	if receipts := ssR.blockStore.GetReceipts(height); receipts != nil {
+		for _, r := range *receipts {
+			snapshot.Receipts = append(snapshot.Receipts, r.ForStorage())
+		}
+	}
+	spentAfter := func() (map[lctypes.Key]struct{}, error) {
+		return ssR.spentAfter(height)
+	}
+	if snapshot.UtxoHash, err = utxoSetHash(ssR.utxoStore, snapshot.UtxoSeqs, spentAfter); err != nil {
+		return nil, err
+	}
+	ssR.snapshot = snapshot
+	return snapshot, nil
+}
+
+// loadNodeData returns the trie nodes and contract codes of the hashes found
+// in db, it stops once the response grows over softResponseLimit.
+func loadNodeData(db dbm.DB, hashes []common.Hash) [][]byte {
+	data := make([][]byte, 0, len(hashes))
+	size := 0
+	for i, hash := range hashes {
+		if i == maxNodeDataPerMsg || size >= softResponseLimit {
+			break
+		}
+		blob, err := db.Load(hash[:])
+		if err != nil || len(blob) == 0 {
+			continue
+		}
+		data = append(data, blob)
+		size += len(blob)
+	}
+	return data
+}
+
+func (ssR *StateSyncReactor) loadUtxoOutputs(token common.Address, from uint64, count uint64) []*types.UTXOOutputData {
+	if count > maxUtxoOutputsPerMsg {
+		count = maxUtxoOutputsPerMsg
+	}
+	maxSeq := ssR.utxoStore.GetMaxUtxoOutputSeq(token)
+	outputs := make([]*types.UTXOOutputData, 0, count)
+	for seq := from; seq < from+count && int64(seq) <= maxSeq; seq++ {
+		output, err := ssR.utxoStore.GetUtxoOutput(token, seq)
+		if err != nil {
+			break
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+// loadKeyImages returns a page of the key images spent up to the block at height.
+func (ssR *StateSyncReactor) loadKeyImages(height uint64, start []byte) ([]lctypes.Key, []byte, error) {
+	kImgs, next := ssR.utxoStore.KeyImages(start, maxKeyImagesPerMsg)
+	// A key image in the UTXO store belongs to a block already in the block
+	// store, so the blocks are walked after the key images are read.
+	spent, err := ssR.spentAfter(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	filtered := make([]lctypes.Key, 0, len(kImgs))
+	for _, kImg := range kImgs {
+		if _, ok := spent[kImg]; !ok {
+			filtered = append(filtered, kImg)
+		}
+	}
+	return filtered, next, nil
+}
+
+// spentKeyImages are the key images spent by the blocks above height up to top.
+type spentKeyImages struct {
+	height uint64
+	top    uint64
+	kImgs  map[lctypes.Key]struct{}
+}
+
+// spentAfter returns the key images spent by the blocks above height, they
+// are not part of the snapshot at height.
+func (ssR *StateSyncReactor) spentAfter(height uint64) (map[lctypes.Key]struct{}, error) {
+	ssR.spentMtx.Lock()
+	defer ssR.spentMtx.Unlock()
+
+	if ssR.spent == nil || ssR.spent.height != height {
+		ssR.spent = &spentKeyImages{
+			height: height,
+			top:    height,
+			kImgs:  make(map[lctypes.Key]struct{}),
+		}
+	}
+	spent := ssR.spent
+	for h := spent.top + 1; h <= ssR.blockStore.Height(); h++ {
+		block := ssR.blockStore.LoadBlock(h)
+		if block == nil {
+			return nil, fmt.Errorf("block %d not found", h)
+		}
+		for _, tx := range block.Txs {
+			if utxoTx, ok := tx.(*types.UTXOTransaction); ok {
+				for _, kImg := range utxoTx.GetInputKeyImages() {
+					spent.kImgs[*kImg] = struct{}{}
+				}
+			}
+		}
+		spent.top = h
+	}
+	return spent.kImgs, nil
+}
+
+// SetLogger implements cmn.Service.
+func (ssR *StateSyncReactor) SetLogger(l log.Logger) {
+	ssR.BaseService.Logger = l
+}
+
+//-----------------------------------------------------------------------------
+// Messages
+
+// StateSyncMessage is a generic message for this reactor.
+type StateSyncMessage interface{}
+
+func RegisterStateSyncMessages() {
+	ser.RegisterInterface((*StateSyncMessage)(nil), nil)
+	ser.RegisterConcrete(&ssSnapshotRequestMessage{}, "statesync/SnapshotRequest", nil)
+	ser.RegisterConcrete(&ssSnapshotResponseMessage{}, "statesync/SnapshotResponse", nil)
+	ser.RegisterConcrete(&ssNodeDataRequestMessage{}, "statesync/NodeDataRequest", nil)
+	ser.RegisterConcrete(&ssNodeDataResponseMessage{}, "statesync/NodeDataResponse", nil)
+	ser.RegisterConcrete(&ssUtxoOutputsRequestMessage{}, "statesync/UtxoOutputsRequest", nil)
+	ser.RegisterConcrete(&ssUtxoOutputsResponseMessage{}, "statesync/UtxoOutputsResponse", nil)
+	ser.RegisterConcrete(&ssKeyImagesRequestMessage{}, "statesync/KeyImagesRequest", nil)
+	ser.RegisterConcrete(&ssKeyImagesResponseMessage{}, "statesync/KeyImagesResponse", nil)
+}
+
+// decodeMsg decodes StateSyncMessage.
+func decodeMsg(bz []byte) (msg StateSyncMessage, err error) {
+	if len(bz) > maxMsgSize {
+		return msg, fmt.Errorf("Msg exceeds max size (%d > %d)",
+			len(bz), maxMsgSize)
+	}
+	err = ser.DecodeBytesWithType(bz, &msg)
+	return
+}
+
+func encodeMsg(msg StateSyncMessage) []byte {
+	return ser.MustEncodeToBytesWithType(msg)
+}
+
+type peerResponse struct {
+	peerID string
+	msg    StateSyncMessage
+}
+
+//-------------------------------------
+
+type ssSnapshotRequestMessage struct {
+	Height uint64
+}
+
+func (m *ssSnapshotRequestMessage) String() string {
+	return cmn.Fmt("[ssSnapshotRequestMessage %v]", m.Height)
+}
+
+type ssSnapshotResponseMessage struct {
+	Snapshot *Snapshot
+}
+
+func (m *ssSnapshotResponseMessage) String() string {
+	return cmn.Fmt("[ssSnapshotResponseMessage %v]", m.Snapshot.Height())
+}
+
+//-------------------------------------
+
+type ssNodeDataRequestMessage struct {
+	Hashes []common.Hash
+}
+
+func (m *ssNodeDataRequestMessage) String() string {
+	return cmn.Fmt("[ssNodeDataRequestMessage %v]", len(m.Hashes))
+}
+
+type ssNodeDataResponseMessage struct {
+	Data [][]byte
+}
+
+func (m *ssNodeDataResponseMessage) String() string {
+	return cmn.Fmt("[ssNodeDataResponseMessage %v]", len(m.Data))
+}
+
+//-------------------------------------
+
+type ssUtxoOutputsRequestMessage struct {
+	Token common.Address
+	From  uint64
+	Count uint64
+}
+
+func (m *ssUtxoOutputsRequestMessage) String() string {
+	return cmn.Fmt("[ssUtxoOutputsRequestMessage %v %v+%v]", m.Token.String(), m.From, m.Count)
+}
+
+type ssUtxoOutputsResponseMessage struct {
+	Token   common.Address
+	From    uint64
+	Outputs []*types.UTXOOutputData
+}
+
+func (m *ssUtxoOutputsResponseMessage) String() string {
+	return cmn.Fmt("[ssUtxoOutputsResponseMessage %v %v+%v]", m.Token.String(), m.From, len(m.Outputs))
+}
+
+//-------------------------------------
+
+type ssKeyImagesRequestMessage struct {
+	Height uint64
+	Start  []byte
+}
+
+func (m *ssKeyImagesRequestMessage) String() string {
+	return cmn.Fmt("[ssKeyImagesRequestMessage %v %X]", m.Height, m.Start)
+}
+
+type ssKeyImagesResponseMessage struct {
+	Height    uint64
+	Start     []byte
+	KeyImages []lctypes.Key
+	Next      []byte
+}
+
+func (m *ssKeyImagesResponseMessage) String() string {
+	return cmn.Fmt("[ssKeyImagesResponseMessage %v %X+%v]", m.Height, m.Start, len(m.KeyImages))
+}
@@ -0,0 +1,215 @@
+package statesync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	cs "github.com/lianxiangcloud/linkchain/consensus"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	lctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	"github.com/lianxiangcloud/linkchain/libs/ser"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
+	"golang.org/x/crypto/sha3"
+)
+
+var errInvalidSnapshot = errors.New("invalid snapshot")
+
+// Snapshot is a committed block together with everything a node needs,
+// besides the state trie and the UTXO store, to continue the chain from it.
+type Snapshot struct {
+	Block      *types.Block
+	SeenCommit *types.Commit
+	Receipts   []*types.ReceiptForStorage
+	TxsResult  *types.TxsResult
+	Status     cs.NewStatus
+
+	// the block after the snapshot block and its commit, its header commits to
+	// the TrieRoot once ForkStateRoot is active, else it is run on the restored
+	// state before the sync completes
+	Next       *types.Block
+	NextCommit *types.Commit
+
+	// max UTXO output seq of every token after the block
+	UtxoSeqs []UtxoSeq
+	// hash of the UTXO set after the block, see utxoSetHash
+	UtxoHash common.Hash
+}
+
+// UtxoSeq is the max UTXO output seq of a token.
+type UtxoSeq struct {
+	Token  common.Address
+	MaxSeq uint64
+}
+
+func makeUtxoSeqs(tokenSeqMap map[string]int64) []UtxoSeq {
+	seqs := make([]UtxoSeq, 0, len(tokenSeqMap))
+	for tokenId, seq := range tokenSeqMap {
+		if seq < 0 {
+			continue
+		}
+		seqs = append(seqs, UtxoSeq{Token: common.HexToAddress(tokenId), MaxSeq: uint64(seq)})
+	}
+	sort.Slice(seqs, func(i, j int) bool {
+		return bytes.Compare(seqs[i].Token[:], seqs[j].Token[:]) < 0
+	})
+	return seqs
+}
+
+// Height returns the height of the snapshot block.
+func (s *Snapshot) Height() uint64 {
+	if s == nil || s.Block == nil {
+		return 0
+	}
+	return s.Block.Height
+}
+
+// TrieRoot returns the root of the state trie after the snapshot block.
+func (s *Snapshot) TrieRoot() common.Hash {
+	return s.TxsResult.TrieRoot
+}
+
+// GetReceipts returns the receipts of the snapshot block.
+func (s *Snapshot) GetReceipts() *types.Receipts {
+	receipts := make(types.Receipts, len(s.Receipts))
+	for i, r := range s.Receipts {
+		receipts[i] = r.ToReceipt()
+	}
+	return &receipts
+}
+
+// Verify checks that the snapshot block is the trusted block of the node
+// configuration, that it and the next block were committed on chainID and
+// that the results and the validators of the snapshot are the ones of the
+// block headers. Once ForkStateRoot is active in forks, the TrieRoot is the
+// StateHash of the next header. Before, it and the UTXO set are trusted once
+// enough peers offer the same snapshot, and the restored state is checked by
+// running the next block on it.
+func (s *Snapshot) Verify(chainID string, trustHeight uint64, trustHash common.Hash, forks types.Forks) error {
+	if s == nil || s.Block == nil || s.Block.Header == nil || s.SeenCommit == nil || s.TxsResult == nil ||
+		s.Status.IsEmpty() || s.Status.LastValidators == nil || s.Status.Validators == nil ||
+		s.Next == nil || s.Next.Header == nil || s.NextCommit == nil {
+		return errInvalidSnapshot
+	}
+	header := s.Block.Header
+	if header.ChainID != chainID || s.Status.ChainID != chainID {
+		return fmt.Errorf("Wrong chain id, want %v, got %v/%v", chainID, header.ChainID, s.Status.ChainID)
+	}
+	if header.Height != trustHeight || s.Block.Hash() != trustHash {
+		return fmt.Errorf("Untrusted block, want %v at %v, got %v at %v", trustHash, trustHeight, s.Block.Hash(), header.Height)
+	}
+	if header.Height <= types.BlockHeightZero || s.Status.LastBlockHeight != header.Height {
+		return fmt.Errorf("Wrong status height, want %v, got %v", header.Height, s.Status.LastBlockHeight)
+	}
+	blockID := s.Status.LastBlockID
+	if blockID.Hash != s.Block.Hash() {
+		return fmt.Errorf("Wrong block hash, want %v, got %v", s.Block.Hash(), blockID.Hash)
+	}
+	if hash := common.BytesToHash(s.Status.LastValidators.Hash()); hash != header.ValidatorsHash {
+		return fmt.Errorf("Wrong validators, want hash %v, got %v", header.ValidatorsHash, hash)
+	}
+	if err := s.Status.LastValidators.VerifyCommit(chainID, blockID, header.Height, s.SeenCommit); err != nil {
+		return err
+	}
+
+	if s.TxsResult.StateHash != header.StateHash {
+		return fmt.Errorf("Wrong state hash, want %v, got %v", header.StateHash, s.TxsResult.StateHash)
+	}
+	if s.TxsResult.ReceiptHash != header.ReceiptHash {
+		return fmt.Errorf("Wrong receipts hash, want %v, got %v", header.ReceiptHash, s.TxsResult.ReceiptHash)
+	}
+	if s.TxsResult.GasUsed != header.GasUsed {
+		return fmt.Errorf("Wrong gas used, want %v, got %v", header.GasUsed, s.TxsResult.GasUsed)
+	}
+	if hash := s.GetReceipts().Hash(); hash != header.ReceiptHash {
+		return fmt.Errorf("Wrong receipts, want hash %v, got %v", header.ReceiptHash, hash)
+	}
+	return s.verifyNext(chainID, forks)
+}
+
+// verifyNext checks that the next block follows the snapshot block and was
+// committed by the validators of the snapshot status.
+func (s *Snapshot) verifyNext(chainID string, forks types.Forks) error {
+	next := s.Next.Header
+	if next.ChainID != chainID || next.Height != s.Height()+1 || next.ParentHash != s.Block.Hash() {
+		return fmt.Errorf("Wrong next block %v at %v, parent %v", s.Next.Hash(), next.Height, next.ParentHash)
+	}
+	if hash := common.BytesToHash(s.Status.Validators.Hash()); hash != next.ValidatorsHash {
+		return fmt.Errorf("Wrong next validators, want hash %v, got %v", next.ValidatorsHash, hash)
+	}
+	blockID := s.NextCommit.BlockID
+	if blockID.Hash != s.Next.Hash() {
+		return fmt.Errorf("Wrong next commit, want block %v, got %v", s.Next.Hash(), blockID.Hash)
+	}
+	if err := s.Status.Validators.VerifyCommit(chainID, blockID, next.Height, s.NextCommit); err != nil {
+		return err
+	}
+	if forks.IsActive(types.ForkStateRoot, next.Height) && next.StateHash != s.TrieRoot() {
+		return fmt.Errorf("Wrong trie root, want %v, got %v", next.StateHash, s.TrieRoot())
+	}
+	return nil
+}
+
+// snapshotDigest is the part of a snapshot every peer serves identically,
+// the seen commits of the peers may hold different precommits.
+type snapshotDigest struct {
+	BlockHash common.Hash
+	TxsResult *types.TxsResult
+	Status    []byte
+	UtxoSeqs  []UtxoSeq
+	UtxoHash  common.Hash
+}
+
+// digest identifies the snapshot among the offers of the peers.
+func (s *Snapshot) digest() common.Hash {
+	return types.RlpHash(&snapshotDigest{
+		BlockHash: s.Block.Hash(),
+		TxsResult: s.TxsResult,
+		Status:    s.Status.Bytes(),
+		UtxoSeqs:  s.UtxoSeqs,
+		UtxoHash:  s.UtxoHash,
+	})
+}
+
+// utxoSetHash hashes the UTXO set of a snapshot: the outputs of every token
+// up to its max seq, then the spent key images in the store order but the
+// ones spent by the blocks above the snapshot, as returned by spentAfter.
+func utxoSetHash(store *utxo.UtxoStore, seqs []UtxoSeq, spentAfter func() (map[lctypes.Key]struct{}, error)) (common.Hash, error) {
+	hw := sha3.NewLegacyKeccak256()
+	for _, seq := range seqs {
+		hw.Write(seq.Token[:])
+		for i := uint64(0); i <= seq.MaxSeq; i++ {
+			output, err := store.GetUtxoOutput(seq.Token, i)
+			if err != nil {
+				return common.EmptyHash, fmt.Errorf("UTXO output %d of %v: %v", i, seq.Token.String(), err)
+			}
+			bz, err := ser.EncodeToBytes(output)
+			if err != nil {
+				return common.EmptyHash, err
+			}
+			hw.Write(bz)
+		}
+	}
+	var start []byte
+	for {
+		kImgs, next := store.KeyImages(start, maxKeyImagesPerMsg)
+		spent, err := spentAfter()
+		if err != nil {
+			return common.EmptyHash, err
+		}
+		for _, kImg := range kImgs {
+			if _, ok := spent[kImg]; !ok {
+				hw.Write(kImg[:])
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		start = next
+	}
+	var hash common.Hash
+	hw.Sum(hash[:0])
+	return hash, nil
+}
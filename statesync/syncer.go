@@ -0,0 +1,494 @@
+package statesync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	cs "github.com/lianxiangcloud/linkchain/consensus"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	lctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	"github.com/lianxiangcloud/linkchain/libs/p2p"
+	"github.com/lianxiangcloud/linkchain/libs/trie"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+const (
+	// ask the peers for their snapshot every 5s until one is trusted
+	snapshotRequestIntervalSeconds = 5
+	// check for timed out requests
+	trySyncIntervalMS = 100
+	// wait before starting over after a failed sync
+	retryIntervalSeconds = 10
+
+	maxSnapshotOffers = 64
+)
+
+// unverifiedSnapshotKey holds the height of a snapshot restored in the stores
+// until the next block has run on it.
+var unverifiedSnapshotKey = []byte("statesync_unverified")
+
+var (
+	errQuit    = errors.New("state sync stopped")
+	errNoPeers = errors.New("no peer left serving the snapshot")
+)
+
+// syncRoutine restores a snapshot from the peers, starting over on failure.
+func (ssR *StateSyncReactor) syncRoutine() {
+	for {
+		s := &syncer{
+			ssR:      ssR,
+			inflight: make(map[string]*ssRequest),
+		}
+		err := s.sync()
+		if err == nil || err == errQuit {
+			return
+		}
+		ssR.Logger.Error("State sync failed", "err", err)
+		select {
+		case <-time.After(retryIntervalSeconds * time.Second):
+		case <-ssR.Quit():
+			return
+		}
+	}
+}
+
+type ssRequest struct {
+	msg  StateSyncMessage
+	sent time.Time
+}
+
+// syncer fetches one snapshot, keeping at most one request in flight per peer.
+type syncer struct {
+	ssR *StateSyncReactor
+
+	// peers offering the snapshot
+	peers    []string
+	inflight map[string]*ssRequest
+}
+
+func (s *syncer) sync() error {
+	ssR := s.ssR
+	snapshot, err := s.discoverSnapshot()
+	if err != nil {
+		return err
+	}
+	ssR.Logger.Info("Syncing snapshot", "height", snapshot.Height(), "hash", snapshot.Block.Hash(),
+		"trieRoot", snapshot.TrieRoot(), "peers", s.peers)
+
+	// The key images first, the peers filter out the ones spent above the
+	// snapshot height and that gets longer as the chain grows.
+	if err := s.syncKeyImages(snapshot.Height()); err != nil {
+		return err
+	}
+	if err := s.syncUtxoOutputs(snapshot.UtxoSeqs); err != nil {
+		return err
+	}
+	if err := s.verifyUtxoSet(snapshot); err != nil {
+		return err
+	}
+	if err := s.syncTrie(snapshot.TrieRoot()); err != nil {
+		return err
+	}
+	if err := state.VerifyState(snapshot.TrieRoot(), ssR.stateDB); err != nil {
+		return err
+	}
+	return s.restore(snapshot)
+}
+
+// discoverSnapshot asks the peers for the snapshot of the trusted block until
+// TrustPeers of them offer the same one.
+func (s *syncer) discoverSnapshot() (*Snapshot, error) {
+	ssR := s.ssR
+	type offer struct {
+		snapshot *Snapshot
+		peers    map[string]bool
+	}
+	offers := make(map[common.Hash]*offer)
+
+	requestTicker := time.NewTicker(snapshotRequestIntervalSeconds * time.Second)
+	defer requestTicker.Stop()
+	trustHeight, trustHash := ssR.config.TrustHeight, common.HexToHash(ssR.config.TrustHash)
+	ssR.sw.Broadcast(StateSyncChannel, encodeMsg(&ssSnapshotRequestMessage{trustHeight}))
+	for {
+		select {
+		case <-requestTicker.C:
+			ssR.sw.Broadcast(StateSyncChannel, encodeMsg(&ssSnapshotRequestMessage{trustHeight}))
+		case resp := <-ssR.responseCh:
+			msg, ok := resp.msg.(*ssSnapshotResponseMessage)
+			if !ok {
+				continue
+			}
+			snapshot := msg.Snapshot
+			if err := snapshot.Verify(ssR.chainID, trustHeight, trustHash, ssR.app.ForkSchedule()); err != nil {
+				ssR.Logger.Info("Invalid snapshot", "peer", resp.peerID, "height", snapshot.Height(), "err", err)
+				if peer := ssR.sw.Peers().GetByID(resp.peerID); peer != nil {
+					ssR.sw.StopPeerForError(peer, err)
+				}
+				continue
+			}
+			if snapshot.Height() <= ssR.app.Height() {
+				continue
+			}
+			if len(offers) >= maxSnapshotOffers {
+				offers = make(map[common.Hash]*offer)
+			}
+			digest := snapshot.digest()
+			o := offers[digest]
+			if o == nil {
+				o = &offer{snapshot: snapshot, peers: make(map[string]bool)}
+				offers[digest] = o
+			}
+			o.peers[resp.peerID] = true
+			ssR.Logger.Debug("Snapshot offered", "peer", resp.peerID, "height", snapshot.Height(), "peers", len(o.peers))
+			if len(o.peers) >= ssR.config.TrustPeers {
+				for peerID := range o.peers {
+					s.peers = append(s.peers, peerID)
+				}
+				return o.snapshot, nil
+			}
+		case <-ssR.Quit():
+			return nil, errQuit
+		}
+	}
+}
+
+// syncKeyImages fetches the key images spent up to the snapshot height page by page.
+func (s *syncer) syncKeyImages(height uint64) error {
+	ssR := s.ssR
+	start := []byte{}
+	done := false
+	synced := 0
+	return s.fetch(func(peer p2p.Peer) bool {
+		if done || len(s.inflight) > 0 {
+			return false
+		}
+		return s.send(peer, &ssKeyImagesRequestMessage{Height: height, Start: start})
+	}, func(peerID string, msg StateSyncMessage, req StateSyncMessage) error {
+		resp, ok := msg.(*ssKeyImagesResponseMessage)
+		if !ok {
+			return nil
+		}
+		if resp.Height != height || !bytes.Equal(resp.Start, start) {
+			s.dropPeer(peerID)
+			return nil
+		}
+		kImgs := make([]*lctypes.Key, len(resp.KeyImages))
+		for i := range resp.KeyImages {
+			kImgs[i] = &resp.KeyImages[i]
+		}
+		if err := ssR.utxoStore.SaveKImages(kImgs); err != nil {
+			return err
+		}
+		synced += len(kImgs)
+		if len(resp.Next) == 0 {
+			done = true
+			ssR.Logger.Info("Key images synced", "count", synced)
+		} else {
+			start = resp.Next
+		}
+		return nil
+	}, func(req StateSyncMessage) {}, func() bool {
+		return done
+	})
+}
+
+// syncUtxoOutputs fetches the UTXO outputs of every token in seq order, the
+// outputs already saved by an interrupted sync are kept.
+func (s *syncer) syncUtxoOutputs(seqs []UtxoSeq) error {
+	ssR := s.ssR
+	next := make(map[common.Address]uint64)
+	maxSeqs := make(map[common.Address]uint64)
+	for _, seq := range seqs {
+		from := uint64(ssR.utxoStore.GetMaxUtxoOutputSeq(seq.Token) + 1)
+		if from <= seq.MaxSeq {
+			next[seq.Token] = from
+			maxSeqs[seq.Token] = seq.MaxSeq
+		}
+	}
+	busy := make(map[common.Address]bool)
+	return s.fetch(func(peer p2p.Peer) bool {
+		for token, from := range next {
+			if busy[token] {
+				continue
+			}
+			count := maxSeqs[token] - from + 1
+			if count > maxUtxoOutputsPerMsg {
+				count = maxUtxoOutputsPerMsg
+			}
+			if !s.send(peer, &ssUtxoOutputsRequestMessage{Token: token, From: from, Count: count}) {
+				return false
+			}
+			busy[token] = true
+			return true
+		}
+		return false
+	}, func(peerID string, msg StateSyncMessage, req StateSyncMessage) error {
+		resp, ok := msg.(*ssUtxoOutputsResponseMessage)
+		if !ok {
+			return nil
+		}
+		request := req.(*ssUtxoOutputsRequestMessage)
+		busy[request.Token] = false
+		if !validUtxoOutputs(request, resp) {
+			s.dropPeer(peerID)
+			return nil
+		}
+		if err := ssR.utxoStore.RestoreUtxoOutputs(resp.Outputs); err != nil {
+			return err
+		}
+		next[request.Token] += uint64(len(resp.Outputs))
+		if next[request.Token] > maxSeqs[request.Token] {
+			delete(next, request.Token)
+			ssR.Logger.Info("UTXO outputs synced", "token", request.Token.String(), "maxSeq", maxSeqs[request.Token])
+		}
+		return nil
+	}, func(req StateSyncMessage) {
+		busy[req.(*ssUtxoOutputsRequestMessage).Token] = false
+	}, func() bool {
+		return len(next) == 0
+	})
+}
+
+func validUtxoOutputs(req *ssUtxoOutputsRequestMessage, resp *ssUtxoOutputsResponseMessage) bool {
+	if resp.Token != req.Token || resp.From != req.From || len(resp.Outputs) == 0 || uint64(len(resp.Outputs)) > req.Count {
+		return false
+	}
+	for _, output := range resp.Outputs {
+		if output == nil || output.TokenID != req.Token {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyUtxoSet checks the restored UTXO set against the hash of the
+// snapshot. The restored set is dropped on mismatch, so that the next sync
+// fetches it again.
+func (s *syncer) verifyUtxoSet(snapshot *Snapshot) error {
+	utxoStore := s.ssR.utxoStore
+	hash, err := utxoSetHash(utxoStore, snapshot.UtxoSeqs, func() (map[lctypes.Key]struct{}, error) {
+		return nil, nil
+	})
+	if err == nil && hash != snapshot.UtxoHash {
+		err = fmt.Errorf("Wrong UTXO set, want hash %v, got %v", snapshot.UtxoHash, hash)
+	}
+	if err != nil {
+		if clearErr := utxoStore.ClearUtxo(); clearErr != nil {
+			return clearErr
+		}
+		return err
+	}
+	return nil
+}
+
+// syncTrie fetches the trie nodes and contract codes of the state at root.
+func (s *syncer) syncTrie(root common.Hash) error {
+	ssR := s.ssR
+	sched := state.NewStateSync(root, ssR.stateDB)
+	var retry []common.Hash
+	synced := 0
+	return s.fetch(func(peer p2p.Peer) bool {
+		hashes := retry
+		if len(hashes) > maxNodeDataPerMsg {
+			hashes = hashes[:maxNodeDataPerMsg]
+		}
+		retry = retry[len(hashes):]
+		if len(hashes) < maxNodeDataPerMsg {
+			hashes = append(hashes, sched.Missing(maxNodeDataPerMsg-len(hashes))...)
+		}
+		if len(hashes) == 0 {
+			return false
+		}
+		if !s.send(peer, &ssNodeDataRequestMessage{Hashes: hashes}) {
+			retry = append(retry, hashes...)
+			return false
+		}
+		return true
+	}, func(peerID string, msg StateSyncMessage, req StateSyncMessage) error {
+		resp, ok := msg.(*ssNodeDataResponseMessage)
+		if !ok {
+			return nil
+		}
+		results, missing := matchNodeData(req.(*ssNodeDataRequestMessage).Hashes, resp.Data)
+		retry = append(retry, missing...)
+		if len(results) == 0 {
+			return nil
+		}
+		if _, _, err := sched.Process(results); err != nil {
+			return err
+		}
+		written, err := sched.Commit(ssR.stateDB)
+		if err != nil {
+			return err
+		}
+		synced += written
+		ssR.Logger.Debug("Trie nodes synced", "count", synced, "pending", sched.Pending())
+		return nil
+	}, func(req StateSyncMessage) {
+		retry = append(retry, req.(*ssNodeDataRequestMessage).Hashes...)
+	}, func() bool {
+		return sched.Pending() == 0
+	})
+}
+
+// matchNodeData pairs the delivered data with the requested hashes, data
+// not hashing to a requested entry is dropped. It also returns the hashes
+// that were not delivered.
+func matchNodeData(requested []common.Hash, data [][]byte) ([]trie.SyncResult, []common.Hash) {
+	want := make(map[common.Hash]bool, len(requested))
+	for _, hash := range requested {
+		want[hash] = true
+	}
+	results := make([]trie.SyncResult, 0, len(data))
+	for _, blob := range data {
+		hash := crypto.Keccak256Hash(blob)
+		if !want[hash] {
+			continue
+		}
+		delete(want, hash)
+		results = append(results, trie.SyncResult{Hash: hash, Data: blob})
+	}
+	missing := make([]common.Hash, 0, len(want))
+	for _, hash := range requested {
+		if want[hash] {
+			missing = append(missing, hash)
+		}
+	}
+	return results, missing
+}
+
+// fetch runs a download loop: assign is called with idle peers until it
+// sends nothing, handle gets the responses of the peers, expire gets the
+// requests that timed out and done tells when the download is complete.
+func (s *syncer) fetch(assign func(peer p2p.Peer) bool,
+	handle func(peerID string, msg StateSyncMessage, req StateSyncMessage) error,
+	expire func(req StateSyncMessage), done func() bool) error {
+
+	ssR := s.ssR
+	ticker := time.NewTicker(trySyncIntervalMS * time.Millisecond)
+	defer ticker.Stop()
+	for !done() {
+		for {
+			peer := s.idlePeer()
+			if peer == nil || !assign(peer) {
+				break
+			}
+		}
+		if len(s.peers) == 0 {
+			return errNoPeers
+		}
+
+		select {
+		case resp := <-ssR.responseCh:
+			req := s.inflight[resp.peerID]
+			if req == nil || reflect.TypeOf(req.msg) != requestTypes[reflect.TypeOf(resp.msg)] {
+				continue
+			}
+			delete(s.inflight, resp.peerID)
+			if err := handle(resp.peerID, resp.msg, req.msg); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			for peerID, req := range s.inflight {
+				if time.Since(req.sent) > ssR.config.Timeout() {
+					ssR.Logger.Info("Request timed out", "peer", peerID, "msg", req.msg)
+					delete(s.inflight, peerID)
+					s.dropPeer(peerID)
+					expire(req.msg)
+				}
+			}
+		case <-ssR.Quit():
+			return errQuit
+		}
+	}
+	return nil
+}
+
+func (s *syncer) idlePeer() p2p.Peer {
+	for _, peerID := range s.peers {
+		if _, busy := s.inflight[peerID]; busy {
+			continue
+		}
+		if peer := s.ssR.sw.Peers().GetByID(peerID); peer != nil {
+			return peer
+		}
+	}
+	return nil
+}
+
+func (s *syncer) send(peer p2p.Peer, msg StateSyncMessage) bool {
+	if !peer.TrySend(StateSyncChannel, encodeMsg(msg)) {
+		return false
+	}
+	s.inflight[peer.ID()] = &ssRequest{msg: msg, sent: time.Now()}
+	return true
+}
+
+// dropPeer stops asking a peer that timed out or misbehaved.
+func (s *syncer) dropPeer(peerID string) {
+	for i, id := range s.peers {
+		if id == peerID {
+			s.peers = append(s.peers[:i], s.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+// restore saves the snapshot block and status, then hands off to fast sync
+// which catches up with the chain and switches to consensus.
+func (s *syncer) restore(snapshot *Snapshot) error {
+	ssR := s.ssR
+	status := snapshot.Status
+	block := snapshot.Block
+	blockParts := block.MakePartSet(status.ConsensusParams.BlockPartSizeBytes)
+	if !blockParts.HasHeader(status.LastBlockID.PartsHeader) {
+		return errInvalidSnapshot
+	}
+	if _, err := state.New(snapshot.TrieRoot(), state.NewKeyValueDBWithCache(ssR.stateDB, 0, true, 0)); err != nil {
+		return err
+	}
+	if err := ssR.utxoStore.RestoreMaxUtxoOutputSeqs(block.Height); err != nil {
+		return err
+	}
+
+	// the TrieRoot is not committed in the next header before ForkStateRoot,
+	// the sync completes once the next block has run on the restored state
+	runNext := !ssR.app.ForkSchedule().IsActive(types.ForkStateRoot, snapshot.Next.Height)
+	if runNext {
+		ssR.statusDB.SetSync(unverifiedSnapshotKey, []byte(strconv.FormatUint(block.Height, 10)))
+	}
+	ssR.blockStore.SaveSnapshotBlock(block, blockParts, snapshot.SeenCommit, snapshot.GetReceipts(), snapshot.TxsResult)
+	cs.SaveStatus(ssR.statusDB, status)
+	if err := ssR.app.Reload(); err != nil {
+		// the stores hold the snapshot, it is loaded on restart
+		ssR.Logger.Error("Reload app failed, restart the node", "height", block.Height, "err", err)
+		return errQuit
+	}
+	if runNext {
+		if !ssR.app.CheckBlock(snapshot.Next) {
+			ssR.Logger.Error("The restored state does not run the next block, remove the data and sync again",
+				"height", block.Height, "trieRoot", snapshot.TrieRoot())
+			return errQuit
+		}
+		ssR.statusDB.DeleteSync(unverifiedSnapshotKey)
+	}
+	atomic.StoreUint32(&ssR.syncing, 0)
+	ssR.Logger.Info("State sync done", "height", block.Height, "hash", block.Hash())
+
+	bcR := ssR.sw.Reactor("BLOCKCHAIN").(blockchainReactor)
+	return bcR.RestartFastSync(status.Copy())
+}
+
+// requestTypes maps the responses to the requests they answer.
+var requestTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(&ssNodeDataResponseMessage{}):    reflect.TypeOf(&ssNodeDataRequestMessage{}),
+	reflect.TypeOf(&ssUtxoOutputsResponseMessage{}): reflect.TypeOf(&ssUtxoOutputsRequestMessage{}),
+	reflect.TypeOf(&ssKeyImagesResponseMessage{}):   reflect.TypeOf(&ssKeyImagesRequestMessage{}),
+}
@@ -0,0 +1,274 @@
+package statesync
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	cs "github.com/lianxiangcloud/linkchain/consensus"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	lctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/utxo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestState(t *testing.T, db dbm.DB) (common.Hash, []common.Address) {
+	st, err := state.New(common.EmptyHash, state.NewKeyValueDBWithCache(db, 0, true, 0))
+	require.Nil(t, err)
+
+	addrs := make([]common.Address, 0, 32)
+	for i := byte(1); i <= 32; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		st.AddBalance(addr, big.NewInt(int64(i)))
+		if i%4 == 0 {
+			st.SetCode(addr, []byte{i, i, i})
+			st.SetState(addr, common.BytesToHash([]byte{i}), []byte{i})
+		}
+		addrs = append(addrs, addr)
+	}
+	root, err := st.Commit(false, 1)
+	require.Nil(t, err)
+	require.Nil(t, st.Database().TrieDB().Commit(root, false))
+	return root, addrs
+}
+
+func TestTrieSync(t *testing.T) {
+	srcDB := dbm.NewMemDB()
+	root, addrs := makeTestState(t, srcDB)
+
+	dstDB := dbm.NewMemDB()
+	sched := state.NewStateSync(root, dstDB)
+	for sched.Pending() > 0 {
+		hashes := sched.Missing(maxNodeDataPerMsg)
+		require.NotEmpty(t, hashes, "pending entries not scheduled")
+
+		results, missing := matchNodeData(hashes, loadNodeData(srcDB, hashes))
+		require.Empty(t, missing, "entries not served")
+		_, _, err := sched.Process(results)
+		require.Nil(t, err)
+		_, err = sched.Commit(dstDB)
+		require.Nil(t, err)
+	}
+
+	st, err := state.New(root, state.NewKeyValueDBWithCache(dstDB, 0, true, 0))
+	require.Nil(t, err)
+	for i, addr := range addrs {
+		n := byte(i + 1)
+		assert.Equal(t, big.NewInt(int64(n)), st.GetBalance(addr), "balance of %v", addr)
+		if n%4 == 0 {
+			assert.Equal(t, []byte{n, n, n}, st.GetCode(addr), "code of %v", addr)
+			assert.Equal(t, []byte{n}, st.GetState(addr, common.BytesToHash([]byte{n})), "storage of %v", addr)
+		}
+	}
+}
+
+func TestMatchNodeData(t *testing.T) {
+	srcDB := dbm.NewMemDB()
+	root, _ := makeTestState(t, srcDB)
+	blob, err := srcDB.Load(root[:])
+	require.Nil(t, err)
+
+	other := common.HexToHash("0x01")
+	results, missing := matchNodeData([]common.Hash{root, other}, [][]byte{blob})
+	require.Len(t, results, 1)
+	assert.Equal(t, root, results[0].Hash)
+	assert.Equal(t, []common.Hash{other}, missing)
+
+	// data not hashing to the requested entry is dropped
+	tampered := append([]byte{}, blob...)
+	tampered[len(tampered)-1] ^= 0xff
+	results, missing = matchNodeData([]common.Hash{root}, [][]byte{tampered})
+	assert.Empty(t, results)
+	assert.Equal(t, []common.Hash{root}, missing)
+}
+
+func TestVerifyState(t *testing.T) {
+	srcDB := dbm.NewMemDB()
+	root, _ := makeTestState(t, srcDB)
+	require.Nil(t, state.VerifyState(root, srcDB))
+
+	// a state missing a node of a storage trie
+	dstDB := dbm.NewMemDB()
+	for it := srcDB.Iterator(nil, nil); it.Valid(); it.Next() {
+		dstDB.Set(it.Key(), it.Value())
+	}
+	require.Nil(t, state.VerifyState(root, dstDB))
+	deleted := 0
+	for it := srcDB.Iterator(nil, nil); it.Valid(); it.Next() {
+		if common.BytesToHash(it.Key()) != root && deleted == 0 {
+			dstDB.Delete(it.Key())
+			if state.VerifyState(root, dstDB) != nil {
+				deleted++
+				continue
+			}
+			dstDB.Set(it.Key(), it.Value())
+		}
+	}
+	assert.Equal(t, 1, deleted)
+
+	// a node stored with other data than its hash
+	for it := srcDB.Iterator(nil, nil); it.Valid(); it.Next() {
+		key, blob := it.Key(), it.Value()
+		if common.BytesToHash(key) != root && crypto.Keccak256Hash(blob) == common.BytesToHash(key) {
+			tampered := append([]byte{}, blob...)
+			tampered[len(tampered)-1] ^= 0xff
+			srcDB.Set(key, tampered)
+			break
+		}
+	}
+	assert.NotNil(t, state.VerifyState(root, srcDB))
+}
+
+func newTestUtxoStore() *utxo.UtxoStore {
+	store := utxo.NewUtxoStore(dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB())
+	store.SetLogger(log.NewNopLogger())
+	return store
+}
+
+func TestUtxoSetHash(t *testing.T) {
+	token := common.HexToAddress("0x01")
+	outputs := make([]*types.UTXOOutputData, 0, 8)
+	for i := byte(0); i < 8; i++ {
+		tokenID := common.EmptyAddress
+		if i%2 == 1 {
+			tokenID = token
+		}
+		outputs = append(outputs, &types.UTXOOutputData{OTAddr: lctypes.Key{i}, Height: uint64(i), TokenID: tokenID})
+	}
+	kImgs := []*lctypes.Key{{1}, {2}, {3}}
+	seqs := []UtxoSeq{{Token: common.EmptyAddress, MaxSeq: 3}, {Token: token, MaxSeq: 3}}
+	nothingSpent := func() (map[lctypes.Key]struct{}, error) { return nil, nil }
+
+	src := newTestUtxoStore()
+	require.Nil(t, src.SaveKImages(kImgs))
+	require.Nil(t, src.RestoreUtxoOutputs(outputs))
+	hash, err := utxoSetHash(src, seqs, nothingSpent)
+	require.Nil(t, err)
+
+	// the outputs restored in several pages
+	dst := newTestUtxoStore()
+	require.Nil(t, dst.SaveKImages(kImgs))
+	require.Nil(t, dst.RestoreUtxoOutputs(outputs[:3]))
+	require.Nil(t, dst.RestoreUtxoOutputs(outputs[3:]))
+	dstHash, err := utxoSetHash(dst, seqs, nothingSpent)
+	require.Nil(t, err)
+	assert.Equal(t, hash, dstHash)
+
+	// the key images spent above the snapshot are left out
+	require.Nil(t, src.SaveKImages([]*lctypes.Key{{4}}))
+	srcHash, err := utxoSetHash(src, seqs, func() (map[lctypes.Key]struct{}, error) {
+		return map[lctypes.Key]struct{}{{4}: {}}, nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, hash, srcHash)
+
+	// a missing key image or a different output
+	srcHash, err = utxoSetHash(src, seqs, nothingSpent)
+	require.Nil(t, err)
+	assert.NotEqual(t, hash, srcHash)
+	_, err = utxoSetHash(src, []UtxoSeq{{Token: token, MaxSeq: 4}}, nothingSpent)
+	assert.NotNil(t, err)
+
+	require.Nil(t, dst.ClearUtxo())
+	assert.Equal(t, int64(-1), dst.GetMaxUtxoOutputSeq(token))
+	assert.False(t, dst.HaveTxKeyimgAsSpent(kImgs[0]))
+	kImgsLeft, _ := dst.KeyImages(nil, 10)
+	assert.Empty(t, kImgsLeft)
+}
+
+func signTestCommit(t *testing.T, chainID string, vals *types.ValidatorSet, pv types.PrivValidator, height uint64, blockID types.BlockID) *types.Commit {
+	voteSet := types.NewVoteSet(chainID, height, 0, types.VoteTypePrecommit, vals)
+	addr := pv.GetAddress()
+	idx, _ := vals.GetByAddress(addr)
+	vote := &types.Vote{
+		ValidatorAddress: addr,
+		ValidatorIndex:   idx,
+		ValidatorSize:    vals.Size(),
+		Height:           height,
+		Type:             types.VoteTypePrecommit,
+		BlockID:          blockID,
+		Timestamp:        time.Now().UTC(),
+	}
+	require.Nil(t, pv.SignVote(chainID, vote))
+	_, err := voteSet.AddVote(vote)
+	require.Nil(t, err)
+	return voteSet.MakeCommit()
+}
+
+func TestSnapshotVerifyTrust(t *testing.T) {
+	const chainID = "statesync"
+	vals, privVals := types.RandValidatorSet(1, 10)
+	block := &types.Block{
+		Header: &types.Header{
+			ChainID:        chainID,
+			Height:         5,
+			ValidatorsHash: common.BytesToHash(vals.Hash()),
+			ReceiptHash:    types.Receipts{}.Hash(),
+		},
+		Data: &types.Data{},
+	}
+	blockID := types.BlockID{Hash: block.Hash()}
+	trieRoot := common.HexToHash("0x02")
+	next := &types.Block{
+		Header: &types.Header{
+			ChainID:        chainID,
+			Height:         6,
+			ParentHash:     block.Hash(),
+			ValidatorsHash: common.BytesToHash(vals.Hash()),
+			StateHash:      trieRoot,
+		},
+		Data: &types.Data{},
+	}
+	nextID := types.BlockID{Hash: next.Hash()}
+	snapshot := &Snapshot{
+		Block:      block,
+		SeenCommit: signTestCommit(t, chainID, vals, privVals[0], 5, types.BlockID{Hash: common.HexToHash("0x01")}),
+		TxsResult:  &types.TxsResult{ReceiptHash: block.ReceiptHash, TrieRoot: trieRoot},
+		Status: cs.NewStatus{
+			ChainID:         chainID,
+			LastBlockHeight: 5,
+			LastBlockID:     blockID,
+			Validators:      vals,
+			LastValidators:  vals,
+		},
+		Next:       next,
+		NextCommit: signTestCommit(t, chainID, vals, privVals[0], 6, nextID),
+	}
+	forks := types.Forks{{Name: types.ForkStateRoot, Height: 6}}
+	require.NotNil(t, snapshot.Verify(chainID, 5, blockID.Hash, forks), "commit of another block")
+
+	snapshot.SeenCommit = signTestCommit(t, chainID, vals, privVals[0], 5, blockID)
+	require.Nil(t, snapshot.Verify(chainID, 5, blockID.Hash, forks))
+
+	// a snapshot of another block than the trusted one
+	assert.NotNil(t, snapshot.Verify(chainID, 6, blockID.Hash, forks))
+	assert.NotNil(t, snapshot.Verify(chainID, 5, common.HexToHash("0x01"), forks))
+
+	// the trie root is committed in the next header once the fork is active
+	snapshot.TxsResult.TrieRoot = common.HexToHash("0x03")
+	assert.NotNil(t, snapshot.Verify(chainID, 5, blockID.Hash, forks))
+	assert.Nil(t, snapshot.Verify(chainID, 5, blockID.Hash, types.Forks{{Name: types.ForkStateRoot, Height: 7}}))
+	snapshot.TxsResult.TrieRoot = trieRoot
+
+	// a next block without commit or not following the snapshot block
+	snapshot.NextCommit = signTestCommit(t, chainID, vals, privVals[0], 6, types.BlockID{Hash: common.HexToHash("0x01")})
+	assert.NotNil(t, snapshot.Verify(chainID, 5, blockID.Hash, forks))
+	snapshot.NextCommit = nil
+	assert.NotNil(t, snapshot.Verify(chainID, 5, blockID.Hash, forks))
+	next.ParentHash = common.HexToHash("0x01")
+	snapshot.NextCommit = signTestCommit(t, chainID, vals, privVals[0], 6, types.BlockID{Hash: next.Hash()})
+	assert.NotNil(t, snapshot.Verify(chainID, 5, blockID.Hash, forks))
+	next.ParentHash = block.Hash()
+	snapshot.NextCommit = signTestCommit(t, chainID, vals, privVals[0], 6, nextID)
+
+	// validators other than the ones of the header
+	others, _ := types.RandValidatorSet(1, 10)
+	snapshot.Status.LastValidators = others
+	assert.NotNil(t, snapshot.Verify(chainID, 5, blockID.Hash, forks))
+}
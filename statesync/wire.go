@@ -0,0 +1,10 @@
+package statesync
+
+import (
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+func init() {
+	RegisterStateSyncMessages()
+	types.RegisterBlockAmino()
+}
@@ -88,6 +88,10 @@ func (b *EventBus) PublishEventLog(event EventDataLog) error {
 	return b.Publish(EventLog, event)
 }
 
+func (b *EventBus) PublishEventPendingTx(event EventDataPendingTx) error {
+	return b.Publish(EventPendingTx, event)
+}
+
 func (b *EventBus) PublishEventProposalHeartbeat(event EventDataProposalHeartbeat) error {
 	return b.Publish(EventProposalHeartbeat, event)
 }
@@ -20,6 +20,7 @@ const (
 	EventNewBlockHeader    = "NewBlockHeader"
 	EventNewRound          = "NewRound"
 	EventNewRoundStep      = "NewRoundStep"
+	EventPendingTx         = "PendingTx"
 	EventPolka             = "Polka"
 	EventRebond            = "Rebond"
 	EventRelock            = "Relock"
@@ -46,6 +47,7 @@ type TMEventData interface {
 func (_ EventDataNewBlock) AssertIsTMEventData()          {}
 func (_ EventDataNewBlockHeader) AssertIsTMEventData()    {}
 func (_ EventDataLog) AssertIsTMEventData()               {}
+func (_ EventDataPendingTx) AssertIsTMEventData()         {}
 func (_ EventDataRoundState) AssertIsTMEventData()        {}
 func (_ EventDataVote) AssertIsTMEventData()              {}
 func (_ EventDataProposalHeartbeat) AssertIsTMEventData() {}
@@ -56,6 +58,7 @@ func RegisterEventDatas() {
 	ser.RegisterConcrete(EventDataNewBlock{}, "event/NewBlock", nil)
 	ser.RegisterConcrete(EventDataNewBlockHeader{}, "event/NewBlockHeader", nil)
 	ser.RegisterConcrete(EventDataLog{}, "event/Log", nil)
+	ser.RegisterConcrete(EventDataPendingTx{}, "event/PendingTx", nil)
 	ser.RegisterConcrete(EventDataRoundState{}, "event/RoundState", nil)
 	ser.RegisterConcrete(EventDataVote{}, "event/Vote", nil)
 	ser.RegisterConcrete(EventDataProposalHeartbeat{}, "event/ProposalHeartbeat", nil)
@@ -78,6 +81,11 @@ type EventDataLog struct {
 	Logs []*Log `json:"logs"`
 }
 
+// EventDataPendingTx carries a transaction which became executable in the mempool
+type EventDataPendingTx struct {
+	Tx Tx `json:"tx"`
+}
+
 type EventDataProposalHeartbeat struct {
 	Heartbeat *Heartbeat
 }
@@ -134,6 +142,7 @@ var (
 	EventQueryProposalHeartbeat = QueryForEvent(EventProposalHeartbeat)
 	EventQueryTx                = QueryForEvent(EventTx)
 	EventQueryLog               = QueryForEvent(EventLog)
+	EventQueryPendingTx         = QueryForEvent(EventPendingTx)
 )
 
 func EventQueryTxFor(tx Tx) tmpubsub.Query {
@@ -150,3 +159,8 @@ type BlockEventPublisher interface {
 	PublishEventNewBlockHeader(header EventDataNewBlockHeader) error
 	PublishEventLog(EventDataLog) error
 }
+
+// TxEventPublisher publishes the transactions becoming executable in the mempool
+type TxEventPublisher interface {
+	PublishEventPendingTx(EventDataPendingTx) error
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	cmn "github.com/lianxiangcloud/linkchain/libs/common"
+)
+
+// Names of the protocol upgrades that can be scheduled in the genesis.
+const (
+	// ForkIstanbul enables the Istanbul instruction set and precompiles of the EVM.
+	ForkIstanbul = "istanbul"
+	// ForkBerlin enables the EIP-2929 access lists of the EVM, it requires Istanbul.
+	ForkBerlin = "berlin"
+	// ForkFeeMarket lets the transactions pay more than ParGasPrice, the mempool
+	// and the block proposers prefer the better paying ones.
+	ForkFeeMarket = "feemarket"
+	// ForkStateRoot makes the StateHash of a block header the root of the state
+	// trie the block runs on, so that the light clients verify the state proofs
+	// with the next header. Every node must keep the state trie from then on.
+	ForkStateRoot = "stateroot"
+	// ForkCrossVM lets the evm contracts call the wasm contracts and the wasm
+	// contracts call the evm ones through TC_CallEVM. Before it the evm runs
+	// the wasm code it is called with, which stops at its first byte.
+	ForkCrossVM = "crossvm"
+)
+
+var knownForks = []string{ForkIstanbul, ForkBerlin, ForkFeeMarket, ForkStateRoot, ForkCrossVM}
+
+// Fork is a protocol upgrade activated from block Height on.
+type Fork struct {
+	Name   string `json:"name"`
+	Height uint64 `json:"height"`
+}
+
+// Forks is the schedule of protocol upgrades of a chain.
+// An upgrade missing from the schedule is never activated.
+type Forks []Fork
+
+// Height returns the activation height of the named upgrade.
+func (forks Forks) Height(name string) (uint64, bool) {
+	for _, fork := range forks {
+		if fork.Name == name {
+			return fork.Height, true
+		}
+	}
+	return 0, false
+}
+
+// IsActive reports whether the named upgrade applies to the block at height.
+func (forks Forks) IsActive(name string, height uint64) bool {
+	h, ok := forks.Height(name)
+	return ok && height >= h
+}
+
+// Rules returns the rules of the block at height.
+func (forks Forks) Rules(height uint64) Rules {
+	return Rules{
+		IsIstanbul:  forks.IsActive(ForkIstanbul, height),
+		IsBerlin:    forks.IsActive(ForkBerlin, height),
+		IsFeeMarket: forks.IsActive(ForkFeeMarket, height),
+	}
+}
+
+// Rules tells which upgrades apply to a block, for the checks that are done
+// outside of the VMs.
+type Rules struct {
+	IsIstanbul  bool
+	IsBerlin    bool
+	IsFeeMarket bool
+}
+
+// Validate checks that every upgrade is known, scheduled once and not
+// before the upgrades it builds on.
+func (forks Forks) Validate() error {
+	seen := make(map[string]bool, len(forks))
+	for _, fork := range forks {
+		known := false
+		for _, name := range knownForks {
+			known = known || fork.Name == name
+		}
+		if !known {
+			return cmn.NewError("Unknown fork %q", fork.Name)
+		}
+		if seen[fork.Name] {
+			return cmn.NewError("Fork %q is scheduled twice", fork.Name)
+		}
+		seen[fork.Name] = true
+	}
+	if berlin, ok := forks.Height(ForkBerlin); ok {
+		if istanbul, ok := forks.Height(ForkIstanbul); !ok || istanbul > berlin {
+			return cmn.NewError("Fork %q must not activate before %q", ForkBerlin, ForkIstanbul)
+		}
+	}
+	return nil
+}
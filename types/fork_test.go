@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForksValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(Forks(nil).Validate())
+	assert.NoError(Forks{{ForkIstanbul, 10}, {ForkBerlin, 10}}.Validate())
+	assert.Error(Forks{{"london", 10}}.Validate(), "unknown fork accepted")
+	assert.Error(Forks{{ForkIstanbul, 10}, {ForkIstanbul, 20}}.Validate(), "duplicated fork accepted")
+	assert.Error(Forks{{ForkBerlin, 10}}.Validate(), "berlin accepted without istanbul")
+	assert.Error(Forks{{ForkIstanbul, 20}, {ForkBerlin, 10}}.Validate(), "berlin accepted before istanbul")
+}
+
+func TestForksIsActive(t *testing.T) {
+	assert := assert.New(t)
+
+	forks := Forks{{ForkIstanbul, 10}}
+	assert.False(forks.IsActive(ForkIstanbul, 9))
+	assert.True(forks.IsActive(ForkIstanbul, 10))
+	assert.False(forks.IsActive(ForkBerlin, 10))
+}
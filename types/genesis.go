@@ -3,10 +3,13 @@ package types
 import (
 	"io/ioutil"
 	"math/big"
+	"strings"
 	"time"
 
 	cmn "github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	lctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/xcrypto"
 	"github.com/lianxiangcloud/linkchain/libs/ser"
 )
 
@@ -22,9 +25,25 @@ type GenesisValidator struct {
 }
 
 // GenesisAccount is an account in the state of the genesis block.
+// Storage is keyed by the hex of the storage slots, Tokens by the
+// hex of the token addresses.
 type GenesisAccount struct {
-	Balance *big.Int `json:"balance"`
-	Nonce   uint64   `json:"nonce"`
+	Balance *big.Int                `json:"balance"`
+	Nonce   uint64                  `json:"nonce"`
+	Code    cmn.HexBytes            `json:"code,omitempty"`
+	Storage map[string]cmn.HexBytes `json:"storage,omitempty"`
+	Tokens  map[string]*big.Int     `json:"tokens,omitempty"`
+}
+
+// GenesisUTXOOutput is an utxo output minted in the genesis block.
+// Only the one-time address and the commitment are kept, there is no tx
+// to scan for its tx pubkey and amount: the wallets do not find these
+// outputs, they are spent by external tooling that knows their one-time
+// keys, amounts and masks.
+type GenesisUTXOOutput struct {
+	OTAddr  cmn.Hash    `json:"otaddr"`
+	Commit  cmn.Hash    `json:"commit"`
+	TokenID cmn.Address `json:"token"`
 }
 
 // GenesisDoc defines the initial conditions for a blockchain, in particular its validator set.
@@ -34,6 +53,7 @@ type GenesisDoc struct {
 	ConsensusParams *ConsensusParams          `json:"consensus_params,omitempty"`
 	Validators      []GenesisValidator        `json:"validators"`
 	AllocAccounts   map[string]GenesisAccount `json:"accounts,omitempty"`
+	UTXOOutputs     []GenesisUTXOOutput       `json:"utxo_outputs,omitempty"`
 }
 
 // SaveAs is a utility method for saving GenensisDoc as a JSON file.
@@ -75,9 +95,86 @@ func (genDoc *GenesisDoc) ValidateAndComplete() error {
 		genDoc.GenesisTime = time.Now().Local().String()
 	}
 
+	if err := genDoc.validateAllocAccounts(); err != nil {
+		return err
+	}
+
+	otAddrs := make(map[cmn.Hash]bool, len(genDoc.UTXOOutputs))
+	for _, o := range genDoc.UTXOOutputs {
+		if !xcrypto.CheckKey(lctypes.PublicKey(o.OTAddr)) {
+			return cmn.NewError("The genesis file contains an utxo output with invalid one-time address %v", o.OTAddr.Hex())
+		}
+		if !xcrypto.CheckKey(lctypes.PublicKey(o.Commit)) {
+			return cmn.NewError("The genesis file contains an utxo output with invalid commitment %v", o.Commit.Hex())
+		}
+		if otAddrs[o.OTAddr] {
+			return cmn.NewError("The genesis file contains duplicate utxo outputs of one-time address %v", o.OTAddr.Hex())
+		}
+		otAddrs[o.OTAddr] = true
+	}
+
+	return nil
+}
+
+// UTXOOutputsHash returns the hash of the utxo outputs of the genesis file, the
+// empty hash if there are none. It is the receipt hash of the genesis block, so
+// that the next block commits to the outputs.
+func (genDoc *GenesisDoc) UTXOOutputsHash() cmn.Hash {
+	if len(genDoc.UTXOOutputs) == 0 {
+		return cmn.EmptyHash
+	}
+	return rlpHash(genDoc.UTXOOutputs)
+}
+
+// validateAllocAccounts checks the alloc accounts and lowercases their addresses,
+// so that the accounts of the genesis file and the default ones are merged by address
+func (genDoc *GenesisDoc) validateAllocAccounts() error {
+	accounts := make(map[string]GenesisAccount, len(genDoc.AllocAccounts))
+	for addr, account := range genDoc.AllocAccounts {
+		if !cmn.IsHexAddress(addr) {
+			return cmn.NewError("The genesis file contains an account with invalid address %v", addr)
+		}
+		if account.Balance != nil && account.Balance.Sign() < 0 {
+			return cmn.NewError("The genesis file contains account %v with negative balance", addr)
+		}
+		for slot := range account.Storage {
+			if !isStorageSlot(slot) {
+				return cmn.NewError("The genesis file contains account %v with invalid storage slot %v", addr, slot)
+			}
+		}
+		for token, balance := range account.Tokens {
+			if !cmn.IsHexAddress(token) {
+				return cmn.NewError("The genesis file contains account %v with invalid token %v", addr, token)
+			}
+			if balance == nil || balance.Sign() < 0 {
+				return cmn.NewError("The genesis file contains account %v with invalid balance of token %v", addr, token)
+			}
+		}
+
+		key := strings.ToLower(cmn.HexToAddress(addr).Hex())
+		if _, ok := accounts[key]; ok {
+			return cmn.NewError("The genesis file contains duplicate accounts of address %v", key)
+		}
+		accounts[key] = account
+	}
+	if genDoc.AllocAccounts != nil {
+		genDoc.AllocAccounts = accounts
+	}
 	return nil
 }
 
+// isStorageSlot reports whether s is the 0x prefixed hex of a storage slot
+func isStorageSlot(s string) bool {
+	if !cmn.HasHexPrefix(s) || len(s) == 2 || len(s) > 2+2*cmn.HashLength {
+		return false
+	}
+	digits := s[2:]
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	return cmn.IsHex(digits)
+}
+
 //------------------------------------------------------------
 // Make genesis state from file
 
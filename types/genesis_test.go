@@ -1,10 +1,12 @@
 package types
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/xcrypto"
 	"github.com/lianxiangcloud/linkchain/libs/ser"
 	"github.com/stretchr/testify/assert"
 )
@@ -61,3 +63,70 @@ func TestGenesisGood(t *testing.T) {
 	genDoc, err = GenesisDocFromJSON(genDocBytes)
 	assert.Error(t, err, "expected error for genDoc json with block size of 0")
 }
+
+func TestGenesisAlloc(t *testing.T) {
+	commit, err := xcrypto.ZeroCommit(100)
+	assert.NoError(t, err)
+	baseGenDoc := &GenesisDoc{
+		ChainID:    "abc",
+		Validators: []GenesisValidator{{crypto.GenPrivKeyEd25519().PubKey(), common.EmptyAddress, 10, "myval"}},
+		AllocAccounts: map[string]GenesisAccount{
+			"0x54FB1C7D0F011DD63B08F85ED7B518AB82028100": {
+				Balance: big.NewInt(1),
+				Code:    common.HexBytes{0x60, 0x00},
+				Storage: map[string]common.HexBytes{"0x1": {0x2a}},
+				Tokens:  map[string]*big.Int{"0x000000000000000000000000000000000000000a": big.NewInt(10)},
+			},
+		},
+		UTXOOutputs: []GenesisUTXOOutput{
+			{
+				OTAddr: common.HexToHash("0x5866666666666666666666666666666666666666666666666666666666666666"),
+				Commit: common.Hash(commit),
+			},
+		},
+	}
+	genDocBytes, err := ser.MarshalJSON(baseGenDoc)
+	assert.NoError(t, err, "error marshalling genDoc")
+
+	genDoc, err := GenesisDocFromJSON(genDocBytes)
+	assert.NoError(t, err, "expected no error for valid genDoc json")
+	// the addresses of the accounts are lowercased
+	account, ok := genDoc.AllocAccounts["0x54fb1c7d0f011dd63b08f85ed7b518ab82028100"]
+	assert.True(t, ok, "expected lowercased address of the account")
+	assert.Equal(t, baseGenDoc.AllocAccounts["0x54FB1C7D0F011DD63B08F85ED7B518AB82028100"], account)
+	assert.Equal(t, baseGenDoc.UTXOOutputs, genDoc.UTXOOutputs)
+
+	// the hash of the outputs changes with any of them
+	hash := genDoc.UTXOOutputsHash()
+	assert.NotEqual(t, common.EmptyHash, hash)
+	assert.Equal(t, common.EmptyHash, (&GenesisDoc{}).UTXOOutputsHash())
+	genDoc.UTXOOutputs[0].TokenID = common.HexToAddress("0xa")
+	assert.NotEqual(t, hash, genDoc.UTXOOutputsHash())
+
+	// test with invalid accounts and outputs
+	invalid := common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	testCases := []func(genDoc *GenesisDoc){
+		func(genDoc *GenesisDoc) { genDoc.AllocAccounts["0x123"] = GenesisAccount{} },
+		func(genDoc *GenesisDoc) {
+			genDoc.AllocAccounts["0x54FB1C7D0F011DD63B08F85ED7B518AB82028100"] = GenesisAccount{Balance: big.NewInt(1)}
+		},
+		func(genDoc *GenesisDoc) {
+			genDoc.AllocAccounts["0xa73810e519e1075010678d706533486d8ecc8000"] = GenesisAccount{Balance: big.NewInt(-1)}
+		},
+		func(genDoc *GenesisDoc) {
+			genDoc.AllocAccounts["0xa73810e519e1075010678d706533486d8ecc8000"] = GenesisAccount{Storage: map[string]common.HexBytes{"1": nil}}
+		},
+		func(genDoc *GenesisDoc) {
+			genDoc.AllocAccounts["0xa73810e519e1075010678d706533486d8ecc8000"] = GenesisAccount{Tokens: map[string]*big.Int{"0xa": big.NewInt(1)}}
+		},
+		func(genDoc *GenesisDoc) { genDoc.UTXOOutputs = append(genDoc.UTXOOutputs, genDoc.UTXOOutputs[0]) },
+		func(genDoc *GenesisDoc) { genDoc.UTXOOutputs[0].OTAddr = invalid },
+		func(genDoc *GenesisDoc) { genDoc.UTXOOutputs[0].Commit = invalid },
+	}
+	for i, testCase := range testCases {
+		genDoc, err := GenesisDocFromJSON(genDocBytes)
+		assert.NoError(t, err)
+		testCase(genDoc)
+		assert.Error(t, genDoc.ValidateAndComplete(), "expected error for test case %d", i)
+	}
+}
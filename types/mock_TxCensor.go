@@ -64,6 +64,20 @@ func (_m *MockTxCensor) GetLastChangedVals() (uint64, []*Validator) {
 	return r0, r1
 }
 
+// ForkRules provides a mock function with given fields:
+func (_m *MockTxCensor) ForkRules() Rules {
+	ret := _m.Called()
+
+	var r0 Rules
+	if rf, ok := ret.Get(0).(func() Rules); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(Rules)
+	}
+
+	return r0
+}
+
 // GetUTXOGas provides a mock function with given fields:
 func (_m *MockTxCensor) GetUTXOGas() uint64 {
 	ret := _m.Called()
@@ -38,6 +38,10 @@ func (NopEventBus) PublishEventLog(log EventDataLog) error {
 	return nil
 }
 
+func (NopEventBus) PublishEventPendingTx(tx EventDataPendingTx) error {
+	return nil
+}
+
 //--- EventDataRoundState events
 
 func (NopEventBus) PublishEventNewRoundStep(rs EventDataRoundState) error {
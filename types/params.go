@@ -38,6 +38,10 @@ type ConsensusParams struct {
 	TxSize         `json:"tx_size_params"`
 	BlockGossip    `json:"block_gossip_params"`
 	EvidenceParams `json:"evidence_params"`
+
+	// Forks is the schedule of the protocol upgrades. It is kept last so that
+	// the params saved before it was added still decode.
+	Forks Forks `json:"forks,omitempty" rlp:"tail"`
 }
 
 // BlockSize contain limits on the block size.
@@ -66,10 +70,10 @@ type EvidenceParams struct {
 // DefaultConsensusParams returns a default ConsensusParams.
 func DefaultConsensusParams() *ConsensusParams {
 	return &ConsensusParams{
-		DefaultBlockSize(),
-		DefaultTxSize(),
-		DefaultBlockGossip(),
-		DefaultEvidenceParams(),
+		BlockSize:      DefaultBlockSize(),
+		TxSize:         DefaultTxSize(),
+		BlockGossip:    DefaultBlockGossip(),
+		EvidenceParams: DefaultEvidenceParams(),
 	}
 }
 
@@ -120,20 +124,25 @@ func (params *ConsensusParams) Validate() error {
 		return cmn.NewError("BlockSize.MaxBytes is too big. %d > %d",
 			params.BlockSize.MaxBytes, MaxBlockSizeBytes)
 	}
-	return nil
+	return params.Forks.Validate()
 }
 
 // Hash returns a merkle hash of the parameters to store
 // in the block header
 func (params *ConsensusParams) Hash() []byte {
-	return merkle.SimpleHashFromMap(map[string]merkle.Hasher{
+	hashers := map[string]merkle.Hasher{
 		"block_gossip_part_size_bytes": aminoHasher(params.BlockGossip.BlockPartSizeBytes),
 		"block_size_max_bytes":         aminoHasher(params.BlockSize.MaxBytes),
 		"block_size_max_gas":           aminoHasher(params.BlockSize.MaxGas),
 		"block_size_max_txs":           aminoHasher(params.BlockSize.MaxTxs),
 		"tx_size_max_bytes":            aminoHasher(params.TxSize.MaxBytes),
 		"tx_size_max_gas":              aminoHasher(params.TxSize.MaxGas),
-	})
+	}
+	// the hash of the chains without upgrades is left unchanged
+	if len(params.Forks) > 0 {
+		hashers["forks"] = aminoHasher(params.Forks)
+	}
+	return merkle.SimpleHashFromMap(hashers)
 }
 
 /*
@@ -82,7 +82,11 @@ type FilePV struct {
 	LastStep      int8             `json:"last_step"`
 	LastSignature crypto.Signature `json:"last_signature,omitempty"` // so we dont lose signatures XXX Why would we lose signatures?
 	LastSignBytes cmn.HexBytes     `json:"last_signbytes,omitempty"` // so we dont lose signatures XXX Why would we lose signatures?
-	PrivKey       crypto.PrivKey   `json:"priv_key"`
+	PrivKey       crypto.PrivKey   `json:"priv_key,omitempty"`
+
+	// EncryptedPrivKey replaces PrivKey in the file once the key is encrypted,
+	// the FilePV is then locked until Unlock is called with the passphrase.
+	EncryptedPrivKey *crypto.EncryptedKey `json:"encrypted_priv_key,omitempty"`
 
 	// For persistence.
 	// Overloaded for testing.
@@ -133,8 +137,9 @@ func LoadPVFromBytes(data []byte) (*FilePV, error) {
 	if err := ser.UnmarshalJSON(data, &pv); err != nil {
 		return nil, err
 	}
-	pv.Address = pv.PrivKey.PubKey().Address()
-	pv.PubKey = pv.PrivKey.PubKey()
+	if err := pv.init(); err != nil {
+		return nil, err
+	}
 	pv.pv = pv.Copy()
 	return pv, nil
 }
@@ -142,6 +147,7 @@ func LoadPVFromBytes(data []byte) (*FilePV, error) {
 // LoadFilePV loads a FilePV from the filePath.  The FilePV handles double
 // signing prevention by persisting data to the filePath.  If the filePath does
 // not exist, the FilePV must be created manually and saved.
+// An encrypted FilePV is loaded locked, see Unlock.
 func LoadFilePV(filePath string) *FilePV {
 	pvJSONBytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
@@ -149,17 +155,36 @@ func LoadFilePV(filePath string) *FilePV {
 	}
 	pv := &FilePV{}
 	err = ser.UnmarshalJSON(pvJSONBytes, &pv)
+	if err == nil {
+		err = pv.init()
+	}
 	if err != nil {
 		cmn.Exit(cmn.Fmt("Error reading PrivValidator from %v: %v\n", filePath, err))
 	}
 
 	pv.filePath = filePath
-	pv.Address = pv.PrivKey.PubKey().Address()
-	pv.PubKey = pv.PrivKey.PubKey()
 	pv.pv = pv.Copy()
 	return pv
 }
 
+// init sets the address and public key of a loaded FilePV. They are only
+// derived from the private key if it is not encrypted.
+func (pv *FilePV) init() error {
+	if pv.PrivKey == nil {
+		if pv.EncryptedPrivKey == nil {
+			return errors.New("missing priv_key")
+		}
+		if pv.PubKey == nil {
+			return errors.New("missing pub_key of the encrypted priv_key")
+		}
+		pv.Address = pv.PubKey.Address()
+	} else {
+		pv.Address = pv.PrivKey.PubKey().Address()
+		pv.PubKey = pv.PrivKey.PubKey()
+	}
+	return nil
+}
+
 // LoadOrGenFilePV loads a FilePV from the given filePath
 // or else generates a new one and saves it to the filePath.
 func LoadOrGenFilePV(filePath string) *FilePV {
@@ -185,9 +210,63 @@ func (pv *FilePV) Copy() *FilePV {
 		LastSignBytes: pv.LastSignBytes,
 		PrivKey:       pv.PrivKey,
 		filePath:      pv.filePath,
+
+		EncryptedPrivKey: pv.EncryptedPrivKey,
 	}
 }
 
+// IsEncrypted returns true if the private key is saved encrypted.
+func (pv *FilePV) IsEncrypted() bool {
+	return pv.EncryptedPrivKey != nil
+}
+
+// IsLocked returns true if the private key is encrypted and not yet unlocked,
+// a locked FilePV cannot sign.
+func (pv *FilePV) IsLocked() bool {
+	return pv.PrivKey == nil
+}
+
+// Unlock decrypts the private key of an encrypted FilePV with the passphrase.
+func (pv *FilePV) Unlock(passphrase string) error {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	if pv.EncryptedPrivKey == nil {
+		return errors.New("private key not encrypted")
+	}
+	privKey, err := crypto.DecryptKey(pv.EncryptedPrivKey, passphrase)
+	if err != nil {
+		return err
+	}
+	if !privKey.PubKey().Equals(pv.PubKey) {
+		return fmt.Errorf("encrypted private key does not match pub_key %v", pv.Address)
+	}
+	pv.PrivKey = privKey
+	if pv.pv != nil {
+		pv.pv.PrivKey = privKey
+	}
+	return nil
+}
+
+// Encrypt encrypts the private key with the passphrase and saves the FilePV,
+// the private key is no longer written in the clear.
+func (pv *FilePV) Encrypt(passphrase string, scryptN, scryptP int) error {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	if pv.PrivKey == nil {
+		return errLocked
+	}
+	key, err := crypto.EncryptKey(pv.PrivKey, passphrase, scryptN, scryptP)
+	if err != nil {
+		return err
+	}
+	pv.EncryptedPrivKey = key
+	if pv.pv != nil && pv.pv.PrivKey != nil && pv.pv.PrivKey.Equals(pv.PrivKey) {
+		pv.pv.EncryptedPrivKey = key
+	}
+	pv.save()
+	return nil
+}
+
 // Save persists the FilePV to disk.
 func (pv *FilePV) Save() {
 	pv.mtx.Lock()
@@ -202,6 +281,9 @@ func (pv *FilePV) save() {
 	}
 
 	bakPrivVal := *pv
+	if bakPrivVal.EncryptedPrivKey != nil {
+		bakPrivVal.PrivKey = nil
+	}
 
 	jsonBytes, err := ser.MarshalJSONIndent(bakPrivVal, "", "  ")
 	if err != nil {
@@ -225,11 +307,21 @@ func (pv *FilePV) Reset() {
 	pv.Save()
 }
 
+var errLocked = errors.New("private key is encrypted and locked")
+
+// sign signs msg with the private key, unless the FilePV is locked.
+func (pv *FilePV) sign(msg []byte) (crypto.Signature, error) {
+	if pv.PrivKey == nil {
+		return nil, errLocked
+	}
+	return pv.PrivKey.Sign(msg)
+}
+
 // SignData signs a piece of data. Implements PrivValidator.
 func (pv *FilePV) SignData(data []byte) ([]byte, error) {
 	pv.mtx.Lock()
 	defer pv.mtx.Unlock()
-	sig, err := pv.PrivKey.Sign(data)
+	sig, err := pv.sign(data)
 	if err != nil {
 		return nil, err
 	}
@@ -327,7 +419,7 @@ func (pv *FilePV) signVote(chainID string, vote *Vote, save bool) error {
 	}
 
 	// It passed the checks. Sign the vote
-	sig, err := pv.PrivKey.Sign(signBytes)
+	sig, err := pv.sign(signBytes)
 	if err != nil {
 		return err
 	}
@@ -370,7 +462,7 @@ func (pv *FilePV) signProposal(chainID string, proposal *Proposal) error {
 	}
 
 	// It passed the checks. Sign the proposal
-	sig, err := pv.PrivKey.Sign(signBytes)
+	sig, err := pv.sign(signBytes)
 	if err != nil {
 		return err
 	}
@@ -404,7 +496,7 @@ func (pv *FilePV) saveSigned(height uint64, round int, step int8,
 func (pv *FilePV) SignHeartbeat(chainID string, heartbeat *Heartbeat) error {
 	pv.mtx.Lock()
 	defer pv.mtx.Unlock()
-	sig, err := pv.PrivKey.Sign(heartbeat.SignBytes(chainID))
+	sig, err := pv.sign(heartbeat.SignBytes(chainID))
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 	"time"
@@ -277,3 +278,36 @@ func clipToMS(t time.Time) time.Time {
 	nano = (nano / million) * million
 	return time.Unix(0, nano).UTC()
 }
+
+func TestEncryptedValidator(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	_, tempFilePath := cmn.Tempfile("priv_validator_")
+	privVal := GenFilePV(tempFilePath)
+	privKey := privVal.GetPrikey()
+	require.Nil(privVal.Encrypt("passphrase", 1<<4, 1))
+	assert.True(privVal.IsEncrypted())
+
+	// the private key is not saved in the clear
+	bz, err := ioutil.ReadFile(tempFilePath)
+	require.Nil(err)
+	assert.NotContains(string(bz), `"priv_key"`)
+
+	privVal = LoadFilePV(tempFilePath)
+	assert.True(privVal.IsLocked())
+	assert.Equal(privKey.PubKey().Address(), privVal.GetAddress())
+	block1 := BlockID{common.BytesToHash([]byte{1, 2, 3}), PartSetHeader{}}
+	vote := newVote(privVal.GetAddress(), 0, 10, 1, VoteTypePrevote, block1)
+	assert.NotNil(privVal.SignVote("mychainid", vote))
+
+	assert.Equal(crypto.ErrDecrypt, privVal.Unlock("wrong"))
+	require.Nil(privVal.Unlock("passphrase"))
+	assert.False(privVal.IsLocked())
+	assert.True(privKey.Equals(privVal.GetPrikey()))
+	require.Nil(privVal.SignVote("mychainid", vote))
+
+	// the signed votes are saved, still encrypted
+	privVal = LoadFilePV(tempFilePath)
+	assert.True(privVal.IsLocked())
+	assert.Equal(uint64(10), privVal.LastHeight)
+}
@@ -219,9 +219,19 @@ func IsContract(data []byte) bool {
 	return false
 }
 
+// IllegalGasPrice tells whether the transactions can't pay price under rules.
+// The price is fixed to ParGasPrice until the fee market fork, it is the
+// lowest price from then on.
+func IllegalGasPrice(price *big.Int, rules Rules) bool {
+	if rules.IsFeeMarket {
+		return price.Cmp(big.NewInt(ParGasPrice)) < 0
+	}
+	return price.Cmp(big.NewInt(ParGasPrice)) != 0
+}
+
 //TODO: return err instead of bool
-func (tx *Transaction) IllegalGasLimitOrGasPrice(hascode bool) bool {
-	if tx.GasPrice().Cmp(big.NewInt(ParGasPrice)) != 0 {
+func (tx *Transaction) IllegalGasLimitOrGasPrice(hascode bool, rules Rules) bool {
+	if IllegalGasPrice(tx.GasPrice(), rules) {
 		log.Info("ParGasPrice!=0", "GasPrice", tx.GasPrice())
 		return true
 	}
@@ -231,7 +241,7 @@ func (tx *Transaction) IllegalGasLimitOrGasPrice(hascode bool) bool {
 		gasRate = cfg.WasmGasRate
 	}
 	contractCreation := tx.data.Recipient == nil
-	intrGas, err := IntrinsicGas(tx.data.Payload, contractCreation, gasRate)
+	intrGas, err := IntrinsicGas(tx.data.Payload, contractCreation, gasRate, rules.IsIstanbul)
 	if err != nil {
 		log.Info("IntrinsicGas overflow")
 		return true
@@ -395,6 +405,15 @@ func (tx *Transaction) WithSignature(signer STDSigner, sig []byte) (*Transaction
 	return cpy, nil
 }
 
+// WithGasPrice returns a new unsigned transaction paying price, the
+// constructors always pay ParGasPrice.
+func (tx *Transaction) WithGasPrice(price *big.Int) *Transaction {
+	cpy := &Transaction{data: tx.data}
+	cpy.data.Price = new(big.Int).Set(price)
+	cpy.data.R, cpy.data.S, cpy.data.V = new(big.Int), new(big.Int), new(big.Int)
+	return cpy
+}
+
 // Cost returns amount + gasprice * gaslimit.
 func (tx *Transaction) Cost() *big.Int {
 	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
@@ -475,9 +494,12 @@ func IsWasmContract(code []byte) bool {
 }
 
 func (tx *Transaction) CheckBasic(censor TxCensor) error {
-	return tx.CheckBasicWithState(censor, nil)
+	return tx.CheckBasicWithState(censor, nil, censor.ForkRules())
 }
-func (tx *Transaction) CheckBasicWithState(censor TxCensor, state State) error {
+
+// CheckBasicWithState checks the transaction with the state, if not nil, and
+// with the rules of the block it is included in.
+func (tx *Transaction) CheckBasicWithState(censor TxCensor, state State, rules Rules) error {
 	if tx == nil {
 		return ErrTxEmpty
 	}
@@ -521,7 +543,7 @@ func (tx *Transaction) CheckBasicWithState(censor TxCensor, state State) error {
 		}
 	}
 
-	if tx.IllegalGasLimitOrGasPrice(hascode) {
+	if tx.IllegalGasLimitOrGasPrice(hascode, rules) {
 		return ErrGasLimitOrGasPrice
 	}
 
@@ -574,7 +596,8 @@ func (tx *Transaction) CheckState(censor TxCensor) error {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation bool, gasRate uint64) (uint64, error) {
+// The non zero bytes of data are repriced by Istanbul.
+func IntrinsicGas(data []byte, contractCreation bool, gasRate uint64, istanbul bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation {
@@ -591,12 +614,16 @@ func IntrinsicGas(data []byte, contractCreation bool, gasRate uint64) (uint64, e
 				nz++
 			}
 		}
+		nonZeroGas := cfg.TxDataNonZeroGas
+		if istanbul {
+			nonZeroGas = cfg.TxDataNonZeroGasEIP2028
+		}
 		// Make sure we don't exceed uint64 for all data combinations
-		if (math.MaxUint64-gas)/cfg.TxDataNonZeroGas < nz {
+		if (math.MaxUint64-gas)/nonZeroGas < nz {
 			log.Warn("IntrinsicGas", "gas", gas, "nz", nz)
 			return 0, ErrIntrinsicGasOverflow
 		}
-		gas += nz * cfg.TxDataNonZeroGas
+		gas += nz * nonZeroGas
 
 		z := uint64(len(data)) - nz
 		if (math.MaxUint64-gas)/cfg.TxDataZeroGas < z {
@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	mock "github.com/stretchr/testify/mock"
 
+	cfg "github.com/lianxiangcloud/linkchain/config"
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
 	"github.com/lianxiangcloud/linkchain/libs/ser"
@@ -214,12 +215,12 @@ func TestTransaction(t *testing.T) {
 	assert.Equal(t, false, IsContract(tx3.Data()))
 	assert.Equal(t, true, IsContract(tx.Data()))
 
-	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false))
+	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false, Rules{}))
 	tx3.data.GasLimit = uint64(1)
-	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false))
+	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false, Rules{}))
 	tx3.data.GasLimit = ParGasLimit
 	tx3.data.Price = big.NewInt(1)
-	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false))
+	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false, Rules{}))
 
 	bf := bytes.NewBuffer(nil)
 	err = tx.EncodeSER(bf)
@@ -283,6 +284,7 @@ func TestTransactionCheckBasic(t *testing.T) {
 	censor.On("State").Return(state)
 	censor.On("LockState").Return()
 	censor.On("UnlockState").Return()
+	censor.On("ForkRules").Return(Rules{})
 	state.On("IsContract", mock.Anything).Return(false)
 
 	to := common.HexToAddress("0x01")
@@ -354,6 +356,7 @@ func TestTransactionCheckState(t *testing.T) {
 	censor.On("State").Return(state)
 	censor.On("LockState").Return()
 	censor.On("UnlockState").Return()
+	censor.On("ForkRules").Return(Rules{})
 
 	nonce := uint64(1)
 	to := common.HexToAddress("0x1")
@@ -443,7 +446,7 @@ func TestIllegalGasLimitOrGasPrice(t *testing.T) {
 			Price:     big.NewInt(ParGasPrice),
 		},
 	}
-	assert.False(t, tx1.IllegalGasLimitOrGasPrice(false))
+	assert.False(t, tx1.IllegalGasLimitOrGasPrice(false, Rules{}))
 	tx2 := Transaction{
 		data: txdata{
 			Amount:    big.NewInt(0),
@@ -453,7 +456,7 @@ func TestIllegalGasLimitOrGasPrice(t *testing.T) {
 			Payload:   nil,
 		},
 	}
-	assert.False(t, tx2.IllegalGasLimitOrGasPrice(true))
+	assert.False(t, tx2.IllegalGasLimitOrGasPrice(true, Rules{}))
 	tx3 := Transaction{
 		data: txdata{
 			Amount:   big.NewInt(0),
@@ -461,7 +464,7 @@ func TestIllegalGasLimitOrGasPrice(t *testing.T) {
 			Price:    big.NewInt(ParGasPrice),
 		},
 	}
-	assert.False(t, tx3.IllegalGasLimitOrGasPrice(true))
+	assert.False(t, tx3.IllegalGasLimitOrGasPrice(true, Rules{}))
 	tx4 := Transaction{
 		data: txdata{
 			Amount:   big.NewInt(1),
@@ -469,5 +472,46 @@ func TestIllegalGasLimitOrGasPrice(t *testing.T) {
 			Price:    big.NewInt(ParGasPrice),
 		},
 	}
-	assert.False(t, tx4.IllegalGasLimitOrGasPrice(true))
+	assert.False(t, tx4.IllegalGasLimitOrGasPrice(true, Rules{}))
+}
+
+func TestIllegalGasPrice(t *testing.T) {
+	feeMarket := Rules{IsFeeMarket: true}
+	lower, higher := big.NewInt(ParGasPrice-1), big.NewInt(ParGasPrice+1)
+
+	assert.False(t, IllegalGasPrice(big.NewInt(ParGasPrice), Rules{}))
+	assert.True(t, IllegalGasPrice(lower, Rules{}))
+	assert.True(t, IllegalGasPrice(higher, Rules{}))
+
+	assert.False(t, IllegalGasPrice(big.NewInt(ParGasPrice), feeMarket))
+	assert.True(t, IllegalGasPrice(lower, feeMarket))
+	assert.False(t, IllegalGasPrice(higher, feeMarket))
+
+	tx := NewTransaction(0, common.EmptyAddress, big.NewInt(1), 500000, nil, nil).WithGasPrice(higher)
+	assert.Equal(t, higher, tx.GasPrice())
+	assert.True(t, tx.IllegalGasLimitOrGasPrice(false, Rules{}))
+	assert.False(t, tx.IllegalGasLimitOrGasPrice(false, feeMarket))
+}
+
+func TestIntrinsicGasIstanbul(t *testing.T) {
+	data := []byte{0, 1, 2}
+	gas, err := IntrinsicGas(data, false, 1, false)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg.TxGas+cfg.TxDataZeroGas+2*cfg.TxDataNonZeroGas, gas)
+	gas, err = IntrinsicGas(data, false, 1, true)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg.TxGas+cfg.TxDataZeroGas+2*cfg.TxDataNonZeroGasEIP2028, gas)
+
+	// the gas limit covers the data repriced by istanbul only
+	tx := Transaction{
+		data: txdata{
+			Amount:    big.NewInt(0),
+			GasLimit:  cfg.TxGas + cfg.TxDataZeroGas + 2*cfg.TxDataNonZeroGasEIP2028,
+			Recipient: &common.EmptyAddress,
+			Price:     big.NewInt(ParGasPrice),
+			Payload:   data,
+		},
+	}
+	assert.True(t, tx.IllegalGasLimitOrGasPrice(true, Rules{}))
+	assert.False(t, tx.IllegalGasLimitOrGasPrice(true, Rules{IsIstanbul: true}))
 }
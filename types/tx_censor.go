@@ -57,4 +57,6 @@ type TxCensor interface {
 	UTXOStore() UTXOStore
 	Mempool() Mempool
 	GetUTXOGas() uint64
+	// ForkRules returns the rules of the next block.
+	ForkRules() Rules
 }
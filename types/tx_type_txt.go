@@ -148,8 +148,8 @@ func (tx *TokenTransaction) StoreFrom(addr common.Address) {
 	tx.data.Signdata.fromValue.Store(stdSigCache{signer: GlobalSTDSigner, from: addr})
 }
 
-func (tx *TokenTransaction) IllegalGasLimitOrGasPrice(hascode bool) bool {
-	if tx.GasPrice().Cmp(big.NewInt(ParGasPrice)) != 0 {
+func (tx *TokenTransaction) IllegalGasLimitOrGasPrice(hascode bool, rules Rules) bool {
+	if IllegalGasPrice(tx.GasPrice(), rules) {
 		return true
 	}
 	var gasFee uint64
@@ -346,6 +346,15 @@ func (tx *TokenTransaction) GasPrice() *big.Int           { return new(big.Int).
 func (tx *TokenTransaction) Value() *big.Int              { return new(big.Int).Set(tx.data.Amount) }
 func (tx *TokenTransaction) Nonce() uint64                { return tx.data.AccountNonce }
 
+// WithGasPrice returns a new unsigned transaction paying price, the
+// constructors always pay ParGasPrice.
+func (tx *TokenTransaction) WithGasPrice(price *big.Int) *TokenTransaction {
+	cpy := &TokenTransaction{data: tx.data}
+	cpy.data.Price = new(big.Int).Set(price)
+	cpy.data.Signdata = signdata{V: new(big.Int), R: new(big.Int), S: new(big.Int)}
+	return cpy
+}
+
 // Cost returns gasprice * gaslimit.
 func (tx *TokenTransaction) GasCost() *big.Int {
 	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
@@ -363,10 +372,12 @@ func (tx *TokenTransaction) RawSignatureValues() (*big.Int, *big.Int, *big.Int)
 }
 
 func (tx *TokenTransaction) CheckBasic(censor TxCensor) error {
-	return tx.CheckBasicWithState(censor, nil)
+	return tx.CheckBasicWithState(censor, nil, censor.ForkRules())
 }
 
-func (tx *TokenTransaction) CheckBasicWithState(censor TxCensor, state State) error {
+// CheckBasicWithState checks the transaction with the state, if not nil, and
+// with the rules of the block it is included in.
+func (tx *TokenTransaction) CheckBasicWithState(censor TxCensor, state State, rules Rules) error {
 	if tx == nil {
 		return ErrTxEmpty
 	}
@@ -412,7 +423,7 @@ func (tx *TokenTransaction) CheckBasicWithState(censor TxCensor, state State) er
 		}
 	}
 
-	if tx.IllegalGasLimitOrGasPrice(hascode) {
+	if tx.IllegalGasLimitOrGasPrice(hascode, rules) {
 		return ErrGasLimitOrGasPrice
 	}
 
@@ -421,7 +432,7 @@ func (tx *TokenTransaction) CheckBasicWithState(censor TxCensor, state State) er
 		return ErrInvalidSender
 	}
 
-	intrGas, err := IntrinsicGas(tx.Data(), false, cfg.EvmGasRate) // txt cannot call contract, use EvmGasRate for default
+	intrGas, err := IntrinsicGas(tx.Data(), false, cfg.EvmGasRate, rules.IsIstanbul) // txt cannot call contract, use EvmGasRate for default
 	if err != nil {
 		return ErrIntrinsicGasOverflow
 	}
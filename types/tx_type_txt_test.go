@@ -54,12 +54,12 @@ func TestTokenTransaction(t *testing.T) {
 	assert.Equal(t, false, IsContract(tx3.Data()))
 	assert.Equal(t, true, IsContract(tx.Data()))
 
-	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false))
+	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false, Rules{}))
 	tx3.data.GasLimit = uint64(1)
-	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false))
+	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false, Rules{}))
 	tx3.data.GasLimit = ParGasLimit
 	tx3.data.Price = big.NewInt(1)
-	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false))
+	assert.Equal(t, true, tx3.IllegalGasLimitOrGasPrice(false, Rules{}))
 
 	bf := bytes.NewBuffer(nil)
 	err = tx.EncodeSER(bf)
@@ -111,6 +111,7 @@ func TestTokenTransactionCheckBasic(t *testing.T) {
 	censor.On("State").Return(state)
 	censor.On("LockState").Return()
 	censor.On("UnlockState").Return()
+	censor.On("ForkRules").Return(Rules{})
 
 	to := common.HexToAddress("0x01")
 	var tx *TokenTransaction
@@ -177,6 +178,7 @@ func TestTokenTransactionCheckState(t *testing.T) {
 	censor.On("State").Return(state)
 	censor.On("LockState").Return()
 	censor.On("UnlockState").Return()
+	censor.On("ForkRules").Return(Rules{})
 
 	nonce := uint64(1)
 	to := common.HexToAddress("0x1")
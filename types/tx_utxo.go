@@ -230,7 +230,6 @@ type UTXOTransaction struct {
 	size       atomic.Value
 	utxoInNum  atomic.Value
 	utxoOutNum atomic.Value
-	nonce      uint64
 }
 
 var _ Tx = &UTXOTransaction{}
@@ -432,8 +431,10 @@ func (tx *UTXOTransaction) TxType() string     { return TxUTXO }
 func (tx *UTXOTransaction) Data() []byte       { panic("should not call"); return nil }
 func (tx *UTXOTransaction) Value() *big.Int    { panic("should not call"); return big.NewInt(0) }
 func (tx *UTXOTransaction) Nonce() uint64 {
-	if (tx.UTXOKind() & Ain) == Ain {
-		return tx.nonce
+	for _, in := range tx.Inputs {
+		if input, ok := in.(*AccountInput); ok {
+			return input.Nonce
+		}
 	}
 	panic("should not call for no AccountInput UTXOTransaction")
 }
@@ -874,7 +875,6 @@ func (tx *UTXOTransaction) checkTxSemantic(censor TxCensor) error {
 
 			kind |= Ain
 			accountInNum++
-			tx.nonce = input.Nonce
 		default:
 			return ErrInputTypeNotExpect
 		}
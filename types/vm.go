@@ -29,8 +29,10 @@ type StateDB interface {
 	IsContract(common.Address) bool
 
 	AddRefund(uint64)
+	SubRefund(uint64)
 	GetRefund() uint64
 
+	GetCommittedState(common.Address, common.Hash) []byte
 	GetState(common.Address, common.Hash) []byte
 	SetState(common.Address, common.Hash, []byte)
 
@@ -44,6 +46,16 @@ type StateDB interface {
 	// is defined according to EIP161 (balance = nonce = code = 0).
 	Empty(common.Address) bool
 
+	PrepareAccessList(sender common.Address, dest *common.Address, precompiles []common.Address, list AccessList)
+	AddressInAccessList(addr common.Address) bool
+	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
+	// AddAddressToAccessList adds the given address to the access list. This operation is safe to perform
+	// even if the feature/fork is not active yet
+	AddAddressToAccessList(addr common.Address)
+	// AddSlotToAccessList adds the given (address,slot) to the access list. This operation is safe to perform
+	// even if the feature/fork is not active yet
+	AddSlotToAccessList(addr common.Address, slot common.Hash)
+
 	RevertToSnapshot(int)
 	Snapshot() int
 
@@ -100,6 +112,24 @@ type Interpreter interface {
 type VmConfig interface {
 }
 
+// AccessList is an EIP-2930 access list.
+type AccessList []AccessTuple
+
+// AccessTuple is the element type of an access list.
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// StorageKeys returns the total number of storage keys in the access list.
+func (al AccessList) StorageKeys() int {
+	sum := 0
+	for _, tuple := range al {
+		sum += len(tuple.StorageKeys)
+	}
+	return sum
+}
+
 // type (
 // 	// CanTransferFunc is the signature of a transfer guard function
 // 	CanTransferFunc func(StateDB, common.Address, *big.Int) bool
@@ -139,3 +169,36 @@ type Context interface {
 	// GetTime() *big.Int
 	// GetDifficulty() *big.Int
 }
+
+// CrossCallMsg is a call from a contract of one vm into a contract of the
+// other one. Origin, GasPrice and Token are the ones of the transaction, Gas
+// is measured in transaction gas and each vm scales it by its own gas rate.
+type CrossCallMsg struct {
+	Origin   common.Address
+	GasPrice *big.Int
+	Token    common.Address
+	Caller   common.Address
+	To       common.Address
+	Input    []byte
+	Gas      uint64
+	Value    *big.Int
+	ReadOnly bool
+}
+
+// CrossCallResult is the outcome of a CrossCallMsg. Otxs are the balance
+// records of the callee to be appended to the trail of the caller, and Fee
+// the transfer fees charged by the callee, which are refunded like the
+// fees of the caller are.
+type CrossCallResult struct {
+	Ret         []byte
+	LeftOverGas uint64
+	Otxs        []BalanceRecord
+	Fee         uint64
+}
+
+// CrossVM runs the calls into the contracts of a vm made by the contracts of
+// the other one. The result is returned along with the error: a reverted call
+// returns ExecutionReverted and the gas left, other errors consume all of it.
+type CrossVM interface {
+	CrossCall(msg *CrossCallMsg) (*CrossCallResult, error)
+}
@@ -1,6 +1,7 @@
 package utxo
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 
@@ -18,9 +19,12 @@ import (
 const (
 	tokenMaxUtxoOutputSeqKeyPre   = "token_muos_"
 	blockTokenInitOutputSeqKeyPre = "btio_"
+	blockMaxUtxoOutputSeqKeyPre   = "bmuos_"
+	firstBlockMaxUtxoOutputSeqKey = "bmuos_first"
 	kImageVal                     = "k"
 	utxoOutputInitSequence uint64 = 1e19
 	positionalNotation     int    = 36
+	maxIndexBlocksPerBatch uint64 = 10000
 )
 
 type UtxoStore struct {
@@ -125,19 +129,18 @@ func (u *UtxoStore) GetBlockTokenUtxoOutputSeq(blockHeight uint64) map[string]in
 	return retMap
 }
 
-func (u *UtxoStore) saveTokenUtxoOutputSeq(tokenSeqMap map[string]int64) error {
+func (u *UtxoStore) saveTokenUtxoOutputSeq(tokenSeqMap map[string]int64, saveBlockSeq bool) error {
 	tokenOutputSeqs := newTokenUtxoSeqs()
 	u.mapMutex.Lock()
+	defer u.mapMutex.Unlock()
 	for tokenId, seq := range tokenSeqMap {
 		if uint64(seq) < 0 {
-			u.mapMutex.Unlock()
 			u.logger.Error("seq too less.")
 			return errors.New("seq too less")
 		}
 		val := []byte(strconv.FormatInt(seq, positionalNotation))
 		err := u.utxoDB.Put(genTokenMaxSeqKey(tokenId), val)
 		if err != nil {
-			u.mapMutex.Unlock()
 			return err
 		}
 		initBlockSeq, ok := u.maxUtxoOutputSeqTokenMap[tokenId]
@@ -147,17 +150,160 @@ func (u *UtxoStore) saveTokenUtxoOutputSeq(tokenSeqMap map[string]int64) error {
 		tokenOutputSeqs.addTokenUtxoSeq(tokenId, initBlockSeq)
 		u.maxUtxoOutputSeqTokenMap[tokenId] = seq
 	}
-	u.mapMutex.Unlock()
+	if !saveBlockSeq {
+		return nil
+	}
 
 	err := u.saveBlockTokenUtxoOutputSeq(tokenOutputSeqs)
 	if err != nil {
 		u.logger.Error("save block tokend utxo outputs seq failed.", "err", err.Error())
 		return err
 	}
+	err = u.saveBlockMaxUtxoOutputSeqs(u.blockHeight)
+	if err != nil {
+		u.logger.Error("save block max utxo outputs seq failed.", "err", err.Error())
+		return err
+	}
 
 	return nil
 }
 
+// saveBlockMaxUtxoOutputSeqs saves the max output seq of every token after
+// the block at height, the caller holds mapMutex.
+func (u *UtxoStore) saveBlockMaxUtxoOutputSeqs(height uint64) error {
+	batch := u.utxoDB.NewBatch()
+	val, err := encodeMaxUtxoOutputSeqs(u.maxUtxoOutputSeqTokenMap)
+	if err != nil {
+		return err
+	}
+	batch.Set(genBlockMaxSeqKey(height), val)
+	if !u.utxoDB.Has([]byte(firstBlockMaxUtxoOutputSeqKey)) {
+		batch.Set([]byte(firstBlockMaxUtxoOutputSeqKey), []byte(strconv.FormatUint(height, positionalNotation)))
+	}
+	return batch.Commit()
+}
+
+// RestoreMaxUtxoOutputSeqs saves the max output seqs of the UTXO outputs
+// restored from the snapshot at height. The ones of the blocks below are
+// unknown to the node, they are no longer served.
+func (u *UtxoStore) RestoreMaxUtxoOutputSeqs(height uint64) error {
+	u.mapMutex.Lock()
+	defer u.mapMutex.Unlock()
+	val, err := encodeMaxUtxoOutputSeqs(u.maxUtxoOutputSeqTokenMap)
+	if err != nil {
+		return err
+	}
+	batch := u.utxoDB.NewBatch()
+	batch.Set(genBlockMaxSeqKey(height), val)
+	batch.Set([]byte(firstBlockMaxUtxoOutputSeqKey), []byte(strconv.FormatUint(height, positionalNotation)))
+	return batch.Commit()
+}
+
+// IndexMaxUtxoOutputSeqs saves the max output seqs of the blocks up to
+// height, that the store saved before it kept them per block. They are
+// rebuilt once from the init seqs of the blocks.
+func (u *UtxoStore) IndexMaxUtxoOutputSeqs(height uint64) error {
+	if u.utxoDB.Has([]byte(firstBlockMaxUtxoOutputSeqKey)) {
+		return nil
+	}
+	u.mapMutex.Lock()
+	retMap := make(map[string]int64, len(u.maxUtxoOutputSeqTokenMap))
+	for tokenId, seq := range u.maxUtxoOutputSeqTokenMap {
+		retMap[tokenId] = seq
+	}
+	u.mapMutex.Unlock()
+
+	batch := u.utxoDB.NewBatch()
+	for h := height; ; h-- {
+		val, err := encodeMaxUtxoOutputSeqs(retMap)
+		if err != nil {
+			return err
+		}
+		batch.Set(genBlockMaxSeqKey(h), val)
+		if (height-h+1)%maxIndexBlocksPerBatch == 0 {
+			if err := batch.Commit(); err != nil {
+				return err
+			}
+			batch = u.utxoDB.NewBatch()
+		}
+		if h == 0 {
+			break
+		}
+
+		// each block records the seqs its tokens had before it
+		if val := u.utxoDB.Get(genBlockTokenInitSeq(h)); len(val) > 0 {
+			tokenOutputSeqs := newTokenUtxoSeqs()
+			if err := ser.DecodeBytes(val, tokenOutputSeqs); err != nil {
+				return err
+			}
+			for _, seqObj := range tokenOutputSeqs.Seqs {
+				if seqObj.Seq < 0 {
+					delete(retMap, seqObj.TokenId)
+				} else {
+					retMap[seqObj.TokenId] = seqObj.Seq
+				}
+			}
+		}
+	}
+	batch.Set([]byte(firstBlockMaxUtxoOutputSeqKey), []byte(strconv.FormatUint(0, positionalNotation)))
+	return batch.Commit()
+}
+
+// MaxUtxoOutputSeqsAt returns the max output seq of every token as it was
+// after the block at height was saved.
+func (u *UtxoStore) MaxUtxoOutputSeqsAt(height uint64) (map[string]int64, error) {
+	if val := u.utxoDB.Get([]byte(firstBlockMaxUtxoOutputSeqKey)); len(val) > 0 {
+		first, err := strconv.ParseUint(string(val), positionalNotation, 64)
+		if err != nil {
+			return nil, err
+		}
+		if height < first {
+			return nil, fmt.Errorf("max output seqs of block %d not kept, the first kept block is %d", height, first)
+		}
+	}
+	val := u.utxoDB.Get(genBlockMaxSeqKey(height))
+	if len(val) == 0 {
+		return nil, fmt.Errorf("max output seqs of block %d not found", height)
+	}
+	tokenOutputSeqs := newTokenUtxoSeqs()
+	if err := ser.DecodeBytes(val, tokenOutputSeqs); err != nil {
+		return nil, err
+	}
+	retMap := make(map[string]int64, len(tokenOutputSeqs.Seqs))
+	for _, seqObj := range tokenOutputSeqs.Seqs {
+		retMap[seqObj.TokenId] = seqObj.Seq
+	}
+	return retMap, nil
+}
+
+func encodeMaxUtxoOutputSeqs(tokenSeqMap map[string]int64) ([]byte, error) {
+	tokenOutputSeqs := newTokenUtxoSeqs()
+	for tokenId, seq := range tokenSeqMap {
+		tokenOutputSeqs.addTokenUtxoSeq(tokenId, seq)
+	}
+	return ser.EncodeToBytes(tokenOutputSeqs)
+}
+
+// KeyImages returns up to limit spent key images, starting from the key image start.
+// The returned next is the key image to continue from, it is nil after the last one.
+func (u *UtxoStore) KeyImages(start []byte, limit int) (kImgs []lctypes.Key, next []byte) {
+	iter := u.utxoDB.Iterator(start, nil)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if len(key) != len(lctypes.Key{}) || string(iter.Value()) != kImageVal {
+			continue
+		}
+		if len(kImgs) == limit {
+			return kImgs, append([]byte(nil), key...)
+		}
+		var kImg lctypes.Key
+		copy(kImg[:], key)
+		kImgs = append(kImgs, kImg)
+	}
+	return kImgs, nil
+}
+
 func (u *UtxoStore) SetLogger(logger log.Logger) {
 	u.logger = logger
 }
@@ -172,6 +318,7 @@ func (u *UtxoStore) SaveUtxo(kImgs []*lctypes.Key, utxoOutputs []*types.UTXOOutp
 	err = u.SaveUtxoOutputs(utxoOutputs)
 	if err != nil {
 		u.logger.Error("SaveUtxoOutputs failed.", "err", err.Error())
+		return err
 	}
 
 	return nil
@@ -194,6 +341,16 @@ func (u *UtxoStore) SaveKImages(kImgs []*lctypes.Key) error {
 }
 
 func (u *UtxoStore) SaveUtxoOutputs(utxoOutputs []*types.UTXOOutputData) error {
+	return u.saveUtxoOutputs(utxoOutputs, true)
+}
+
+// RestoreUtxoOutputs appends outputs fetched from a snapshot, they belong to
+// no block of this node.
+func (u *UtxoStore) RestoreUtxoOutputs(utxoOutputs []*types.UTXOOutputData) error {
+	return u.saveUtxoOutputs(utxoOutputs, false)
+}
+
+func (u *UtxoStore) saveUtxoOutputs(utxoOutputs []*types.UTXOOutputData, saveBlockSeq bool) error {
 	tmpTokenSeq := make(map[string]int64, 0)
 	batch      := u.utxoOutputDB.NewBatch()
 	tokenBatch := u.utxoOutputTokenDB.NewBatch()
@@ -228,7 +385,7 @@ func (u *UtxoStore) SaveUtxoOutputs(utxoOutputs []*types.UTXOOutputData) error {
 		return err
 	}
 	// update maxUtxoOutputSeq
-	err = u.saveTokenUtxoOutputSeq(tmpTokenSeq)
+	err = u.saveTokenUtxoOutputSeq(tmpTokenSeq, saveBlockSeq)
 	if err != nil {
 		u.logger.Error("UtxoStore saveMaxUtxoOutputSeq exec failed.", "err", err.Error())
 		return err
@@ -237,6 +394,29 @@ func (u *UtxoStore) SaveUtxoOutputs(utxoOutputs []*types.UTXOOutputData) error {
 	return nil
 }
 
+// ClearUtxo drops the key images and the outputs of the store, it is called
+// by state sync when the UTXO set restored from a snapshot is wrong. The
+// outputs are overwritten by the next ones saved.
+func (u *UtxoStore) ClearUtxo() error {
+	u.mapMutex.Lock()
+	defer u.mapMutex.Unlock()
+	batch := u.utxoDB.NewBatch()
+	iter := u.utxoDB.Iterator(nil, nil)
+	for ; iter.Valid(); iter.Next() {
+		key := iter.Key()
+		if (len(key) == len(lctypes.Key{}) && string(iter.Value()) == kImageVal) ||
+			bytes.HasPrefix(key, []byte(tokenMaxUtxoOutputSeqKeyPre)) {
+			batch.Delete(append([]byte(nil), key...))
+		}
+	}
+	iter.Close()
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+	u.maxUtxoOutputSeqTokenMap = make(map[string]int64)
+	return nil
+}
+
 func (u *UtxoStore) GetUtxoOutput(tokenId common.Address, seq uint64) (*types.UTXOOutputData, error) {
 	key := []byte(strconv.FormatUint(utxoOutputInitSequence + seq, positionalNotation))
 	var val []byte
@@ -356,3 +536,7 @@ func genTokenPreKey(tokenId common.Address) []byte {
 func genBlockTokenInitSeq(blockHeight uint64) []byte {
 	return []byte(fmt.Sprintf("%s%d", blockTokenInitOutputSeqKeyPre, blockHeight))
 }
+
+func genBlockMaxSeqKey(blockHeight uint64) []byte {
+	return []byte(fmt.Sprintf("%s%d", blockMaxUtxoOutputSeqKeyPre, blockHeight))
+}
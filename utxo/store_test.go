@@ -0,0 +1,91 @@
+package utxo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	lctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/types"
+)
+
+func newTestUtxoStore(utxoDB dbm.DB) *UtxoStore {
+	store := NewUtxoStore(utxoDB, dbm.NewMemDB(), dbm.NewMemDB())
+	store.SetLogger(log.NewNopLogger())
+	return store
+}
+
+func genTestOutputs(tokens ...common.Address) []*types.UTXOOutputData {
+	outputs := make([]*types.UTXOOutputData, 0, len(tokens))
+	for i, token := range tokens {
+		outputs = append(outputs, &types.UTXOOutputData{OTAddr: lctypes.Key{byte(i)}, TokenID: token})
+	}
+	return outputs
+}
+
+func TestMaxUtxoOutputSeqsAt(t *testing.T) {
+	token := common.HexToAddress("0x01")
+	link := common.EmptyAddress.String()
+	store := newTestUtxoStore(dbm.NewMemDB())
+	require.Nil(t, store.SaveUtxo(nil, genTestOutputs(common.EmptyAddress, common.EmptyAddress), 0))
+	require.Nil(t, store.SaveUtxo(nil, nil, 1))
+	require.Nil(t, store.SaveUtxo(nil, genTestOutputs(token, common.EmptyAddress), 2))
+
+	want := []map[string]int64{
+		{link: 1},
+		{link: 1},
+		{link: 2, token.String(): 0},
+	}
+	for height, seqs := range want {
+		got, err := store.MaxUtxoOutputSeqsAt(uint64(height))
+		require.Nil(t, err)
+		assert.Equal(t, seqs, got, "height %d", height)
+	}
+	_, err := store.MaxUtxoOutputSeqsAt(3)
+	assert.NotNil(t, err)
+
+	// the seqs of the blocks below a restored snapshot are unknown
+	require.Nil(t, store.RestoreMaxUtxoOutputSeqs(2))
+	_, err = store.MaxUtxoOutputSeqsAt(1)
+	assert.NotNil(t, err)
+	got, err := store.MaxUtxoOutputSeqsAt(2)
+	require.Nil(t, err)
+	assert.Equal(t, want[2], got)
+}
+
+func TestIndexMaxUtxoOutputSeqs(t *testing.T) {
+	token := common.HexToAddress("0x01")
+	utxoDB := dbm.NewMemDB()
+	store := newTestUtxoStore(utxoDB)
+	require.Nil(t, store.SaveUtxo(nil, genTestOutputs(common.EmptyAddress), 0))
+	require.Nil(t, store.SaveUtxo(nil, genTestOutputs(token), 1))
+	require.Nil(t, store.SaveUtxo(nil, nil, 2))
+	require.Nil(t, store.SaveUtxo(nil, genTestOutputs(common.EmptyAddress, token), 3))
+	want := make([]map[string]int64, 0, 4)
+	for height := uint64(0); height < 4; height++ {
+		seqs, err := store.MaxUtxoOutputSeqsAt(height)
+		require.Nil(t, err)
+		want = append(want, seqs)
+	}
+
+	// a store saved before the seqs were kept per block
+	iter := utxoDB.NewIteratorWithPrefix([]byte(blockMaxUtxoOutputSeqKeyPre))
+	for ; iter.Valid(); iter.Next() {
+		utxoDB.Delete(iter.Key())
+	}
+	iter.Close()
+	store = newTestUtxoStore(utxoDB)
+	_, err := store.MaxUtxoOutputSeqsAt(0)
+	assert.NotNil(t, err)
+
+	require.Nil(t, store.IndexMaxUtxoOutputSeqs(3))
+	for height, seqs := range want {
+		got, err := store.MaxUtxoOutputSeqsAt(uint64(height))
+		require.Nil(t, err)
+		assert.Equal(t, seqs, got, "height %d", height)
+	}
+}
@@ -0,0 +1,287 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/vm/evm"
+	"github.com/lianxiangcloud/linkchain/vm/wasm"
+	tcvm "github.com/xunleichain/tc-wasm/vm"
+)
+
+var errInternalFailure = errors.New("internal failure")
+
+var (
+	_ evm.Tracer  = (*CallTracer)(nil)
+	_ wasm.Tracer = (*CallTracer)(nil)
+)
+
+// callScope holds the bookkeeping of an open call frame which is not part of
+// its json representation.
+type callScope struct {
+	frame   *types.CallFrame
+	gasIn   uint64
+	gasCost uint64
+	gasSet  bool
+	outOff  int64
+	outLen  int64
+}
+
+// CallTracer rebuilds the tree of message calls made by a transaction. It
+// implements both evm.Tracer and wasm.Tracer, so one instance can be handed to
+// both vms of a VmFactory.
+//
+// EVM calls are recovered from the CALL, CREATE and SELFDESTRUCT family of
+// opcodes as they are stepped over, WASM calls from the contract calling host
+// functions.
+type CallTracer struct {
+	frames    []*types.CallFrame
+	callstack []*callScope
+	descended bool
+
+	// wasm nested calls are reported once they returned, children first.
+	wasmCalls map[int][]*types.CallFrame
+}
+
+// NewCallTracer returns a new call tracer.
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// CaptureStart opens the frame of a top level call.
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	frame := &types.CallFrame{
+		Type:  "CALL",
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if create {
+		frame.Type = "CREATE"
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.frames = append(t.frames, frame)
+	t.callstack = []*callScope{{frame: frame}}
+	t.descended = false
+	t.wasmCalls = make(map[int][]*types.CallFrame)
+	return nil
+}
+
+// CaptureState implements evm.Tracer, following the message calls opened and
+// closed by the interpreter.
+func (t *CallTracer) CaptureState(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory, stack *evm.Stack, contract *evm.Contract, depth int, err error) error {
+	if len(t.callstack) == 0 {
+		return nil
+	}
+	if err != nil {
+		t.fault(err)
+		return nil
+	}
+
+	switch op {
+	case evm.CREATE, evm.CREATE2:
+		t.enter(&types.CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			Input: memory.Get(stack.Back(1).Int64(), stack.Back(2).Int64()),
+			Value: (*hexutil.Big)(new(big.Int).Set(stack.Back(0))),
+		}, gas, cost, 0, 0)
+		return nil
+
+	case evm.SELFDESTRUCT:
+		top := t.callstack[len(t.callstack)-1].frame
+		top.Calls = append(top.Calls, &types.CallFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			To:      common.BigToAddress(stack.Back(0)),
+			Gas:     hexutil.Uint64(gas),
+			GasUsed: hexutil.Uint64(cost),
+			Value:   (*hexutil.Big)(env.StateDB.GetBalance(contract.Address())),
+		})
+		return nil
+
+	case evm.CALL, evm.CALLCODE, evm.DELEGATECALL, evm.STATICCALL:
+		to := common.BigToAddress(stack.Back(1))
+		if env.IsPrecompile(to) {
+			return nil
+		}
+		off := 1
+		if op == evm.DELEGATECALL || op == evm.STATICCALL {
+			off = 0
+		}
+		frame := &types.CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			To:    to,
+			Input: memory.Get(stack.Back(2+off).Int64(), stack.Back(3+off).Int64()),
+		}
+		if op == evm.CALL || op == evm.CALLCODE {
+			frame.Value = (*hexutil.Big)(new(big.Int).Set(stack.Back(2)))
+		}
+		t.enter(frame, gas, cost, stack.Back(4+off).Int64(), stack.Back(5+off).Int64())
+		return nil
+
+	case evm.REVERT:
+		if depth == len(t.callstack) {
+			top := t.callstack[len(t.callstack)-1].frame
+			top.Output = memory.Get(stack.Back(0).Int64(), stack.Back(1).Int64())
+			top.Error = "execution reverted"
+		}
+	}
+
+	if t.descended {
+		// The first step of the callee tells how much gas it received. If it
+		// has no code at all, the gas is left unknown.
+		if depth >= len(t.callstack) {
+			scope := t.callstack[len(t.callstack)-1]
+			scope.frame.Gas = hexutil.Uint64(gas)
+			scope.gasSet = true
+		}
+		t.descended = false
+	}
+
+	// Back in the caller after a nested call or create returned.
+	if depth == len(t.callstack)-1 && len(t.callstack) > 1 {
+		scope := t.callstack[len(t.callstack)-1]
+		t.callstack = t.callstack[:len(t.callstack)-1]
+
+		frame := scope.frame
+		if frame.Type == "CREATE" || frame.Type == "CREATE2" {
+			frame.GasUsed = hexutil.Uint64(scope.gasIn - scope.gasCost - gas)
+			frame.To = common.BigToAddress(stack.Back(0))
+			if frame.To == common.EmptyAddress {
+				frame.Error = errInternalFailure.Error()
+			} else {
+				frame.Output = env.StateDB.GetCode(frame.To)
+			}
+		} else {
+			if scope.gasSet {
+				frame.GasUsed = hexutil.Uint64(scope.gasIn - scope.gasCost + uint64(frame.Gas) - gas)
+			}
+			if stack.Back(0).Sign() != 0 {
+				frame.Output = memory.Get(scope.outOff, scope.outLen)
+			} else if frame.Error == "" {
+				frame.Error = errInternalFailure.Error()
+			}
+		}
+		parent := t.callstack[len(t.callstack)-1].frame
+		parent.Calls = append(parent.Calls, frame)
+	}
+	return nil
+}
+
+// CaptureFault implements evm.Tracer, closing the frame the fault happened in.
+func (t *CallTracer) CaptureFault(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory, stack *evm.Stack, contract *evm.Contract, depth int, err error) error {
+	if len(t.callstack) == 0 {
+		return nil
+	}
+	t.fault(err)
+	return nil
+}
+
+// CaptureHostCall implements wasm.Tracer. Only the host functions entering
+// another contract open a frame, everything else is ignored.
+func (t *CallTracer) CaptureHostCall(eng *tcvm.Engine, contract common.Address, name string, args []uint64, ret uint64, gas, cost uint64, depth int, err error) error {
+	if len(t.callstack) == 0 || len(args) == 0 {
+		return nil
+	}
+	var typ string
+	switch name {
+	case "TC_CallContract":
+		typ = "CALL"
+	case "TC_DelegateCallContract":
+		typ = "DELEGATECALL"
+	default:
+		return nil
+	}
+
+	frame := &types.CallFrame{
+		Type: typ,
+		From: contract,
+		Gas:  hexutil.Uint64(gas),
+	}
+	if running, _ := eng.RunningAppFrame(); running != nil {
+		vmem := running.VM.VMemory()
+		if to, err := vmem.GetString(args[0]); err == nil {
+			frame.To = common.HexToAddress(string(to))
+		}
+		// Same "action|params" layout as the callee's contract input.
+		if len(args) > 1 {
+			if action, err := vmem.GetString(args[1]); err == nil {
+				frame.Input = common.CopyBytes(action)
+			}
+		}
+		if len(args) > 2 {
+			if params, err := vmem.GetString(args[2]); err == nil {
+				frame.Input = append(append(frame.Input, '|'), params...)
+			}
+		}
+	}
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	if gasLeft := eng.Gas(); gas > gasLeft {
+		frame.GasUsed = hexutil.Uint64(gas - gasLeft)
+	}
+	frame.Calls = t.wasmCalls[depth+1]
+	delete(t.wasmCalls, depth+1)
+	t.wasmCalls[depth] = append(t.wasmCalls[depth], frame)
+	return nil
+}
+
+// CaptureEnd closes the top level call.
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if len(t.callstack) == 0 {
+		return nil
+	}
+	root := t.callstack[0].frame
+	root.GasUsed = hexutil.Uint64(gasUsed)
+	root.Output = common.CopyBytes(output)
+	if err != nil {
+		root.Error = err.Error()
+	}
+	root.Calls = append(root.Calls, t.wasmCalls[1]...)
+
+	t.callstack = nil
+	t.wasmCalls = nil
+	return nil
+}
+
+// Frames returns the traced top level calls. A transaction has one of them,
+// except UTXO transactions paying to several contracts.
+func (t *CallTracer) Frames() []*types.CallFrame { return t.frames }
+
+func (t *CallTracer) enter(frame *types.CallFrame, gas, cost uint64, outOff, outLen int64) {
+	t.callstack = append(t.callstack, &callScope{
+		frame:   frame,
+		gasIn:   gas,
+		gasCost: cost,
+		outOff:  outOff,
+		outLen:  outLen,
+	})
+	t.descended = true
+}
+
+func (t *CallTracer) fault(err error) {
+	scope := t.callstack[len(t.callstack)-1]
+	if scope.frame.Error != "" {
+		return
+	}
+	scope.frame.Error = err.Error()
+	if len(t.callstack) == 1 {
+		return
+	}
+	if scope.gasSet {
+		scope.frame.GasUsed = scope.frame.Gas
+	}
+	t.callstack = t.callstack[:len(t.callstack)-1]
+	parent := t.callstack[len(t.callstack)-1].frame
+	parent.Calls = append(parent.Calls, scope.frame)
+}
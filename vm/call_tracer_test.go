@@ -0,0 +1,61 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/vm/evm"
+	"github.com/lianxiangcloud/linkchain/vm/runtime"
+)
+
+func TestCallTracerNestedCall(t *testing.T) {
+	var (
+		caller = common.HexToAddress("0xaa")
+		callee = common.HexToAddress("0xbb")
+	)
+	statedb, _ := state.New(common.EmptyHash, state.NewDatabase(dbm.NewMemDB()))
+	// mstore(0, 42) return(0, 32)
+	statedb.SetCode(callee, []byte{0x60, 0x2a, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3})
+	// call(0xffff, callee, 0, 0, 0, 0, 32) stop
+	code := []byte{0x60, 0x20, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x73}
+	code = append(code, callee.Bytes()...)
+	code = append(code, 0x61, 0xff, 0xff, 0xf1, 0x00)
+	statedb.SetCode(caller, code)
+
+	tracer := NewCallTracer()
+	_, _, err := runtime.Call(caller, nil, &runtime.Config{
+		State:     statedb,
+		GasLimit:  1000000,
+		EVMConfig: evm.Config{Debug: true, Tracer: tracer},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := tracer.Frames()
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 top level call, got %d", len(frames))
+	}
+	root := frames[0]
+	if root.Type != "CALL" || root.To != caller {
+		t.Errorf("unexpected top level call %+v", root)
+	}
+	if len(root.Calls) != 1 {
+		t.Fatalf("expected 1 nested call, got %d", len(root.Calls))
+	}
+	child := root.Calls[0]
+	if child.Type != "CALL" || child.From != caller || child.To != callee {
+		t.Errorf("unexpected nested call %+v", child)
+	}
+	if child.Error != "" {
+		t.Errorf("unexpected nested call error %q", child.Error)
+	}
+	if len(child.Output) != 32 || child.Output[31] != 0x2a {
+		t.Errorf("unexpected nested call output %x", []byte(child.Output))
+	}
+	if child.GasUsed == 0 || child.GasUsed > child.Gas {
+		t.Errorf("unexpected nested call gas used %d of %d", child.GasUsed, child.Gas)
+	}
+}
@@ -80,6 +80,7 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
+	Forks       types.Forks    // Provides the protocol upgrades of the chain
 
 	EvmGasRate uint64
 }
@@ -161,8 +162,7 @@ func UnsafeTransfer(db types.StateDB, recipient, token common.Address, amount *b
 func run(evm *EVM, c types.Contract, input []byte, readOnly bool) ([]byte, error) {
 	contract := c.(*Contract)
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContractsHomestead
-		if p := precompiles[*contract.CodeAddr]; p != nil {
+		if p := evm.precompile(*contract.CodeAddr); p != nil {
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -206,6 +206,13 @@ type EVM struct {
 	fees       []uint64
 	refundFees []uint64
 	feeSaved   bool
+
+	// crossVM runs the contracts of wasm code called by evm contracts
+	crossVM types.CrossVM
+
+	// istanbul and berlin are the protocol upgrades active at BlockNumber
+	istanbul bool
+	berlin   bool
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -223,11 +230,125 @@ func NewEVM(c types.Context, statedb types.StateDB, vmc types.VmConfig) *EVM {
 		refundFees: make([]uint64, 0),
 		Issued:     make(chan bool, 1),
 	}
+	evm.istanbul = evm.IsForkActive(types.ForkIstanbul)
+	evm.berlin = evm.IsForkActive(types.ForkBerlin)
 
 	evm.interpreter = NewInterpreter(evm, vmConfig)
 	return evm
 }
 
+// SetCrossVM sets the vm running the calls into wasm contracts, without it
+// such calls run the wasm code on the interpreter.
+func (evm *EVM) SetCrossVM(crossVM types.CrossVM) {
+	evm.crossVM = crossVM
+}
+
+// isCrossCall reports whether the contract is run by the crossVM, which the
+// crossvm fork enables.
+func (evm *EVM) isCrossCall(contract *Contract) bool {
+	return evm.crossVM != nil && types.IsWasmContract(contract.Code) && evm.IsForkActive(types.ForkCrossVM)
+}
+
+// runCode runs the contract like run does, but hands the contracts of wasm
+// code over to the crossVM. The balance records and the transfer fees of
+// the wasm call join the ones of the evm.
+func (evm *EVM) runCode(contract *Contract, input []byte, readOnly bool) ([]byte, error) {
+	if !evm.isCrossCall(contract) {
+		return run(evm, contract, input, readOnly)
+	}
+	res, err := evm.crossVM.CrossCall(&types.CrossCallMsg{
+		Origin:   evm.Origin,
+		GasPrice: evm.GasPrice,
+		Token:    evm.Token,
+		Caller:   contract.Caller(),
+		To:       contract.Address(),
+		Input:    input,
+		Gas:      contract.Gas,
+		Value:    contract.Value(),
+		ReadOnly: readOnly || evm.interpreter.readOnly,
+	})
+	contract.Gas = res.LeftOverGas
+	evm.otxs = append(evm.otxs, res.Otxs...)
+	if res.Fee > 0 {
+		evm.fees = append(evm.fees, res.Fee)
+	}
+	return res.Ret, err
+}
+
+// CrossCall runs the call of a wasm contract into an evm contract. The call
+// is nested in the wasm one, which counts as a level of the call depth.
+func (evm *EVM) CrossCall(msg *types.CrossCallMsg) (*types.CrossCallResult, error) {
+	evm.Context.Origin = msg.Origin
+	evm.Context.Token = msg.Token
+	if msg.GasPrice != nil {
+		evm.Context.GasPrice = new(big.Int).Set(msg.GasPrice)
+	}
+	var (
+		startTxRecordsIndex  = len(evm.otxs)
+		startFeesIndex       = len(evm.fees)
+		startRefundFeesIndex = len(evm.refundFees)
+		ret                  []byte
+		leftOverGas          uint64
+		err                  error
+	)
+	evm.depth++
+	if msg.ReadOnly {
+		ret, leftOverGas, _, err = evm.StaticCall(AccountRef(msg.Caller), msg.To, msg.Input, msg.Gas)
+	} else {
+		ret, leftOverGas, _, err = evm.Call(AccountRef(msg.Caller), msg.To, common.EmptyAddress, msg.Input, msg.Gas, msg.Value)
+	}
+	evm.depth--
+
+	res := &types.CrossCallResult{Ret: ret, LeftOverGas: leftOverGas}
+	for _, fee := range evm.fees[startFeesIndex:] {
+		res.Fee += fee
+	}
+	for _, fee := range evm.refundFees[startRefundFeesIndex:] {
+		res.Fee += fee
+	}
+	evm.fees = evm.fees[:startFeesIndex]
+	evm.refundFees = evm.refundFees[:startRefundFeesIndex]
+	if err == nil {
+		res.Otxs = append(res.Otxs, evm.otxs[startTxRecordsIndex:]...)
+	}
+	evm.otxs = evm.otxs[:startTxRecordsIndex]
+	return res, err
+}
+
+// precompile returns the pre-compiled contract at addr, nil if there is none.
+func (evm *EVM) precompile(addr common.Address) PrecompiledContract {
+	if evm.istanbul {
+		return PrecompiledContractsIstanbul[addr]
+	}
+	return PrecompiledContractsHomestead[addr]
+}
+
+// IsPrecompile reports whether addr is a pre-compiled contract of the active rules.
+func (evm *EVM) IsPrecompile(addr common.Address) bool {
+	return evm.precompile(addr) != nil
+}
+
+// ActivePrecompiles returns the addresses of the pre-compiled contracts of the active rules.
+func (evm *EVM) ActivePrecompiles() []common.Address {
+	precompiles := PrecompiledContractsHomestead
+	if evm.istanbul {
+		precompiles = PrecompiledContractsIstanbul
+	}
+	addrs := make([]common.Address, 0, len(precompiles))
+	for addr := range precompiles {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// prepareAccessList warms up the accounts of the transaction (EIP-2929),
+// it only applies to the outermost call.
+func (evm *EVM) prepareAccessList(sender common.Address, dest *common.Address) {
+	if evm.berlin && evm.depth == 0 {
+		evm.StateDB.PrepareAccessList(sender, dest, evm.ActivePrecompiles(), nil)
+	}
+}
+
 func (evm *EVM) Reset(msg types.Message) {
 	evm.depth = 0
 	evm.abort = 0
@@ -261,13 +382,15 @@ func (evm *EVM) UTXOCall(c types.ContractRef, addr, token common.Address, input
 		return nil, gas, 0, ErrDepth
 	}
 
+	caller := c.(ContractRef)
+	evm.prepareAccessList(caller.Address(), &addr)
+
 	var (
 		to       = AccountRef(addr)
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		precompiles := PrecompiledContractsHomestead
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if evm.precompile(addr) == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do antything
 			return nil, gas, 0, nil
 		}
@@ -277,10 +400,19 @@ func (evm *EVM) UTXOCall(c types.ContractRef, addr, token common.Address, input
 
 	// Initialise a new contract and set the code that is to be used by the EVM.
 	// The contract is a scoped environment for this execution context only.
-	caller := c.(ContractRef)
 	contract := NewContract(caller, to, value, gas)
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
+	start := time.Now()
+
+	// Capture the tracer start/end events in debug mode
+	if evm.vmConfig.Debug && evm.depth == 0 {
+		evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
+
+		defer func() { // Lazy evaluation of the parameters
+			evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
+		}()
+	}
 	ret, err = run(evm, contract, input, false)
 
 	// When an error was returned by the EVM or when setting the creation code
@@ -330,14 +462,14 @@ func (evm *EVM) Call(c types.ContractRef, addr, token common.Address, input []by
 	if !evm.Context.CanTransfer(evm.StateDB, caller.Address(), token, value) {
 		return nil, gas, 0, ErrInsufficientBalance
 	}
+	evm.prepareAccessList(caller.Address(), &addr)
 
 	var (
 		to       = AccountRef(addr)
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		precompiles := PrecompiledContractsHomestead
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if evm.precompile(addr) == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do antything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -371,7 +503,7 @@ func (evm *EVM) Call(c types.ContractRef, addr, token common.Address, input []by
 			evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
 		}()
 	}
-	ret, err = run(evm, contract, input, false)
+	ret, err = evm.runCode(contract, input, false)
 
 	// When an error was returned by the EVM or when setting the creation code
 	// above we revert to the snapshot and consume any gas remaining. Additionally
@@ -434,7 +566,12 @@ func (evm *EVM) CallCode(c types.ContractRef, addr common.Address, input []byte,
 	contract := NewContract(caller, to, value, gas)
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
-	ret, err = run(evm, contract, input, false)
+	if evm.isCrossCall(contract) {
+		// wasm code never runs in the context of the caller
+		ret, err = nil, ErrWasmCodeDelegated
+	} else {
+		ret, err = run(evm, contract, input, false)
+	}
 	gasUsed := gas - contract.Gas
 	if gasUsed < contract.ByteCodeGas {
 		byteCodeGas = contract.ByteCodeGas - gasUsed
@@ -485,7 +622,12 @@ func (evm *EVM) DelegateCall(c types.ContractRef, addr common.Address, input []b
 	contract := NewContract(caller, to, nil, gas).AsDelegate()
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
-	ret, err = run(evm, contract, input, false)
+	if evm.isCrossCall(contract) {
+		// wasm code never runs in the context of the caller
+		ret, err = nil, ErrWasmCodeDelegated
+	} else {
+		ret, err = run(evm, contract, input, false)
+	}
 	gasUsed := gas - contract.Gas
 	if gasUsed < contract.ByteCodeGas {
 		byteCodeGas = contract.ByteCodeGas - gasUsed
@@ -546,7 +688,7 @@ func (evm *EVM) StaticCall(c types.ContractRef, addr common.Address, input []byt
 	// When an error was returned by the EVM or when setting the creation code
 	// above we revert to the snapshot and consume any gas remaining. Additionally
 	// when we're in Homestead this also counts for code storage gas errors.
-	ret, err = run(evm, contract, input, true)
+	ret, err = evm.runCode(contract, input, true)
 	gasUsed := gas - contract.Gas
 	if gasUsed < contract.ByteCodeGas {
 		byteCodeGas = contract.ByteCodeGas - gasUsed
@@ -592,6 +734,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	if evm.depth > int(cfg.CallCreateDepth) {
 		return nil, common.EmptyAddress, gas, ErrDepth
 	}
+	evm.prepareAccessList(caller.Address(), nil)
 
 	if evm.depth != 0 {
 		if !evm.CanTransfer(evm.StateDB, caller.Address(), common.EmptyAddress, value) {
@@ -601,6 +744,11 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		evm.StateDB.SetNonce(caller.Address(), nonce+1)
 	}
 
+	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
+	// the access-list change should not be rolled back
+	if evm.berlin {
+		evm.StateDB.AddAddressToAccessList(contractAddr)
+	}
 	// Ensure there's no existing contract already at the designated address
 	contractHash := evm.StateDB.GetCodeHash(contractAddr)
 	if evm.StateDB.GetNonce(contractAddr) != 0 || (contractHash != common.EmptyHash && contractHash != emptyCodeHash) {
@@ -724,6 +872,11 @@ func (evm *EVM) GetBlockNumber() *big.Int {
 	return evm.BlockNumber
 }
 
+// IsForkActive reports whether the named protocol upgrade applies to the current block.
+func (evm *EVM) IsForkActive(name string) bool {
+	return evm.BlockNumber != nil && evm.Forks.IsActive(name, evm.BlockNumber.Uint64())
+}
+
 //Time
 func (evm *EVM) GetTime() *big.Int {
 	return evm.Time
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/types"
 )
 
 type twoOperandTest struct {
@@ -463,3 +464,30 @@ func BenchmarkOpMstore(bench *testing.B) {
 		opMstore(&pc, env, nil, mem, stack)
 	}
 }
+
+func TestIstanbulInstructionSet(t *testing.T) {
+	forks := types.Forks{{Name: types.ForkIstanbul, Height: 10}}
+
+	env := NewEVM(Context{BlockNumber: big.NewInt(9), Forks: forks}, nil, Config{})
+	if env.interpreter.cfg.JumpTable[CHAINID].valid {
+		t.Fatal("CHAINID is valid before istanbul")
+	}
+	if env.IsPrecompile(common.BytesToAddress([]byte{9})) {
+		t.Fatal("blake2F is precompiled before istanbul")
+	}
+
+	env = NewEVM(Context{BlockNumber: big.NewInt(10), Forks: forks}, nil, Config{})
+	if !env.interpreter.cfg.JumpTable[CHAINID].valid || !env.interpreter.cfg.JumpTable[SELFBALANCE].valid {
+		t.Fatal("CHAINID or SELFBALANCE is invalid after istanbul")
+	}
+	if !env.IsPrecompile(common.BytesToAddress([]byte{9})) {
+		t.Fatal("blake2F is not precompiled after istanbul")
+	}
+
+	pc := uint64(0)
+	stack := newstack()
+	opChainID(&pc, env, nil, nil, stack)
+	if got := stack.pop(); got.Cmp(types.SignParam) != 0 {
+		t.Fatalf("ChainID fail, got %v, expected %v", got, types.SignParam)
+	}
+}
@@ -64,13 +64,24 @@ func NewInterpreter(evm *EVM, cfg Config) *Interpreter {
 	// we'll set the default jump table.
 	if !cfg.JumpTable[STOP].valid {
 		// cfg.JumpTable = byzantiumInstructionSet
-		cfg.JumpTable = constantinopleInstructionSet
+		switch {
+		case evm.berlin:
+			cfg.JumpTable = berlinInstructionSet
+		case evm.istanbul:
+			cfg.JumpTable = istanbulInstructionSet
+		default:
+			cfg.JumpTable = constantinopleInstructionSet
+		}
 	}
 
+	gasTable := config.Gastable(evm.BlockNumber)
+	if evm.istanbul {
+		gasTable = config.GasTableIstanbul
+	}
 	return &Interpreter{
 		evm:      evm,
 		cfg:      cfg,
-		gasTable: config.Gastable(evm.BlockNumber),
+		gasTable: gasTable,
 		intPool:  newIntPool(),
 	}
 }
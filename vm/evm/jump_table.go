@@ -55,8 +55,49 @@ var (
 	homesteadInstructionSet      = newHomesteadInstructionSet()
 	byzantiumInstructionSet      = newByzantiumInstructionSet()
 	constantinopleInstructionSet = newConstantinopleInstructionSet()
+	istanbulInstructionSet       = newIstanbulInstructionSet()
+	berlinInstructionSet         = newBerlinInstructionSet()
 )
 
+// newBerlinInstructionSet returns the istanbul instructions with the
+// access list gas costs of EIP-2929.
+func newBerlinInstructionSet() [256]operation {
+	instructionSet := newIstanbulInstructionSet()
+	instructionSet[SLOAD].gasCost = gasSLoadEIP2929
+	instructionSet[SSTORE].gasCost = gasSStoreEIP2929
+	instructionSet[EXTCODECOPY].gasCost = gasExtCodeCopyEIP2929
+	instructionSet[EXTCODESIZE].gasCost = gasEip2929AccountCheck
+	instructionSet[EXTCODEHASH].gasCost = gasEip2929AccountCheck
+	instructionSet[BALANCE].gasCost = gasEip2929AccountCheck
+	instructionSet[CALL].gasCost = gasCallEIP2929
+	instructionSet[CALLCODE].gasCost = gasCallCodeEIP2929
+	instructionSet[STATICCALL].gasCost = gasStaticCallEIP2929
+	instructionSet[DELEGATECALL].gasCost = gasDelegateCallEIP2929
+	instructionSet[SELFDESTRUCT].gasCost = gasSelfdestructEIP2929
+	return instructionSet
+}
+
+// newIstanbulInstructionSet returns the constantinople instructions with
+// CHAINID, SELFBALANCE (EIP-1344, EIP-1884) and the net gas metering of
+// SSTORE (EIP-2200). The repricing of EIP-1884 lives in GasTableIstanbul.
+func newIstanbulInstructionSet() [256]operation {
+	instructionSet := newConstantinopleInstructionSet()
+	instructionSet[CHAINID] = operation{
+		execute:       opChainID,
+		gasCost:       constGasFunc(GasQuickStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	instructionSet[SELFBALANCE] = operation{
+		execute:       opSelfBalance,
+		gasCost:       constGasFunc(GasFastStep),
+		validateStack: makeStackFunc(0, 1),
+		valid:         true,
+	}
+	instructionSet[SSTORE].gasCost = gasSStoreEIP2200
+	return instructionSet
+}
+
 // NewConstantinopleInstructionSet returns the frontier, homestead
 // byzantium and contantinople instructions.
 func newConstantinopleInstructionSet() [256]operation {
@@ -0,0 +1,263 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package evm
+
+import (
+	"errors"
+
+	cfg "github.com/lianxiangcloud/linkchain/config"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/math"
+)
+
+var errSstoreSentry = errors.New("not enough gas for reentrancy sentry")
+
+// gasSStoreEIP2200 implements the net gas metering of EIP-2200, storage values
+// are compared as words since they are stored without their leading zeros.
+//
+// The refunds are tracked as in go-ethereum, although they are not paid back.
+func gasSStoreEIP2200(gt cfg.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	// If we fail the minimum gas availability invariant, fail (0)
+	if contract.Gas <= cfg.SstoreSentryGasEIP2200 {
+		return 0, errSstoreSentry
+	}
+	// Gas sentry honoured, do the actual gas calculation based on the stored value
+	var (
+		y, x    = stack.Back(1), stack.Back(0)
+		slot    = common.BigToHash(x)
+		current = common.BytesToHash(evm.StateDB.GetState(contract.Address(), slot))
+		value   = common.BigToHash(y)
+	)
+	if current == value { // noop (1)
+		return cfg.SstoreNoopGasEIP2200, nil
+	}
+	original := common.BytesToHash(evm.StateDB.GetCommittedState(contract.Address(), slot))
+	if original == current {
+		if original == common.EmptyHash { // create slot (2.1.1)
+			return cfg.SstoreInitGasEIP2200, nil
+		}
+		if value == common.EmptyHash { // delete slot (2.1.2b)
+			evm.StateDB.AddRefund(cfg.SstoreClearRefundEIP2200)
+		}
+		return cfg.SstoreCleanGasEIP2200, nil // write existing slot (2.1.2)
+	}
+	if original != common.EmptyHash {
+		if current == common.EmptyHash { // recreate slot (2.2.1.1)
+			evm.StateDB.SubRefund(cfg.SstoreClearRefundEIP2200)
+		} else if value == common.EmptyHash { // delete slot (2.2.1.2)
+			evm.StateDB.AddRefund(cfg.SstoreClearRefundEIP2200)
+		}
+	}
+	if original == value {
+		if original == common.EmptyHash { // reset to original inexistent slot (2.2.2.1)
+			evm.StateDB.AddRefund(cfg.SstoreInitRefundEIP2200)
+		} else { // reset to original existing slot (2.2.2.2)
+			evm.StateDB.AddRefund(cfg.SstoreCleanRefundEIP2200)
+		}
+	}
+	return cfg.SstoreDirtyGasEIP2200, nil // dirty update (2.2)
+}
+
+// gasSStoreEIP2929 implements gas cost for SSTORE according to EIP-2929
+//
+// When calling SSTORE, check if the (address, storage_key) pair is in accessed_storage_keys.
+// If it is not, charge an additional COLD_SLOAD_COST gas, and add the pair to accessed_storage_keys.
+// Additionally, modify the parameters defined in EIP 2200 as follows:
+//
+// Parameter 	Old value 	New value
+// SLOAD_GAS 	800 	= WARM_STORAGE_READ_COST
+// SSTORE_RESET_GAS 	5000 	5000 - COLD_SLOAD_COST
+//
+// The other parameters defined in EIP 2200 are unchanged, see gasSStoreEIP2200.
+func gasSStoreEIP2929(gt cfg.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	// If we fail the minimum gas availability invariant, fail (0)
+	if contract.Gas <= cfg.SstoreSentryGasEIP2200 {
+		return 0, errSstoreSentry
+	}
+	// Gas sentry honoured, do the actual gas calculation based on the stored value
+	var (
+		y, x    = stack.Back(1), stack.Back(0)
+		slot    = common.BigToHash(x)
+		current = common.BytesToHash(evm.StateDB.GetState(contract.Address(), slot))
+		value   = common.BigToHash(y)
+		cost    = uint64(0)
+	)
+	// Check slot presence in the access list
+	if _, slotPresent := evm.StateDB.SlotInAccessList(contract.Address(), slot); !slotPresent {
+		cost = cfg.ColdSloadCostEIP2929
+		// If the caller cannot afford the cost, this change will be rolled back
+		evm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+	}
+	if current == value { // noop (1)
+		return cost + cfg.WarmStorageReadCostEIP2929, nil // SLOAD_GAS
+	}
+	original := common.BytesToHash(evm.StateDB.GetCommittedState(contract.Address(), slot))
+	if original == current {
+		if original == common.EmptyHash { // create slot (2.1.1)
+			return cost + cfg.SstoreInitGasEIP2200, nil
+		}
+		if value == common.EmptyHash { // delete slot (2.1.2b)
+			evm.StateDB.AddRefund(cfg.SstoreClearRefundEIP2200)
+		}
+		return cost + (cfg.SstoreCleanGasEIP2200 - cfg.ColdSloadCostEIP2929), nil // write existing slot (2.1.2)
+	}
+	if original != common.EmptyHash {
+		if current == common.EmptyHash { // recreate slot (2.2.1.1)
+			evm.StateDB.SubRefund(cfg.SstoreClearRefundEIP2200)
+		} else if value == common.EmptyHash { // delete slot (2.2.1.2)
+			evm.StateDB.AddRefund(cfg.SstoreClearRefundEIP2200)
+		}
+	}
+	if original == value {
+		if original == common.EmptyHash { // reset to original inexistent slot (2.2.2.1)
+			evm.StateDB.AddRefund(cfg.SstoreInitGasEIP2200 - cfg.WarmStorageReadCostEIP2929)
+		} else { // reset to original existing slot (2.2.2.2)
+			evm.StateDB.AddRefund((cfg.SstoreCleanGasEIP2200 - cfg.ColdSloadCostEIP2929) - cfg.WarmStorageReadCostEIP2929)
+		}
+	}
+	return cost + cfg.WarmStorageReadCostEIP2929, nil // dirty update (2.2)
+}
+
+// gasSLoadEIP2929 calculates dynamic gas for SLOAD according to EIP-2929
+// For SLOAD, if the (address, storage_key) pair (where address is the address of the contract
+// whose storage is being read) is not yet in accessed_storage_keys,
+// charge 2100 gas and add the pair to accessed_storage_keys.
+// If the pair is already in accessed_storage_keys, charge 100 gas.
+func gasSLoadEIP2929(gt cfg.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	slot := common.BigToHash(stack.peek())
+	// Check slot presence in the access list
+	if _, slotPresent := evm.StateDB.SlotInAccessList(contract.Address(), slot); !slotPresent {
+		// If the caller cannot afford the cost, this change will be rolled back
+		// If he does afford it, we can skip checking the same thing later on, during execution
+		evm.StateDB.AddSlotToAccessList(contract.Address(), slot)
+		return cfg.ColdSloadCostEIP2929, nil
+	}
+	return cfg.WarmStorageReadCostEIP2929, nil
+}
+
+// gasExtCodeCopyEIP2929 implements extcodecopy according to EIP-2929
+// EIP spec:
+// > If the target is not in accessed_addresses,
+// > charge COLD_ACCOUNT_ACCESS_COST gas, and add the address to accessed_addresses.
+// > Otherwise, charge WARM_STORAGE_READ_COST gas.
+func gasExtCodeCopyEIP2929(gt cfg.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	// memory expansion first (dynamic part of pre-2929 implementation)
+	gt.ExtcodeCopy = cfg.WarmStorageReadCostEIP2929
+	gas, err := gasExtCodeCopy(gt, evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	addr := common.BigToAddress(stack.peek())
+	// Check slot presence in the access list
+	if !evm.StateDB.AddressInAccessList(addr) {
+		evm.StateDB.AddAddressToAccessList(addr)
+		var overflow bool
+		// We charge (cold-warm), since 'warm' is already charged as constantGas
+		if gas, overflow = math.SafeAdd(gas, cfg.ColdAccountAccessCostEIP2929-cfg.WarmStorageReadCostEIP2929); overflow {
+			return 0, errGasUintOverflow
+		}
+		return gas, nil
+	}
+	return gas, nil
+}
+
+// gasEip2929AccountCheck checks whether the first stack item (as address) is present in the access list.
+// If it is, this method returns the warm cost, otherwise the cold one and the
+// address is added to the access list.
+//
+// This method is used by:
+// - extcodehash,
+// - extcodesize,
+// - (ext) balance
+func gasEip2929AccountCheck(gt cfg.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	addr := common.BigToAddress(stack.peek())
+	// Check slot presence in the access list
+	if !evm.StateDB.AddressInAccessList(addr) {
+		// If the caller cannot afford the cost, this change will be rolled back
+		evm.StateDB.AddAddressToAccessList(addr)
+		return cfg.ColdAccountAccessCostEIP2929, nil
+	}
+	return cfg.WarmStorageReadCostEIP2929, nil
+}
+
+func makeCallVariantGasCallEIP2929(oldCalculator gasFunc) gasFunc {
+	return func(gt cfg.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		addr := common.BigToAddress(stack.Back(1))
+		// Check slot presence in the access list
+		warmAccess := evm.StateDB.AddressInAccessList(addr)
+		// The WARM_STORAGE_READ_COST (100) replaces the pre-2929 call cost
+		// of the gas table, the difference to the cold cost is charged here.
+		coldCost := cfg.ColdAccountAccessCostEIP2929 - cfg.WarmStorageReadCostEIP2929
+		if !warmAccess {
+			evm.StateDB.AddAddressToAccessList(addr)
+			// Charge the remaining difference here already, to correctly calculate available
+			// gas for call
+			if !contract.UseGas(coldCost) {
+				return 0, ErrOutOfGas
+			}
+		}
+		// Now call the old calculator, which takes into account
+		// - create new account
+		// - transfer value
+		// - memory expansion
+		// - 63/64ths rule
+		gt.Calls = cfg.WarmStorageReadCostEIP2929
+		gas, err := oldCalculator(gt, evm, contract, stack, mem, memorySize)
+		if warmAccess || err != nil {
+			return gas, err
+		}
+		// In case of a cold access, we temporarily add the cold charge back, and also
+		// add it to the returned gas. By adding it to the return, it will be charged
+		// outside of this function, as part of the dynamic gas, and that will make it
+		// also become correctly reported to tracers.
+		contract.Gas += coldCost
+
+		var overflow bool
+		if gas, overflow = math.SafeAdd(gas, coldCost); overflow {
+			return 0, errGasUintOverflow
+		}
+		return gas, nil
+	}
+}
+
+var (
+	gasCallEIP2929         = makeCallVariantGasCallEIP2929(gasCall)
+	gasDelegateCallEIP2929 = makeCallVariantGasCallEIP2929(gasDelegateCall)
+	gasStaticCallEIP2929   = makeCallVariantGasCallEIP2929(gasStaticCall)
+	gasCallCodeEIP2929     = makeCallVariantGasCallEIP2929(gasCallCode)
+)
+
+// gasSelfdestructEIP2929 charges the cold account access of the beneficiary
+// on top of the token transfer fees of gasSuicide.
+func gasSelfdestructEIP2929(gt cfg.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	address := common.BigToAddress(stack.peek())
+	var cold uint64
+	if !evm.StateDB.AddressInAccessList(address) {
+		// If the caller cannot afford the cost, this change will be rolled back
+		evm.StateDB.AddAddressToAccessList(address)
+		cold = cfg.ColdAccountAccessCostEIP2929
+	}
+	gas, err := gasSuicide(gt, evm, contract, stack, mem, memorySize)
+	if err != nil {
+		return 0, err
+	}
+	var overflow bool
+	if gas, overflow = math.SafeAdd(gas, cold); overflow {
+		return 0, errGasUintOverflow
+	}
+	return gas, nil
+}
@@ -0,0 +1,69 @@
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/vm/evm"
+	"github.com/lianxiangcloud/linkchain/vm/wasm"
+	tcvm "github.com/xunleichain/tc-wasm/vm"
+)
+
+var (
+	_ evm.Tracer  = (*StructLogger)(nil)
+	_ wasm.Tracer = (*StructLogger)(nil)
+)
+
+// StructLogger logs the opcodes stepped over by the EVM and the host
+// functions called from the WASM. It implements both evm.Tracer and
+// wasm.Tracer.
+type StructLogger struct {
+	evmLogger  *evm.StructLogger
+	wasmLogger *wasm.HostLogger
+}
+
+// NewStructLogger returns a new logger, cfg.Limit bounds each kind of log.
+func NewStructLogger(cfg *evm.LogConfig) *StructLogger {
+	limit := 0
+	if cfg != nil {
+		limit = cfg.Limit
+	}
+	return &StructLogger{
+		evmLogger:  evm.NewStructLogger(cfg),
+		wasmLogger: wasm.NewHostLogger(limit),
+	}
+}
+
+// CaptureStart implements the Tracer interfaces to initialize the tracing operation.
+func (l *StructLogger) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	l.evmLogger.CaptureStart(from, to, create, input, gas, value)
+	return l.wasmLogger.CaptureStart(from, to, create, input, gas, value)
+}
+
+// CaptureState implements evm.Tracer.
+func (l *StructLogger) CaptureState(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory, stack *evm.Stack, contract *evm.Contract, depth int, err error) error {
+	return l.evmLogger.CaptureState(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+}
+
+// CaptureFault implements evm.Tracer.
+func (l *StructLogger) CaptureFault(env *evm.EVM, pc uint64, op evm.OpCode, gas, cost uint64, memory *evm.Memory, stack *evm.Stack, contract *evm.Contract, depth int, err error) error {
+	return l.evmLogger.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+}
+
+// CaptureHostCall implements wasm.Tracer.
+func (l *StructLogger) CaptureHostCall(eng *tcvm.Engine, contract common.Address, name string, args []uint64, ret uint64, gas, cost uint64, depth int, err error) error {
+	return l.wasmLogger.CaptureHostCall(eng, contract, name, args, ret, gas, cost, depth, err)
+}
+
+// CaptureEnd implements the Tracer interfaces to finalize the tracing.
+func (l *StructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	l.evmLogger.CaptureEnd(output, gasUsed, t, err)
+	return l.wasmLogger.CaptureEnd(output, gasUsed, t, err)
+}
+
+// StructLogs returns the captured EVM log entries.
+func (l *StructLogger) StructLogs() []evm.StructLog { return l.evmLogger.StructLogs() }
+
+// HostLogs returns the captured WASM log entries.
+func (l *StructLogger) HostLogs() []wasm.HostLog { return l.wasmLogger.HostLogs() }
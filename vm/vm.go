@@ -24,6 +24,7 @@ type VmInterface interface {
 	SetToken(addr common.Address)
 	GetCoinbase() common.Address
 	GetBlockNumber() *big.Int
+	IsForkActive(name string) bool
 	GetTime() *big.Int
 	GasRate() uint64
 	GetStateDB() types.StateDB
@@ -62,10 +63,30 @@ func (v *VmFactory) AddVm(context types.Context, statedb types.StateDB, cfg type
 		v.evm = evm.NewEVM(*ctx, statedb, cfg)
 	case *wasm.Context:
 		log.Debug("VmFactory.AddVm", "Context", "NewWASM")
-		v.wasm = wasm.NewWASM(*ctx, statedb, cfg)
+		v.wasm = wasm.NewWASM(*ctx, statedb, wasmConfig(cfg))
 	default:
 		log.Error("VmFactory.AddVm", "context", "unknown type")
 	}
+	if v.evm != nil && v.wasm != nil {
+		// evm contracts call wasm contracts and the other way round, from the crossvm fork on
+		v.evm.SetCrossVM(v.wasm)
+		v.wasm.SetCrossVM(v.evm)
+	}
+}
+
+// wasmConfig derives the WASM options from the evm.Config both vms are
+// added with: tracing is turned on if its tracer can follow host calls too.
+func wasmConfig(cfg types.VmConfig) types.VmConfig {
+	evmCfg, ok := cfg.(evm.Config)
+	if !ok {
+		return cfg
+	}
+	var wasmCfg wasm.Config
+	if tracer, ok := evmCfg.Tracer.(wasm.Tracer); ok && evmCfg.Debug {
+		wasmCfg.Debug = true
+		wasmCfg.Tracer = tracer
+	}
+	return wasmCfg
 }
 
 func (v *VmFactory) isWasmCode(code []byte) bool {
@@ -83,6 +104,15 @@ func (v *VmFactory) GetRealVm(code []byte, toPtr *common.Address) VmInterface {
 	return v.evm
 }
 
+// IsForkActive reports whether the named protocol upgrade applies to the
+// block the vms run in.
+func (v *VmFactory) IsForkActive(name string) bool {
+	if v.evm != nil {
+		return v.evm.IsForkActive(name)
+	}
+	return v.wasm != nil && v.wasm.IsForkActive(name)
+}
+
 func (v *VmFactory) GetEvm() VmInterface {
 	return v.evm
 }
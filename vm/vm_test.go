@@ -0,0 +1,159 @@
+package vm
+
+import (
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	dbm "github.com/lianxiangcloud/linkchain/libs/db"
+	"github.com/lianxiangcloud/linkchain/state"
+	"github.com/lianxiangcloud/linkchain/types"
+	"github.com/lianxiangcloud/linkchain/vm/evm"
+	"github.com/lianxiangcloud/linkchain/vm/wasm"
+)
+
+// forwardCode returns evm code forwarding the call data to addr and
+// returning, or reverting with, the data returned
+func forwardCode(addr common.Address) []byte {
+	// calldatacopy(0, 0, calldatasize)
+	code := []byte{0x36, 0x60, 0x00, 0x60, 0x00, 0x37}
+	// call(gas, addr, 0, 0, calldatasize, 0, 0)
+	code = append(code, 0x60, 0x00, 0x60, 0x00, 0x36, 0x60, 0x00, 0x60, 0x00, 0x73)
+	code = append(code, addr.Bytes()...)
+	code = append(code, 0x5a, 0xf1)
+	// returndatacopy(0, 0, returndatasize)
+	code = append(code, 0x3d, 0x60, 0x00, 0x60, 0x00, 0x3e)
+	// jumpi(ok, success) revert(0, returndatasize) ok: return(0, returndatasize)
+	code = append(code, 0x60, byte(len(code)+7), 0x57, 0x3d, 0x60, 0x00, 0xfd, 0x5b, 0x3d, 0x60, 0x00, 0xf3)
+	return code
+}
+
+func newCrossVMFactory(statedb *state.StateDB) *VmFactory {
+	header := &types.Header{Height: 1, Time: 1565078742}
+	author := common.EmptyAddress
+	contextEvm := evm.NewEVMContext(header, nil, &author, 1)
+	contextWasm := wasm.NewWASMContext(header, nil, &author, 1000)
+	contextEvm.Forks = types.Forks{{Name: types.ForkCrossVM, Height: 1}}
+	contextWasm.Forks = contextEvm.Forks
+	vmenv := NewVM()
+	vmenv.AddVm(&contextEvm, statedb, evm.Config{})
+	vmenv.AddVm(&contextWasm, statedb, evm.Config{})
+	return &vmenv
+}
+
+func TestCrossVMCall(t *testing.T) {
+	types.SaveBalanceRecord = true
+	code, err := ioutil.ReadFile("./wasm/wasm-run/transfer.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		origin   = common.HexToAddress("0xaa")
+		forward  = common.HexToAddress("0xbb")
+		transfer = common.HexToAddress("0xcc")
+		to       = common.HexToAddress("0x01")
+		gas      = uint64(1000000)
+	)
+	call := func(statedb *state.StateDB) ([]byte, uint64, []types.BalanceRecord, error) {
+		vm := newCrossVMFactory(statedb).GetRealVm(statedb.GetCode(forward), &forward)
+		vm.Reset(types.NewMessage(origin, &forward, common.EmptyAddress, 0, big.NewInt(0), gas, big.NewInt(1), nil))
+		ret, leftOverGas, _, err := vm.Call(evm.AccountRef(origin), forward, common.EmptyAddress, []byte("a|a"), gas, big.NewInt(0))
+		return ret, leftOverGas, vm.GetOTxs(), err
+	}
+
+	// the wasm contract transfers 125 to 0x01
+	statedb, _ := state.New(common.EmptyHash, state.NewDatabase(dbm.NewMemDB()))
+	statedb.SetCode(forward, forwardCode(transfer))
+	statedb.SetCode(transfer, code)
+	statedb.AddBalance(transfer, big.NewInt(10000))
+	_, leftOverGas, otxs, err := call(statedb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leftOverGas == 0 || leftOverGas >= gas {
+		t.Errorf("unexpected gas left %d of %d", leftOverGas, gas)
+	}
+	if balance := statedb.GetBalance(to); balance.Cmp(big.NewInt(125)) != 0 {
+		t.Errorf("unexpected balance %v", balance)
+	}
+	if len(otxs) == 0 {
+		t.Fatal("no balance records")
+	}
+	if last := otxs[len(otxs)-1]; last.From != transfer || last.To != to || last.Amount.Cmp(big.NewInt(125)) != 0 {
+		t.Errorf("unexpected balance record %+v of the wasm transfer", last)
+	}
+
+	// the wasm contract fails to transfer, the evm caller reverts
+	statedb, _ = state.New(common.EmptyHash, state.NewDatabase(dbm.NewMemDB()))
+	statedb.SetCode(forward, forwardCode(transfer))
+	statedb.SetCode(transfer, code)
+	_, leftOverGas, otxs, err = call(statedb)
+	if err != types.ExecutionReverted {
+		t.Fatalf("expected revert, got %v", err)
+	}
+	if leftOverGas == 0 {
+		t.Error("revert consumed all the gas")
+	}
+	for _, br := range otxs {
+		if br.From == transfer {
+			t.Errorf("balance record %+v of the failed wasm call", br)
+		}
+	}
+}
+
+func TestCrossCallEVM(t *testing.T) {
+	types.SaveBalanceRecord = true
+	var (
+		caller = common.HexToAddress("0xaa")
+		callee = common.HexToAddress("0xbb")
+		gas    = uint64(100000)
+	)
+	statedb, _ := state.New(common.EmptyHash, state.NewDatabase(dbm.NewMemDB()))
+	statedb.AddBalance(caller, big.NewInt(100))
+	// mstore(0, 42) return(0, 32)
+	statedb.SetCode(callee, []byte{0x60, 0x2a, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3})
+
+	crossVM := newCrossVMFactory(statedb).GetEvm().(types.CrossVM)
+	msg := &types.CrossCallMsg{
+		Origin:   caller,
+		GasPrice: big.NewInt(1),
+		Caller:   caller,
+		To:       callee,
+		Gas:      gas,
+		Value:    big.NewInt(10),
+	}
+	res, err := crossVM.CrossCall(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Ret) != 32 || res.Ret[31] != 0x2a {
+		t.Errorf("unexpected output %x", res.Ret)
+	}
+	if res.LeftOverGas == 0 || res.LeftOverGas >= gas {
+		t.Errorf("unexpected gas left %d of %d", res.LeftOverGas, gas)
+	}
+	if statedb.GetBalance(callee).Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("unexpected balance %v", statedb.GetBalance(callee))
+	}
+	// the call is nested in the wasm one
+	if len(res.Otxs) != 1 || res.Otxs[0].Type != types.TxContract || res.Otxs[0].To != callee {
+		t.Errorf("unexpected balance records %+v", res.Otxs)
+	}
+
+	// revert(0, 0)
+	statedb.SetCode(callee, []byte{0x60, 0x00, 0x60, 0x00, 0xfd})
+	res, err = crossVM.CrossCall(msg)
+	if err != types.ExecutionReverted {
+		t.Fatalf("expected revert, got %v", err)
+	}
+	if res.LeftOverGas == 0 {
+		t.Error("revert consumed all the gas")
+	}
+	if len(res.Otxs) != 0 {
+		t.Errorf("unexpected balance records %+v of the reverted call", res.Otxs)
+	}
+	if statedb.GetBalance(callee).Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("reverted transfer, balance %v", statedb.GetBalance(callee))
+	}
+}
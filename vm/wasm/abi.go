@@ -0,0 +1,164 @@
+package wasm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/lianxiangcloud/linkchain/accounts/abi"
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
+)
+
+// packEVMCall returns the input of a call of the evm contract method, which is
+// given by its signature like "transfer(address,uint256)", with the arguments
+// params, a JSON array of the values of the method arguments. Numbers are JSON
+// numbers or decimal or hex strings, addresses and bytes are hex strings.
+func packEVMCall(method string, params []byte) ([]byte, error) {
+	open := strings.IndexByte(method, '(')
+	if open <= 0 || !strings.HasSuffix(method, ")") {
+		return nil, fmt.Errorf("invalid method signature %q", method)
+	}
+	name, typeList := method[:open], strings.TrimSpace(method[open+1:len(method)-1])
+
+	var types []string
+	if len(typeList) > 0 {
+		types = strings.Split(typeList, ",")
+	}
+	args := make(abi.Arguments, len(types))
+	for i, t := range types {
+		types[i] = strings.TrimSpace(t)
+		if strings.ContainsAny(types[i], "()") {
+			return nil, fmt.Errorf("tuple argument %q not supported", types[i])
+		}
+		typ, err := abi.NewType(types[i])
+		if err != nil {
+			return nil, err
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+
+	var raws []json.RawMessage
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &raws); err != nil {
+			return nil, fmt.Errorf("arguments are not a JSON array: %v", err)
+		}
+	}
+	if len(raws) != len(args) {
+		return nil, fmt.Errorf("%s takes %d arguments, %d given", name, len(args), len(raws))
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		v, err := abiValue(arg.Type, raws[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %v", i, err)
+		}
+		values[i] = v
+	}
+	packed, err := args.Pack(values...)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := name + "(" + strings.Join(types, ",") + ")"
+	return append(crypto.Keccak256([]byte(sig))[:4], packed...), nil
+}
+
+// abiValue converts the JSON value raw to the Go value abi packs as typ.
+func abiValue(typ abi.Type, raw json.RawMessage) (interface{}, error) {
+	switch typ.T {
+	case abi.BoolTy:
+		var b bool
+		err := json.Unmarshal(raw, &b)
+		return b, err
+	case abi.StringTy:
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %s", raw)
+		}
+		return common.HexToAddress(s), nil
+	case abi.BytesTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return hexutil.Decode(s)
+	case abi.FixedBytesTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != typ.Size {
+			return nil, fmt.Errorf("%d bytes given for %v", len(b), typ)
+		}
+		v := reflect.New(typ.Type).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v.Interface(), nil
+	case abi.IntTy, abi.UintTy:
+		return abiNumber(typ, raw)
+	case abi.SliceTy, abi.ArrayTy:
+		var raws []json.RawMessage
+		if err := json.Unmarshal(raw, &raws); err != nil {
+			return nil, err
+		}
+		var v reflect.Value
+		if typ.T == abi.SliceTy {
+			v = reflect.MakeSlice(typ.Type, len(raws), len(raws))
+		} else if len(raws) != typ.Size {
+			return nil, fmt.Errorf("%d elements given for %v", len(raws), typ)
+		} else {
+			v = reflect.New(typ.Type).Elem()
+		}
+		for i, r := range raws {
+			elem, err := abiValue(*typ.Elem, r)
+			if err != nil {
+				return nil, err
+			}
+			v.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return v.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported argument type %v", typ)
+}
+
+// abiNumber converts the JSON number or string raw to the integer type typ.
+func abiNumber(typ abi.Type, raw json.RawMessage) (interface{}, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		s = string(raw)
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", raw)
+	}
+	min, max := new(big.Int), new(big.Int).Lsh(big.NewInt(1), uint(typ.Size))
+	if typ.T == abi.IntTy {
+		max.Rsh(max, 1)
+		min.Neg(max)
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) >= 0 {
+		return nil, fmt.Errorf("%v out of range of %v", n, typ)
+	}
+
+	if typ.Kind == reflect.Ptr {
+		return n, nil
+	}
+	v := reflect.New(typ.Type).Elem()
+	if typ.T == abi.UintTy {
+		v.SetUint(n.Uint64())
+	} else {
+		v.SetInt(n.Int64())
+	}
+	return v.Interface(), nil
+}
@@ -10,13 +10,17 @@ import (
 	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
 	"github.com/lianxiangcloud/linkchain/libs/crypto/secp256k1"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
 	"github.com/lianxiangcloud/linkchain/libs/math"
 	"github.com/lianxiangcloud/linkchain/types"
 	"github.com/xunleichain/tc-wasm/vm"
 	"golang.org/x/crypto/sha3"
 )
 
-var errGasUintOverflow = errors.New("gas uint64 overflow")
+var (
+	errGasUintOverflow = errors.New("gas uint64 overflow")
+	errNoCrossVM       = errors.New("no evm to call")
+)
 
 func init() {
 	env := vm.NewEnvTable()
@@ -62,6 +66,9 @@ func init() {
 	env.RegisterFunc("TC_GetMsgValue", &TCGetMsgValue{})
 	env.RegisterFunc("TC_GetMsgTokenValue", &TCGetMsgTokenValue{})
 	env.RegisterFunc("TC_CallContract", &TCCallContract{})
+	env.RegisterFunc("TC_CallEVM", &TCCallEVM{})
+
+	traceEnvTable(env)
 }
 
 type TCNotify struct{}
@@ -1677,6 +1684,165 @@ func gasCallContract(eng *vm.Engine, index int64, args []uint64) (uint64, error)
 	return gas, nil
 }
 
+type TCCallEVM struct{}
+
+func (t *TCCallEVM) Call(index int64, ops interface{}, args []uint64) (uint64, error) {
+	eng := ops.(*vm.Engine)
+	return tcCallEVM(eng, index, args)
+}
+
+func (t *TCCallEVM) Gas(index int64, ops interface{}, args []uint64) (uint64, error) {
+	eng := ops.(*vm.Engine)
+	return gasCallEVM(eng, index, args)
+}
+
+// char *TC_CallEVM(char *address, char *method, char *args, char *amount)
+//
+// method is the signature of the evm contract method, like "transfer(address,uint256)",
+// and args the JSON array of its arguments, which are ABI encoded into the input of
+// the call. The call gets all the gas left, the return data is returned hex encoded.
+// A revert of the evm contract reverts the calling contract too.
+func tcCallEVM(eng *vm.Engine, index int64, args []uint64) (uint64, error) {
+	if len(args) < 2 {
+		return 0, vm.ErrAppInput
+	}
+	runningFrame, _ := eng.RunningAppFrame()
+	if runningFrame == nil {
+		return 0, vm.ErrEmptyFrame
+	}
+	vmem := runningFrame.VM.VMemory()
+	toTmp, err := vmem.GetString(args[0])
+	if err != nil || !common.IsHexAddress(string(toTmp)) {
+		return 0, vm.ErrInvalidApiArgs
+	}
+	to := common.HexToAddress(string(toTmp))
+	method, err := vmem.GetString(args[1])
+	if err != nil {
+		return 0, vm.ErrInvalidApiArgs
+	}
+	var params []byte
+	if len(args) >= 3 {
+		params, err = vmem.GetString(args[2])
+		if err != nil {
+			return 0, vm.ErrInvalidApiArgs
+		}
+	}
+	val := big.NewInt(0)
+	if len(args) >= 4 {
+		valTmp, err := vmem.GetString(args[3])
+		if err != nil {
+			return 0, vm.ErrInvalidApiArgs
+		}
+		if len(valTmp) > 0 {
+			var ok bool
+			val, ok = big.NewInt(0).SetString(string(valTmp), 0)
+			if !ok || val.Sign() < 0 {
+				return 0, vm.ErrInvalidApiArgs
+			}
+		}
+	}
+	input, err := packEVMCall(string(method), params)
+	if err != nil {
+		eng.Logger().Debug("TC_CallEVM pack input fail", "method", string(method), "args", string(params), "err", err)
+		return 0, vm.ErrInvalidApiArgs
+	}
+
+	mWasm, ok := eng.Ctx.(*WASM)
+	if !ok {
+		eng.Logger().Error("TC_CallEVM get WASM failed")
+		return 0, fmt.Errorf("TC_CallEVM get WASM failed")
+	}
+	if mWasm.crossVM == nil || !mWasm.IsForkActive(types.ForkCrossVM) {
+		return 0, errNoCrossVM
+	}
+
+	// the engine counts gas scaled by WasmGasRate, the evm transaction gas
+	gas := eng.Gas() / mWasm.WasmGasRate
+	from := common.BytesToAddress(eng.Contract.Address().Bytes())
+	eng.Logger().Debug("TC_CallEVM", "from", from.String(), "to", to.String(), "method", string(method), "val", val, "gas", gas)
+	res, err := mWasm.crossVM.CrossCall(&types.CrossCallMsg{
+		Origin:   mWasm.Origin,
+		GasPrice: mWasm.GasPrice,
+		Token:    mWasm.Token,
+		Caller:   from,
+		To:       to,
+		Input:    input,
+		Gas:      gas,
+		Value:    val,
+	})
+	eng.UseGas((gas - res.LeftOverGas) * mWasm.WasmGasRate)
+	mWasm.refundFee += res.Fee
+	if err != nil {
+		if err == types.ExecutionReverted {
+			return 0, vm.ErrExecutionReverted
+		}
+		return 0, err
+	}
+	mWasm.otxs = append(mWasm.otxs, res.Otxs...)
+
+	return vmem.SetBytes([]byte(hexutil.Encode(res.Ret)))
+}
+
+func gasCallEVM(eng *vm.Engine, index int64, args []uint64) (uint64, error) {
+	if len(args) < 2 {
+		return 0, vm.ErrAppInput
+	}
+	app, _ := eng.RunningAppFrame()
+	vmem := app.VM.VMemory()
+	toTmp, err := vmem.GetString(args[0])
+	if err != nil || !common.IsHexAddress(string(toTmp)) {
+		return 0, vm.ErrInvalidApiArgs
+	}
+	to := common.HexToAddress(string(toTmp))
+	dataLen, err := vmem.Strlen(args[1])
+	if err != nil {
+		return 0, err
+	}
+	if len(args) >= 3 {
+		paramLen, err := vmem.Strlen(args[2])
+		if err != nil {
+			return 0, err
+		}
+		dataLen += paramLen
+	}
+	gas := vm.GasTableEIP158.Calls + vm.GasExtStep*2
+	wordGas, overflow := vm.SafeMul(vm.ToWordSize(uint64(dataLen)), vm.CopyGas)
+	if overflow {
+		return 0, vm.ErrGasOverflow
+	}
+	if gas, overflow = vm.SafeAdd(gas, wordGas); overflow {
+		return 0, vm.ErrGasOverflow
+	}
+
+	if len(args) >= 4 {
+		valTmp, err := vmem.GetString(args[3])
+		if err != nil {
+			return 0, err
+		}
+		if len(valTmp) > 0 {
+			val, ok := big.NewInt(0).SetString(string(valTmp), 0)
+			if !ok || val.Sign() < 0 {
+				return 0, vm.ErrInvalidApiArgs
+			}
+			if val.Sign() > 0 {
+				transferGas, err := vm.GasTransfer(eng, index, args)
+				if err != nil {
+					return 0, err
+				}
+				fee := gasFee(eng, to, val)
+				if transferGas, overflow = math.SafeAdd(transferGas, fee); overflow {
+					return 0, errGasUintOverflow
+				}
+				if gas, overflow = vm.SafeAdd(gas, transferGas); overflow {
+					return 0, vm.ErrGasOverflow
+				}
+				eng.AddFee(fee)
+			}
+		}
+	}
+	return gas, nil
+}
+
 func gasFee(eng *vm.Engine, toAddr common.Address, val *big.Int) uint64 {
 	from := common.BytesToAddress(eng.Contract.Address().Bytes())
 	//Inner contract will not need gas when tranfer
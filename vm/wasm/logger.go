@@ -0,0 +1,139 @@
+package wasm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/xunleichain/tc-wasm/vm"
+)
+
+// Tracer is used to collect execution traces from a WASM transaction
+// execution. Contract code itself is opaque to the tracer, so a trace is made
+// of the host functions called by the contract: CaptureHostCall is invoked
+// after every host function returns.
+type Tracer interface {
+	CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error
+	CaptureHostCall(eng *vm.Engine, contract common.Address, name string, args []uint64, ret uint64, gas, cost uint64, depth int, err error) error
+	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error
+}
+
+// HostLog is emitted to the WASM for each host function call made by a contract.
+// Gas and GasCost are measured in engine units, i.e. scaled by WasmGasRate.
+type HostLog struct {
+	Contract common.Address `json:"contract"`
+	Name     string         `json:"name"`
+	Args     []uint64       `json:"args"`
+	Ret      uint64         `json:"ret"`
+	Gas      uint64         `json:"gas"`
+	GasCost  uint64         `json:"gasCost"`
+	Depth    int            `json:"depth"`
+	Err      error          `json:"-"`
+}
+
+// ErrorString formats the log's error as a string.
+func (l *HostLog) ErrorString() string {
+	if l.Err != nil {
+		return l.Err.Error()
+	}
+	return ""
+}
+
+// HostLogger is a WASM host call logger and implements Tracer.
+type HostLogger struct {
+	limit int
+
+	logs   []HostLog
+	output []byte
+	err    error
+}
+
+// NewHostLogger returns a new logger keeping at most limit entries,
+// zero means unlimited.
+func NewHostLogger(limit int) *HostLogger {
+	return &HostLogger{limit: limit}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (l *HostLogger) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureHostCall logs a new host function call.
+func (l *HostLogger) CaptureHostCall(eng *vm.Engine, contract common.Address, name string, args []uint64, ret uint64, gas, cost uint64, depth int, err error) error {
+	if l.limit != 0 && l.limit <= len(l.logs) {
+		return nil
+	}
+	argsCopy := make([]uint64, len(args))
+	copy(argsCopy, args)
+	l.logs = append(l.logs, HostLog{contract, name, argsCopy, ret, gas, cost, depth, err})
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (l *HostLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	l.output = output
+	l.err = err
+	return nil
+}
+
+// HostLogs returns the captured log entries.
+func (l *HostLogger) HostLogs() []HostLog { return l.logs }
+
+// Error returns the VM error captured by the trace.
+func (l *HostLogger) Error() error { return l.err }
+
+// Output returns the VM return value captured by the trace.
+func (l *HostLogger) Output() []byte { return l.output }
+
+// tracedEnvFunc decorates a host function so that its calls are reported to
+// the tracer of the WASM driving the engine. It is a plain passthrough unless
+// that WASM was created with Config.Debug.
+type tracedEnvFunc struct {
+	name string
+	fn   vm.EnvFunc
+}
+
+func (t *tracedEnvFunc) Call(index int64, ops interface{}, args []uint64) (uint64, error) {
+	eng := ops.(*vm.Engine)
+	mWasm, ok := eng.Ctx.(*WASM)
+	if !ok || !mWasm.vmConfig.Debug {
+		return t.fn.Call(index, ops, args)
+	}
+
+	// The callee may switch eng.Contract and push frames (TC_CallContract),
+	// so take the caller's view before running it.
+	var (
+		contract = common.BytesToAddress(eng.Contract.Address().Bytes())
+		gas      = eng.Gas()
+		cost     = mWasm.hostGasCost
+		depth    = eng.FrameIndex + 1
+	)
+	ret, err := t.fn.Call(index, ops, args)
+	mWasm.vmConfig.Tracer.CaptureHostCall(eng, contract, t.name, args, ret, gas, cost, depth, err)
+	return ret, err
+}
+
+func (t *tracedEnvFunc) Gas(index int64, ops interface{}, args []uint64) (uint64, error) {
+	cost, err := t.fn.Gas(index, ops, args)
+	if mWasm, ok := ops.(*vm.Engine).Ctx.(*WASM); ok {
+		mWasm.hostGasCost = cost
+	}
+	return cost, err
+}
+
+// traceEnvTable wraps every host function registered in env with a tracedEnvFunc.
+// It has to run before any contract is loaded, as modules copy the host
+// functions they import.
+func traceEnvTable(env *vm.EnvTable) {
+	for _, name := range env.Exports.Names {
+		fn := env.GetFuncByName(name)
+		if fn == nil {
+			continue
+		}
+		if _, ok := fn.(*tracedEnvFunc); ok {
+			continue
+		}
+		env.RegisterFunc(name, &tracedEnvFunc{name: name, fn: fn})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"time"
 
 	cfg "github.com/lianxiangcloud/linkchain/config"
 	"github.com/lianxiangcloud/linkchain/libs/common"
@@ -68,6 +69,7 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
+	Forks       types.Forks    // Provides the protocol upgrades of the chain
 
 	WasmGasRate uint64
 }
@@ -198,7 +200,12 @@ func run(wasm *WASM, c types.Contract, input []byte) ([]byte, uint64, error) {
 	return []byte(retData), gas, err
 }
 
+// Config are the configuration options for the WASM
 type Config struct {
+	// Debug enables tracing of host function calls
+	Debug bool
+	// Tracer is the host function call logger
+	Tracer Tracer
 }
 
 type WASM struct {
@@ -213,20 +220,31 @@ type WASM struct {
 	eng *vm.Engine
 	app *vm.APP
 
+	// virtual machine configuration options used to initialise the wasm.
+	vmConfig Config
+	// hostGasCost holds the cost of the host function about to be called,
+	// it is only maintained for the tracer.
+	hostGasCost uint64
+
 	otxs      []types.BalanceRecord
 	refundFee uint64
+
+	// crossVM runs the calls of TC_CallEVM
+	crossVM types.CrossVM
 }
 
 // NewWASM returns a new WASM. The returned WASM is not thread safe and should
 // only ever be used *once*.
 func NewWASM(c types.Context, statedb types.StateDB, vmc types.VmConfig) *WASM {
 	ctx := c.(Context)
+	vmConfig, _ := vmc.(Config)
 
 	return &WASM{
-		Context: ctx,
-		StateDB: statedb,
-		Issued:  make(chan bool, 1),
-		otxs:    make([]types.BalanceRecord, 0),
+		Context:  ctx,
+		StateDB:  statedb,
+		Issued:   make(chan bool, 1),
+		vmConfig: vmConfig,
+		otxs:     make([]types.BalanceRecord, 0),
 	}
 }
 
@@ -240,6 +258,61 @@ func (wasm *WASM) Reset(msg types.Message) {
 	wasm.Issued = make(chan bool, 1)
 }
 
+// SetCrossVM sets the vm running the calls of TC_CallEVM into evm contracts.
+func (wasm *WASM) SetCrossVM(crossVM types.CrossVM) {
+	wasm.crossVM = crossVM
+}
+
+// CrossCall runs the call of an evm contract into a wasm contract, the value
+// has already been transferred by the evm. The wasm vm has no read only mode,
+// the changes of a read only call are reverted instead.
+func (wasm *WASM) CrossCall(msg *types.CrossCallMsg) (*types.CrossCallResult, error) {
+	wasm.Context.Origin = msg.Origin
+	wasm.Context.Token = msg.Token
+	if msg.GasPrice != nil {
+		wasm.Context.GasPrice = new(big.Int).Set(msg.GasPrice)
+	}
+	var (
+		snapshot            = wasm.StateDB.Snapshot()
+		startTxRecordsIndex = len(wasm.otxs)
+		startRefundFee      = wasm.refundFee
+		to                  = AccountRef(msg.To)
+	)
+	contract := NewContract(AccountRef(msg.Caller), to, msg.Value, msg.Gas)
+	contract.SetCallCode(&msg.To, wasm.StateDB.GetCodeHash(msg.To), wasm.StateDB.GetCode(msg.To))
+	contract.Input = msg.Input
+
+	ret, leftOverGas, err := run(wasm, contract, msg.Input)
+	res := &types.CrossCallResult{Ret: ret, LeftOverGas: leftOverGas, Fee: wasm.refundFee - startRefundFee}
+	wasm.refundFee = startRefundFee
+
+	select {
+	case <-wasm.Issued:
+		if err == nil {
+			_, err = wasm.GetUTXOChangeRate(contract.Address())
+			if err != nil {
+				log.Error("issue without decimals set", "conAddr", contract.Address())
+				err = vm.ErrExecutionReverted
+			}
+		}
+	default:
+	}
+
+	if err == nil && !msg.ReadOnly {
+		res.Otxs = append(res.Otxs, wasm.otxs[startTxRecordsIndex:]...)
+	}
+	wasm.otxs = wasm.otxs[:startTxRecordsIndex]
+	if err != nil || msg.ReadOnly {
+		wasm.StateDB.RevertToSnapshot(snapshot)
+	}
+	if err == vm.ErrExecutionReverted {
+		err = types.ExecutionReverted
+	} else if err != nil {
+		res.LeftOverGas = 0
+	}
+	return res, err
+}
+
 func (wasm *WASM) GetCode(bz []byte) []byte {
 	return wasm.StateDB.GetCode(common.BytesToAddress(bz))
 }
@@ -267,6 +340,15 @@ func (wasm *WASM) UTXOCall(c types.ContractRef, addr, token common.Address, inpu
 	contract.SetCallCode(&addr, wasm.StateDB.GetCodeHash(addr), wasm.StateDB.GetCode(addr))
 	contract.Input = input
 
+	// Capture the tracer start/end events in debug mode
+	if wasm.vmConfig.Debug {
+		start := time.Now()
+		wasm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
+
+		defer func() { // Lazy evaluation of the parameters
+			wasm.vmConfig.Tracer.CaptureEnd(ret, gas-leftOverGas, time.Since(start), err)
+		}()
+	}
 	ret, leftOverGas, err = run(wasm, contract, input)
 	if err == nil {
 		wasm.refundFee = 0
@@ -331,6 +413,15 @@ func (wasm *WASM) Call(c types.ContractRef, addr, token common.Address, input []
 	contract.SetCallCode(&addr, wasm.StateDB.GetCodeHash(addr), wasm.StateDB.GetCode(addr))
 	contract.Input = input
 
+	// Capture the tracer start/end events in debug mode
+	if wasm.vmConfig.Debug {
+		start := time.Now()
+		wasm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
+
+		defer func() { // Lazy evaluation of the parameters
+			wasm.vmConfig.Tracer.CaptureEnd(ret, gas-leftOverGas, time.Since(start), err)
+		}()
+	}
 	ret, leftOverGas, err = run(wasm, contract, input)
 	if err == nil {
 		wasm.refundFee = 0
@@ -558,6 +649,15 @@ func (wasm *WASM) Create(c types.ContractRef, data []byte, gas uint64, value *bi
 	contract.Input = []byte(strInput)
 	contract.CreateCall = true
 
+	// Capture the tracer start/end events in debug mode
+	if wasm.vmConfig.Debug {
+		start := time.Now()
+		wasm.vmConfig.Tracer.CaptureStart(caller.Address(), contractAddr, true, data, gas, value)
+
+		defer func() { // Lazy evaluation of the parameters
+			wasm.vmConfig.Tracer.CaptureEnd(ret, gas-leftOverGas, time.Since(start), err)
+		}()
+	}
 	// TODO :wasm not found code ,return err,create fail,
 	ret, leftOverGas, err = run(wasm, contract, contract.Input)
 	if err == nil {
@@ -650,6 +750,11 @@ func (wasm *WASM) GetBlockNumber() *big.Int {
 	return wasm.BlockNumber
 }
 
+// IsForkActive reports whether the named protocol upgrade applies to the current block.
+func (wasm *WASM) IsForkActive(name string) bool {
+	return wasm.BlockNumber != nil && wasm.Forks.IsActive(name, wasm.BlockNumber.Uint64())
+}
+
 //Time
 func (wasm *WASM) GetTime() *big.Int {
 	return wasm.Time
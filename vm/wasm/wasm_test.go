@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/libs/crypto"
 	"github.com/lianxiangcloud/linkchain/libs/db"
 	"github.com/lianxiangcloud/linkchain/libs/log"
 	"github.com/lianxiangcloud/linkchain/state"
@@ -560,3 +561,40 @@ func TestTransfer(t *testing.T) {
 	t.Logf("to account balance: %d after exec contract method", cState.GetBalance(common.HexToAddress("0x0000000000000000000000000000000000000001")))
 	return
 }
+
+func TestPackEVMCall(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	input, err := packEVMCall("transfer(address, uint256)", []byte(`["0x00000000000000000000000000000000000000aa", "0x64"]`))
+	if err != nil {
+		t.Fatalf("packEVMCall fail: %s", err)
+	}
+	wanted := append(crypto.Keccak256([]byte("transfer(address,uint256)"))[:4], common.LeftPadBytes(to.Bytes(), 32)...)
+	wanted = append(wanted, common.LeftPadBytes([]byte{100}, 32)...)
+	if !bytes.Equal(input, wanted) {
+		t.Fatalf("input not match: wanted(%x), got(%x)", wanted, input)
+	}
+
+	input, err = packEVMCall("set(uint8,bool,bytes2,int64[])", []byte(`[7, true, "0x0102", [-1, "2"]]`))
+	if err != nil {
+		t.Fatalf("packEVMCall fail: %s", err)
+	}
+	if len(input) != 4+32*7 {
+		t.Fatalf("unexpected input length %d", len(input))
+	}
+
+	for _, c := range []struct{ method, args string }{
+		{"transfer", `[]`},
+		{"transfer(address,uint256)", `["0xaa"]`},
+		{"transfer(address,uint256)", `["0x00000000000000000000000000000000000000aa", -1]`},
+		{"set(uint8)", `[256]`},
+		{"set(bytes2)", `["0x01"]`},
+		{"set((uint8,uint8))", `[[1, 2]]`},
+	} {
+		if _, err := packEVMCall(c.method, []byte(c.args)); err == nil {
+			t.Errorf("packEVMCall(%s, %s) should fail", c.method, c.args)
+		}
+	}
+	if input, err = packEVMCall("get()", nil); err != nil || len(input) != 4 {
+		t.Fatalf("packEVMCall(get()) = %x, %v", input, err)
+	}
+}
@@ -21,9 +21,11 @@ func AddNodeFlags(cmd *cobra.Command) {
 	cmd.Flags().String("home", config.BaseConfig.RootDir, "home")
 	cmd.Flags().String("log_dir", config.BaseConfig.LogPath, "log_dir")
 	cmd.Flags().Bool("test_net", config.BaseConfig.TestNet, "signparam will be set to 29154 if this flag is set")
+	cmd.Flags().Bool("watch_only", config.BaseConfig.WatchOnly, "Drop the spend keys of the opened accounts, transactions are signed offline")
 
 	cmd.Flags().StringSlice("daemon.peer_rpc", config.Daemon.PeerRPC, "peer rpc url")
 	cmd.Flags().Bool("daemon.sync_quick", config.Daemon.SyncQuick, "wallet sync block use quick api")
+	cmd.Flags().Int("daemon.scan_workers", config.Daemon.ScanWorkers, "Number of blocks fetched and scanned in parallel by the wallet sync")
 	cmd.Flags().Bool("daemon.skip_verify", config.Daemon.SkipVerify, "set daemon skip verify https")
 	cmd.Flags().StringSlice("daemon.bootnode", config.Daemon.BootNode, "set daemon bootnode")
 	cmd.Flags().String("daemon.nc", config.Daemon.NC, "set daemon header nc")
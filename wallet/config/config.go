@@ -44,6 +44,9 @@ type BaseConfig struct {
 	LogPath string `mapstructure:"log_dir"`
 	LogFile string `mapstructure:"log_file"`
 	TestNet bool   `mapstructure:"test_net"`
+	// WatchOnly drops the spend keys of the opened accounts, they can
+	// create unsigned transactions but not sign them
+	WatchOnly bool `mapstructure:"watch_only"`
 }
 
 // DefaultBaseConfig return default config
@@ -121,6 +124,8 @@ type DaemonConfig struct {
 	SyncQuick  bool     `mapstructure:"sync_quick"`
 	SkipVerify bool     `mapstructure:"skip_verify"`
 	BootNode   []string `mapstructure:"bootnode"`
+	// ScanWorkers is the number of blocks fetched and scanned in parallel
+	ScanWorkers int `mapstructure:"scan_workers"`
 }
 
 // RPCConfig rpc config
@@ -139,13 +144,14 @@ type RPCConfig struct {
 // DefaultDaemonConfig returns default daemon config
 func DefaultDaemonConfig() *DaemonConfig {
 	return &DaemonConfig{
-		PeerRPC:    []string{},
-		NC:         defaultNC,
-		Origin:     defaultOrigin,
-		Appversion: defaultAppversion,
-		SyncQuick:  false,
-		SkipVerify: false,
-		BootNode:   []string{},
+		PeerRPC:     []string{},
+		NC:          defaultNC,
+		Origin:      defaultOrigin,
+		Appversion:  defaultAppversion,
+		SyncQuick:   false,
+		SkipVerify:  false,
+		BootNode:    []string{},
+		ScanWorkers: 4,
 	}
 }
 
@@ -8,6 +8,8 @@ import (
 	"github.com/lianxiangcloud/linkchain/accounts"
 	"github.com/lianxiangcloud/linkchain/accounts/keystore"
 	"github.com/lianxiangcloud/linkchain/libs/common"
+	lkctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	"github.com/lianxiangcloud/linkchain/libs/hexutil"
 	"github.com/lianxiangcloud/linkchain/wallet/types"
 )
 
@@ -78,7 +80,10 @@ func fetchKeystore(am *accounts.Manager) *keystore.KeyStore {
 // UnlockAccount will unlock the account associated with the given address with
 // the given password for duration seconds. If duration is nil it will use a
 // default of 300 seconds. It returns an indication if the account was unlocked.
-func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string, duration *uint64) (bool, error) {
+// The blocks before restoreHeight are not scanned for utxo outputs, if it is nil
+// the restore height of the last unlocking is kept. A restore date is not looked
+// up, the height of a block of that date is to be found from the node first.
+func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string, duration *uint64, restoreHeight *hexutil.Uint64) (bool, error) {
 	const max = uint64(time.Duration(math.MaxInt64) / time.Second)
 	var d time.Duration
 	if duration == nil {
@@ -98,7 +103,11 @@ func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string,
 			for _, account := range wallet.Accounts() {
 				if account.Address == addr {
 					keypath := account.URL.Path
-					err = s.wallet.OpenWallet(keypath, password)
+					var height uint64
+					if restoreHeight != nil {
+						height = uint64(*restoreHeight)
+					}
+					err = s.wallet.OpenWallet(keypath, password, height)
 					if err != nil {
 						return false, types.ErrInnerServer
 					}
@@ -111,6 +120,13 @@ func (s *PrivateAccountAPI) UnlockAccount(addr common.Address, password string,
 	return err == nil, err
 }
 
+// OpenViewWallet opens a watch only wallet from the view secret key and the spend public key. The
+// outputs received since the restore height are found, but no transaction can be signed. It returns
+// the address identifying the wallet in the addr args.
+func (s *PrivateAccountAPI) OpenViewWallet(args types.OpenViewWalletArgs) (common.Address, error) {
+	return s.wallet.OpenViewWallet(lkctypes.SecretKey(args.ViewKey), lkctypes.PublicKey(args.SpendPublicKey), uint64(args.RestoreHeight))
+}
+
 // LockAccount will lock the account associated with the given address when it's unlocked.
 func (s *PrivateAccountAPI) LockAccount(addr common.Address) bool {
 	s.wallet.LockAccount(addr)
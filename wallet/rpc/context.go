@@ -49,11 +49,17 @@ func (c *Context) SetLogger(logger log.Logger) {
 type Wallet interface {
 	CreateUTXOTransaction(from common.Address, nonce uint64, subaddrs []uint64, dests []types.DestEntry,
 		tokenID common.Address, refundAddr common.Address, extra []byte) ([]*types.UTXOTransaction, error)
+	CreateUnsignedUTXOTransaction(from common.Address, subaddrs []uint64, dests []types.DestEntry,
+		tokenID common.Address, extra []byte) ([]*wtypes.UnsignedUTXOTx, error)
+	SignUnsignedUTXOTransaction(utx *wtypes.UnsignedUTXOTx) (*types.UTXOTransaction, error)
+	ExportKeyImages(addr *common.Address) ([]wtypes.KeyImageEntry, error)
+	ImportKeyImages(entries []wtypes.KeyImageEntry, addr *common.Address) (uint64, error)
 	GetBalance(index uint64, token *common.Address, addr *common.Address) (*big.Int, error)
 	GetHeight(addr *common.Address) (localHeight *big.Int, remoteHeight *big.Int)
 	GetAddress(index uint64, addr *common.Address) (string, error)
 	Transfer(txs []string) (ret []wtypes.SendTxRet)
-	OpenWallet(walletfile string, password string) error
+	OpenWallet(walletfile string, password string, restoreHeight uint64) error
+	OpenViewWallet(viewSKey lkctypes.SecretKey, spendPKey lkctypes.PublicKey, restoreHeight uint64) (common.Address, error)
 	CreateSubAccount(maxSub uint64, addr *common.Address) error
 	AutoRefreshBlockchain(autoRefresh bool, addr *common.Address) error
 	GetAccountInfo(tokenID *common.Address, addr *common.Address) (*wtypes.GetAccountInfoResult, error)
@@ -33,13 +33,11 @@ func NewPublicTransactionPoolAPI(b Backend, nonceLock *AddrLocker) *PublicTransa
 	return &PublicTransactionPoolAPI{b, nonceLock, b.GetWallet()}
 }
 
-func (s *PublicTransactionPoolAPI) signUTXOTransaction(ctx context.Context, args wtypes.SendUTXOTxArgs) (*wtypes.SignUTXOTransactionResult, error) {
-	args.SetDefaults()
-
-	log.Debug("signTx", "input", args)
+// utxoDests return the dests of args, and whether one of them is an account output
+func utxoDests(args wtypes.SendUTXOTxArgs) ([]types.DestEntry, bool, error) {
 	destsCnt := len(args.Dests)
 	if destsCnt == 0 {
-		return nil, wtypes.ErrArgsInvalid
+		return nil, false, wtypes.ErrArgsInvalid
 	}
 
 	dests := make([]types.DestEntry, 0)
@@ -49,12 +47,12 @@ func (s *PublicTransactionPoolAPI) signUTXOTransaction(ctx context.Context, args
 		toAddress := args.Dests[i].Addr
 		if len(toAddress) == wtypes.UTXO_ADDR_STR_LEN {
 			if utxoDestsCnt >= wtypes.UTXO_DESTS_MAX_NUM {
-				return nil, wtypes.ErrUTXODestsOverLimit
+				return nil, false, wtypes.ErrUTXODestsOverLimit
 			}
 			// utxo address
 			addr, err := wallet.StrToAddress(args.Dests[i].Addr)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 
 			var remark [32]byte
@@ -62,17 +60,17 @@ func (s *PublicTransactionPoolAPI) signUTXOTransaction(ctx context.Context, args
 			log.Debug("signUTXOTransaction", "Remark", args.Dests[i].Remark, "len", len(args.Dests[i].Remark), "remark", remark)
 			isSubaddr, err := wallet.IsSubaddress(args.Dests[i].Addr)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			dests = append(dests, &types.UTXODestEntry{Addr: *addr, Amount: args.Dests[i].Amount.ToInt(), IsSubaddress: isSubaddr, Remark: remark})
 			utxoDestsCnt++
 		} else {
 			if !common.IsHexAddress(toAddress) {
-				return nil, wtypes.ErrArgsInvalid
+				return nil, false, wtypes.ErrArgsInvalid
 			}
 			if hasOneAccountOutput {
 				// can not sign more than one account output
-				return nil, wtypes.ErrAccDestsOverLimit
+				return nil, false, wtypes.ErrAccDestsOverLimit
 			}
 			addr := common.HexToAddress(toAddress)
 			dests = append(dests, &types.AccountDestEntry{To: addr, Amount: args.Dests[i].Amount.ToInt(), Data: args.Dests[i].Data})
@@ -80,6 +78,17 @@ func (s *PublicTransactionPoolAPI) signUTXOTransaction(ctx context.Context, args
 		}
 
 	}
+	return dests, hasOneAccountOutput, nil
+}
+
+func (s *PublicTransactionPoolAPI) signUTXOTransaction(ctx context.Context, args wtypes.SendUTXOTxArgs) (*wtypes.SignUTXOTransactionResult, error) {
+	args.SetDefaults()
+
+	log.Debug("signTx", "input", args)
+	dests, hasOneAccountOutput, err := utxoDests(args)
+	if err != nil {
+		return nil, err
+	}
 	if args.From != common.EmptyAddress && hasOneAccountOutput {
 		return nil, wtypes.ErrTxTypeNotSupport
 	}
@@ -128,6 +137,88 @@ func (s *PublicTransactionPoolAPI) SignUTXOTransaction(ctx context.Context, args
 	return s.signUTXOTransaction(ctx, args)
 }
 
+// CreateUnsignedUTXOTx builds utxo input transactions without signing them, the account does not
+// need its spend key. They are signed by SignUnsignedUTXOTx on a wallet holding the spend key, and
+// sent by SendRawUTXOTransaction.
+func (s *PublicTransactionPoolAPI) CreateUnsignedUTXOTx(ctx context.Context, args wtypes.SendUTXOTxArgs) (*wtypes.CreateUnsignedUTXOTxResult, error) {
+	args.SetDefaults()
+
+	log.Debug("CreateUnsignedUTXOTx", "input", args)
+	if args.From != common.EmptyAddress {
+		// account input transactions are signed by the account manager
+		return nil, wtypes.ErrTxTypeNotSupport
+	}
+	dests, _, err := utxoDests(args)
+	if err != nil {
+		return nil, err
+	}
+	utxs, err := s.wallet.CreateUnsignedUTXOTransaction(args.From, args.SubAddrs, dests, *args.TokenID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsignedtxs []wtypes.UnsignedUTXORet
+	for _, utx := range utxs {
+		bz, err := ser.EncodeToBytes(utx)
+		if err != nil {
+			return nil, wtypes.ErrInnerServer
+		}
+		unsignedtxs = append(unsignedtxs, wtypes.UnsignedUTXORet{
+			Raw:       fmt.Sprintf("0x%s", hex.EncodeToString(bz)),
+			Subaddrs:  utx.Subaddrs,
+			OutAmount: utx.OutAmount,
+		})
+	}
+	return &wtypes.CreateUnsignedUTXOTxResult{Txs: unsignedtxs}, nil
+}
+
+// SignUnsignedUTXOTx signs the transactions created by CreateUnsignedUTXOTx, it does not need the
+// node. The account needs its spend key.
+func (s *PublicTransactionPoolAPI) SignUnsignedUTXOTx(ctx context.Context, raws []hexutil.Bytes) (*wtypes.SignUTXOTransactionResult, error) {
+	if len(raws) == 0 {
+		return nil, wtypes.ErrArgsInvalid
+	}
+	var signedtxs []wtypes.SignUTXORet
+	for _, raw := range raws {
+		var utx wtypes.UnsignedUTXOTx
+		if err := ser.DecodeBytes(raw, &utx); err != nil {
+			return nil, wtypes.ErrUnsignedTxInvalid
+		}
+		tx, err := s.wallet.SignUnsignedUTXOTransaction(&utx)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := ser.EncodeToBytes(tx)
+		if err != nil {
+			return nil, wtypes.ErrInnerServer
+		}
+		signedtxs = append(signedtxs, wtypes.SignUTXORet{
+			Raw:       fmt.Sprintf("0x%s", hex.EncodeToString(bz)),
+			Hash:      tx.Hash(),
+			Gas:       hexutil.Uint64(tx.Gas()),
+			Subaddrs:  utx.Subaddrs,
+			OutAmount: utx.OutAmount,
+		})
+	}
+	return &wtypes.SignUTXOTransactionResult{Txs: signedtxs}, nil
+}
+
+// ExportKeyImages returns the key images of the outputs known by the account, to be imported by
+// a watch only wallet.
+func (s *PublicTransactionPoolAPI) ExportKeyImages(ctx context.Context, addr *common.Address) ([]wtypes.KeyImageEntry, error) {
+	return s.wallet.ExportKeyImages(addr)
+}
+
+// ImportKeyImages imports the key images exported by ExportKeyImages, so that a watch only wallet
+// tracks the spent outputs.
+func (s *PublicTransactionPoolAPI) ImportKeyImages(ctx context.Context, args wtypes.ImportKeyImagesArgs) (*wtypes.ImportKeyImagesResult, error) {
+	imported, err := s.wallet.ImportKeyImages(args.KeyImages, args.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return &wtypes.ImportKeyImagesResult{Imported: hexutil.Uint64(imported)}, nil
+}
+
 // SendUTXOTransaction send utxo tx
 func (s *PublicTransactionPoolAPI) SendUTXOTransaction(ctx context.Context, args wtypes.SendUTXOTxArgs) (*wtypes.SendUTXOTransactionResult, error) {
 	signRet, err := s.signUTXOTransaction(ctx, args)
@@ -98,6 +98,21 @@ func (mr *MockWalletMockRecorder) CreateUTXOTransaction(arg0, arg1, arg2, arg3,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUTXOTransaction", reflect.TypeOf((*MockWallet)(nil).CreateUTXOTransaction), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
+// CreateUnsignedUTXOTransaction mocks base method
+func (m *MockWallet) CreateUnsignedUTXOTransaction(arg0 common.Address, arg1 []uint64, arg2 []types0.DestEntry, arg3 common.Address, arg4 []byte) ([]*types1.UnsignedUTXOTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUnsignedUTXOTransaction", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*types1.UnsignedUTXOTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUnsignedUTXOTransaction indicates an expected call of CreateUnsignedUTXOTransaction
+func (mr *MockWalletMockRecorder) CreateUnsignedUTXOTransaction(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUnsignedUTXOTransaction", reflect.TypeOf((*MockWallet)(nil).CreateUnsignedUTXOTransaction), arg0, arg1, arg2, arg3, arg4)
+}
+
 // DelUTXOAddInfo mocks base method
 func (m *MockWallet) DelUTXOAddInfo(arg0 common.Hash) error {
 	m.ctrl.T.Helper()
@@ -127,6 +142,21 @@ func (mr *MockWalletMockRecorder) EthEstimateGas(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EthEstimateGas", reflect.TypeOf((*MockWallet)(nil).EthEstimateGas), arg0)
 }
 
+// ExportKeyImages mocks base method
+func (m *MockWallet) ExportKeyImages(arg0 *common.Address) ([]types1.KeyImageEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportKeyImages", arg0)
+	ret0, _ := ret[0].([]types1.KeyImageEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportKeyImages indicates an expected call of ExportKeyImages
+func (mr *MockWalletMockRecorder) ExportKeyImages(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportKeyImages", reflect.TypeOf((*MockWallet)(nil).ExportKeyImages), arg0)
+}
+
 // GetAccountInfo mocks base method
 func (m *MockWallet) GetAccountInfo(arg0, arg1 *common.Address) (*types1.GetAccountInfoResult, error) {
 	m.ctrl.T.Helper()
@@ -442,6 +472,21 @@ func (mr *MockWalletMockRecorder) GetWalletEthAddress() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletEthAddress", reflect.TypeOf((*MockWallet)(nil).GetWalletEthAddress))
 }
 
+// ImportKeyImages mocks base method
+func (m *MockWallet) ImportKeyImages(arg0 []types1.KeyImageEntry, arg1 *common.Address) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportKeyImages", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportKeyImages indicates an expected call of ImportKeyImages
+func (mr *MockWalletMockRecorder) ImportKeyImages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportKeyImages", reflect.TypeOf((*MockWallet)(nil).ImportKeyImages), arg0, arg1)
+}
+
 // LockAccount mocks base method
 func (m *MockWallet) LockAccount(arg0 common.Address) error {
 	m.ctrl.T.Helper()
@@ -456,18 +501,33 @@ func (mr *MockWalletMockRecorder) LockAccount(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAccount", reflect.TypeOf((*MockWallet)(nil).LockAccount), arg0)
 }
 
+// OpenViewWallet mocks base method
+func (m *MockWallet) OpenViewWallet(arg0 types.SecretKey, arg1 types.PublicKey, arg2 uint64) (common.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenViewWallet", arg0, arg1, arg2)
+	ret0, _ := ret[0].(common.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenViewWallet indicates an expected call of OpenViewWallet
+func (mr *MockWalletMockRecorder) OpenViewWallet(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenViewWallet", reflect.TypeOf((*MockWallet)(nil).OpenViewWallet), arg0, arg1, arg2)
+}
+
 // OpenWallet mocks base method
-func (m *MockWallet) OpenWallet(arg0, arg1 string) error {
+func (m *MockWallet) OpenWallet(arg0, arg1 string, arg2 uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "OpenWallet", arg0, arg1)
+	ret := m.ctrl.Call(m, "OpenWallet", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // OpenWallet indicates an expected call of OpenWallet
-func (mr *MockWalletMockRecorder) OpenWallet(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) OpenWallet(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenWallet", reflect.TypeOf((*MockWallet)(nil).OpenWallet), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenWallet", reflect.TypeOf((*MockWallet)(nil).OpenWallet), arg0, arg1, arg2)
 }
 
 // RescanBlockchain mocks base method
@@ -542,6 +602,21 @@ func (mr *MockWalletMockRecorder) SetRefreshBlockInterval(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRefreshBlockInterval", reflect.TypeOf((*MockWallet)(nil).SetRefreshBlockInterval), arg0, arg1)
 }
 
+// SignUnsignedUTXOTransaction mocks base method
+func (m *MockWallet) SignUnsignedUTXOTransaction(arg0 *types1.UnsignedUTXOTx) (*types0.UTXOTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignUnsignedUTXOTransaction", arg0)
+	ret0, _ := ret[0].(*types0.UTXOTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignUnsignedUTXOTransaction indicates an expected call of SignUnsignedUTXOTransaction
+func (mr *MockWalletMockRecorder) SignUnsignedUTXOTransaction(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUnsignedUTXOTransaction", reflect.TypeOf((*MockWallet)(nil).SignUnsignedUTXOTransaction), arg0)
+}
+
 // Status mocks base method
 func (m *MockWallet) Status(arg0 *common.Address) *types1.StatusResult {
 	m.ctrl.T.Helper()
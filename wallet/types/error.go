@@ -37,6 +37,11 @@ var (
 	ErrWalletNotOpen       = NewWErr(-600010, "wallet not open")
 	ErrAccountNeedUnlock   = NewWErr(-600011, "account need unlock")
 	ErrNewUTXOAccount      = NewWErr(-600012, "fail new UTXO account")
+	ErrUnsignedTxInvalid   = NewWErr(-600013, "unsigned tx invalid")
+	ErrWatchOnlyAccount    = NewWErr(-600014, "watch only account can not sign")
+	ErrUnsignedTxChange    = NewWErr(-600015, "unsigned tx change not to the account")
+	ErrUnsignedTxAmount    = NewWErr(-600016, "unsigned tx amounts not balanced")
+	ErrUnsignedTxFee       = NewWErr(-600017, "unsigned tx fee too high")
 
 	ErrAccountNotFound      = NewWErr(-601001, "account not found")
 	ErrNewAccount           = NewWErr(-601002, "new account fail")
@@ -90,6 +95,8 @@ var (
 	ErrAddInfoNotFound   = NewWErr(-604010, "utxo add info not found")
 	ErrTxAddInfoCommit   = NewWErr(-604011, "tx add info commit fail")
 	ErrTxAddInfoDel      = NewWErr(-604012, "tx add info del fail")
+	ErrKeyImageMismatch  = NewWErr(-604013, "key image mismatch")
+	ErrKeyImageCommit    = NewWErr(-604014, "key image commit fail")
 
 	ErrInnerServer = NewWErr(-605001, "server inner error")
 )
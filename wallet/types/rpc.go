@@ -72,6 +72,54 @@ type SignUTXOTransactionResult struct {
 	Txs []SignUTXORet `json:"txs"`
 }
 
+// UnsignedUTXOTx is a utxo input transaction whose inputs and ring members
+// are selected but which is not signed yet. It is built by a wallet which
+// does not need the spend key and signed by one holding it.
+type UnsignedUTXOTx struct {
+	From        common.Address
+	TokenID     common.Address
+	Sources     []*types.UTXOSourceEntry
+	SourceTxIDs []common.Hash // txs of the outputs spent by Sources
+	Dests       []types.DestEntry
+	Fee         *big.Int
+	Extra       []byte
+	Subaddrs    []uint64
+	OutAmount   *big.Int
+	ChangeIdx   uint64
+}
+
+type UnsignedUTXORet struct {
+	Raw       string   `json:"raw"`
+	Subaddrs  []uint64 `json:"subaddrs"`
+	OutAmount *big.Int `json:"outamount"`
+}
+
+type CreateUnsignedUTXOTxResult struct {
+	Txs []UnsignedUTXORet `json:"txs"`
+}
+
+// KeyImageEntry is the key image of the output OutIndex of the tx TxHash.
+type KeyImageEntry struct {
+	TxHash   common.Hash    `json:"tx_hash"`
+	OutIndex hexutil.Uint64 `json:"out_index"`
+	KeyImage common.Hash    `json:"key_image"`
+}
+
+type ImportKeyImagesArgs struct {
+	KeyImages []KeyImageEntry `json:"key_images"`
+	Addr      *common.Address `json:"addr"`
+}
+
+type ImportKeyImagesResult struct {
+	Imported hexutil.Uint64 `json:"imported"`
+}
+
+type OpenViewWalletArgs struct {
+	ViewKey        common.Hash    `json:"view_key"`
+	SpendPublicKey common.Hash    `json:"spend_public_key"`
+	RestoreHeight  hexutil.Uint64 `json:"restore_height"`
+}
+
 type SendTxRet struct {
 	Raw     string         `json:"raw"`
 	Hash    common.Hash    `json:"hash"`
@@ -190,6 +238,9 @@ type StatusResult struct {
 	EthAddress           common.Address `json:"eth_address"`
 	RefreshBlockInterval time.Duration  `json:"refresh_block_interval"`
 	InitBlockHeight      hexutil.Uint64 `json:"init_block_height"`
+	WatchOnly            bool           `json:"watch_only"`
+	RestoreHeight        hexutil.Uint64 `json:"restore_height"`
+	SyncProgress         float64        `json:"sync_progress"` // part of the blocks since InitBlockHeight scanned
 }
 
 type ProofKeyArgs struct {
@@ -295,6 +346,7 @@ type UTXOOutputDetail struct {
 	SubAddrIndex hexutil.Uint64 `json:"sub_addr_index"`
 	TokenID      common.Address `json:"token_id"`
 	Remark       hexutil.Bytes  `json:"remark"`
+	SpentUnknown bool           `json:"spent_unknown,omitempty"` // watch only, the key image is not imported
 }
 
 type LocalOutputsArgs struct {
@@ -143,3 +143,20 @@ func (a *AccountBase) ZeroKey() {
 		}
 	}
 }
+
+// ZeroSpendKey zeroes the spend secret key in memory, the account can only
+// watch its outputs afterwards.
+func (a *AccountBase) ZeroSpendKey() {
+	mainAccount := a.GetKeys()
+	if mainAccount != nil {
+		for i := range mainAccount.SpendSKey {
+			mainAccount.SpendSKey[i] = byte(0)
+		}
+	}
+}
+
+// HasSpendKey return false if the account is watch only
+func (a *AccountBase) HasSpendKey() bool {
+	mainAccount := a.GetKeys()
+	return mainAccount != nil && mainAccount.SpendSKey != lkctypes.SecretKey{}
+}
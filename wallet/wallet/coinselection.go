@@ -628,6 +628,14 @@ func (wallet *Wallet) saveAddInfo(from common.Address, hash common.Hash, packet
 	if err != nil {
 		return err
 	}
+	if err = currAccount.saveAddInfo(hash, packetAddInfo(packet, changeSubaddr)); err != nil {
+		return err
+	}
+	return nil
+}
+
+//packetAddInfo return trans additional info of packet, the paid subaddresses and outamount
+func packetAddInfo(packet *inOutPacket, changeSubaddr uint64) *wtypes.UTXOAddInfo {
 	addrmap := make(map[uint64]bool, 0)
 	for _, utxo := range packet.Inputs {
 		addrmap[utxo.subaddr] = true
@@ -646,14 +654,11 @@ func (wallet *Wallet) saveAddInfo(from common.Address, hash common.Hash, packet
 		}
 		outAmount.Add(outAmount, dest.GetAmount())
 	}
-	if err = currAccount.saveAddInfo(hash, &wtypes.UTXOAddInfo{
+	return &wtypes.UTXOAddInfo{
 		Subaddrs:  subAddrs,
 		OutAmount: outAmount,
 		ChangeIdx: int(changeSubaddr),
-	}); err != nil {
-		return err
 	}
-	return nil
 }
 
 func (wallet *Wallet) currAccAndKeys(from common.Address) (*LinkAccount, *lkctypes.AccountKey, error) {
@@ -669,6 +674,9 @@ func (wallet *Wallet) currAccAndKeys(from common.Address) (*LinkAccount, *lkctyp
 		SpendSKey: keys.SpendSKey,
 		ViewSKey:  keys.ViewSKey,
 	}
+	if currAccount.isWatchOnly() {
+		return nil, nil, wtypes.ErrWatchOnlyAccount
+	}
 	emptySecret := lkctypes.SecretKey{}
 	if keysCopy.SpendSKey == emptySecret || keysCopy.ViewSKey == emptySecret {
 		return nil, nil, wtypes.ErrAccountNeedUnlock
@@ -679,92 +687,118 @@ func (wallet *Wallet) currAccAndKeys(from common.Address) (*LinkAccount, *lkctyp
 //CreateUinTransaction return a UTXOTransaction for utxo input only
 func (wallet *Wallet) CreateUinTransaction(from common.Address, subaddrs []uint64, dests []types.DestEntry,
 	tokenID common.Address, extra []byte) ([]*types.UTXOTransaction, error) {
-	needMoney, _, err := wallet.checkDest(dests, tokenID, UTXOInputMode)
+	inOutPackets, fees, changeSubaddr, err := wallet.selectUinPackets(from, subaddrs, dests, tokenID)
 	if err != nil {
 		return nil, err
 	}
-	unspentBalancePerSubaddr, err := wallet.unspentBalancePerSubaddr(from, tokenID)
+	currAccount, keys, err := wallet.currAccAndKeys(from)
 	if err != nil {
 		return nil, err
 	}
+	txs := make([]*types.UTXOTransaction, 0)
+	for i, packet := range inOutPackets {
+		utxoTx, _, err := wallet.signUinTransaction(from, currAccount, keys, packet.Sources, packet.Outputs, tokenID, fees[i], extra)
+		if err != nil {
+			return nil, err
+		}
+		//save trans additional info. such as paid subaddress, outamount
+		if err = wallet.saveAddInfo(from, utxoTx.Hash(), packet, changeSubaddr); err != nil {
+			return nil, err
+		}
+		txs = append(txs, utxoTx)
+	}
+	return txs, nil
+}
+
+//selectUinPackets select inputs, ring members and outputs of the utxo input transactions paying dests,
+//return them with the token fee of each transaction and the change subaddress
+func (wallet *Wallet) selectUinPackets(from common.Address, subaddrs []uint64, dests []types.DestEntry,
+	tokenID common.Address) ([]*inOutPacket, []*big.Int, uint64, error) {
+	needMoney, _, err := wallet.checkDest(dests, tokenID, UTXOInputMode)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	unspentBalancePerSubaddr, err := wallet.unspentBalancePerSubaddr(from, tokenID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
 	subaddrs, availableMoney := updateSubaddrs(subaddrs, unspentBalancePerSubaddr)
 	wallet.Logger.Debug("CreateUinTransaction", "availableMoney", availableMoney, "needMoney", needMoney)
 	if availableMoney.Cmp(needMoney) < 0 {
-		return nil, wtypes.ErrBalanceNotEnough
+		return nil, nil, 0, wtypes.ErrBalanceNotEnough
 	}
 	changeSubaddr := getChangeSubaddr(subaddrs, unspentBalancePerSubaddr)
 	unspentIndicePerSubaddr, err := wallet.unspentIndicePerSubaddr(from, tokenID)
 	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
 	utxoPool := wallet.constructUTXOPool(subaddrs, unspentIndicePerSubaddr)
 	inOutPackets, err := wallet.selectionProcess(utxoPool, dests, changeSubaddr, tokenID)
 	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
 	if err = wallet.constructRingMembers(from, inOutPackets, tokenID); err != nil {
-		return nil, err
-	}
-	currAccount, keys, err := wallet.currAccAndKeys(from)
-	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
 	availableLkcMoney := big.NewInt(0)
 	if !common.IsLKC(tokenID) {
 		availableLkcMoney, err = wallet.api.GetTokenBalance(from, common.EmptyAddress)
 		if err != nil {
 			wallet.Logger.Error("CreateUinTransaction getTokenBalance fail", "from", from, "tokenID", tokenID, "err", err)
-			return nil, err
+			return nil, nil, 0, err
 		}
 	}
-	txs := make([]*types.UTXOTransaction, 0)
-	for _, packet := range inOutPackets {
+	fees := make([]*big.Int, len(inOutPackets))
+	for i, packet := range inOutPackets {
 		fee := big.NewInt(0)
 		if !common.IsLKC(tokenID) {
 			_, outKind, err := wallet.checkDest(packet.Outputs, tokenID, UTXOInputMode)
 			if err != nil {
-				return nil, err
+				return nil, nil, 0, err
 			}
 			fee = wallet.calTokenFee(UTXOInputMode, outKind)
 			if availableLkcMoney.Cmp(fee) < 0 {
-				return nil, wtypes.ErrTokenFeeNotEnough
+				return nil, nil, 0, wtypes.ErrTokenFeeNotEnough
 			}
 			availableLkcMoney.Sub(availableLkcMoney, fee)
 		}
-		utxoTx, utxoInEphs, mKeys, txKey, err := types.NewUinTokenTransaction(keys, currAccount.account.KeyIndex,
-			packet.Sources, packet.Outputs, tokenID, common.EmptyAddress, fee, extra)
+		fees[i] = fee
+	}
+	return inOutPackets, fees, changeSubaddr, nil
+}
+
+//signUinTransaction return the signed utxo input transaction spending sources, and the key images of sources
+func (wallet *Wallet) signUinTransaction(from common.Address, currAccount *LinkAccount, keys *lkctypes.AccountKey,
+	sources []*types.UTXOSourceEntry, outputs []types.DestEntry, tokenID common.Address, fee *big.Int,
+	extra []byte) (*types.UTXOTransaction, []*types.UTXOInputEphemeral, error) {
+	utxoTx, utxoInEphs, mKeys, txKey, err := types.NewUinTokenTransaction(keys, currAccount.account.KeyIndex,
+		sources, outputs, tokenID, common.EmptyAddress, fee, extra)
+	if err != nil {
+		return nil, nil, wtypes.ErrNewUinTrans
+	}
+	if !common.IsLKC(tokenID) {
+		acc := accounts.Account{Address: from}
+		w, err := wallet.accManager.Find(acc)
 		if err != nil {
-			return nil, wtypes.ErrNewUinTrans
-		}
-		if !common.IsLKC(tokenID) {
-			acc := accounts.Account{Address: from}
-			w, err := wallet.accManager.Find(acc)
-			if err != nil {
-				wallet.Logger.Error("createUinTransaction wallet.accManager.Find fail", "acc", acc, "err", err)
-				return nil, wtypes.ErrAccountNotFound
-			}
-			signedTx, err := w.SignTx(acc, utxoTx, types.SignParam)
-			if err != nil {
-				wallet.Logger.Error("createUinTransaction SignTx fail", "err", err)
-				return nil, wtypes.ErrSignTx
-			}
-			utxoTx = signedTx.(*types.UTXOTransaction)
-		}
-		if err = types.UInTransWithRctSig(utxoTx, packet.Sources, utxoInEphs, packet.Outputs, mKeys); err != nil {
-			return nil, wtypes.ErrUinTransWithSign
-		}
-		if utxoTx.Size() > types.MaxPureTransactionSize {
-			return nil, wtypes.ErrTxTooBig
+			wallet.Logger.Error("createUinTransaction wallet.accManager.Find fail", "acc", acc, "err", err)
+			return nil, nil, wtypes.ErrAccountNotFound
 		}
-		// save txkey
-		if err = currAccount.saveTxKeys(utxoTx.Hash(), txKey); err != nil {
-			return nil, err
-		}
-		//save trans additional info. such as paid subaddress, outamount
-		if err = wallet.saveAddInfo(from, utxoTx.Hash(), packet, changeSubaddr); err != nil {
-			return nil, err
+		signedTx, err := w.SignTx(acc, utxoTx, types.SignParam)
+		if err != nil {
+			wallet.Logger.Error("createUinTransaction SignTx fail", "err", err)
+			return nil, nil, wtypes.ErrSignTx
 		}
-		txs = append(txs, utxoTx)
+		utxoTx = signedTx.(*types.UTXOTransaction)
 	}
-	return txs, nil
+	if err = types.UInTransWithRctSig(utxoTx, sources, utxoInEphs, outputs, mKeys); err != nil {
+		return nil, nil, wtypes.ErrUinTransWithSign
+	}
+	if utxoTx.Size() > types.MaxPureTransactionSize {
+		return nil, nil, wtypes.ErrTxTooBig
+	}
+	// save txkey
+	if err = currAccount.saveTxKeys(utxoTx.Hash(), txKey); err != nil {
+		return nil, nil, err
+	}
+	return utxoTx, utxoInEphs, nil
 }
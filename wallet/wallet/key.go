@@ -7,6 +7,7 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/lianxiangcloud/linkchain/accounts/keystore"
+	"github.com/lianxiangcloud/linkchain/libs/common"
 	"github.com/lianxiangcloud/linkchain/libs/crypto"
 	lktypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
 	"github.com/lianxiangcloud/linkchain/libs/cryptonote/xcrypto"
@@ -76,6 +77,34 @@ func RecoveryKeyToAccount(rk lktypes.SecretKey) (*AccountBase, error) {
 	return &ab, nil
 }
 
+//ViewKeyToAccount recovery a view only utxo account from the view secret key and the spend public key,
+//its EthAddress is derived from the public keys to identify the account in the wallet
+func ViewKeyToAccount(viewSK lktypes.SecretKey, spendPK lktypes.PublicKey) (*AccountBase, error) {
+	viewPK, err := xcrypto.SecretKeyToPublicKey(viewSK)
+	if err != nil || !xcrypto.CheckKey(spendPK) {
+		return nil, types.ErrArgsInvalid
+	}
+	acc := lktypes.AccountKey{
+		Addr: lktypes.AccountAddress{
+			SpendPublicKey: spendPK,
+			ViewPublicKey:  viewPK,
+		},
+		ViewSKey: viewSK,
+		SubIdx:   uint64(0),
+	}
+	acc.Address = AddressToStr(&acc, uint64(0))
+
+	ab := AccountBase{
+		KeyIndex:          make(map[lktypes.PublicKey]uint64),
+		CreationTimestamp: time.Now().Unix(),
+		EthAddress:        common.BytesToAddress(crypto.Keccak256(spendPK[:], viewPK[:])),
+	}
+	ab.Keys = append(ab.Keys, &acc)
+	ab.KeyIndex[acc.Addr.SpendPublicKey] = 0
+	ab.CurrIdx = uint64(0)
+	return &ab, nil
+}
+
 //GetSubaddr return a subaddr
 func GetSubaddr(key *lktypes.AccountKey, index uint64) string {
 	//TODO put spendPK into AccountKey.KeyIndex map
@@ -167,3 +167,32 @@ func TestGetSubaddr(t *testing.T) {
 		return []byte(addr), nil
 	})
 }
+
+func TestViewKeyToAccount(t *testing.T) {
+	acc, err := WordsToAccount("sequence atlas unveil summon pebbles tuesday beer rudely snake rockets different fuselage woven tagged bested dented vegan hover rapid fawns obvious muppet randomly seasons randomly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := acc.GetKeys()
+	viewAcc, err := ViewKeyToAccount(keys.ViewSKey, keys.Addr.SpendPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if viewAcc.GetKeys().Address != keys.Address {
+		t.Errorf("address mismatch:\ngot   %s\nwant  %s", viewAcc.GetKeys().Address, keys.Address)
+	}
+	if viewAcc.HasSpendKey() {
+		t.Errorf("view account has spend key")
+	}
+	if sub, want := GetSubaddr(viewAcc.GetKeys(), 1), GetSubaddr(keys, 1); sub != want {
+		t.Errorf("subaddr mismatch:\ngot   %s\nwant  %s", sub, want)
+	}
+
+	var invalid lktypes.PublicKey
+	for i := range invalid {
+		invalid[i] = 0xff
+	}
+	if _, err := ViewKeyToAccount(keys.ViewSKey, invalid); err == nil {
+		t.Errorf("invalid spend public key accepted")
+	}
+}
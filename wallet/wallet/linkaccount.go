@@ -62,11 +62,49 @@ type LinkAccount struct {
 	walletDB             dbm.DB
 	refreshBlockInterval time.Duration
 	syncQuick            bool
+	restoreHeight        uint64 // outputs of lower blocks are not looked for
+	viewOnly             bool
+	scanWorkers          int
 	api                  BackendAPI
 }
 
-// NewLinkAccount return a LinkAccount
-func NewLinkAccount(walletDB dbm.DB, logger log.Logger, keystoreFile string, password string, api BackendAPI) (*LinkAccount, error) {
+// NewLinkAccount return a LinkAccount opened from the keystore file, the outputs received before restoreHeight
+// are not looked for. The restore height is kept in the db, 0 keeps the one of the last opening
+func NewLinkAccount(walletDB dbm.DB, logger log.Logger, keystoreFile string, password string, restoreHeight uint64, api BackendAPI) (*LinkAccount, error) {
+	newAccount, err := NewUTXOAccount(keystoreFile, password)
+	if err != nil {
+		logger.Error("NewLinkAccount NewUTXOAccount fail", "keystoreFile", keystoreFile, "err", err)
+		return nil, types.ErrNewUTXOAccount
+	}
+	return newLinkAccount(walletDB, logger, newAccount, restoreHeight, api)
+}
+
+// NewWatchLinkAccount return a watch only LinkAccount opened from the keystore file. The spend key is
+// dropped before the db is loaded, so that it shares the db with the account opened from the view keys
+func NewWatchLinkAccount(walletDB dbm.DB, logger log.Logger, keystoreFile string, password string, restoreHeight uint64, api BackendAPI) (*LinkAccount, error) {
+	newAccount, err := NewUTXOAccount(keystoreFile, password)
+	if err != nil {
+		logger.Error("NewWatchLinkAccount NewUTXOAccount fail", "keystoreFile", keystoreFile, "err", err)
+		return nil, types.ErrNewUTXOAccount
+	}
+	newAccount.ZeroSpendKey()
+	return newLinkAccount(walletDB, logger, newAccount, restoreHeight, api)
+}
+
+// NewViewLinkAccount return a watch only LinkAccount, constructed from the view secret key and the spend
+// public key. It finds the outputs received since restoreHeight, but can not sign, and knows the spent
+// outputs only if their key images are imported
+func NewViewLinkAccount(walletDB dbm.DB, logger log.Logger, viewSKey lkctypes.SecretKey, spendPKey lkctypes.PublicKey,
+	restoreHeight uint64, api BackendAPI) (*LinkAccount, error) {
+	newAccount, err := ViewKeyToAccount(viewSKey, spendPKey)
+	if err != nil {
+		logger.Error("NewViewLinkAccount ViewKeyToAccount fail", "err", err)
+		return nil, err
+	}
+	return newLinkAccount(walletDB, logger, newAccount, restoreHeight, api)
+}
+
+func newLinkAccount(walletDB dbm.DB, logger log.Logger, newAccount *AccountBase, restoreHeight uint64, api BackendAPI) (*LinkAccount, error) {
 	la := &LinkAccount{
 		remoteHeight:         new(big.Int).SetUint64(defaultInitBlockHeight),
 		localHeight:          new(big.Int).SetUint64(defaultInitBlockHeight),
@@ -82,15 +120,13 @@ func NewLinkAccount(walletDB dbm.DB, logger log.Logger, keystoreFile string, pas
 		stop:                 make(chan int, 1),
 		walletDB:             walletDB,
 		refreshBlockInterval: defaultRefreshBlockInterval,
+		restoreHeight:        restoreHeight,
+		scanWorkers:          defaultScanWorkers,
 		api:                  api,
 	}
-
-	newAccount, err := NewUTXOAccount(keystoreFile, password)
-	if err != nil {
-		logger.Error("NewLinkAccount NewUTXOAccount fail", "keystoreFile", keystoreFile, "err", err)
-		return nil, types.ErrNewUTXOAccount
-	}
 	la.account = newAccount
+	// view only accounts are opened without the spend key, they do not share the db with the full account
+	la.viewOnly = !newAccount.HasSpendKey()
 
 	logModule := fmt.Sprintf("LinkAccount-%s", la.getEthAddress().String())
 	// la.BaseService = *cmn.NewBaseService(logger, logModule, la)
@@ -100,10 +136,18 @@ func NewLinkAccount(walletDB dbm.DB, logger log.Logger, keystoreFile string, pas
 	la.mainUTXOAddress = la.account.GetKeys().Address
 	la.setTokenBalanceBySubIndex(LinkToken, 0, big.NewInt(0))
 
-	err = la.loadLocalHeight()
+	err := la.loadLocalHeight()
 	if err != nil {
 		return nil, err
 	}
+	if restoreHeight > 0 {
+		batch := la.walletDB.NewBatch()
+		if la.saveRestoreHeight(batch) != nil || batch.Commit() != nil {
+			return nil, types.ErrBatchSave
+		}
+	} else if err = la.loadRestoreHeight(); err != nil {
+		return nil, err
+	}
 
 	if remoteHeight, err := la.api.RefreshMaxBlock(); err == nil {
 		la.remoteHeight.Set(remoteHeight)
@@ -277,64 +321,145 @@ func (la *LinkAccount) checkBlock(block *rtypes.RPCBlock) error {
 	return nil
 }
 
-// Refresh wallet
-func (la *LinkAccount) Refresh() {
-	for {
-		var block *rtypes.RPCBlock
-		var err error
+// skipToRestoreHeight move the local height of an account below its restore height to it, the blocks
+// before are not fetched. The hash of the block before is saved for checkBlock, and the outindexes
+// start with the max output seqs of the node after that block
+func (la *LinkAccount) skipToRestoreHeight() error {
+	la.lock.Lock()
+	restoreHeight := new(big.Int).SetUint64(la.restoreHeight)
+	skip := la.walletOpen && la.autoRefresh && la.restoreHeight > defaultInitBlockHeight &&
+		la.localHeight.Cmp(restoreHeight) < 0
+	la.lock.Unlock()
+	if !skip {
+		return nil
+	}
 
-		if la.walletOpen && la.autoRefresh && la.localHeight.Cmp(la.remoteHeight) <= 0 {
-			la.Logger.Debug("Refresh", "localHeight", la.localHeight, "remoteHeight", la.remoteHeight)
-			block, err = la.api.GetBlockUTXOsByNumber(la.localHeight)
-			if err != nil {
-				la.Logger.Error("Refresh getBlockUTXOsByNumber fail", "height", la.localHeight, "err", err)
-				return
-			}
-		}
+	parentHeight := new(big.Int).Sub(restoreHeight, big.NewInt(1))
+	parent, err := la.api.GetBlockUTXOsByNumber(parentHeight)
+	if err != nil {
+		return err
+	}
+	if parent.Hash == nil || parent.Height.ToInt().Cmp(parentHeight) != 0 {
+		return types.ErrBlockNotFound
+	}
+	seqs, err := la.api.GetMaxOutputIndexes(parentHeight)
+	if err != nil {
+		return err
+	}
 
-		la.lock.Lock()
+	la.lock.Lock()
+	defer la.lock.Unlock()
+	// the account may be rescanned or restored from another height meanwhile
+	if la.restoreHeight != restoreHeight.Uint64() || la.localHeight.Cmp(restoreHeight) >= 0 {
+		return nil
+	}
+	la.gOutIndex = make(map[common.Address]uint64)
+	la.syncGOutIndex(seqs)
+	batch := la.walletDB.NewBatch()
+	if la.saveLocalHeight(batch, restoreHeight) != nil || la.saveGOutIndex(batch) != nil ||
+		la.saveBlockHash(batch, parentHeight, *parent.Hash) != nil {
+		return types.ErrBatchSave
+	}
+	if err := batch.Commit(); err != nil {
+		return types.ErrBatchCommit
+	}
+	la.Logger.Info("skipToRestoreHeight", "localHeight", la.localHeight, "restoreHeight", restoreHeight)
+	la.localHeight.Set(restoreHeight)
+	return nil
+}
 
-		if la.walletOpen && la.autoRefresh && la.localHeight.Cmp(la.remoteHeight) <= 0 {
-			// check block parent hash
-			err = la.checkBlock(block)
-			if err != nil {
-				la.Logger.Error("Refresh CheckBlock fail", "height", la.localHeight, "err", err)
-				la.lock.Unlock()
+// Refresh wallet. The blocks ahead of the local height are fetched and scanned on
+// the worker pool of the account, and processed in order
+func (la *LinkAccount) Refresh() {
+	if err := la.skipToRestoreHeight(); err != nil {
+		la.Logger.Error("Refresh skipToRestoreHeight fail", "err", err)
+		return
+	}
+	for {
+		la.lock.Lock()
+		if !la.walletOpen || !la.autoRefresh || la.localHeight.Cmp(la.remoteHeight) > 0 {
+			la.lock.Unlock()
+			return
+		}
+		height := new(big.Int).Set(la.localHeight)
+		cnt := scanWindow
+		if left := new(big.Int).Sub(la.remoteHeight, height); left.Cmp(big.NewInt(scanWindow)) < 0 {
+			cnt = int(left.Int64()) + 1
+		}
+		sk := la.getScanKeys()
+		la.Logger.Debug("Refresh", "localHeight", height, "remoteHeight", la.remoteHeight, "blocks", cnt)
+		la.lock.Unlock()
+
+		quit := make(chan struct{})
+		scans := la.scanBlocks(height, cnt, sk, quit)
+		for _, scan := range scans {
+			<-scan.done
+			if scan.err != nil {
+				la.Logger.Error("Refresh getBlockUTXOsByNumber fail", "height", scan.height, "err", scan.err)
+				close(quit)
 				return
 			}
-
-			ids, myTxs, err := la.processBlock(block)
-			if err != nil {
-				la.Logger.Error("Refresh processBlock fail", "height", la.localHeight, "err", err)
-				la.lock.Unlock()
+			if !la.processScan(scan, len(sk.keyIndex)) {
+				close(quit)
 				return
 			}
+		}
+		close(quit)
+	}
+}
 
-			nextHeight := new(big.Int).Add(la.localHeight, big.NewInt(1))
-			localBlock := &types.UTXOBlock{
-				Height:     (*hexutil.Big)(new(big.Int).Set(block.Height.ToInt())),
-				NextHeight: (*hexutil.Big)(new(big.Int).Set(nextHeight)),
-				Time:       (*hexutil.Big)(new(big.Int).Set(block.Time.ToInt())),
-				Txs:        myTxs,
-			}
+// processScan process a block scanned by scanBlocks with keyCnt keys, it returns false if
+// the refresh must stop
+func (la *LinkAccount) processScan(scan *blockScan, keyCnt int) bool {
+	la.lock.Lock()
+	defer la.lock.Unlock()
 
-			err = la.save(ids, *block.Hash, localBlock)
-			if err != nil {
-				la.Logger.Error("Refresh la.save fail", "height", la.localHeight, "err", err)
+	// the wallet may be closed or rescanned meanwhile
+	if !la.walletOpen || !la.autoRefresh || la.localHeight.Cmp(scan.height) != 0 {
+		return false
+	}
+	block := scan.block
+	// check block parent hash
+	err := la.checkBlock(block)
+	if err != nil {
+		la.Logger.Error("Refresh CheckBlock fail", "height", la.localHeight, "err", err)
+		return false
+	}
 
-			}
-			la.localHeight.Set(nextHeight)
-			la.lock.Unlock()
-		} else {
-			la.lock.Unlock()
-			return
-		}
+	owners := scan.owners
+	if keyCnt != len(la.account.KeyIndex) {
+		// sub accounts created meanwhile
+		owners = nil
+	}
+	ids, myTxs, err := la.processBlock(block, owners)
+	if err != nil {
+		la.Logger.Error("Refresh processBlock fail", "height", la.localHeight, "err", err)
+		return false
+	}
+
+	nextHeight := new(big.Int).Add(la.localHeight, big.NewInt(1))
+	localBlock := &types.UTXOBlock{
+		Height:     (*hexutil.Big)(new(big.Int).Set(block.Height.ToInt())),
+		NextHeight: (*hexutil.Big)(new(big.Int).Set(nextHeight)),
+		Time:       (*hexutil.Big)(new(big.Int).Set(block.Time.ToInt())),
+		Txs:        myTxs,
 	}
 
+	err = la.save(ids, *block.Hash, localBlock)
+	if err != nil {
+		la.Logger.Error("Refresh la.save fail", "height", la.localHeight, "err", err)
+
+	}
+	la.localHeight.Set(nextHeight)
+	return true
 }
 
 // RefreshQuick wallet
 func (la *LinkAccount) RefreshQuick() {
+	if err := la.skipToRestoreHeight(); err != nil {
+		la.Logger.Error("RefreshQuick skipToRestoreHeight fail", "err", err)
+		return
+	}
 	for {
 		var quickBlock *rtypes.QuickRPCBlock
 		var err error
@@ -374,7 +499,7 @@ func (la *LinkAccount) RefreshQuick() {
 				continue
 			}
 
-			ids, myTxs, err := la.processBlock(quickBlock.Block)
+			ids, myTxs, err := la.processBlock(quickBlock.Block, nil)
 			if err != nil {
 				la.Logger.Error("RefreshQuick processBlock fail", "height", la.localHeight, "err", err)
 				la.lock.Unlock()
@@ -410,9 +535,15 @@ func (la *LinkAccount) RefreshQuick() {
 	}
 }
 
-func (la *LinkAccount) processBlock(block *rtypes.RPCBlock) (ids []uint64, myTxs []types.UTXOTransaction, err error) {
+// processBlock process the txs of block, owners are the owners of their utxo outputs, see scanBlock.
+// They are checked now if owners is nil
+func (la *LinkAccount) processBlock(block *rtypes.RPCBlock, owners [][]*outputOwner) (ids []uint64, myTxs []types.UTXOTransaction, err error) {
 	numTxs := len(block.Txs)
 	la.Logger.Info("processBlock", "Height", block.Height, "numTxs", numTxs)
+	if owners == nil {
+		owners = la.getScanKeys().scanBlock(block, la.Logger)
+	}
+	la.syncGOutIndex(block.TokenOutputSeqs)
 
 	for index := 0; index < numTxs; index++ {
 		rpctx := block.Txs[index].(*rtypes.RPCTx)
@@ -420,7 +551,7 @@ func (la *LinkAccount) processBlock(block *rtypes.RPCBlock) (ids []uint64, myTxs
 		case *tctypes.Transaction:
 			// TODO
 		case *tctypes.UTXOTransaction:
-			tids, myTx, err := la.processNewTransaction(t, block.Height.ToInt().Uint64())
+			tids, myTx, err := la.processNewTransaction(t, block.Height.ToInt().Uint64(), owners[index])
 			if err != nil {
 				return nil, nil, err
 			}
@@ -445,7 +576,8 @@ func (la *LinkAccount) isChangeOutput(addinfo *types.UTXOAddInfo, idx int) bool
 	return addinfo.ChangeIdx == idx
 }
 
-func (la *LinkAccount) processNewTransaction(tx *tctypes.UTXOTransaction, height uint64) (tids []uint64, myTx *types.UTXOTransaction, err error) {
+// processNewTransaction process tx of the block at height, owners are the owners of its utxo outputs, see scanOutputs
+func (la *LinkAccount) processNewTransaction(tx *tctypes.UTXOTransaction, height uint64, owners []*outputOwner) (tids []uint64, myTx *types.UTXOTransaction, err error) {
 	la.Logger.Info("processNewTransaction", "height", height, "txhash", tx.Hash())
 
 	myTx = &types.UTXOTransaction{}
@@ -475,56 +607,14 @@ func (la *LinkAccount) processNewTransaction(tx *tctypes.UTXOTransaction, height
 			outputID++
 			// TODO
 			gid := la.increaseGOutIndex(tx.TokenID)
-
-			keyMaps := make(map[lkctypes.KeyDerivation]lkctypes.PublicKey, 0)
-			derivationKeys := make([]lkctypes.KeyDerivation, 0)
-			derivationKey, err := xcrypto.GenerateKeyDerivation(tx.RKey, la.account.GetKeys().ViewSKey)
-			if err != nil {
-				la.Logger.Error("GenerateKeyDerivation fail", "rkey", tx.RKey, "err", err)
-				continue
-			}
-			derivationKeys = append(derivationKeys, derivationKey)
-			keyMaps[derivationKey] = tx.RKey
-			if len(tx.AddKeys) > 0 {
-				//we use a addinational key for utxo->account proof, maybe cause err here
-				for _, addkey := range tx.AddKeys {
-					derivationKey, err = xcrypto.GenerateKeyDerivation(addkey, la.account.GetKeys().ViewSKey)
-					if err != nil {
-						la.Logger.Info("GenerateKeyDerivation fail", "addkey", addkey, "err", err)
-						continue
-					}
-					derivationKeys = append(derivationKeys, derivationKey)
-					keyMaps[derivationKey] = addkey
-				}
-			}
-
-			recIdx := uint64(outputID)
-			realDeriKey, subaddrIndex, err := tctypes.IsOutputBelongToAccount(la.account.GetKeys(), la.account.KeyIndex, ro.OTAddr, derivationKeys, recIdx)
-			if err != nil {
-				la.Logger.Info("IsOutputBelongToAccount fail", "ro.OTAddr", ro.OTAddr, "derivationKey", derivationKey, "recIdx", recIdx, "err", err)
+			if outputID >= len(owners) || owners[outputID] == nil {
 				myTx.Outputs = append(myTx.Outputs, types.UTXOOutput{OTAddr: common.EmptyHash})
 				continue
 			}
-
-			realRKey, exist := keyMaps[realDeriKey]
-			if !exist {
-				la.Logger.Error("real rkey not found", "real derivation key", realDeriKey)
-				continue
-			}
+			realDeriKey, realRKey, subaddrIndex := owners[outputID].deriKey, owners[outputID].rkey, owners[outputID].subaddrIndex
 			la.Logger.Debug("processNewTransaction", "real derivation key", realDeriKey, "real random key", realRKey)
-			secretKey, err := xcrypto.DeriveSecretKey(realDeriKey, outputID, la.account.GetKeys().SpendSKey)
-			if err != nil {
-				la.Logger.Error("DeriveSecretKey fail", "err", err)
-				continue
-			}
-			sk1 := secretKey
-			if subaddrIndex > 0 {
-				subaddrSk := xcrypto.GetSubaddressSecretKey(la.account.GetKeys().ViewSKey, uint32(subaddrIndex))
-				sk1 = xcrypto.SecretAdd(secretKey, subaddrSk)
-			}
-			keyImage, err := xcrypto.GenerateKeyImage(lkctypes.PublicKey(ro.OTAddr), sk1)
+			keyImage, err := la.outputKeyImage(realDeriKey, outputID, subaddrIndex, ro.OTAddr, tx.Hash())
 			if err != nil {
-				la.Logger.Error("GenerateKeyImage fail", "otaddr", ro.OTAddr, "err", err)
 				continue
 			}
 
@@ -572,7 +662,7 @@ func (la *LinkAccount) processNewTransaction(tx *tctypes.UTXOTransaction, height
 			uod.Spent = false
 			uod.Frozen = false
 			uod.SpentHeight = uint64(0)
-			uod.KeyImage = lkctypes.Key(keyImage)
+			uod.KeyImage = keyImage
 			uod.SubAddrIndex = subaddrIndex
 			uod.RKey = realRKey
 			uod.Mask = ecdh.Mask
@@ -593,7 +683,9 @@ func (la *LinkAccount) processNewTransaction(tx *tctypes.UTXOTransaction, height
 			la.Transfers = append(la.Transfers, &uod)
 			tid := len(la.Transfers) - 1
 
-			la.keyImages[uod.KeyImage] = uint64(tid)
+			if uod.KeyImage != (lkctypes.Key{}) {
+				la.keyImages[uod.KeyImage] = uint64(tid)
+			}
 			tids = append(tids, uint64(tid))
 
 			myTx.Outputs = append(myTx.Outputs, types.UTXOOutput{OTAddr: (common.Hash)(ro.OTAddr), GlobalIndex: (hexutil.Uint64)(tid), IsChange: la.isChangeOutput(addinfo, i)})
@@ -663,6 +755,36 @@ func (la *LinkAccount) processNewTransaction(tx *tctypes.UTXOTransaction, height
 	return tids, nil, nil
 }
 
+// outputKeyImage return the key image of an output of the account. A watch only account can not generate it,
+// it returns the imported key image or an empty key if the key image is not imported yet
+func (la *LinkAccount) outputKeyImage(deriKey lkctypes.KeyDerivation, outputID int, subaddrIndex uint64,
+	otaddr lkctypes.Key, txHash common.Hash) (lkctypes.Key, error) {
+	if !la.account.HasSpendKey() {
+		entry, err := la.loadKeyImage(txHash, uint64(outputID))
+		if err != nil || entry == nil {
+			la.Logger.Info("outputKeyImage key image not imported", "txhash", txHash, "outputID", outputID, "err", err)
+			return lkctypes.Key{}, nil
+		}
+		return lkctypes.Key(entry.KeyImage), nil
+	}
+	secretKey, err := xcrypto.DeriveSecretKey(deriKey, outputID, la.account.GetKeys().SpendSKey)
+	if err != nil {
+		la.Logger.Error("DeriveSecretKey fail", "err", err)
+		return lkctypes.Key{}, err
+	}
+	sk1 := secretKey
+	if subaddrIndex > 0 {
+		subaddrSk := xcrypto.GetSubaddressSecretKey(la.account.GetKeys().ViewSKey, uint32(subaddrIndex))
+		sk1 = xcrypto.SecretAdd(secretKey, subaddrSk)
+	}
+	keyImage, err := xcrypto.GenerateKeyImage(lkctypes.PublicKey(otaddr), sk1)
+	if err != nil {
+		la.Logger.Error("GenerateKeyImage fail", "otaddr", otaddr, "err", err)
+		return lkctypes.Key{}, err
+	}
+	return lkctypes.Key(keyImage), nil
+}
+
 // increaseGOutIndex increase outindex,return curr idx
 func (la *LinkAccount) increaseGOutIndex(token common.Address) uint64 {
 	_, ok := la.gOutIndex[token]
@@ -813,6 +935,21 @@ func (la *LinkAccount) RescanBlockchain() error {
 	return nil
 }
 
+// syncGOutIndex set the outindexes to the max output seqs of the tokens before the block, which
+// the node counts, so that the outputs of no tx, like the genesis ones, are counted too
+func (la *LinkAccount) syncGOutIndex(seqs map[string]int64) {
+	for token, seq := range seqs {
+		if !common.IsHexAddress(token) {
+			continue
+		}
+		if seq < 0 {
+			delete(la.gOutIndex, common.HexToAddress(token))
+		} else {
+			la.gOutIndex[common.HexToAddress(token)] = uint64(seq)
+		}
+	}
+}
+
 // GetGOutIndex return curr idx
 func (la *LinkAccount) GetGOutIndex(token common.Address) uint64 {
 	la.lock.Lock()
@@ -849,9 +986,27 @@ func (la *LinkAccount) Status() *types.StatusResult {
 		EthAddress:           ethAddress,
 		RefreshBlockInterval: refreshBlockInterval,
 		InitBlockHeight:      (hexutil.Uint64)(defaultInitBlockHeight),
+		WatchOnly:            la.isWatchOnly(),
+		RestoreHeight:        (hexutil.Uint64)(la.restoreHeight),
+		SyncProgress:         syncProgress(lh, rh),
 	}
 }
 
+// syncProgress return the part of the blocks since the init block height scanned
+func syncProgress(localHeight *big.Int, remoteHeight *big.Int) float64 {
+	initHeight := new(big.Int).SetUint64(defaultInitBlockHeight)
+	total := new(big.Int).Sub(remoteHeight, initHeight)
+	scanned := new(big.Int).Sub(localHeight, initHeight)
+	if total.Sign() <= 0 || scanned.Cmp(total) >= 0 {
+		return 1
+	}
+	if scanned.Sign() <= 0 {
+		return 0
+	}
+	progress, _ := new(big.Float).Quo(new(big.Float).SetInt(scanned), new(big.Float).SetInt(total)).Float64()
+	return progress
+}
+
 // GetTxKey return transaction's tx secKey
 func (la *LinkAccount) GetTxKey(hash *common.Hash) (*lkctypes.Key, error) {
 	// if !la.walletOpen {
@@ -940,17 +1095,39 @@ func (la *LinkAccount) GetLocalOutputs(ids []hexutil.Uint64) ([]types.UTXOOutput
 			SubAddrIndex: (hexutil.Uint64)(o.SubAddrIndex),
 			TokenID:      o.TokenID,
 			Remark:       (hexutil.Bytes)(o.Remark[:]),
+			SpentUnknown: o.KeyImage == (lkctypes.Key{}),
 		}
 		outputs = append(outputs, rpcUTXOOutputDetail)
 	}
 	return outputs, err
 }
 
+// isWatchOnly return true if the account is opened without its spend key
+func (la *LinkAccount) isWatchOnly() bool {
+	keys := la.account.GetKeys()
+	return keys.ViewSKey != (lkctypes.SecretKey{}) && keys.SpendSKey == (lkctypes.SecretKey{})
+}
+
 // SetSyncQuick set la.syncQuick
 func (la *LinkAccount) SetSyncQuick(quick bool) {
 	la.syncQuick = quick
 }
 
+// setRestoreHeight set the restore height of an opened account, for the next rescan
+func (la *LinkAccount) setRestoreHeight(height uint64) {
+	la.lock.Lock()
+	defer la.lock.Unlock()
+	la.restoreHeight = height
+}
+
+// SetScanWorkers set the number of blocks fetched and scanned in parallel by Refresh
+func (la *LinkAccount) SetScanWorkers(workers int) {
+	if workers <= 0 {
+		workers = defaultScanWorkers
+	}
+	la.scanWorkers = workers
+}
+
 func (la *LinkAccount) GetUTXOAddInfo(hash common.Hash) (*types.UTXOAddInfo, error) {
 	return la.loadAddInfo(hash)
 }
@@ -958,3 +1135,104 @@ func (la *LinkAccount) GetUTXOAddInfo(hash common.Hash) (*types.UTXOAddInfo, err
 func (la *LinkAccount) DelUTXOAddInfo(hash common.Hash) error {
 	return la.delAddInfo(hash)
 }
+
+// ExportKeyImages return the key images of the outputs known by the account,
+// the ones of its outputs and of the outputs spent by the txs it signed
+func (la *LinkAccount) ExportKeyImages() ([]types.KeyImageEntry, error) {
+	la.lock.Lock()
+	defer la.lock.Unlock()
+
+	entries, err := la.loadKeyImages()
+	if err != nil {
+		return nil, err
+	}
+	exported := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		exported[string(la.getKeyImageKey(entry.TxHash, uint64(entry.OutIndex)))] = true
+	}
+	for _, uod := range la.Transfers {
+		if uod == nil || uod.KeyImage == (lkctypes.Key{}) {
+			continue
+		}
+		if exported[string(la.getKeyImageKey(common.Hash(uod.TxID), uod.OutIndex))] {
+			continue
+		}
+		entries = append(entries, types.KeyImageEntry{
+			TxHash:   common.Hash(uod.TxID),
+			OutIndex: hexutil.Uint64(uod.OutIndex),
+			KeyImage: common.Hash(uod.KeyImage),
+		})
+	}
+	return entries, nil
+}
+
+// ImportKeyImages save the key images of outputs, so that a watch only account can track the spent outputs.
+// Outputs not received yet take their key images when they are, the spents of outputs received before
+// their key images are imported are only found by rescanning the blockchain
+func (la *LinkAccount) ImportKeyImages(entries []types.KeyImageEntry) (uint64, error) {
+	la.lock.Lock()
+	defer la.lock.Unlock()
+
+	outputs := make(map[string]uint64, len(la.Transfers))
+	for i, uod := range la.Transfers {
+		if uod != nil {
+			outputs[string(la.getKeyImageKey(common.Hash(uod.TxID), uod.OutIndex))] = uint64(i)
+		}
+	}
+	// check all key images before updating any output
+	for _, entry := range entries {
+		tid, ok := outputs[string(la.getKeyImageKey(entry.TxHash, uint64(entry.OutIndex)))]
+		if !ok {
+			continue
+		}
+		keyImage := la.Transfers[tid].KeyImage
+		if keyImage != (lkctypes.Key{}) && keyImage != lkctypes.Key(entry.KeyImage) {
+			la.Logger.Error("ImportKeyImages key image mismatch", "txhash", entry.TxHash, "outIndex", entry.OutIndex)
+			return 0, types.ErrKeyImageMismatch
+		}
+	}
+
+	batch := la.walletDB.NewBatch()
+	tids := make([]uint64, 0)
+	for i := range entries {
+		entry := &entries[i]
+		if err := la.saveKeyImage(batch, entry); err != nil {
+			return 0, err
+		}
+		tid, ok := outputs[string(la.getKeyImageKey(entry.TxHash, uint64(entry.OutIndex)))]
+		if !ok || la.Transfers[tid].KeyImage != (lkctypes.Key{}) {
+			continue
+		}
+		la.Transfers[tid].KeyImage = lkctypes.Key(entry.KeyImage)
+		la.keyImages[la.Transfers[tid].KeyImage] = tid
+		tids = append(tids, tid)
+	}
+	if len(tids) > 0 && la.saveTransfers(batch, tids) != nil {
+		return 0, types.ErrBatchSave
+	}
+	if err := batch.Commit(); err != nil {
+		return 0, types.ErrKeyImageCommit
+	}
+	la.Logger.Info("ImportKeyImages", "imported", len(entries), "outputs", len(tids))
+	return uint64(len(entries)), nil
+}
+
+// saveSignedKeyImages save the key images of the outputs spent by a signed tx
+func (la *LinkAccount) saveSignedKeyImages(txIDs []common.Hash, sources []*tctypes.UTXOSourceEntry,
+	utxoInEphs []*tctypes.UTXOInputEphemeral) error {
+	batch := la.walletDB.NewBatch()
+	for i, source := range sources {
+		err := la.saveKeyImage(batch, &types.KeyImageEntry{
+			TxHash:   txIDs[i],
+			OutIndex: hexutil.Uint64(source.OutIndex),
+			KeyImage: common.Hash(utxoInEphs[i].KeyImage),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := batch.Commit(); err != nil {
+		return types.ErrKeyImageCommit
+	}
+	return nil
+}
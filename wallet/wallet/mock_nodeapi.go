@@ -174,6 +174,21 @@ func (mr *MockBackendAPIMockRecorder) GetBlockUTXOsByNumber(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockUTXOsByNumber", reflect.TypeOf((*MockBackendAPI)(nil).GetBlockUTXOsByNumber), arg0)
 }
 
+// GetMaxOutputIndexes mocks base method
+func (m *MockBackendAPI) GetMaxOutputIndexes(arg0 *big.Int) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxOutputIndexes", arg0)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxOutputIndexes indicates an expected call of GetMaxOutputIndexes
+func (mr *MockBackendAPIMockRecorder) GetMaxOutputIndexes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxOutputIndexes", reflect.TypeOf((*MockBackendAPI)(nil).GetMaxOutputIndexes), arg0)
+}
+
 // GetChainVersion mocks base method
 func (m *MockBackendAPI) GetChainVersion() (string, error) {
 	m.ctrl.T.Helper()
@@ -32,6 +32,7 @@ type BackendAPI interface {
 	Transfer(txs []string) (ret []wtypes.SendTxRet)
 	GetChainVersion() (string, error)
 	GetBlockUTXOsByNumber(height *big.Int) (*rtypes.RPCBlock, error)
+	GetMaxOutputIndexes(height *big.Int) (map[string]int64, error)
 	GetUTXOGas() (uint64, error)
 	GetBlockTransactionCountByNumber(blockNr rpc.BlockNumber) (*hexutil.Uint, error)
 	GetBlockTransactionCountByHash(blockHash common.Hash) (*hexutil.Uint, error)
@@ -353,6 +354,26 @@ func (api *NodeAPI) GetBlockUTXOsByNumber(height *big.Int) (*rtypes.RPCBlock, er
 	return &block, nil
 }
 
+// GetMaxOutputIndexes return the max utxo output index of every token after the block at height
+func (api *NodeAPI) GetMaxOutputIndexes(height *big.Int) (map[string]int64, error) {
+	body, err := daemon.CallJSONRPC("eth_getMaxOutputIndexes", []interface{}{hexutil.EncodeBig(height)})
+	if err != nil || body == nil || len(body) == 0 {
+		return nil, wtypes.ErrNoConnectionToDaemon
+	}
+	var jsonRes wtypes.RPCResponse
+	if err = json.Unmarshal(body, &jsonRes); err != nil {
+		return nil, wtypes.ErrDaemonResponseBody
+	}
+	if jsonRes.Error.Code != 0 {
+		return nil, wtypes.ErrDaemonResponseCode
+	}
+	var seqs map[string]int64
+	if err = json.Unmarshal(jsonRes.Result, &seqs); err != nil {
+		return nil, wtypes.ErrDaemonResponseData
+	}
+	return seqs, nil
+}
+
 func (api *NodeAPI) GetUTXOGas() (uint64, error) {
 	body, err := daemon.CallJSONRPC("eth_getUTXOGas", []interface{}{})
 	if err != nil || body == nil || len(body) == 0 {
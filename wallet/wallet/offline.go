@@ -0,0 +1,190 @@
+package wallet
+
+import (
+	"math/big"
+
+	"github.com/lianxiangcloud/linkchain/libs/common"
+	"github.com/lianxiangcloud/linkchain/types"
+	wtypes "github.com/lianxiangcloud/linkchain/wallet/types"
+)
+
+// CreateUnsignedUTXOTransaction return the utxo input transactions paying dests unsigned. The inputs and
+// ring members are selected like CreateUinTransaction does, but the spend key is not needed, so that
+// a watch only wallet builds the transactions and an offline one holding the spend key signs them
+func (wallet *Wallet) CreateUnsignedUTXOTransaction(from common.Address, subaddrs []uint64, dests []types.DestEntry,
+	tokenID common.Address, extra []byte) ([]*wtypes.UnsignedUTXOTx, error) {
+	if wallet.IsWalletClosed() {
+		return nil, wtypes.ErrWalletNotOpen
+	}
+	currAccount, err := wallet.getCurrAccount(from)
+	if err != nil {
+		return nil, err
+	}
+	from = currAccount.getEthAddress()
+	inOutPackets, fees, changeSubaddr, err := wallet.selectUinPackets(from, subaddrs, dests, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	utxs := make([]*wtypes.UnsignedUTXOTx, 0)
+	for i, packet := range inOutPackets {
+		fee := fees[i]
+		if common.IsLKC(tokenID) {
+			// the fee of a LKC transaction is what its inputs pay above the outputs
+			fee = new(big.Int).Sub(sourcesAmount(packet.Sources), destsAmount(packet.Outputs, false))
+		}
+		txIDs := make([]common.Hash, len(packet.Inputs))
+		for j, item := range packet.Inputs {
+			txIDs[j] = common.Hash(currAccount.Transfers[item.localIdx].TxID)
+		}
+		addInfo := packetAddInfo(packet, changeSubaddr)
+		utxs = append(utxs, &wtypes.UnsignedUTXOTx{
+			From:        from,
+			TokenID:     tokenID,
+			Sources:     packet.Sources,
+			SourceTxIDs: txIDs,
+			Dests:       packet.Outputs,
+			Fee:         fee,
+			Extra:       extra,
+			Subaddrs:    addInfo.Subaddrs,
+			OutAmount:   addInfo.OutAmount,
+			ChangeIdx:   changeSubaddr,
+		})
+	}
+	return utxs, nil
+}
+
+// SignUnsignedUTXOTransaction sign a transaction created by CreateUnsignedUTXOTransaction, it does not
+// need the node. The change must go to the account, the inputs must pay the outputs and the fee, and
+// the fee must not be above the one the wallet pays. The key images of the spent outputs are saved
+// to be exported by ExportKeyImages
+func (wallet *Wallet) SignUnsignedUTXOTransaction(utx *wtypes.UnsignedUTXOTx) (*types.UTXOTransaction, error) {
+	if wallet.IsWalletClosed() {
+		return nil, wtypes.ErrWalletNotOpen
+	}
+	if len(utx.Sources) == 0 || len(utx.Sources) != len(utx.SourceTxIDs) || len(utx.Dests) == 0 ||
+		utx.Fee == nil || utx.OutAmount == nil {
+		return nil, wtypes.ErrUnsignedTxInvalid
+	}
+	currAccount, keys, err := wallet.currAccAndKeys(utx.From)
+	if err != nil {
+		return nil, err
+	}
+	if err = wallet.checkUnsignedUTXOTx(currAccount, utx); err != nil {
+		return nil, err
+	}
+	utxoTx, utxoInEphs, err := wallet.signUinTransaction(utx.From, currAccount, keys, utx.Sources, utx.Dests,
+		utx.TokenID, utx.Fee, utx.Extra)
+	if err != nil {
+		return nil, err
+	}
+	err = currAccount.saveAddInfo(utxoTx.Hash(), &wtypes.UTXOAddInfo{
+		Subaddrs:  utx.Subaddrs,
+		OutAmount: utx.OutAmount,
+		ChangeIdx: int(utx.ChangeIdx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = currAccount.saveSignedKeyImages(utx.SourceTxIDs, utx.Sources, utxoInEphs); err != nil {
+		return nil, err
+	}
+	return utxoTx, nil
+}
+
+// checkUnsignedUTXOTx check what the signer pays for: the change goes to the change subaddress of
+// currAccount, sum(sources) == sum(dests) + fee and the fee is not above maxUTXOFee of the dests
+func (wallet *Wallet) checkUnsignedUTXOTx(currAccount *LinkAccount, utx *wtypes.UnsignedUTXOTx) error {
+	for _, source := range utx.Sources {
+		if source == nil || source.Amount == nil || source.Amount.Sign() < 0 {
+			return wtypes.ErrUnsignedTxInvalid
+		}
+	}
+	for _, dest := range utx.Dests {
+		if dest == nil || dest.GetAmount() == nil || dest.GetAmount().Sign() <= 0 {
+			return wtypes.ErrUnsignedTxInvalid
+		}
+		if utxodest, ok := dest.(*types.UTXODestEntry); ok && utxodest.IsChange {
+			keys := currAccount.account.Keys
+			if utx.ChangeIdx >= uint64(len(keys)) || utxodest.Addr != keys[utx.ChangeIdx].Addr {
+				return wtypes.ErrUnsignedTxChange
+			}
+		}
+	}
+	if utx.Fee.Sign() < 0 || utx.OutAmount.Cmp(destsAmount(utx.Dests, true)) != 0 {
+		return wtypes.ErrUnsignedTxAmount
+	}
+	inAmount := sourcesAmount(utx.Sources)
+	outAmount := destsAmount(utx.Dests, false)
+	if common.IsLKC(utx.TokenID) {
+		outAmount.Add(outAmount, utx.Fee)
+	}
+	// the fee of a token transaction is paid by the LKC balance of the account
+	if inAmount.Cmp(outAmount) != 0 {
+		return wtypes.ErrUnsignedTxAmount
+	}
+	if utx.Fee.Cmp(wallet.maxUTXOFee(utx.Dests, utx.TokenID)) > 0 {
+		return wtypes.ErrUnsignedTxFee
+	}
+	return nil
+}
+
+// maxUTXOFee return the fee the wallet selects the inputs of a utxo input transaction paying dests with
+func (wallet *Wallet) maxUTXOFee(dests []types.DestEntry, tokenID common.Address) *big.Int {
+	accTransMoney := big.NewInt(0)
+	outKind := NilOut
+	for _, dest := range dests {
+		if types.TypeAcDest == dest.Type() {
+			accTransMoney.Add(accTransMoney, dest.GetAmount())
+			outKind |= AccOut
+		} else {
+			outKind |= UtxoOut
+		}
+	}
+	if !common.IsLKC(tokenID) {
+		return wallet.calTokenFee(UTXOInputMode, outKind)
+	}
+	// a LKC transaction pays the utxo fee even if the change output is merged into the fee
+	fee := new(big.Int).Set(wallet.estimateUtxoTxFee())
+	if accTransMoney.Sign() > 0 {
+		fee.Add(fee, wallet.estimateTxFee(accTransMoney))
+	}
+	return fee
+}
+
+func sourcesAmount(sources []*types.UTXOSourceEntry) *big.Int {
+	amount := big.NewInt(0)
+	for _, source := range sources {
+		amount.Add(amount, source.Amount)
+	}
+	return amount
+}
+
+// destsAmount return the amount paid to dests, without the change if noChange
+func destsAmount(dests []types.DestEntry, noChange bool) *big.Int {
+	amount := big.NewInt(0)
+	for _, dest := range dests {
+		if utxodest, ok := dest.(*types.UTXODestEntry); ok && utxodest.IsChange && noChange {
+			continue
+		}
+		amount.Add(amount, dest.GetAmount())
+	}
+	return amount
+}
+
+// ExportKeyImages return the key images known by the account
+func (wallet *Wallet) ExportKeyImages(addr *common.Address) ([]wtypes.KeyImageEntry, error) {
+	lkaccount := wallet.getLKAccountByAddress(addr)
+	if lkaccount != nil {
+		return lkaccount.ExportKeyImages()
+	}
+	return nil, wtypes.ErrWalletNotOpen
+}
+
+// ImportKeyImages import key images exported by a wallet holding the spend key of the account
+func (wallet *Wallet) ImportKeyImages(entries []wtypes.KeyImageEntry, addr *common.Address) (uint64, error) {
+	lkaccount := wallet.getLKAccountByAddress(addr)
+	if lkaccount != nil {
+		return lkaccount.ImportKeyImages(entries)
+	}
+	return 0, wtypes.ErrWalletNotOpen
+}
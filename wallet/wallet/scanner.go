@@ -0,0 +1,165 @@
+package wallet
+
+import (
+	"math/big"
+	"sync"
+
+	lkctypes "github.com/lianxiangcloud/linkchain/libs/cryptonote/types"
+	"github.com/lianxiangcloud/linkchain/libs/cryptonote/xcrypto"
+	"github.com/lianxiangcloud/linkchain/libs/log"
+	"github.com/lianxiangcloud/linkchain/rpc/rtypes"
+	tctypes "github.com/lianxiangcloud/linkchain/types"
+)
+
+const (
+	defaultScanWorkers = 4
+	// scanWindow is the maximum number of blocks fetched ahead of the local height
+	scanWindow = 64
+)
+
+// scanKeys is a copy of the keys the ownership checks of outputs need, so that
+// the checks run without holding the lock of the account
+type scanKeys struct {
+	keys          lkctypes.AccountKey
+	keyIndex      map[lkctypes.PublicKey]uint64
+	restoreHeight uint64
+}
+
+// getScanKeys return the keys of the account for the scanner, la.lock must be held
+func (la *LinkAccount) getScanKeys() *scanKeys {
+	sk := &scanKeys{
+		keys:          *la.account.GetKeys(),
+		keyIndex:      make(map[lkctypes.PublicKey]uint64, len(la.account.KeyIndex)),
+		restoreHeight: la.restoreHeight,
+	}
+	for k, v := range la.account.KeyIndex {
+		sk.keyIndex[k] = v
+	}
+	return sk
+}
+
+// outputOwner is the ownership of an utxo output of the account
+type outputOwner struct {
+	deriKey      lkctypes.KeyDerivation
+	rkey         lkctypes.PublicKey
+	subaddrIndex uint64
+}
+
+// scanBlock checks which utxo outputs of the block belong to the account. The
+// result is indexed by the position of the txs in the block, the outputs of
+// blocks lower than the restore height are not checked
+func (sk *scanKeys) scanBlock(block *rtypes.RPCBlock, logger log.Logger) [][]*outputOwner {
+	owners := make([][]*outputOwner, len(block.Txs))
+	if block.Height.ToInt().Cmp(new(big.Int).SetUint64(sk.restoreHeight)) < 0 {
+		return owners
+	}
+	for i, rpctx := range block.Txs {
+		if tx, ok := rpctx.(*rtypes.RPCTx).Tx.(*tctypes.UTXOTransaction); ok {
+			owners[i] = sk.scanOutputs(tx, logger)
+		}
+	}
+	return owners
+}
+
+// scanOutputs checks which utxo outputs of tx belong to the account. The result is
+// indexed by the position of the outputs among the utxo outputs of tx, the owners
+// of the outputs of other accounts are nil
+func (sk *scanKeys) scanOutputs(tx *tctypes.UTXOTransaction, logger log.Logger) []*outputOwner {
+	keyMaps := make(map[lkctypes.KeyDerivation]lkctypes.PublicKey, 0)
+	derivationKeys := make([]lkctypes.KeyDerivation, 0)
+	derivationKey, err := xcrypto.GenerateKeyDerivation(tx.RKey, sk.keys.ViewSKey)
+	if err != nil {
+		logger.Error("GenerateKeyDerivation fail", "rkey", tx.RKey, "err", err)
+		return nil
+	}
+	derivationKeys = append(derivationKeys, derivationKey)
+	keyMaps[derivationKey] = tx.RKey
+	//we use a addinational key for utxo->account proof, maybe cause err here
+	for _, addkey := range tx.AddKeys {
+		derivationKey, err := xcrypto.GenerateKeyDerivation(addkey, sk.keys.ViewSKey)
+		if err != nil {
+			logger.Info("GenerateKeyDerivation fail", "addkey", addkey, "err", err)
+			continue
+		}
+		derivationKeys = append(derivationKeys, derivationKey)
+		keyMaps[derivationKey] = addkey
+	}
+
+	owners := make([]*outputOwner, 0, len(tx.Outputs))
+	for _, o := range tx.Outputs {
+		ro, ok := o.(*tctypes.UTXOOutput)
+		if !ok {
+			continue
+		}
+		recIdx := uint64(len(owners))
+		realDeriKey, subaddrIndex, err := tctypes.IsOutputBelongToAccount(&sk.keys, sk.keyIndex, ro.OTAddr, derivationKeys, recIdx)
+		if err != nil {
+			logger.Debug("IsOutputBelongToAccount fail", "ro.OTAddr", ro.OTAddr, "recIdx", recIdx, "err", err)
+			owners = append(owners, nil)
+			continue
+		}
+		realRKey, exist := keyMaps[realDeriKey]
+		if !exist {
+			logger.Error("real rkey not found", "real derivation key", realDeriKey)
+			owners = append(owners, nil)
+			continue
+		}
+		owners = append(owners, &outputOwner{deriKey: realDeriKey, rkey: realRKey, subaddrIndex: subaddrIndex})
+	}
+	return owners
+}
+
+// blockScan is a block fetched and scanned ahead of the local height
+type blockScan struct {
+	height *big.Int
+	block  *rtypes.RPCBlock
+	owners [][]*outputOwner
+	err    error
+	done   chan struct{}
+}
+
+// scanBlocks fetches the cnt blocks from height on the worker pool of the account
+// and checks their outputs. The scans are done in any order, the caller waits for
+// them in order on their done channels. Closing quit stops the workers
+func (la *LinkAccount) scanBlocks(height *big.Int, cnt int, sk *scanKeys, quit chan struct{}) []*blockScan {
+	scans := make([]*blockScan, cnt)
+	for i := range scans {
+		scans[i] = &blockScan{
+			height: new(big.Int).Add(height, big.NewInt(int64(i))),
+			done:   make(chan struct{}),
+		}
+	}
+
+	workers := la.scanWorkers
+	if workers <= 0 {
+		workers = defaultScanWorkers
+	}
+	var next int
+	var nextLock sync.Mutex
+	for w := 0; w < workers; w++ {
+		go func() {
+			for {
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				nextLock.Lock()
+				i := next
+				next++
+				nextLock.Unlock()
+				if i >= cnt {
+					return
+				}
+
+				scan := scans[i]
+				scan.block, scan.err = la.api.GetBlockUTXOsByNumber(scan.height)
+				if scan.err == nil {
+					scan.owners = sk.scanBlock(scan.block, la.Logger)
+				}
+				close(scan.done)
+			}
+		}()
+	}
+	return scans
+}
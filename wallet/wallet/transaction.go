@@ -50,6 +50,9 @@ func (wallet *Wallet) CreateUTXOTransaction(from common.Address, nonce uint64, s
 	if err != nil {
 		return nil, err
 	}
+	if currAccount.isWatchOnly() {
+		return nil, wtypes.ErrWatchOnlyAccount
+	}
 	if from == common.EmptyAddress {
 		wallet.Logger.Debug("CreateUTXOTransaction from is EmptyAddress,use CreateUinTransaction")
 		return wallet.CreateUinTransaction(currAccount.getEthAddress(), subaddrs, dests, tokenID, extra)
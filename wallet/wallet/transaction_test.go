@@ -1013,3 +1013,132 @@ func TestOneRingMember(t *testing.T) {
 		return []byte{0}, nil
 	})
 }
+
+func TestUnsignedUTXOTxCodec(t *testing.T) {
+	addr, err := StrToAddress(utxoAccount0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utx := &wtypes.UnsignedUTXOTx{
+		From:    common.HexToAddress("0x54fb1c7d0f011dd63b08f85ed7b518ab82028100"),
+		TokenID: common.EmptyAddress,
+		Sources: []*types.UTXOSourceEntry{
+			&types.UTXOSourceEntry{
+				Ring:      []types.UTXORingEntry{types.UTXORingEntry{Index: 1}, types.UTXORingEntry{Index: 3}},
+				RingIndex: 1,
+				RKey:      lktypes.PublicKey{1},
+				Amount:    big.NewInt(2e18),
+				Mask:      lktypes.Key{2},
+			},
+		},
+		SourceTxIDs: []common.Hash{common.HexToHash("0x01")},
+		Dests: []types.DestEntry{
+			&types.UTXODestEntry{Addr: *addr, Amount: big.NewInt(1e18)},
+			&types.AccountDestEntry{To: common.HexToAddress("0x7b6837189a3464d3c696069b2b42a9ae8e17dda1"), Amount: big.NewInt(5e17)},
+		},
+		Fee:       big.NewInt(1e17),
+		Subaddrs:  []uint64{0},
+		OutAmount: big.NewInt(15e17),
+	}
+	bz, err := ser.EncodeToBytes(utx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded wtypes.UnsignedUTXOTx
+	if err := ser.DecodeBytes(bz, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	rebz, err := ser.EncodeToBytes(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bz, rebz) {
+		t.Errorf("unsigned tx mismatch:\ngot   %x\nwant  %x", rebz, bz)
+	}
+	if _, ok := decoded.Dests[1].(*types.AccountDestEntry); !ok {
+		t.Errorf("dest type mismatch: %T", decoded.Dests[1])
+	}
+}
+
+func TestCheckUnsignedUTXOTx(t *testing.T) {
+	addr, err := StrToAddress(utxoAccount0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := mockWallet.currAccount
+	fee := mockWallet.estimateUtxoTxFee()
+	newUtx := func() *wtypes.UnsignedUTXOTx {
+		return &wtypes.UnsignedUTXOTx{
+			From:        account.getEthAddress(),
+			TokenID:     common.EmptyAddress,
+			Sources:     []*types.UTXOSourceEntry{&types.UTXOSourceEntry{Amount: big.NewInt(2e18)}},
+			SourceTxIDs: []common.Hash{common.HexToHash("0x01")},
+			Dests: []types.DestEntry{
+				&types.UTXODestEntry{Addr: *addr, Amount: big.NewInt(1e18)},
+				&types.UTXODestEntry{
+					Addr:     account.account.Keys[0].Addr,
+					Amount:   new(big.Int).Sub(big.NewInt(1e18), fee),
+					IsChange: true,
+				},
+			},
+			Fee:       new(big.Int).Set(fee),
+			Subaddrs:  []uint64{0},
+			OutAmount: big.NewInt(1e18),
+		}
+	}
+	if err := mockWallet.checkUnsignedUTXOTx(account, newUtx()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(utx *wtypes.UnsignedUTXOTx)
+		err    error
+	}{
+		{"change to another account", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.Dests[1].(*types.UTXODestEntry).Addr = *addr
+		}, wtypes.ErrUnsignedTxChange},
+		{"change to an unknown subaddress", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.ChangeIdx = uint64(len(account.account.Keys))
+		}, wtypes.ErrUnsignedTxChange},
+		{"inputs above outputs and fee", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.Sources[0].Amount = big.NewInt(3e18)
+		}, wtypes.ErrUnsignedTxAmount},
+		{"inputs below outputs and fee", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.Dests[0].(*types.UTXODestEntry).Amount = big.NewInt(2e18)
+			utx.OutAmount = big.NewInt(2e18)
+		}, wtypes.ErrUnsignedTxAmount},
+		{"out amount not the dests", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.OutAmount = big.NewInt(1e17)
+		}, wtypes.ErrUnsignedTxAmount},
+		{"negative fee", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.Fee = new(big.Int).Neg(fee)
+		}, wtypes.ErrUnsignedTxAmount},
+		{"fee above the wallet fee", func(utx *wtypes.UnsignedUTXOTx) {
+			change := utx.Dests[1].(*types.UTXODestEntry)
+			change.Amount = new(big.Int).Sub(change.Amount, big.NewInt(1e10))
+			utx.Fee.Add(utx.Fee, big.NewInt(1e10))
+		}, wtypes.ErrUnsignedTxFee},
+		{"token fee above the wallet fee", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.TokenID = common.HexToAddress("0x01")
+			utx.Dests[1].(*types.UTXODestEntry).Amount = big.NewInt(1e18)
+			utx.Fee.Add(utx.Fee, big.NewInt(1e10))
+		}, wtypes.ErrUnsignedTxFee},
+		{"empty dest amount", func(utx *wtypes.UnsignedUTXOTx) {
+			utx.Dests[0].(*types.UTXODestEntry).Amount = nil
+		}, wtypes.ErrUnsignedTxInvalid},
+	}
+	for _, test := range tests {
+		utx := newUtx()
+		test.modify(utx)
+		if err := mockWallet.checkUnsignedUTXOTx(account, utx); err != test.err {
+			t.Errorf("%s: error mismatch\ngot   %v\nwant  %v", test.name, err, test.err)
+		}
+	}
+
+	utx := newUtx()
+	utx.Dests[1].(*types.UTXODestEntry).Addr = *addr
+	if _, err := mockWallet.SignUnsignedUTXOTransaction(utx); err != wtypes.ErrUnsignedTxChange {
+		t.Errorf("sign error mismatch\ngot   %v\nwant  %v", err, wtypes.ErrUnsignedTxChange)
+	}
+}
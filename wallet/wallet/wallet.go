@@ -27,6 +27,11 @@ const (
 func init() {
 	tctypes.RegisterUTXOTxData()
 	ser.RegisterInterface((*tctypes.Input)(nil), nil)
+
+	// dests of unsigned transactions
+	ser.RegisterInterface((*tctypes.DestEntry)(nil), nil)
+	ser.RegisterConcrete(&tctypes.UTXODestEntry{}, "UTXODestEntry", nil)
+	ser.RegisterConcrete(&tctypes.AccountDestEntry{}, "AccountDestEntry", nil)
 }
 
 // Wallet user wallet
@@ -79,26 +84,52 @@ func NewWallet(config *cfg.Config,
 	return wallet, nil
 }
 
-// OpenWallet ,open wallet with password
-func (w *Wallet) OpenWallet(keystoreFile string, password string) error {
+// OpenWallet ,open wallet with password. Outputs received before restoreHeight are not found,
+// 0 keeps the restore height of the last opening
+func (w *Wallet) OpenWallet(keystoreFile string, password string, restoreHeight uint64) error {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 
-	la, err := NewLinkAccount(w.walletDB, w.Logger, keystoreFile, password, w.api)
+	newAccount := NewLinkAccount
+	if w.config.WatchOnly {
+		newAccount = NewWatchLinkAccount
+	}
+	la, err := newAccount(w.walletDB, w.Logger, keystoreFile, password, restoreHeight, w.api)
 	if err != nil {
 		w.Logger.Error("OpenWallet NewLinkAccount fail", "err", err)
 		return err
 	}
+	w.openAccount(la)
+	return nil
+}
+
+// OpenViewWallet open a watch only wallet from the view secret key and the spend public key, outputs
+// received before restoreHeight are not found. It returns the address identifying the account
+func (w *Wallet) OpenViewWallet(viewSKey lkctypes.SecretKey, spendPKey lkctypes.PublicKey, restoreHeight uint64) (common.Address, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	la, err := NewViewLinkAccount(w.walletDB, w.Logger, viewSKey, spendPKey, restoreHeight, w.api)
+	if err != nil {
+		w.Logger.Error("OpenViewWallet NewViewLinkAccount fail", "err", err)
+		return common.EmptyAddress, err
+	}
+	w.openAccount(la)
+	return la.getEthAddress(), nil
+}
+
+func (w *Wallet) openAccount(la *LinkAccount) {
 	la.SetSyncQuick(w.config.Daemon.SyncQuick)
+	la.SetScanWorkers(w.config.Daemon.ScanWorkers)
 	addr := la.getEthAddress()
 
-	w.Logger.Info("OpenWallet", "address", addr)
+	w.Logger.Info("OpenWallet", "address", addr, "watchOnly", !la.account.HasSpendKey(), "restoreHeight", la.restoreHeight)
 
 	laOld, ok := w.addrMap[addr]
 	if ok {
+		laOld.setRestoreHeight(la.restoreHeight)
 		w.currAccount = laOld
-
-		return nil
+		return
 	}
 
 	w.addrMap[addr] = la
@@ -106,8 +137,6 @@ func (w *Wallet) OpenWallet(keystoreFile string, password string) error {
 
 	// default start account refresh
 	la.OnStart()
-
-	return nil
 }
 
 // IsWalletClosed return true if currAccount is nil
@@ -26,6 +26,8 @@ const (
 	keyBlockHash        = "blockHash"
 	keyBlockTxs         = "blockTxs"
 	keyUTXOAddInfo      = "utxoAddInfo"
+	keyKeyImage         = "keyImage"
+	keyRestoreHeight    = "restoreHeight"
 )
 
 func (la *LinkAccount) save(ids []uint64, blockHash common.Hash, localBlock *types.UTXOBlock) error {
@@ -50,6 +52,9 @@ func (la *LinkAccount) save(ids []uint64, blockHash common.Hash, localBlock *typ
 
 func (la *LinkAccount) addPrefixDBkey(key string) string {
 	prefix := la.mainUTXOAddress
+	if la.viewOnly {
+		prefix = fmt.Sprintf("view_%s", prefix)
+	}
 	return fmt.Sprintf("%s_%s", prefix, key)
 }
 
@@ -91,6 +96,36 @@ func (la *LinkAccount) saveLocalHeight(b dbm.Batch, nextHeigth *big.Int) error {
 	return nil
 }
 
+// restoreHeight
+func (la *LinkAccount) getRestoreHeightKey() []byte {
+	return []byte(la.addPrefixDBkey(keyRestoreHeight))
+}
+
+func (la *LinkAccount) loadRestoreHeight() error {
+	key := la.getRestoreHeightKey()
+
+	val := la.walletDB.Get(key[:])
+	if len(val) != 0 {
+		if err := ser.DecodeBytes(val, &la.restoreHeight); err != nil {
+			la.Logger.Error("loadRestoreHeight DecodeBytes fail", "val", string(val), "err", err)
+			return types.ErrInnerServer
+		}
+	}
+	la.Logger.Debug("loadRestoreHeight", "la.restoreHeight", la.restoreHeight)
+	return nil
+}
+
+func (la *LinkAccount) saveRestoreHeight(b dbm.Batch) error {
+	key := la.getRestoreHeightKey()
+	val, err := ser.EncodeToBytes(la.restoreHeight)
+	if err != nil {
+		la.Logger.Error("saveRestoreHeight EncodeToBytes fail", "err", err)
+		return types.ErrInnerServer
+	}
+	b.Set(key, val)
+	return nil
+}
+
 // gOutIndex
 func (la *LinkAccount) getGOutIndexKey() []byte {
 	return []byte(la.addPrefixDBkey(keyGOutIndex))
@@ -163,7 +198,9 @@ func (la *LinkAccount) loadTransfers() error {
 				la.updateBalance(tx.TokenID, tx.SubAddrIndex, true, tx.Amount)
 			}
 
-			la.keyImages[tx.KeyImage] = i
+			if tx.KeyImage != (lkctypes.Key{}) {
+				la.keyImages[tx.KeyImage] = i
+			}
 		}
 	}
 	return nil
@@ -451,3 +488,53 @@ func (la *LinkAccount) delAddInfo(hash common.Hash) error {
 	}
 	return nil
 }
+
+// key images of outputs, signed or imported
+func (la *LinkAccount) getKeyImagePrefix() []byte {
+	return []byte(fmt.Sprintf("%s_", la.addPrefixDBkey(keyKeyImage)))
+}
+
+func (la *LinkAccount) getKeyImageKey(txID common.Hash, outIndex uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s_%d", la.getKeyImagePrefix(), txID.String(), outIndex))
+}
+
+func (la *LinkAccount) loadKeyImage(txID common.Hash, outIndex uint64) (*types.KeyImageEntry, error) {
+	key := la.getKeyImageKey(txID, outIndex)
+	val := la.walletDB.Get(key[:])
+	if len(val) == 0 {
+		return nil, nil
+	}
+	var entry types.KeyImageEntry
+	if err := json.Unmarshal(val, &entry); err != nil {
+		la.Logger.Error("loadKeyImage json.Unmarshal fail", "val", string(val), "err", err)
+		return nil, types.ErrInnerServer
+	}
+	return &entry, nil
+}
+
+func (la *LinkAccount) loadKeyImages() ([]types.KeyImageEntry, error) {
+	itr := la.walletDB.NewIteratorWithPrefix(la.getKeyImagePrefix())
+	defer itr.Close()
+
+	entries := make([]types.KeyImageEntry, 0)
+	for ; itr.Valid(); itr.Next() {
+		var entry types.KeyImageEntry
+		if err := json.Unmarshal(itr.Value(), &entry); err != nil {
+			la.Logger.Error("loadKeyImages json.Unmarshal fail", "val", string(itr.Value()), "err", err)
+			return nil, types.ErrInnerServer
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (la *LinkAccount) saveKeyImage(b dbm.Batch, entry *types.KeyImageEntry) error {
+	key := la.getKeyImageKey(entry.TxHash, uint64(entry.OutIndex))
+	val, err := json.Marshal(entry)
+	if err != nil {
+		la.Logger.Error("saveKeyImage json.Marshal fail", "err", err)
+		return types.ErrInnerServer
+	}
+	b.Set(key, val)
+	return nil
+}